@@ -0,0 +1,68 @@
+package entities
+
+import "time"
+
+// Kinds of records that are archived instead of deleted
+const (
+	ArchiveKindMetadata  = "metadata"
+	ArchiveKindCategory  = "category"
+	ArchiveKindStore     = "store"
+	ArchiveKindWarehouse = "warehouse"
+)
+
+// What archiving a record does to the records referencing it
+const (
+	DependencyBlocks   = "blocks"   // the record cannot be archived while they exist
+	DependencyCascades = "cascades" // archived along with the record when cascade is asked for, blocking otherwise
+	DependencyKept     = "kept"     // left as they are, still pointing at the archived record
+)
+
+type Dependency struct {
+	Collection string `json:"collection"`
+	Count      int64  `json:"count"`
+	Effect     string `json:"effect"`
+	Reason     string `json:"reason"`
+}
+
+// DependencyReport lists what references a record, and whether it can be archived with or without cascade.
+// Archived is set once the record has been archived.
+type DependencyReport struct {
+	Kind         string        `json:"kind"`
+	ID           string        `json:"id"`
+	Cascade      bool          `json:"cascade"`
+	Blocked      bool          `json:"blocked"`
+	Archived     bool          `json:"archived"`
+	Dependencies []*Dependency `json:"dependencies"`
+}
+
+// ArchiveRequest archives a record. Without Cascade the archive is refused while other records depend on it.
+type ArchiveRequest struct {
+	Cascade    bool   `form:"cascade"`
+	ArchivedBy string `json:"archived_by" bson:"omitempty"`
+}
+
+// ArchivedRecord is an archived record as listed for restore. ArchivedWith names the record it was archived
+// along with, as kind:id, when it was archived by a cascade.
+type ArchivedRecord struct {
+	Kind         string    `json:"kind" bson:"-"`
+	ID           string    `json:"id" bson:"_id"`
+	Name         string    `json:"name" bson:"name"`
+	ArchivedAt   time.Time `json:"archived_at" bson:"archived_at"`
+	ArchivedBy   string    `json:"archived_by" bson:"archived_by"`
+	ArchivedWith string    `json:"archived_with,omitempty" bson:"archived_with,omitempty"`
+}
+
+type PaginatedArchivedRecords struct {
+	Records    []*ArchivedRecord `json:"records"`
+	Total      int64             `json:"total"`
+	Limit      int64             `json:"limit"`
+	Offset     int64             `json:"offset"`
+	TotalPages int64             `json:"total_pages"`
+}
+
+// RestoreReport counts the records brought back with a restored record, by collection
+type RestoreReport struct {
+	Kind     string           `json:"kind"`
+	ID       string           `json:"id"`
+	Restored map[string]int64 `json:"restored"`
+}
@@ -0,0 +1,48 @@
+package entities
+
+import "time"
+
+// Types an attribute of a metadata product can have
+const (
+	AttributeTypeText    = "text"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeEnum    = "enum"
+)
+
+// AttributeDefinition describes one attribute metadata of a subcategory carries, such as the fat % of dairy.
+// Options lists the allowed values of an enum, and Min and Max optionally bound a number.
+type AttributeDefinition struct {
+	Key      string   `json:"key" bson:"key"`
+	Label    string   `json:"label" bson:"label"`
+	Type     string   `json:"type" bson:"type"`
+	Unit     string   `json:"unit,omitempty" bson:"unit,omitempty"`
+	Required bool     `json:"required" bson:"required"`
+	Options  []string `json:"options,omitempty" bson:"options,omitempty"`
+	Min      *float64 `json:"min,omitempty" bson:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty" bson:"max,omitempty"`
+}
+
+// AttributeSchema is the set of attributes metadata of a subcategory is validated against
+type AttributeSchema struct {
+	ID            string                 `json:"id" bson:"_id,omitempty"`
+	SubcategoryID string                 `json:"subcategory_id" bson:"subcategory_id"`
+	Attributes    []*AttributeDefinition `json:"attributes" bson:"attributes"`
+	UpdatedBy     string                 `json:"updated_by" bson:"updated_by"`
+	CreatedAt     time.Time              `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at" bson:"updated_at"`
+}
+
+type SaveAttributeSchemaRequest struct {
+	SubcategoryID string                 `json:"subcategory_id"`
+	Attributes    []*AttributeDefinition `json:"attributes"`
+	OperationalID string                 `json:"operational_id" bson:"omitempty"`
+}
+
+// AttributeFilter narrows a product listing on one attribute, to any of Values or to the range Min to Max
+type AttributeFilter struct {
+	Key    string
+	Values []interface{}
+	Min    *float64
+	Max    *float64
+}
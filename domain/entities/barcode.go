@@ -0,0 +1,36 @@
+package entities
+
+import "time"
+
+// Barcode formats, told apart by the number of digits
+const (
+	BarcodeEAN8   = "EAN-8"
+	BarcodeUPCA   = "UPC-A"
+	BarcodeEAN13  = "EAN-13"
+	BarcodeGTIN14 = "GTIN-14"
+)
+
+// Barcode ties a scannable code to a metadata product. GTIN is the code zero-padded to 14 digits, so the UPC-A
+// and EAN-13 forms of the same code are one barcode and a code can only belong to one product.
+type Barcode struct {
+	GTIN              string    `json:"gtin" bson:"_id"`
+	Code              string    `json:"code" bson:"code"`
+	Format            string    `json:"format" bson:"format"`
+	MetadataProductID string    `json:"metadata_product_id" bson:"metadata_product_id"`
+	AddedBy           string    `json:"added_by" bson:"added_by"`
+	CreatedAt         time.Time `json:"created_at" bson:"created_at"`
+}
+
+type AddBarcodesRequest struct {
+	MetadataProductID string   `json:"metadata_product_id" binding:"required"`
+	Codes             []string `json:"codes" binding:"required"`
+	AddedBy           string   `json:"added_by" bson:"omitempty"`
+}
+
+// BarcodeLookupResponse is what a scanned code resolves to. InventoryProduct is the seller's own listing of the
+// product, nil when the seller does not stock it yet.
+type BarcodeLookupResponse struct {
+	Barcode          *Barcode          `json:"barcode"`
+	Metadata         *Metadata         `json:"metadata"`
+	InventoryProduct *InventoryProduct `json:"inventory_product"`
+}
@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// States of a seller's catalog proposal. An approved proposal became new metadata, a merged one was matched to
+// metadata already in the catalog.
+const (
+	CatalogProposalPending  = "pending"
+	CatalogProposalApproved = "approved"
+	CatalogProposalMerged   = "merged"
+	CatalogProposalRejected = "rejected"
+)
+
+// Decisions an admin takes on a pending proposal
+const (
+	CatalogProposalApprove = "approve"
+	CatalogProposalMerge   = "merge"
+	CatalogProposalReject  = "reject"
+)
+
+// CatalogProposal is a product a seller asked to add to the catalog. Once approved or merged, MetadataProductID is
+// the metadata it became and InventoryProductID the listing added to the seller's inventory for it.
+type CatalogProposal struct {
+	ID                 string                 `json:"id" bson:"_id,omitempty"`
+	SellerID           string                 `json:"seller_id" bson:"seller_id"`
+	Name               string                 `json:"name" bson:"name"`
+	HsnCode            string                 `json:"hsn_code" bson:"hsn_code"`
+	Description        string                 `json:"description" bson:"description"`
+	MRP                float64                `json:"mrp" bson:"mrp"`
+	CategoryID         string                 `json:"category_id" bson:"category_id"`
+	SubcategoryID      string                 `json:"subcategory_id" bson:"subcategory_id"`
+	Images             []string               `json:"images" bson:"images"`
+	Attributes         map[string]interface{} `json:"attributes,omitempty" bson:"attributes,omitempty"`
+	Status             string                 `json:"status" bson:"status"`
+	MetadataProductID  string                 `json:"metadata_product_id,omitempty" bson:"metadata_product_id,omitempty"`
+	InventoryProductID string                 `json:"inventory_product_id,omitempty" bson:"inventory_product_id,omitempty"`
+	ReviewedBy         string                 `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewNote         string                 `json:"review_note,omitempty" bson:"review_note,omitempty"`
+	ReviewedAt         *time.Time             `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+	CreatedAt          time.Time              `json:"created_at" bson:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at" bson:"updated_at"`
+}
+
+type SubmitCatalogProposalRequest struct {
+	Name          string                 `json:"name" binding:"required"`
+	HsnCode       string                 `json:"hsn_code" binding:"required"`
+	Description   string                 `json:"description" binding:"required"`
+	MRP           float64                `json:"mrp" binding:"required"`
+	CategoryID    string                 `json:"category_id" binding:"required"`
+	SubcategoryID string                 `json:"subcategory_id" binding:"required"`
+	Images        []string               `json:"images"`
+	Attributes    map[string]interface{} `json:"attributes"`
+	SellerID      string                 `json:"seller_id" bson:"omitempty"`
+}
+
+// ReviewCatalogProposalRequest decides a proposal. MetadataProductID names the existing metadata to merge into.
+type ReviewCatalogProposalRequest struct {
+	ProposalID        string `json:"proposal_id"`
+	Decision          string `json:"decision" binding:"required"`
+	MetadataProductID string `json:"metadata_product_id"`
+	Note              string `json:"note"`
+	ReviewerID        string `json:"reviewer_id" bson:"omitempty"`
+}
+
+type PaginatedCatalogProposals struct {
+	Proposals  []*CatalogProposal `json:"proposals"`
+	Total      int64              `json:"total"`
+	Limit      int64              `json:"limit"`
+	Offset     int64              `json:"offset"`
+	TotalPages int64              `json:"total_pages"`
+}
@@ -3,20 +3,24 @@ package entities
 import "time"
 
 type Category struct {
-	CategoryID        string    `json:"id" bson:"_id,omitempty"`
-	CategoryName      string    `json:"category_name" bson:"category_name"`
-	CategoryImage     string    `json:"category_image" bson:"category_image"`
-	CategoryCreatedAt time.Time `json:"category_created_at" bson:"category_created_at"`
-	CategoryUpdatedAt time.Time `json:"category_updated_at" bson:"category_updated_at"`
+	CategoryID        string     `json:"id" bson:"_id,omitempty"`
+	CategoryName      string     `json:"category_name" bson:"category_name"`
+	CategoryImage     string     `json:"category_image" bson:"category_image"`
+	CategoryCreatedAt time.Time  `json:"category_created_at" bson:"category_created_at"`
+	CategoryUpdatedAt time.Time  `json:"category_updated_at" bson:"category_updated_at"`
+	ArchivedAt        *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	ArchivedBy        string     `json:"archived_by,omitempty" bson:"archived_by,omitempty"`
 }
 
 type Subcategory struct {
-	SubcategoryID        string    `json:"id" bson:"_id,omitempty"`
-	SubcategoryName      string    `json:"subcategory_name" bson:"subcategory_name"`
-	SubcategoryImage     string    `json:"subcategory_image" bson:"subcategory_image"`
-	CategoryID           string    `json:"category_id" bson:"category_id"`
-	SubcategoryCreatedAt time.Time `json:"subcategory_created_at" bson:"subcategory_created_at"`
-	SubcategoryUpdatedAt time.Time `json:"subcategory_updated_at" bson:"subcategory_updated_at"`
+	SubcategoryID        string     `json:"id" bson:"_id,omitempty"`
+	SubcategoryName      string     `json:"subcategory_name" bson:"subcategory_name"`
+	SubcategoryImage     string     `json:"subcategory_image" bson:"subcategory_image"`
+	CategoryID           string     `json:"category_id" bson:"category_id"`
+	SubcategoryCreatedAt time.Time  `json:"subcategory_created_at" bson:"subcategory_created_at"`
+	SubcategoryUpdatedAt time.Time  `json:"subcategory_updated_at" bson:"subcategory_updated_at"`
+	ArchivedAt           *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	ArchivedBy           string     `json:"archived_by,omitempty" bson:"archived_by,omitempty"`
 }
 
 // Request DTOs
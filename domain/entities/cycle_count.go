@@ -0,0 +1,101 @@
+package entities
+
+import "time"
+
+// Cycle count session states. Counts are accepted while open and frozen once the session is in review.
+const (
+	CycleCountOpen      = "open"
+	CycleCountInReview  = "in_review"
+	CycleCountCompleted = "completed"
+	CycleCountCancelled = "cancelled"
+)
+
+type CycleCountSession struct {
+	ID          string             `json:"id" bson:"_id,omitempty"`
+	WarehouseID string             `json:"warehouse_id" bson:"warehouse_id"`
+	StoreID     string             `json:"store_id" bson:"store_id"`
+	SellerID    string             `json:"seller_id" bson:"seller_id"`
+	InventoryID string             `json:"inventory_id" bson:"inventory_id"`
+	Rack        string             `json:"rack" bson:"rack"`
+	RackID      string             `json:"rack_id,omitempty" bson:"rack_id,omitempty"`
+	Status      string             `json:"status" bson:"status"`
+	Lines       []*CycleCountLine  `json:"lines" bson:"lines"`
+	Summary     *CycleCountSummary `json:"summary,omitempty" bson:"summary,omitempty"`
+	CreatedBy   string             `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	SubmittedAt *time.Time         `json:"submitted_at,omitempty" bson:"submitted_at,omitempty"`
+	ApprovedBy  string             `json:"approved_by,omitempty" bson:"approved_by,omitempty"`
+	ApprovedAt  *time.Time         `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
+}
+
+// CycleCountLine compares the counted quantity of a product with the system quantity. The system quantity is captured
+// when the session opens and again, from the ledger, as of the time the product is counted.
+type CycleCountLine struct {
+	InventoryProductID string     `json:"inventory_product_id" bson:"inventory_product_id"`
+	MetadataProductID  string     `json:"metadata_product_id" bson:"metadata_product_id"`
+	MetadataName       string     `json:"metadata_name" bson:"metadata_name"`
+	ProductPrice       float64    `json:"product_price" bson:"product_price"`
+	SystemQuantity     int        `json:"system_quantity" bson:"system_quantity"`
+	CountedQuantity    *int       `json:"counted_quantity" bson:"counted_quantity"`
+	Variance           int        `json:"variance" bson:"variance"`
+	CountedBy          string     `json:"counted_by,omitempty" bson:"counted_by,omitempty"`
+	CountedAt          *time.Time `json:"counted_at,omitempty" bson:"counted_at,omitempty"`
+	Approved           bool       `json:"approved" bson:"approved"`
+	Reason             string     `json:"reason,omitempty" bson:"reason,omitempty"`
+	QuantityAfter      *int       `json:"quantity_after,omitempty" bson:"quantity_after,omitempty"`
+}
+
+type CycleCountSummary struct {
+	TotalLines       int     `json:"total_lines" bson:"total_lines"`
+	CountedLines     int     `json:"counted_lines" bson:"counted_lines"`
+	VarianceLines    int     `json:"variance_lines" bson:"variance_lines"`
+	AdjustedLines    int     `json:"adjusted_lines" bson:"adjusted_lines"`
+	ShortageUnits    int     `json:"shortage_units" bson:"shortage_units"`
+	ExcessUnits      int     `json:"excess_units" bson:"excess_units"`
+	NetVarianceUnits int     `json:"net_variance_units" bson:"net_variance_units"`
+	NetVarianceValue float64 `json:"net_variance_value" bson:"net_variance_value"`
+}
+
+type CreateCycleCountSessionRequest struct {
+	StoreID       string `json:"store_id"`
+	Rack          string `json:"rack"`
+	OperationalID string `json:"operational_id" bson:"omitempty"`
+}
+
+// SubmitCycleCountsRequest carries a batch of counts, possibly collected offline. Resubmitting a batch is safe,
+// a line keeps the count with the latest counted_at.
+type SubmitCycleCountsRequest struct {
+	Counts []*CycleCountEntry `json:"counts"`
+}
+
+type CycleCountEntry struct {
+	InventoryProductID string    `json:"inventory_product_id"`
+	CountedQuantity    int       `json:"counted_quantity"`
+	CountedAt          time.Time `json:"counted_at"`
+}
+
+type ApproveCycleCountRequest struct {
+	Lines []*CycleCountDecision `json:"lines"`
+}
+
+type CycleCountDecision struct {
+	InventoryProductID string `json:"inventory_product_id"`
+	Approve            bool   `json:"approve"`
+	Reason             string `json:"reason"`
+}
+
+type GetCycleCountSessionsRequest struct {
+	OperationalID string
+	StoreID       string
+	Status        string
+	Offset        int64
+	Limit         int64
+}
+
+type PaginatedCycleCountSessionResponse struct {
+	Sessions   []*CycleCountSession `json:"sessions"`
+	Total      int64                `json:"total"`
+	Limit      int64                `json:"limit"`
+	Offset     int64                `json:"offset"`
+	TotalPages int64                `json:"total_pages"`
+}
@@ -0,0 +1,60 @@
+package entities
+
+import "time"
+
+// Column headers of the HSN master import sheet. effective_from is optional and defaults to the import date.
+const (
+	HSNSheetCode          = "hsn_code"
+	HSNSheetDescription   = "description"
+	HSNSheetGSTRate       = "gst_rate"
+	HSNSheetEffectiveFrom = "effective_from"
+)
+
+// GSTRate is the GST percentage charged on an HSN code from EffectiveFrom until the next rate takes over
+type GSTRate struct {
+	Rate          float64   `json:"rate" bson:"rate"`
+	EffectiveFrom time.Time `json:"effective_from" bson:"effective_from"`
+}
+
+// HSNCode is an entry of the HSN master. Rates are kept oldest first so a rate change can be scheduled ahead of
+// time. CurrentRate is the rate in force when the code was read.
+type HSNCode struct {
+	Code        string     `json:"code" bson:"_id"`
+	Description string     `json:"description" bson:"description"`
+	Rates       []*GSTRate `json:"rates" bson:"rates"`
+	CurrentRate *float64   `json:"current_rate,omitempty" bson:"-"`
+	UpdatedBy   string     `json:"updated_by" bson:"updated_by"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// SaveHSNCodeRequest adds an HSN code or a rate to it. A rate with the same EffectiveFrom as an existing one
+// replaces it.
+type SaveHSNCodeRequest struct {
+	Code          string     `json:"code" binding:"required"`
+	Description   string     `json:"description"`
+	GSTRate       *float64   `json:"gst_rate" binding:"required"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+	UpdatedBy     string     `json:"updated_by" bson:"omitempty"`
+}
+
+type PaginatedHSNCodes struct {
+	Codes      []*HSNCode `json:"codes"`
+	Total      int64      `json:"total"`
+	Limit      int64      `json:"limit"`
+	Offset     int64      `json:"offset"`
+	TotalPages int64      `json:"total_pages"`
+}
+
+type HSNImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	HsnCode string `json:"hsn_code"`
+	Message string `json:"message"`
+}
+
+type HSNImportResult struct {
+	TotalRows   int                  `json:"total_rows"`
+	SavedCount  int                  `json:"saved_count"`
+	FailedCount int                  `json:"failed_count"`
+	Errors      []*HSNImportRowError `json:"errors"`
+}
@@ -0,0 +1,98 @@
+package entities
+
+import "time"
+
+// Inbound shipment states. A seller declares a shipment, ops receive it at the warehouse and issue the
+// goods-received note (GRN).
+const (
+	InboundShipmentDeclared  = "declared"
+	InboundShipmentReceived  = "received"
+	InboundShipmentCancelled = "cancelled"
+)
+
+type InboundShipment struct {
+	ID          string                 `json:"id" bson:"_id,omitempty"`
+	GRNNumber   string                 `json:"grn_number,omitempty" bson:"grn_number,omitempty"`
+	WarehouseID string                 `json:"warehouse_id" bson:"warehouse_id"`
+	StoreID     string                 `json:"store_id" bson:"store_id"`
+	StoreName   string                 `json:"store_name" bson:"store_name"`
+	SellerID    string                 `json:"seller_id" bson:"seller_id"`
+	Items       []*InboundShipmentItem `json:"items" bson:"items"`
+	Status      string                 `json:"status" bson:"status"`
+	Note        string                 `json:"note" bson:"note"`
+	ExpectedAt  *time.Time             `json:"expected_at,omitempty" bson:"expected_at,omitempty"`
+	DeclaredAt  time.Time              `json:"declared_at" bson:"declared_at"`
+	ReceiveNote string                 `json:"receive_note,omitempty" bson:"receive_note,omitempty"`
+	ReceivedBy  string                 `json:"received_by,omitempty" bson:"received_by,omitempty"`
+	ReceivedAt  *time.Time             `json:"received_at,omitempty" bson:"received_at,omitempty"`
+	CancelledBy string                 `json:"cancelled_by,omitempty" bson:"cancelled_by,omitempty"`
+	CancelledAt *time.Time             `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+}
+
+// InboundShipmentItem is one declared line. On receipt the declared quantity is split into accepted, short and
+// damaged, and only the accepted quantity is posted to InventoryProductID. UnitCost is what the seller paid per
+// unit, it feeds the average cost the stock is valued at.
+type InboundShipmentItem struct {
+	MetadataProductID        string    `json:"metadata_product_id" bson:"metadata_product_id"`
+	MetadataName             string    `json:"metadata_name" bson:"metadata_name"`
+	DeclaredQuantity         int       `json:"declared_quantity" bson:"declared_quantity"`
+	ProductPrice             float64   `json:"product_price" bson:"product_price"`
+	UnitCost                 float64   `json:"unit_cost" bson:"unit_cost"`
+	ProductExpiryDate        time.Time `json:"product_expiry_date" bson:"product_expiry_date"`
+	ProductManufacturingDate time.Time `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
+	AcceptedQuantity         int       `json:"accepted_quantity" bson:"accepted_quantity"`
+	ShortQuantity            int       `json:"short_quantity" bson:"short_quantity"`
+	DamagedQuantity          int       `json:"damaged_quantity" bson:"damaged_quantity"`
+	Remark                   string    `json:"remark,omitempty" bson:"remark,omitempty"`
+	InventoryProductID       string    `json:"inventory_product_id,omitempty" bson:"inventory_product_id,omitempty"`
+}
+
+type CreateInboundShipmentRequest struct {
+	StoreID    string                              `json:"store_id"`
+	Items      []*CreateInboundShipmentItemRequest `json:"items"`
+	Note       string                              `json:"note"`
+	ExpectedAt string                              `json:"expected_at"`
+	SellerID   string                              `json:"seller_id" bson:"omitempty"`
+}
+
+type CreateInboundShipmentItemRequest struct {
+	MetadataProductID        string  `json:"metadata_product_id"`
+	Quantity                 int     `json:"quantity"`
+	ProductPrice             float64 `json:"product_price"`
+	UnitCost                 float64 `json:"unit_cost"`
+	ProductExpiryDate        string  `json:"product_expiry_date"`
+	ProductManufacturingDate string  `json:"product_manufacturing_date"`
+}
+
+// ReceiveInboundShipmentRequest carries the counts for every declared line, matched by position
+type ReceiveInboundShipmentRequest struct {
+	Items         []*ReceiveInboundShipmentItemRequest `json:"items"`
+	Note          string                               `json:"note"`
+	ShipmentID    string                               `json:"shipment_id" bson:"omitempty"`
+	OperationalID string                               `json:"operational_id" bson:"omitempty"`
+}
+
+type ReceiveInboundShipmentItemRequest struct {
+	Line             int    `json:"line"`
+	AcceptedQuantity int    `json:"accepted_quantity"`
+	ShortQuantity    int    `json:"short_quantity"`
+	DamagedQuantity  int    `json:"damaged_quantity"`
+	Remark           string `json:"remark"`
+}
+
+type GetInboundShipmentsRequest struct {
+	OperationalID string
+	SellerID      string
+	StoreID       string
+	Status        string
+	Offset        int64
+	Limit         int64
+}
+
+type PaginatedInboundShipmentResponse struct {
+	Shipments  []*InboundShipment `json:"shipments"`
+	Total      int64              `json:"total"`
+	Limit      int64              `json:"limit"`
+	Offset     int64              `json:"offset"`
+	TotalPages int64              `json:"total_pages"`
+}
@@ -17,6 +17,11 @@ type InventoryProduct struct {
 	ProductPrice             float64   `json:"product_price" bson:"product_price"`
 	ProductExpiryDate        time.Time `json:"product_expiry_date" bson:"product_expiry_date"`
 	ProductManufacturingDate time.Time `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
+	UnitCost                 float64   `json:"unit_cost" bson:"unit_cost,omitempty"`
+	ReviewStatus             string    `json:"review_status" bson:"review_status,omitempty"`
+	ReviewReason             string    `json:"review_reason" bson:"review_reason,omitempty"`
+	RackID                   string    `json:"rack_id,omitempty" bson:"rack_id,omitempty"`
+	Bin                      int       `json:"bin,omitempty" bson:"bin,omitempty"`
 }
 type GetAllInventoryRequest struct {
 	Limit  int64  `json:"limit"`
@@ -25,24 +30,26 @@ type GetAllInventoryRequest struct {
 }
 
 type GetAllInventoryResponse struct {
-	InventoryId              string  `json:"inventory_id" bson:"inventory_id"`
-	InventoryProductId       string  `json:"inventory_product_id" bson:"inventory_product_id"`
-	MetadataProductId        string  `json:"metadata_product_id" bson:"metadata_product_id"`
-	ProductVisibility        bool    `json:"product_visibility" bson:"product_visibility"`
-	MetadataName             string  `json:"metadata_name" bson:"metadata_name"`
-	MetadataDescription      string  `json:"metadata_description" bson:"metadata_description"`
-	MetadataImage            string  `json:"metadata_image" bson:"metadata_image"`
-	MetadataCategoryId       string  `json:"metadata_category_id" bson:"metadata_category_id"`
-	MetadataSubcategoryId    string  `json:"metadata_subcategory_id" bson:"metadata_subcategory_id"`
-	MetadataMrp              float64 `json:"metadata_mrp" bson:"metadata_mrp"`
-	ProductQuantity          int     `json:"product_quantity" bson:"product_quantity"`
-	ProductPrice             float64 `json:"product_price" bson:"product_price"`
-	ProductExpiryDate        string  `json:"product_expiry_date" bson:"product_expiry_date"`
-	ProductManufacturingDate string  `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
-	MetadataCreatedAt        string  `json:"metadata_created_at" bson:"metadata_created_at"`
-	MetadataCategoryName     string  `json:"metadata_category_name" bson:"metadata_category_name"`
-	MetadataSubcategoryName  string  `json:"metadata_subcategory_name" bson:"metadata_subcategory_name"`
-	MetadataHSNCode          string  `json:"metadata_hsn_code" bson:"metadata_hsn_code"`
+	InventoryId              string     `json:"inventory_id" bson:"inventory_id"`
+	InventoryProductId       string     `json:"inventory_product_id" bson:"inventory_product_id"`
+	MetadataProductId        string     `json:"metadata_product_id" bson:"metadata_product_id"`
+	ProductVisibility        bool       `json:"product_visibility" bson:"product_visibility"`
+	MetadataName             string     `json:"metadata_name" bson:"metadata_name"`
+	MetadataDescription      string     `json:"metadata_description" bson:"metadata_description"`
+	MetadataImage            string     `json:"metadata_image" bson:"metadata_image"`
+	MetadataCategoryId       string     `json:"metadata_category_id" bson:"metadata_category_id"`
+	MetadataSubcategoryId    string     `json:"metadata_subcategory_id" bson:"metadata_subcategory_id"`
+	MetadataMrp              float64    `json:"metadata_mrp" bson:"metadata_mrp"`
+	ProductQuantity          int        `json:"product_quantity" bson:"product_quantity"`
+	ProductPrice             float64    `json:"product_price" bson:"product_price"`
+	ProductExpiryDate        string     `json:"product_expiry_date" bson:"product_expiry_date"`
+	ProductManufacturingDate string     `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
+	MetadataCreatedAt        string     `json:"metadata_created_at" bson:"metadata_created_at"`
+	MetadataCategoryName     string     `json:"metadata_category_name" bson:"metadata_category_name"`
+	MetadataSubcategoryName  string     `json:"metadata_subcategory_name" bson:"metadata_subcategory_name"`
+	MetadataHSNCode          string     `json:"metadata_hsn_code" bson:"metadata_hsn_code"`
+	SalePrice                *float64   `json:"sale_price,omitempty" bson:"sale_price"`
+	SaleEndsAt               *time.Time `json:"sale_ends_at,omitempty" bson:"sale_ends_at"`
 }
 
 type PaginatedInventoryResponse struct {
@@ -51,6 +58,40 @@ type PaginatedInventoryResponse struct {
 	Limit            int64                     `json:"limit"`
 	Offset           int64                     `json:"offset"`
 	TotalPages       int64                     `json:"total_pages"`
+	Facets           *InventoryFacets          `json:"facets,omitempty"`
+}
+
+// InventoryListFilter narrows the seller inventory listing. Nil and empty fields are not applied.
+type InventoryListFilter struct {
+	CategoryIDs    []string
+	SubcategoryIDs []string
+	Visibility     *bool
+	MinQuantity    *int
+	MaxQuantity    *int
+	MinPrice       *float64
+	MaxPrice       *float64
+	ExpiryFrom     *time.Time
+	ExpiryTo       *time.Time
+}
+
+// Fields the inventory listing can be sorted on. Prefix a field with "-" in the sort parameter for descending order.
+var InventorySortFields = []string{"name", "price", "mrp", "quantity", "expiry_date", "manufacturing_date", "category", "subcategory", "visibility", "created_at"}
+
+// InventoryFacets counts the products matching the search per filter value. Listing filters are not applied,
+// so every filter chip keeps its count while others are selected.
+type InventoryFacets struct {
+	Categories    []*InventoryFacetCount `json:"categories" bson:"categories"`
+	Subcategories []*InventoryFacetCount `json:"subcategories" bson:"subcategories"`
+	Visible       int64                  `json:"visible" bson:"visible"`
+	Hidden        int64                  `json:"hidden" bson:"hidden"`
+	InStock       int64                  `json:"in_stock" bson:"in_stock"`
+	OutOfStock    int64                  `json:"out_of_stock" bson:"out_of_stock"`
+}
+
+type InventoryFacetCount struct {
+	ID    string `json:"id" bson:"_id"`
+	Name  string `json:"name" bson:"name"`
+	Count int64  `json:"count" bson:"count"`
 }
 
 type AddInventoryRequest struct {
@@ -59,8 +100,8 @@ type AddInventoryRequest struct {
 }
 
 type UpdateInventoryRequest struct {
+	SellerID                 string  `json:"seller_id" bson:"omitempty"`
 	InventoryProductID       string  `json:"inventory_product_id"`
-	ProductVisibility        bool    `json:"product_visibility"`
 	ProductQuantity          int     `json:"product_quantity"`
 	ProductPrice             float64 `json:"product_price"`
 	ProductExpiryDate        string  `json:"product_expiry_date"`
@@ -99,14 +140,75 @@ type GetInventoryByIdResponse struct {
 }
 
 type AddInventoryByExcelRequest struct {
-	SellerID         string `json:"seller_id" bson:"omitempty"`
-	MetadataProducts []struct {
-		ProductMetadataId        string  `json:"metadata_product_id" bson:"metadata_product_id"`
-		ProductQuantity          int     `json:"product_quantity" bson:"product_quantity"`
-		ProductPrice             float64 `json:"product_price" bson:"product_price" `
-		ProductExpiryDate        string  `json:"product_expiry_date" bson:"product_expiry_date"`
-		ProductManufacturingDate string  `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
-	} `json:"metadataProducts" bson:"metadataProducts"`
+	SellerID         string                       `json:"seller_id" bson:"omitempty"`
+	MetadataProducts []AddInventoryByExcelProduct `json:"metadataProducts" bson:"metadataProducts"`
+}
+
+// AddInventoryByExcelProduct adds stock of a product. With InventoryProductID it instead sets the quantity,
+// price and dates of that listing.
+type AddInventoryByExcelProduct struct {
+	InventoryProductID       string  `json:"inventory_product_id" bson:"inventory_product_id,omitempty"`
+	ProductMetadataId        string  `json:"metadata_product_id" bson:"metadata_product_id"`
+	ProductQuantity          int     `json:"product_quantity" bson:"product_quantity"`
+	ProductPrice             float64 `json:"product_price" bson:"product_price" `
+	ProductExpiryDate        string  `json:"product_expiry_date" bson:"product_expiry_date"`
+	ProductManufacturingDate string  `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
+}
+
+// Column headers of the inventory spreadsheet template, shared by upload and download.
+// Exported rows carry their inventory product id, so uploading them again updates the same listings.
+const (
+	InventorySheetInventoryProductID = "inventory_product_id"
+	InventorySheetMetadataID         = "metadata_product_id"
+	InventorySheetMetadataName       = "metadata_name"
+	InventorySheetHSNCode            = "hsn_code"
+	InventorySheetMRP                = "metadata_mrp"
+	InventorySheetQuantity           = "product_quantity"
+	InventorySheetPrice              = "product_price"
+	InventorySheetExpiryDate         = "product_expiry_date"
+	InventorySheetManufacturingDate  = "product_manufacturing_date"
+)
+
+var InventorySheetColumns = []string{
+	InventorySheetInventoryProductID,
+	InventorySheetMetadataID,
+	InventorySheetMetadataName,
+	InventorySheetHSNCode,
+	InventorySheetMRP,
+	InventorySheetQuantity,
+	InventorySheetPrice,
+	InventorySheetExpiryDate,
+	InventorySheetManufacturingDate,
+}
+
+type InventorySheetRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	Message string `json:"message"`
+}
+
+type InventorySheetRow struct {
+	Row                      int     `json:"row"`
+	InventoryProductID       string  `json:"inventory_product_id,omitempty"`
+	MetadataProductID        string  `json:"metadata_product_id"`
+	MetadataName             string  `json:"metadata_name"`
+	MetadataHSNCode          string  `json:"hsn_code"`
+	MetadataMRP              float64 `json:"metadata_mrp"`
+	ProductQuantity          int     `json:"product_quantity"`
+	ProductPrice             float64 `json:"product_price"`
+	ProductExpiryDate        string  `json:"product_expiry_date"`
+	ProductManufacturingDate string  `json:"product_manufacturing_date"`
+	PendingApproval          bool    `json:"pending_approval,omitempty"`
+}
+
+type InventorySheetUploadResponse struct {
+	DryRun      bool                      `json:"dry_run"`
+	Committed   bool                      `json:"committed"`
+	TotalRows   int                       `json:"total_rows"`
+	ValidRows   int                       `json:"valid_rows"`
+	InvalidRows int                       `json:"invalid_rows"`
+	Rows        []*InventorySheetRow      `json:"rows"`
+	Errors      []*InventorySheetRowError `json:"errors"`
 }
 
 type GetAllInventoryRequestResponse struct {
@@ -126,6 +228,8 @@ type GetAllInventoryRequestResponse struct {
 	Price               float64   `bson:"price" json:"price"`
 	ExpiryDate          time.Time `bson:"expiry_date" json:"expiry_date"`
 	ManufacturingDate   time.Time `bson:"manufacturing_date" json:"manufacturing_date"`
+	ReviewStatus        string    `bson:"review_status" json:"review_status"`
+	ReviewReason        string    `bson:"review_reason" json:"review_reason"`
 }
 
 type PaginatedInventoryRequestResponse struct {
@@ -135,3 +239,109 @@ type PaginatedInventoryRequestResponse struct {
 	Offset           int64                             `json:"offset"`
 	TotalPages       int64                             `json:"total_pages"`
 }
+
+// Review statuses of an inventory product. Products without a status are treated as pending.
+const (
+	ReviewStatusPending          = "pending"
+	ReviewStatusApproved         = "approved"
+	ReviewStatusRejected         = "rejected"
+	ReviewStatusChangesRequested = "changes_requested"
+	ReviewStatusAll              = "all"
+)
+
+type InventoryReviewRequest struct {
+	InventoryProductID string `json:"inventory_product_id"`
+	Decision           string `json:"decision"`
+	Reason             string `json:"reason"`
+	ReviewerID         string `json:"reviewer_id" bson:"omitempty"`
+	ReviewerName       string `json:"reviewer_name" bson:"omitempty"`
+}
+
+// InventoryReviewDecision is one entry in the review history of an inventory product
+type InventoryReviewDecision struct {
+	ID                 string    `json:"id" bson:"_id,omitempty"`
+	InventoryProductID string    `json:"inventory_product_id" bson:"inventory_product_id"`
+	SellerID           string    `json:"seller_id" bson:"seller_id"`
+	Decision           string    `json:"decision" bson:"decision"`
+	PreviousStatus     string    `json:"previous_status" bson:"previous_status"`
+	Reason             string    `json:"reason" bson:"reason"`
+	ReviewerID         string    `json:"reviewer_id" bson:"reviewer_id"`
+	ReviewerName       string    `json:"reviewer_name" bson:"reviewer_name"`
+	CreatedAt          time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Bulk operation types for BulkInventoryOperation.Type
+const (
+	BulkOperationSetPrice           = "set_price"
+	BulkOperationAdjustPricePercent = "adjust_price_percent"
+	BulkOperationAdjustPriceAmount  = "adjust_price_amount"
+	BulkOperationSetQuantity        = "set_quantity"
+	BulkOperationAdjustQuantity     = "adjust_quantity"
+	BulkOperationSetVisibility      = "set_visibility"
+)
+
+// BulkInventoryRequest either lists explicit changes or applies one operation to every product matching a filter.
+// Visibility can only be turned off in bulk, products only go live through the review.
+type BulkInventoryRequest struct {
+	SellerID     string                  `json:"seller_id" bson:"omitempty"`
+	Changes      []*BulkInventoryChange  `json:"changes"`
+	Filter       *BulkInventoryFilter    `json:"filter"`
+	Operation    *BulkInventoryOperation `json:"operation"`
+	AllOrNothing bool                    `json:"all_or_nothing"`
+}
+
+type BulkInventoryChange struct {
+	InventoryProductID string   `json:"inventory_product_id"`
+	ProductPrice       *float64 `json:"product_price"`
+	ProductQuantity    *int     `json:"product_quantity"`
+	ProductVisibility  *bool    `json:"product_visibility"`
+}
+
+type BulkInventoryFilter struct {
+	CategoryID         string   `json:"category_id"`
+	SubcategoryID      string   `json:"subcategory_id"`
+	MetadataProductIDs []string `json:"metadata_product_ids"`
+	ProductVisibility  *bool    `json:"product_visibility"`
+	MinProductQuantity *int     `json:"min_product_quantity"`
+	MaxProductQuantity *int     `json:"max_product_quantity"`
+}
+
+type BulkInventoryOperation struct {
+	Type       string  `json:"type"`
+	Value      float64 `json:"value"`
+	Visibility bool    `json:"visibility"`
+}
+
+// BulkInventoryTarget is an inventory product selected for a bulk change, with the MRP its price is checked against
+type BulkInventoryTarget struct {
+	InventoryProductID string  `bson:"_id"`
+	MetadataProductID  string  `bson:"metadata_product_id"`
+	MetadataName       string  `bson:"metadata_name"`
+	MetadataMRP        float64 `bson:"metadata_mrp"`
+	ProductPrice       float64 `bson:"product_price"`
+	ProductQuantity    int     `bson:"product_quantity"`
+	ProductVisibility  bool    `bson:"product_visibility"`
+}
+
+type BulkInventoryItemResult struct {
+	InventoryProductID string  `json:"inventory_product_id"`
+	MetadataName       string  `json:"metadata_name"`
+	Success            bool    `json:"success"`
+	Error              string  `json:"error,omitempty"`
+	ProductPrice       float64 `json:"product_price"`
+	ProductQuantity    int     `json:"product_quantity"`
+	ProductVisibility  bool    `json:"product_visibility"`
+	PendingApproval    bool    `json:"pending_approval,omitempty"`
+	PreviousPrice      float64 `json:"-"`
+	PreviousQuantity   int     `json:"-"`
+	PreviousVisibility bool    `json:"-"`
+	MetadataProductID  string  `json:"-"`
+}
+
+type BulkInventoryResponse struct {
+	Matched   int                        `json:"matched"`
+	Updated   int                        `json:"updated"`
+	Failed    int                        `json:"failed"`
+	Committed bool                       `json:"committed"`
+	Results   []*BulkInventoryItemResult `json:"results"`
+}
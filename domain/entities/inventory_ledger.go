@@ -0,0 +1,40 @@
+package entities
+
+import "time"
+
+// Ledger entry types recorded against an inventory product whenever its quantity moves
+const (
+	LedgerTypeTransferOut    = "transfer_out"
+	LedgerTypeTransferIn     = "transfer_in"
+	LedgerTypeTransferReturn = "transfer_return"
+	LedgerTypeCycleCount     = "cycle_count_adjustment"
+	LedgerTypeGoodsReceipt   = "goods_receipt"
+	LedgerTypeSellerUpdate   = "seller_update"
+)
+
+// UnitCostBefore is set on goods receipts that changed the average unit cost of the product, holding the cost
+// the receipt replaced
+type InventoryLedgerEntry struct {
+	ID                 string    `json:"id" bson:"_id,omitempty"`
+	InventoryProductID string    `json:"inventory_product_id" bson:"inventory_product_id"`
+	InventoryID        string    `json:"inventory_id" bson:"inventory_id"`
+	StoreID            string    `json:"store_id" bson:"store_id"`
+	SellerID           string    `json:"seller_id" bson:"seller_id"`
+	MetadataProductID  string    `json:"metadata_product_id" bson:"metadata_product_id"`
+	Type               string    `json:"type" bson:"type"`
+	QuantityChange     int       `json:"quantity_change" bson:"quantity_change"`
+	QuantityAfter      int       `json:"quantity_after" bson:"quantity_after"`
+	ReferenceID        string    `json:"reference_id" bson:"reference_id"`
+	UnitCostBefore     *float64  `json:"unit_cost_before,omitempty" bson:"unit_cost_before,omitempty"`
+	Reason             string    `json:"reason" bson:"reason"`
+	CreatedBy          string    `json:"created_by" bson:"created_by"`
+	CreatedAt          time.Time `json:"created_at" bson:"created_at"`
+}
+
+type PaginatedInventoryLedgerResponse struct {
+	Entries    []*InventoryLedgerEntry `json:"entries"`
+	Total      int64                   `json:"total"`
+	Limit      int64                   `json:"limit"`
+	Offset     int64                   `json:"offset"`
+	TotalPages int64                   `json:"total_pages"`
+}
@@ -0,0 +1,93 @@
+package entities
+
+import "time"
+
+// Dimensions a valuation report can be grouped by
+const (
+	ValuationGroupStore     = "store"
+	ValuationGroupSeller    = "seller"
+	ValuationGroupCategory  = "category"
+	ValuationGroupWarehouse = "warehouse"
+)
+
+// InventorySnapshotLine is the stock of one inventory product at the end of SnapshotDate. Names are copied
+// so old reports read the same after a store or category is renamed. Stock is valued at its average unit cost
+// from goods receipts (CostValue), at the seller's selling price (PriceValue) and at MRP. Stock that never came
+// in through a receipt has no cost and is valued at zero cost.
+type InventorySnapshotLine struct {
+	ID                 string    `json:"id" bson:"_id,omitempty"`
+	SnapshotDate       string    `json:"snapshot_date" bson:"snapshot_date"`
+	InventoryProductID string    `json:"inventory_product_id" bson:"inventory_product_id"`
+	InventoryID        string    `json:"inventory_id" bson:"inventory_id"`
+	StoreID            string    `json:"store_id" bson:"store_id"`
+	StoreName          string    `json:"store_name" bson:"store_name"`
+	SellerID           string    `json:"seller_id" bson:"seller_id"`
+	SellerName         string    `json:"seller_name" bson:"seller_name"`
+	WarehouseID        string    `json:"warehouse_id" bson:"warehouse_id"`
+	WarehouseName      string    `json:"warehouse_name" bson:"warehouse_name"`
+	MetadataProductID  string    `json:"metadata_product_id" bson:"metadata_product_id"`
+	MetadataName       string    `json:"metadata_name" bson:"metadata_name"`
+	CategoryID         string    `json:"category_id" bson:"category_id"`
+	CategoryName       string    `json:"category_name" bson:"category_name"`
+	Quantity           int       `json:"quantity" bson:"quantity"`
+	Price              float64   `json:"price" bson:"price"`
+	MRP                float64   `json:"mrp" bson:"mrp"`
+	UnitCost           float64   `json:"unit_cost" bson:"unit_cost"`
+	CostValue          float64   `json:"cost_value" bson:"cost_value"`
+	PriceValue         float64   `json:"price_value" bson:"price_value"`
+	MRPValue           float64   `json:"mrp_value" bson:"mrp_value"`
+	TakenAt            time.Time `json:"taken_at" bson:"taken_at"`
+}
+
+type InventorySnapshotSummary struct {
+	SnapshotDate string    `json:"snapshot_date" bson:"_id"`
+	Products     int64     `json:"products" bson:"products"`
+	Quantity     int64     `json:"quantity" bson:"quantity"`
+	CostValue    float64   `json:"cost_value" bson:"cost_value"`
+	PriceValue   float64   `json:"price_value" bson:"price_value"`
+	MRPValue     float64   `json:"mrp_value" bson:"mrp_value"`
+	TakenAt      time.Time `json:"taken_at" bson:"taken_at"`
+}
+
+type InventoryValuationRequest struct {
+	Date          string
+	CompareDate   string
+	GroupBy       string
+	WarehouseID   string
+	StoreID       string
+	SellerID      string
+	CategoryID    string
+	OperationalID string
+}
+
+// InventoryValuationGroup is the stock of one store, seller, category or warehouse on a snapshot date
+type InventoryValuationGroup struct {
+	GroupID    string  `json:"group_id" bson:"_id"`
+	GroupName  string  `json:"group_name" bson:"group_name"`
+	Products   int64   `json:"products" bson:"products"`
+	Quantity   int64   `json:"quantity" bson:"quantity"`
+	CostValue  float64 `json:"cost_value" bson:"cost_value"`
+	PriceValue float64 `json:"price_value" bson:"price_value"`
+	MRPValue   float64 `json:"mrp_value" bson:"mrp_value"`
+}
+
+// InventoryValuationRow holds a group on the report date and, when comparing, on the compare date with the change
+// between the two
+type InventoryValuationRow struct {
+	GroupID          string                   `json:"group_id"`
+	GroupName        string                   `json:"group_name"`
+	Current          *InventoryValuationGroup `json:"current"`
+	Compare          *InventoryValuationGroup `json:"compare,omitempty"`
+	QuantityChange   int64                    `json:"quantity_change"`
+	CostValueChange  float64                  `json:"cost_value_change"`
+	PriceValueChange float64                  `json:"price_value_change"`
+	MRPValueChange   float64                  `json:"mrp_value_change"`
+}
+
+type InventoryValuationReport struct {
+	Date        string                   `json:"date"`
+	CompareDate string                   `json:"compare_date,omitempty"`
+	GroupBy     string                   `json:"group_by"`
+	Rows        []*InventoryValuationRow `json:"rows"`
+	Totals      *InventoryValuationRow   `json:"totals"`
+}
@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+// Owners an uploaded image can belong to
+const (
+	MediaOwnerMetadata    = "metadata"
+	MediaOwnerCategory    = "category"
+	MediaOwnerSubcategory = "subcategory"
+	MediaOwnerStore       = "store"
+)
+
+// Sizes every image is stored in. Web and thumbnail fit within the given number of pixels on their longest side.
+const (
+	MediaSizeOriginal  = "original"
+	MediaSizeWeb       = "web"
+	MediaSizeThumbnail = "thumbnail"
+
+	MediaWebMaxSide       = 1200
+	MediaThumbnailMaxSide = 240
+)
+
+type MediaRendition struct {
+	Key         string `json:"-" bson:"key"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Width       int    `json:"width" bson:"width"`
+	Height      int    `json:"height" bson:"height"`
+	Bytes       int    `json:"bytes" bson:"bytes"`
+}
+
+// Media is an uploaded image. URL serves the web size, and ?size= selects the others.
+type Media struct {
+	ID           string                     `json:"id" bson:"_id,omitempty"`
+	OwnerType    string                     `json:"owner_type" bson:"owner_type"`
+	OwnerID      string                     `json:"owner_id" bson:"owner_id"`
+	FileName     string                     `json:"file_name" bson:"file_name"`
+	URL          string                     `json:"url" bson:"url"`
+	ThumbnailURL string                     `json:"thumbnail_url" bson:"thumbnail_url"`
+	Renditions   map[string]*MediaRendition `json:"renditions" bson:"renditions"`
+	UploadedBy   string                     `json:"uploaded_by" bson:"uploaded_by"`
+	CreatedAt    time.Time                  `json:"created_at" bson:"created_at"`
+}
+
+// UploadMediaRequest is an image upload for an owner. Primary makes it the owner's main image; the first image of
+// a metadata product always becomes its main image.
+type UploadMediaRequest struct {
+	OwnerType    string
+	OwnerID      string
+	Primary      bool
+	FileName     string
+	Data         []byte
+	UploadedBy   string
+	UploaderRole string
+}
@@ -5,39 +5,57 @@ import (
 )
 
 type Metadata struct {
-	MetadataProductID     string    `json:"product_id" bson:"_id,omitempty"`
-	MetadataHSNCode       string    `json:"hsn_code" bson:"hsn_code"`
-	MetadataName          string    `json:"name" bson:"metadata_name"`
-	MetadataDescription   string    `json:"description" bson:"metadata_description"`
-	MetadataImage         string    `json:"image" bson:"metadata_image"`
-	MetadataCategoryID    string    `json:"category_id" bson:"metadata_category_id"`
-	MetadataSubcategoryID string    `json:"subcategory_id" bson:"metadata_subcategory_id"`
-	MetadataMRP           float64   `json:"mrp" bson:"metadata_mrp"`
-	MetadataCreatedAt     time.Time `json:"created_at" bson:"metadata_created_at"`
-	MetadataUpdatedAt     time.Time `json:"updated_at" bson:"metadata_updated_at"`
+	MetadataProductID     string                 `json:"product_id" bson:"_id,omitempty"`
+	MetadataHSNCode       string                 `json:"hsn_code" bson:"hsn_code"`
+	MetadataName          string                 `json:"name" bson:"metadata_name"`
+	MetadataDescription   string                 `json:"description" bson:"metadata_description"`
+	MetadataImage         string                 `json:"image" bson:"metadata_image"`
+	MetadataCategoryID    string                 `json:"category_id" bson:"metadata_category_id"`
+	MetadataSubcategoryID string                 `json:"subcategory_id" bson:"metadata_subcategory_id"`
+	MetadataMRP           float64                `json:"mrp" bson:"metadata_mrp"`
+	MetadataCreatedAt     time.Time              `json:"created_at" bson:"metadata_created_at"`
+	MetadataUpdatedAt     time.Time              `json:"updated_at" bson:"metadata_updated_at"`
+	MetadataGallery       []string               `json:"gallery,omitempty" bson:"metadata_gallery,omitempty"`
+	VariantGroupID        string                 `json:"variant_group_id,omitempty" bson:"variant_group_id,omitempty"`
+	VariantAttributes     map[string]string      `json:"variant_attributes,omitempty" bson:"variant_attributes,omitempty"`
+	MetadataAttributes    map[string]interface{} `json:"attributes,omitempty" bson:"metadata_attributes,omitempty"`
+	MetadataRevision      int                    `json:"revision" bson:"metadata_revision"`
+	MetadataUpdatedBy     string                 `json:"updated_by,omitempty" bson:"metadata_updated_by,omitempty"`
+	ArchivedAt            *time.Time             `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	ArchivedBy            string                 `json:"archived_by,omitempty" bson:"archived_by,omitempty"`
 }
 
+// Review holds the star totals of a metadata product. The baseline is what was counted before reviews were stored
+// one by one, and the totals are the baseline plus the approved reviews.
 type Review struct {
 	MetadataProductID string `json:"metadata_product_id" bson:"_id"`
 	TotalStars        int    `json:"total_stars" bson:"total_stars"`
 	TotalReviews      int    `json:"total_reviews" bson:"total_reviews"`
+	BaselineStars     int    `json:"-" bson:"baseline_stars"`
+	BaselineReviews   int    `json:"-" bson:"baseline_reviews"`
 }
 
 type MetadataResponse struct {
-	ID              string  `json:"id" bson:"_id,omitempty"`
-	HsnCode         string  `json:"hsn_code"`
-	Name            string  `json:"name"`
-	Description     string  `json:"description"`
-	Image           string  `json:"image"`
-	CategoryID      string  `json:"category_id"`
-	SubcategoryID   string  `json:"subcategory_id"`
-	MRP             float64 `json:"mrp"`
-	CategoryName    string  `json:"category_name"`
-	SubCategoryName string  `json:"subcategory_name"`
-	CreatedAt       string  `json:"created_at"`
-	UpdatedAt       string  `json:"updated_at"`
-	TotalStars      int     `json:"total_stars"`
-	TotalReviews    int     `json:"total_reviews"`
+	ID              string                 `json:"id" bson:"_id,omitempty"`
+	HsnCode         string                 `json:"hsn_code"`
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description"`
+	Image           string                 `json:"image"`
+	Gallery         []string               `json:"gallery,omitempty"`
+	Attributes      map[string]interface{} `json:"attributes,omitempty"`
+	CategoryID      string                 `json:"category_id"`
+	SubcategoryID   string                 `json:"subcategory_id"`
+	MRP             float64                `json:"mrp"`
+	CategoryName    string                 `json:"category_name"`
+	SubCategoryName string                 `json:"subcategory_name"`
+	CreatedAt       string                 `json:"created_at"`
+	UpdatedAt       string                 `json:"updated_at"`
+	TotalStars      int                    `json:"total_stars"`
+	TotalReviews    int                    `json:"total_reviews"`
+	GSTRate         *float64               `json:"gst_rate"`
+	Barcodes        []string               `json:"barcodes"`
+	Revision        int                    `json:"revision"`
+	ArchivedAt      *time.Time             `json:"archived_at,omitempty"`
 }
 
 type MetadataApiResponse struct {
@@ -50,24 +68,28 @@ type MetadataApiResponse struct {
 
 // CreateMetadataRequest represents the request structure for creating metadata
 type CreateMetadataRequest struct {
-	Name          string  `json:"name" binding:"required"`
-	HsnCode       string  `json:"hsn_code" binding:"required"`
-	Description   string  `json:"description" binding:"required"`
-	Image         string  `json:"image"`
-	CategoryID    string  `json:"category_id"  binding:"required"`
-	SubcategoryID string  `json:"subcategory_id"  binding:"required"`
-	MRP           float64 `json:"mrp" binding:"required"`
+	Name          string                 `json:"name" binding:"required"`
+	HsnCode       string                 `json:"hsn_code" binding:"required"`
+	Description   string                 `json:"description" binding:"required"`
+	Image         string                 `json:"image"`
+	CategoryID    string                 `json:"category_id"  binding:"required"`
+	SubcategoryID string                 `json:"subcategory_id"  binding:"required"`
+	MRP           float64                `json:"mrp" binding:"required"`
+	Attributes    map[string]interface{} `json:"attributes"`
+	UpdatedBy     string                 `json:"updated_by" bson:"omitempty"`
 }
 
 // UpdateMetadataRequest represents the request structure for updating metadata
 type UpdateMetadataRequest struct {
-	Name          string  `json:"name" binding:"required"`
-	Description   string  `json:"description" binding:"required"`
-	Image         string  `json:"image"`
-	CategoryID    string  `json:"category_id" binding:"required"`
-	SubcategoryID string  `json:"subcategory_id" binding:"required"`
-	MRP           float64 `json:"mrp" binding:"required"`
-	HsnCode       string  `json:"hsn_code"`
+	Name          string                 `json:"name" binding:"required"`
+	Description   string                 `json:"description" binding:"required"`
+	Image         string                 `json:"image"`
+	CategoryID    string                 `json:"category_id" binding:"required"`
+	SubcategoryID string                 `json:"subcategory_id" binding:"required"`
+	MRP           float64                `json:"mrp" binding:"required"`
+	HsnCode       string                 `json:"hsn_code"`
+	Attributes    map[string]interface{} `json:"attributes"`
+	UpdatedBy     string                 `json:"updated_by" bson:"omitempty"`
 }
 
 // PaginatedMetadataResponse represents paginated metadata response
@@ -82,6 +104,7 @@ type PaginatedMetadataResponse struct {
 type AddReviewRequest struct {
 	MetadataProductID string `json:"metadata_product_id" binding:"required"`
 	Rating            int    `json:"rating" binding:"required"`
+	UserID            string `json:"user_id" bson:"omitempty"`
 }
 
 type GetAllMetadata struct {
@@ -97,6 +120,7 @@ type GetAllMetadata struct {
 	UpdatedAt       time.Time `json:"updated_at" bson:"updated_at"`
 	CategoryName    string    `json:"category_name" bson:"category_name"`
 	SubCategoryName string    `json:"subcategory_name" bson:"subcategory_name"`
+	GSTRate         *float64  `json:"gst_rate" bson:"-"`
 }
 
 type GetMetadataForSubcategoriesRequest struct {
@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// Column headers of the metadata import sheet. Attribute columns are named attr.<key> after the subcategory schema.
+const (
+	MetadataSheetName          = "name"
+	MetadataSheetHSNCode       = "hsn_code"
+	MetadataSheetDescription   = "description"
+	MetadataSheetCategory      = "category"
+	MetadataSheetSubcategory   = "subcategory"
+	MetadataSheetMRP           = "mrp"
+	MetadataSheetImage         = "image"
+	MetadataSheetAttributePref = "attr."
+)
+
+// Import modes. Create only adds new HSN codes, upsert also updates the metadata already holding an HSN code.
+const (
+	MetadataImportCreate = "create"
+	MetadataImportUpsert = "upsert"
+)
+
+// Lifecycle of an import job run in the background
+const (
+	MetadataImportQueued    = "queued"
+	MetadataImportRunning   = "running"
+	MetadataImportCompleted = "completed"
+	MetadataImportFailed    = "failed"
+)
+
+type MetadataImportRowError struct {
+	Row     int    `json:"row" bson:"row"`
+	Column  string `json:"column" bson:"column"`
+	HsnCode string `json:"hsn_code" bson:"hsn_code"`
+	Name    string `json:"name" bson:"name"`
+	Message string `json:"message" bson:"message"`
+}
+
+// MetadataImportJob tracks a bulk metadata import, its progress and the rows it could not import
+type MetadataImportJob struct {
+	ID            string                    `json:"id" bson:"_id,omitempty"`
+	FileName      string                    `json:"file_name" bson:"file_name"`
+	Mode          string                    `json:"mode" bson:"mode"`
+	Status        string                    `json:"status" bson:"status"`
+	TotalRows     int                       `json:"total_rows" bson:"total_rows"`
+	ProcessedRows int                       `json:"processed_rows" bson:"processed_rows"`
+	CreatedCount  int                       `json:"created_count" bson:"created_count"`
+	UpdatedCount  int                       `json:"updated_count" bson:"updated_count"`
+	FailedCount   int                       `json:"failed_count" bson:"failed_count"`
+	Errors        []*MetadataImportRowError `json:"errors" bson:"errors"`
+	Failure       string                    `json:"failure,omitempty" bson:"failure,omitempty"`
+	CreatedBy     string                    `json:"created_by" bson:"created_by"`
+	CreatedAt     time.Time                 `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at" bson:"updated_at"`
+	StartedAt     *time.Time                `json:"started_at,omitempty" bson:"started_at,omitempty"`
+	FinishedAt    *time.Time                `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
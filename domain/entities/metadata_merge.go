@@ -0,0 +1,56 @@
+package entities
+
+import "time"
+
+// DuplicateCandidate is a pair of metadata that look like the same product. Score runs from 0 to 1 and Reasons
+// says which of name, HSN code and MRP matched.
+type DuplicateCandidate struct {
+	Left    *Metadata `json:"left"`
+	Right   *Metadata `json:"right"`
+	Score   float64   `json:"score"`
+	Reasons []string  `json:"reasons"`
+}
+
+type FindDuplicatesRequest struct {
+	SubcategoryID string
+	MinScore      float64
+	Limit         int
+}
+
+type DismissDuplicateRequest struct {
+	LeftID      string `json:"left_id" binding:"required"`
+	RightID     string `json:"right_id" binding:"required"`
+	DismissedBy string `json:"dismissed_by" bson:"omitempty"`
+}
+
+// DismissedDuplicate is a pair an admin marked as different products, stored with LeftID < RightID so the
+// detector stops suggesting it
+type DismissedDuplicate struct {
+	LeftID      string    `json:"left_id" bson:"left_id"`
+	RightID     string    `json:"right_id" bson:"right_id"`
+	DismissedBy string    `json:"dismissed_by" bson:"dismissed_by"`
+	DismissedAt time.Time `json:"dismissed_at" bson:"dismissed_at"`
+}
+
+type MergeMetadataRequest struct {
+	SurvivorID string `json:"survivor_id" binding:"required"`
+	MergedID   string `json:"merged_id" binding:"required"`
+	Reason     string `json:"reason"`
+	MergedBy   string `json:"merged_by" bson:"omitempty"`
+}
+
+// MetadataMerge is the audit of a merge. Merged is the removed metadata as it was, Repointed counts the documents
+// of each collection moved over to the survivor and DroppedReviews the reviews removed because the same customer
+// had reviewed both.
+type MetadataMerge struct {
+	ID             string           `json:"id" bson:"_id,omitempty"`
+	SurvivorID     string           `json:"survivor_id" bson:"survivor_id"`
+	MergedID       string           `json:"merged_id" bson:"merged_id"`
+	Survivor       *Metadata        `json:"survivor" bson:"survivor"`
+	Merged         *Metadata        `json:"merged" bson:"merged"`
+	Repointed      map[string]int64 `json:"repointed" bson:"repointed"`
+	DroppedReviews []*ProductReview `json:"dropped_reviews" bson:"dropped_reviews"`
+	Reason         string           `json:"reason" bson:"reason"`
+	MergedBy       string           `json:"merged_by" bson:"merged_by"`
+	CreatedAt      time.Time        `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,47 @@
+package entities
+
+import "time"
+
+// What produced a metadata revision
+const (
+	MetadataRevisionBaseline = "baseline"
+	MetadataRevisionCreate   = "create"
+	MetadataRevisionUpdate   = "update"
+	MetadataRevisionProposal = "proposal"
+	MetadataRevisionMerge    = "merge"
+	MetadataRevisionRollback = "rollback"
+	MetadataRevisionMedia    = "media"
+)
+
+type MetadataFieldChange struct {
+	Field    string      `json:"field" bson:"field"`
+	OldValue interface{} `json:"old_value" bson:"old_value"`
+	NewValue interface{} `json:"new_value" bson:"new_value"`
+}
+
+// MetadataRevision is the state of a metadata after an edit, with the fields the edit changed. Metadata edited
+// before revisions were kept gets a baseline revision 0 holding its state before the first tracked edit.
+type MetadataRevision struct {
+	ID                string                 `json:"id" bson:"_id,omitempty"`
+	MetadataProductID string                 `json:"metadata_product_id" bson:"metadata_product_id"`
+	Revision          int                    `json:"revision" bson:"revision"`
+	Action            string                 `json:"action" bson:"action"`
+	Changes           []*MetadataFieldChange `json:"changes" bson:"changes"`
+	Snapshot          *Metadata              `json:"snapshot" bson:"snapshot"`
+	RolledBackTo      *int                   `json:"rolled_back_to,omitempty" bson:"rolled_back_to,omitempty"`
+	EditedBy          string                 `json:"edited_by" bson:"edited_by"`
+	EditedAt          time.Time              `json:"edited_at" bson:"edited_at"`
+}
+
+type RollbackMetadataRequest struct {
+	Revision *int   `json:"revision" binding:"required"`
+	EditedBy string `json:"edited_by" bson:"omitempty"`
+}
+
+type PaginatedMetadataRevisions struct {
+	Revisions  []*MetadataRevision `json:"revisions"`
+	Total      int64               `json:"total"`
+	Limit      int64               `json:"limit"`
+	Offset     int64               `json:"offset"`
+	TotalPages int64               `json:"total_pages"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// Notification types
+const (
+	NotificationTypeInventoryReview = "inventory_review"
+	NotificationTypePriceChange     = "price_change"
+	NotificationTypeGoodsReceipt    = "goods_receipt"
+	NotificationTypeCatalogProposal = "catalog_proposal"
+)
+
+type Notification struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	UserID      string    `json:"user_id" bson:"user_id"`
+	Type        string    `json:"type" bson:"type"`
+	Title       string    `json:"title" bson:"title"`
+	Message     string    `json:"message" bson:"message"`
+	ReferenceID string    `json:"reference_id" bson:"reference_id"`
+	Read        bool      `json:"read" bson:"read"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+}
+
+type PaginatedNotificationResponse struct {
+	Notifications []*Notification `json:"notifications"`
+	Total         int64           `json:"total"`
+	Unread        int64           `json:"unread"`
+	Limit         int64           `json:"limit"`
+	Offset        int64           `json:"offset"`
+	TotalPages    int64           `json:"total_pages"`
+}
@@ -8,17 +8,29 @@ type Orders struct {
 	UserID      string    `json:"user_id" bson:"user_id"`
 	WarehouseID string    `json:"warehouse_id" bson:"warehouse_id"`
 	Address     string    `json:"address"   bson:"address"`
-	OrderTotal  int       `json:"order_total"  bson:"order_total"`
+	OrderTotal  float64   `json:"order_total"  bson:"order_total"`
+	TaxTotal    float64   `json:"tax_total"  bson:"tax_total"`
 	OrderedAt   time.Time `json:"ordered_at"  bson:"ordered_at"`
 }
 
+// GSTRate is the rate in force when the order was placed and TaxAmount the GST included in the line total.
+// TaxPending marks a line sold while its HSN code had no rate in force, its tax is to be settled later.
+// MetadataRevision is the revision of the product's metadata the customer saw when ordering; once that metadata
+// is merged into another, MergedFromMetadataID keeps the id the revision belongs to.
 type OrderedItems struct {
-	OrderID   string `json:"order_id" bson:"order_id"`
-	ProductID string `json:"product_id"  bson:"product_id"`
-	Quantity  int    `json:"quantity"  bson:"quantity"`
-	Price     int    `json:"price"  bson:"price"`
-	MRP       int    `json:"mrp"  bson:"mrp"`
-	SellerID  string `json:"seller_id"  bson:"seller_id"`
+	OrderID              string  `json:"order_id" bson:"order_id"`
+	ProductID            string  `json:"product_id"  bson:"product_id"`
+	Quantity             int     `json:"quantity"  bson:"quantity"`
+	Price                float64 `json:"price"  bson:"price"`
+	MRP                  float64 `json:"mrp"  bson:"mrp"`
+	SellerID             string  `json:"seller_id"  bson:"seller_id"`
+	HsnCode              string  `json:"hsn_code"  bson:"hsn_code"`
+	GSTRate              float64 `json:"gst_rate"  bson:"gst_rate"`
+	TaxAmount            float64 `json:"tax_amount"  bson:"tax_amount"`
+	TaxPending           bool    `json:"tax_pending,omitempty"  bson:"tax_pending,omitempty"`
+	MetadataProductID    string  `json:"metadata_product_id"  bson:"metadata_product_id"`
+	MetadataRevision     int     `json:"metadata_revision"  bson:"metadata_revision"`
+	MergedFromMetadataID string  `json:"merged_from_metadata_id,omitempty"  bson:"merged_from_metadata_id,omitempty"`
 }
 
 // requests and respone types
@@ -33,7 +45,8 @@ type GetAllOrdersReturn struct {
 	UserID      string          `json:"user_id"`
 	WarehouseID string          `json:"warehouse_id"`
 	Address     string          `json:"address"`
-	OrderTotal  int             `json:"order_total"`
+	OrderTotal  float64         `json:"order_total"`
+	TaxTotal    float64         `json:"tax_total"`
 	OrderedAt   time.Time       `json:"ordered_at"`
 	Products    []*OrderedItems `json:"products"`
 }
@@ -48,15 +61,22 @@ type GetAllOrderPaginated struct {
 }
 
 type CreateOrderRequest struct {
-	UserID      string `json:"user_id"`
-	WarehouseID string `json:"warehouse_id"`
-	Address     string `json:"address"`
-	OrderTotal  int    `json:"order_total"`
+	UserID      string  `json:"user_id"`
+	WarehouseID string  `json:"warehouse_id"`
+	Address     string  `json:"address"`
+	OrderTotal  float64 `json:"order_total"`
+	TaxTotal    float64 `json:"tax_total"`
 	Products    []*struct {
-		ProductID string `json:"product_id"  bson:"product_id"`
-		Quantity  int    `json:"quantity"  bson:"quantity"`
-		Price     int    `json:"price"  bson:"price"`
-		MRP       int    `json:"mrp"  bson:"mrp"`
-		SellerID  string `json:"seller_id"  bson:"seller_id"`
+		ProductID         string  `json:"product_id"  bson:"product_id"`
+		Quantity          int     `json:"quantity"  bson:"quantity"`
+		Price             float64 `json:"price"  bson:"price"`
+		MRP               float64 `json:"mrp"  bson:"mrp"`
+		SellerID          string  `json:"seller_id"  bson:"seller_id"`
+		HsnCode           string  `json:"hsn_code"  bson:"hsn_code"`
+		GSTRate           float64 `json:"gst_rate"  bson:"gst_rate"`
+		TaxAmount         float64 `json:"tax_amount"  bson:"tax_amount"`
+		TaxPending        bool    `json:"tax_pending,omitempty"  bson:"tax_pending,omitempty"`
+		MetadataProductID string  `json:"metadata_product_id"  bson:"metadata_product_id"`
+		MetadataRevision  int     `json:"metadata_revision"  bson:"metadata_revision"`
 	} `json:"products"`
 }
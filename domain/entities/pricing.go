@@ -0,0 +1,161 @@
+package entities
+
+import "time"
+
+// What happens to a price change that breaks a guardrail
+const (
+	PriceViolationReject = "reject"
+	PriceViolationReview = "review"
+)
+
+// Where a price change came from
+const (
+	PriceSourceUpdate     = "update"
+	PriceSourceBulkUpdate = "bulk_update"
+	PriceSourceReview     = "price_review"
+	PriceSourceSheet      = "sheet_upload"
+	PriceSourceReceipt    = "goods_receipt"
+	PriceSourceTransfer   = "stock_transfer"
+)
+
+// Price change request states
+const (
+	PriceChangePending    = "pending"
+	PriceChangeApproved   = "approved"
+	PriceChangeRejected   = "rejected"
+	PriceChangeSuperseded = "superseded"
+)
+
+// Scheduled price states, derived from the time window when listed
+const (
+	ScheduledPriceUpcoming  = "upcoming"
+	ScheduledPriceActive    = "active"
+	ScheduledPriceEnded     = "ended"
+	ScheduledPriceCancelled = "cancelled"
+)
+
+// PricingGuardrail limits seller price changes. The guardrail with an empty SubcategoryID is the default
+// for subcategories without their own. Zero limits are not enforced.
+type PricingGuardrail struct {
+	ID                    string    `json:"id" bson:"_id,omitempty"`
+	SubcategoryID         string    `json:"subcategory_id" bson:"subcategory_id"`
+	MaxDailyChangePercent float64   `json:"max_daily_change_percent" bson:"max_daily_change_percent"`
+	MinPricePercentOfMRP  float64   `json:"min_price_percent_of_mrp" bson:"min_price_percent_of_mrp"`
+	ViolationAction       string    `json:"violation_action" bson:"violation_action"`
+	UpdatedBy             string    `json:"updated_by" bson:"updated_by"`
+	UpdatedAt             time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+type PriceHistoryEntry struct {
+	ID                 string    `json:"id" bson:"_id,omitempty"`
+	InventoryProductID string    `json:"inventory_product_id" bson:"inventory_product_id"`
+	SellerID           string    `json:"seller_id" bson:"seller_id"`
+	OldPrice           float64   `json:"old_price" bson:"old_price"`
+	NewPrice           float64   `json:"new_price" bson:"new_price"`
+	Source             string    `json:"source" bson:"source"`
+	ReferenceID        string    `json:"reference_id,omitempty" bson:"reference_id,omitempty"`
+	ChangedBy          string    `json:"changed_by" bson:"changed_by"`
+	ChangedAt          time.Time `json:"changed_at" bson:"changed_at"`
+}
+
+// PricingContext is what a price change of an inventory product is checked against
+type PricingContext struct {
+	InventoryProductID string            `bson:"inventory_product_id"`
+	SellerID           string            `bson:"seller_id"`
+	WarehouseID        string            `bson:"warehouse_id"`
+	MetadataProductID  string            `bson:"metadata_product_id"`
+	MetadataName       string            `bson:"metadata_name"`
+	SubcategoryID      string            `bson:"subcategory_id"`
+	MetadataMRP        float64           `bson:"metadata_mrp"`
+	CurrentPrice       float64           `bson:"current_price"`
+	DayStartPrice      float64           `bson:"-"`
+	Guardrail          *PricingGuardrail `bson:"-"`
+}
+
+// PriceChangeRequest holds a price change that broke a guardrail until ops approve or reject it
+type PriceChangeRequest struct {
+	ID                 string     `json:"id" bson:"_id,omitempty"`
+	InventoryProductID string     `json:"inventory_product_id" bson:"inventory_product_id"`
+	SellerID           string     `json:"seller_id" bson:"seller_id"`
+	WarehouseID        string     `json:"warehouse_id" bson:"warehouse_id"`
+	MetadataName       string     `json:"metadata_name" bson:"metadata_name"`
+	MetadataMRP        float64    `json:"metadata_mrp" bson:"metadata_mrp"`
+	CurrentPrice       float64    `json:"current_price" bson:"current_price"`
+	RequestedPrice     float64    `json:"requested_price" bson:"requested_price"`
+	Violations         []string   `json:"violations" bson:"violations"`
+	Status             string     `json:"status" bson:"status"`
+	RequestedBy        string     `json:"requested_by" bson:"requested_by"`
+	RequestedAt        time.Time  `json:"requested_at" bson:"requested_at"`
+	ReviewedBy         string     `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewReason       string     `json:"review_reason,omitempty" bson:"review_reason,omitempty"`
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+}
+
+type ReviewPriceChangeRequest struct {
+	Approve    bool   `json:"approve"`
+	Reason     string `json:"reason"`
+	RequestID  string `json:"request_id" bson:"omitempty"`
+	ReviewerID string `json:"reviewer_id" bson:"omitempty"`
+}
+
+type GetPriceChangeRequestsRequest struct {
+	OperationalID string
+	SellerID      string
+	Status        string
+	Offset        int64
+	Limit         int64
+}
+
+type PaginatedPriceChangeRequestResponse struct {
+	Requests   []*PriceChangeRequest `json:"requests"`
+	Total      int64                 `json:"total"`
+	Limit      int64                 `json:"limit"`
+	Offset     int64                 `json:"offset"`
+	TotalPages int64                 `json:"total_pages"`
+}
+
+type PaginatedPriceHistoryResponse struct {
+	History    []*PriceHistoryEntry `json:"history"`
+	Total      int64                `json:"total"`
+	Limit      int64                `json:"limit"`
+	Offset     int64                `json:"offset"`
+	TotalPages int64                `json:"total_pages"`
+}
+
+// ScheduledPrice overrides the price of an inventory product between StartsAt and EndsAt. The product price
+// itself is left untouched, so it applies again as soon as the window closes.
+type ScheduledPrice struct {
+	ID                 string     `json:"id" bson:"_id,omitempty"`
+	InventoryProductID string     `json:"inventory_product_id" bson:"inventory_product_id"`
+	SellerID           string     `json:"seller_id" bson:"seller_id"`
+	Price              float64    `json:"price" bson:"price"`
+	Label              string     `json:"label,omitempty" bson:"label,omitempty"`
+	StartsAt           time.Time  `json:"starts_at" bson:"starts_at"`
+	EndsAt             time.Time  `json:"ends_at" bson:"ends_at"`
+	Status             string     `json:"status" bson:"-"`
+	CreatedBy          string     `json:"created_by" bson:"created_by"`
+	CreatedAt          time.Time  `json:"created_at" bson:"created_at"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+}
+
+type CreateScheduledPriceRequest struct {
+	InventoryProductID string    `json:"inventory_product_id" binding:"required"`
+	Price              float64   `json:"price" binding:"required"`
+	Label              string    `json:"label"`
+	StartsAt           time.Time `json:"starts_at" binding:"required"`
+	EndsAt             time.Time `json:"ends_at" binding:"required"`
+	SellerID           string    `json:"seller_id" bson:"omitempty"`
+}
+
+// EffectiveProductPrice is the price of an inventory product right now, with the regular price as WasPrice
+// while a scheduled price runs
+type EffectiveProductPrice struct {
+	InventoryProductID string   `bson:"inventory_product_id"`
+	SellerID           string   `bson:"seller_id"`
+	Price              float64  `bson:"price"`
+	WasPrice           *float64 `bson:"was_price"`
+	MRP                float64  `bson:"mrp"`
+	HsnCode            string   `bson:"hsn_code"`
+	MetadataProductID  string   `bson:"metadata_product_id"`
+	MetadataRevision   int      `bson:"metadata_revision"`
+}
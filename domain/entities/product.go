@@ -7,24 +7,29 @@ type GetProductsForSpecificStoreRequest struct {
 }
 
 type GetProductsForSpecificStoreResponse struct {
-	InventoryId              string    `json:"inventory_id"`
-	InventoryProductId       string    `json:"inventory_product_id"`
-	MetadataProductId        string    `json:"metadata_product_id"`
-	ProductVisibility        bool      `json:"visibility"`
-	ProductPrice             float64   `json:"price"`
-	MetadataName             string    `json:"name"`
-	MetadataDescription      string    `json:"description"`
-	MetadataImage            string    `json:"image"`
-	MetadataCategoryId       string    `json:"category_id"`
-	MetadataSubcategoryId    string    `json:"subcategory_id"`
-	MetadataMrp              float64   `json:"mrp"`
-	ProductQuantity          int       `json:"quantity"`
-	ProductExpiryDate        time.Time `json:"expiry_date"`
-	ProductManufacturingDate time.Time `json:"manufacturing_date"`
-	ProductCategoryName      string    `json:"category_name"`
-	ProductSubCategoryName   string    `json:"subcategory_name"`
-	TotalStars               string    `json:"TotalStars"`
-	TotalReviews             string    `json:"TotalReviews"`
+	InventoryId              string     `json:"inventory_id"`
+	InventoryProductId       string     `json:"inventory_product_id"`
+	MetadataProductId        string     `json:"metadata_product_id"`
+	ProductVisibility        bool       `json:"visibility"`
+	ProductPrice             float64    `json:"price"`
+	MetadataName             string     `json:"name"`
+	MetadataDescription      string     `json:"description"`
+	MetadataImage            string     `json:"image"`
+	MetadataCategoryId       string     `json:"category_id"`
+	MetadataSubcategoryId    string     `json:"subcategory_id"`
+	MetadataMrp              float64    `json:"mrp"`
+	ProductQuantity          int        `json:"quantity"`
+	ProductExpiryDate        time.Time  `json:"expiry_date"`
+	ProductManufacturingDate time.Time  `json:"manufacturing_date"`
+	ProductCategoryName      string     `json:"category_name"`
+	ProductSubCategoryName   string     `json:"subcategory_name"`
+	TotalStars               string     `json:"TotalStars"`
+	TotalReviews             string     `json:"TotalReviews"`
+	WasPrice                 *float64   `json:"was_price,omitempty"`
+	SaleEndsAt               *time.Time `json:"sale_ends_at,omitempty"`
+	SaleLabel                string     `json:"sale_label,omitempty"`
+	HsnCode                  string     `json:"hsn_code"`
+	GSTRate                  *float64   `json:"gst_rate"`
 }
 
 type GetProductsForAllStoresRequest struct {
@@ -39,25 +44,36 @@ type GetProductsForAllStoresResponse struct {
 }
 
 type GetProductsForStoreSubcategory struct {
-	MetadataProductId        string    `json:"metadata_product_id"`
-	MetadataName             string    `json:"name"`
-	MetadataDescription      string    `json:"description"`
-	MetadataImage            string    `json:"image"`
-	MetadataCategoryId       string    `json:"category_id"`
-	MetadataSubcategoryId    string    `json:"subcategory_id"`
-	MetadataMrp              float64   `json:"mrp"`
-	ProductCategoryName      string    `json:"category_name"`
-	ProductSubCategoryName   string    `json:"subcategory_name"`
-	TotalStars               int       `json:"TotalStars"`
-	TotalReviews             int       `json:"TotalReviews"`
-	InventoryId              string    `json:"inventory_id"`
-	InventoryProductId       string    `json:"inventory_product_id"`
-	ProductPrice             float64   `json:"price"`
-	ProductQuantity          int       `json:"quantity"`
-	ProductExpiryDate        time.Time `json:"expiry_date"`
-	ProductManufacturingDate time.Time `json:"manufacturing_date"`
-	MetadataRating           float64   `json:"metadata_rating"`
-	StoreName                string    `json:"store_name"`
+	MetadataProductId        string                  `json:"metadata_product_id"`
+	MetadataName             string                  `json:"name"`
+	MetadataDescription      string                  `json:"description"`
+	MetadataImage            string                  `json:"image"`
+	MetadataGallery          []string                `json:"gallery,omitempty"`
+	MetadataCategoryId       string                  `json:"category_id"`
+	MetadataSubcategoryId    string                  `json:"subcategory_id"`
+	MetadataMrp              float64                 `json:"mrp"`
+	ProductCategoryName      string                  `json:"category_name"`
+	ProductSubCategoryName   string                  `json:"subcategory_name"`
+	TotalStars               int                     `json:"TotalStars"`
+	TotalReviews             int                     `json:"TotalReviews"`
+	InventoryId              string                  `json:"inventory_id"`
+	InventoryProductId       string                  `json:"inventory_product_id"`
+	ProductPrice             float64                 `json:"price"`
+	ProductQuantity          int                     `json:"quantity"`
+	ProductExpiryDate        time.Time               `json:"expiry_date"`
+	ProductManufacturingDate time.Time               `json:"manufacturing_date"`
+	MetadataRating           float64                 `json:"metadata_rating"`
+	StoreName                string                  `json:"store_name"`
+	WasPrice                 *float64                `json:"was_price,omitempty"`
+	SaleEndsAt               *time.Time              `json:"sale_ends_at,omitempty"`
+	SaleLabel                string                  `json:"sale_label,omitempty"`
+	VariantGroupID           string                  `json:"variant_group_id,omitempty"`
+	VariantAttributes        map[string]string       `json:"variant_attributes,omitempty"`
+	VariantGroup             *VariantGroupSummary    `json:"variant_group,omitempty"`
+	Variants                 []*ProductVariantOption `json:"variants,omitempty"`
+	MetadataAttributes       map[string]interface{}  `json:"attributes,omitempty"`
+	HsnCode                  string                  `json:"hsn_code"`
+	GSTRate                  *float64                `json:"gst_rate"`
 }
 
 type GetBasicDetailsForProductRequest struct {
@@ -67,12 +83,15 @@ type GetBasicDetailsForProductRequest struct {
 type GetBasicDetailsForProductResponse = GetProductsForStoreSubcategory
 
 type GetProductComparisonByStoreResult struct {
-	StoreName                string    `json:"store_name" bson:"storeName"`
-	InventoryProductID       string    `json:"id" bson:"_id,omitempty"`
-	InventoryID              string    `json:"inventory_id" bson:"inventory_id"`
-	MetadataProductID        string    `json:"metadata_product_id" bson:"metadata_product_id"`
-	ProductManufacturingDate time.Time `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
-	ProductQuantity          int       `json:"product_quantity" bson:"product_quantity"`
-	ProductPrice             float64   `json:"product_price" bson:"product_price"`
-	ProductExpiryDate        time.Time `json:"product_expiry_date" bson:"product_expiry_date"`
+	StoreName                string     `json:"store_name" bson:"storeName"`
+	InventoryProductID       string     `json:"id" bson:"_id,omitempty"`
+	InventoryID              string     `json:"inventory_id" bson:"inventory_id"`
+	MetadataProductID        string     `json:"metadata_product_id" bson:"metadata_product_id"`
+	ProductManufacturingDate time.Time  `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
+	ProductQuantity          int        `json:"product_quantity" bson:"product_quantity"`
+	ProductPrice             float64    `json:"product_price" bson:"product_price"`
+	ProductExpiryDate        time.Time  `json:"product_expiry_date" bson:"product_expiry_date"`
+	WasPrice                 *float64   `json:"was_price,omitempty" bson:"was_price"`
+	SaleEndsAt               *time.Time `json:"sale_ends_at,omitempty" bson:"sale_ends_at"`
+	SaleLabel                string     `json:"sale_label,omitempty" bson:"sale_label"`
 }
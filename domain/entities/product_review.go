@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+// Moderation states of a customer review. Only approved reviews are shown and counted in the rating.
+const (
+	ProductReviewPending  = "pending"
+	ProductReviewApproved = "approved"
+	ProductReviewRejected = "rejected"
+)
+
+// ProductReview is the review a customer left on a metadata product, at most one per customer and product.
+// VerifiedPurchase is set when the customer has an order with the product in it.
+type ProductReview struct {
+	ID                string     `json:"id" bson:"_id,omitempty"`
+	MetadataProductID string     `json:"metadata_product_id" bson:"metadata_product_id"`
+	UserID            string     `json:"user_id" bson:"user_id"`
+	Rating            int        `json:"rating" bson:"rating"`
+	Title             string     `json:"title,omitempty" bson:"title,omitempty"`
+	Text              string     `json:"text,omitempty" bson:"text,omitempty"`
+	Photos            []string   `json:"photos,omitempty" bson:"photos,omitempty"`
+	VerifiedPurchase  bool       `json:"verified_purchase" bson:"verified_purchase"`
+	Status            string     `json:"status" bson:"status"`
+	ModeratedBy       string     `json:"moderated_by,omitempty" bson:"moderated_by,omitempty"`
+	ModerationNote    string     `json:"moderation_note,omitempty" bson:"moderation_note,omitempty"`
+	ModeratedAt       *time.Time `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+type SubmitProductReviewRequest struct {
+	MetadataProductID string   `json:"metadata_product_id" binding:"required"`
+	Rating            int      `json:"rating" binding:"required"`
+	Title             string   `json:"title"`
+	Text              string   `json:"text"`
+	Photos            []string `json:"photos"`
+	UserID            string   `json:"user_id" bson:"omitempty"`
+}
+
+type ModerateProductReviewRequest struct {
+	ReviewID    string `json:"review_id"`
+	Decision    string `json:"decision" binding:"required"`
+	Note        string `json:"note"`
+	ModeratorID string `json:"moderator_id" bson:"omitempty"`
+}
+
+type PaginatedProductReviews struct {
+	Reviews      []*ProductReview `json:"reviews"`
+	TotalStars   int              `json:"total_stars"`
+	TotalReviews int              `json:"total_reviews"`
+	Rating       float64          `json:"rating"`
+	Total        int64            `json:"total"`
+	Limit        int64            `json:"limit"`
+	Offset       int64            `json:"offset"`
+}
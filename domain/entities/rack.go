@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// Rack is a physical rack of a store. Bins are numbered 1 to BinCount and Capacity is the number of units the
+// rack holds, zero meaning unlimited. Occupancy is computed from the inventory products assigned to it.
+type Rack struct {
+	ID          string         `json:"id" bson:"_id,omitempty"`
+	WarehouseID string         `json:"warehouse_id" bson:"warehouse_id"`
+	StoreID     string         `json:"store_id" bson:"store_id"`
+	Code        string         `json:"code" bson:"code"`
+	Description string         `json:"description" bson:"description"`
+	BinCount    int            `json:"bin_count" bson:"bin_count"`
+	Capacity    int            `json:"capacity" bson:"capacity"`
+	CreatedBy   string         `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time      `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" bson:"updated_at"`
+	Occupancy   *RackOccupancy `json:"occupancy,omitempty" bson:"-"`
+}
+
+type RackOccupancy struct {
+	Products         int64   `json:"products" bson:"products"`
+	Quantity         int64   `json:"quantity" bson:"quantity"`
+	OccupiedBins     int     `json:"occupied_bins" bson:"occupied_bins"`
+	OccupancyPercent float64 `json:"occupancy_percent" bson:"-"`
+}
+
+type CreateRackRequest struct {
+	StoreID       string `json:"store_id"`
+	Code          string `json:"code"`
+	Description   string `json:"description"`
+	BinCount      int    `json:"bin_count"`
+	Capacity      int    `json:"capacity"`
+	OperationalID string `json:"operational_id" bson:"omitempty"`
+}
+
+type UpdateRackRequest struct {
+	Description   string `json:"description"`
+	BinCount      int    `json:"bin_count"`
+	Capacity      int    `json:"capacity"`
+	RackID        string `json:"rack_id" bson:"omitempty"`
+	OperationalID string `json:"operational_id" bson:"omitempty"`
+}
+
+// AssignRackBinRequest places an inventory product in a rack bin. An empty RackID takes it off its rack.
+type AssignRackBinRequest struct {
+	InventoryProductID string `json:"inventory_product_id"`
+	RackID             string `json:"rack_id"`
+	Bin                int    `json:"bin"`
+	OperationalID      string `json:"operational_id" bson:"omitempty"`
+}
+
+// ProductLocation answers where an inventory product is kept within a warehouse
+type ProductLocation struct {
+	InventoryProductID string    `json:"inventory_product_id" bson:"inventory_product_id"`
+	MetadataProductID  string    `json:"metadata_product_id" bson:"metadata_product_id"`
+	MetadataName       string    `json:"metadata_name" bson:"metadata_name"`
+	StoreID            string    `json:"store_id" bson:"store_id"`
+	StoreName          string    `json:"store_name" bson:"store_name"`
+	RackID             string    `json:"rack_id,omitempty" bson:"rack_id"`
+	RackCode           string    `json:"rack_code,omitempty" bson:"rack_code"`
+	Bin                int       `json:"bin,omitempty" bson:"bin"`
+	ProductQuantity    int       `json:"product_quantity" bson:"product_quantity"`
+	ProductExpiryDate  time.Time `json:"product_expiry_date" bson:"product_expiry_date"`
+}
+
+type FindProductLocationRequest struct {
+	WarehouseID   string
+	Search        string
+	OperationalID string
+}
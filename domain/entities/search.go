@@ -0,0 +1,17 @@
+package entities
+
+// SearchSuggestion is a product, category or subcategory suggested while the customer types. Popularity is the
+// number of units ordered from the warehouse.
+type SearchSuggestion struct {
+	ID         string `json:"id"`
+	Text       string `json:"text"`
+	Image      string `json:"image,omitempty"`
+	Popularity int64  `json:"popularity"`
+}
+
+type AutocompleteResponse struct {
+	Query         string              `json:"query"`
+	Products      []*SearchSuggestion `json:"products"`
+	Categories    []*SearchSuggestion `json:"categories"`
+	Subcategories []*SearchSuggestion `json:"subcategories"`
+}
@@ -0,0 +1,68 @@
+package entities
+
+import "time"
+
+// Stock transfer states. Stock leaves the source store on dispatch and reaches the destination on receipt.
+const (
+	StockTransferDispatched = "dispatched"
+	StockTransferReceived   = "received"
+	StockTransferCancelled  = "cancelled"
+)
+
+type StockTransfer struct {
+	ID                  string               `json:"id" bson:"_id,omitempty"`
+	WarehouseID         string               `json:"warehouse_id" bson:"warehouse_id"`
+	SourceStoreID       string               `json:"source_store_id" bson:"source_store_id"`
+	SourceSellerID      string               `json:"source_seller_id" bson:"source_seller_id"`
+	DestinationStoreID  string               `json:"destination_store_id" bson:"destination_store_id"`
+	DestinationSellerID string               `json:"destination_seller_id" bson:"destination_seller_id"`
+	Items               []*StockTransferItem `json:"items" bson:"items"`
+	Status              string               `json:"status" bson:"status"`
+	Note                string               `json:"note" bson:"note"`
+	DispatchedBy        string               `json:"dispatched_by" bson:"dispatched_by"`
+	DispatchedAt        time.Time            `json:"dispatched_at" bson:"dispatched_at"`
+	ReceivedBy          string               `json:"received_by,omitempty" bson:"received_by,omitempty"`
+	ReceivedAt          *time.Time           `json:"received_at,omitempty" bson:"received_at,omitempty"`
+	CancelledBy         string               `json:"cancelled_by,omitempty" bson:"cancelled_by,omitempty"`
+	CancelledAt         *time.Time           `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+}
+
+type StockTransferItem struct {
+	SourceInventoryProductID      string    `json:"source_inventory_product_id" bson:"source_inventory_product_id"`
+	DestinationInventoryProductID string    `json:"destination_inventory_product_id,omitempty" bson:"destination_inventory_product_id,omitempty"`
+	MetadataProductID             string    `json:"metadata_product_id" bson:"metadata_product_id"`
+	Quantity                      int       `json:"quantity" bson:"quantity"`
+	ProductPrice                  float64   `json:"product_price" bson:"product_price"`
+	ProductExpiryDate             time.Time `json:"product_expiry_date" bson:"product_expiry_date"`
+	ProductManufacturingDate      time.Time `json:"product_manufacturing_date" bson:"product_manufacturing_date"`
+}
+
+type CreateStockTransferRequest struct {
+	SourceStoreID      string                            `json:"source_store_id"`
+	DestinationStoreID string                            `json:"destination_store_id"`
+	Items              []*CreateStockTransferItemRequest `json:"items"`
+	Note               string                            `json:"note"`
+	OperationalID      string                            `json:"operational_id" bson:"omitempty"`
+}
+
+type CreateStockTransferItemRequest struct {
+	InventoryProductID string `json:"inventory_product_id"`
+	Quantity           int    `json:"quantity"`
+}
+
+type GetStockTransfersRequest struct {
+	OperationalID string
+	SellerID      string
+	StoreID       string
+	Status        string
+	Offset        int64
+	Limit         int64
+}
+
+type PaginatedStockTransferResponse struct {
+	Transfers  []*StockTransfer `json:"transfers"`
+	Total      int64            `json:"total"`
+	Limit      int64            `json:"limit"`
+	Offset     int64            `json:"offset"`
+	TotalPages int64            `json:"total_pages"`
+}
@@ -2,17 +2,21 @@ package entities
 
 import "time"
 
+// NumberOfRacks and OccupiedRacks are not stored, they are computed from the store's racks when it is read
 type Store struct {
-	StoreID       string    `json:"store_id" bson:"_id,omitempty"`
-	SellerID      string    `json:"seller_id" bson:"seller_id"`
-	WarehouseID   string    `json:"warehouse_id" bson:"warehouse_id"`
-	StoreName     string    `json:"store_name" bson:"store_name"`
-	StoreAddress  string    `json:"store_address" bson:"store_address"`
-	StoreContact  string    `json:"store_contact" bson:"store_contact"`
-	NumberOfRacks int       `json:"number_of_racks" bson:"number_of_racks"`
-	OccupiedRacks int       `json:"occupied_racks" bson:"occupied_racks"`
-	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+	StoreID       string     `json:"store_id" bson:"_id,omitempty"`
+	SellerID      string     `json:"seller_id" bson:"seller_id"`
+	WarehouseID   string     `json:"warehouse_id" bson:"warehouse_id"`
+	StoreName     string     `json:"store_name" bson:"store_name"`
+	StoreAddress  string     `json:"store_address" bson:"store_address"`
+	StoreContact  string     `json:"store_contact" bson:"store_contact"`
+	StoreImage    string     `json:"store_image,omitempty" bson:"store_image,omitempty"`
+	NumberOfRacks int        `json:"number_of_racks" bson:"-"`
+	OccupiedRacks int        `json:"occupied_racks" bson:"-"`
+	CreatedAt     time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" bson:"updated_at"`
+	ArchivedAt    *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	ArchivedBy    string     `json:"archived_by,omitempty" bson:"archived_by,omitempty"`
 }
 
 // Request structures
@@ -28,20 +32,17 @@ type GetStoreByIdRequest struct {
 }
 
 type CreateStoreRequest struct {
-	WarehouseID   string `json:"warehouse_id" binding:"required"`
-	SellerID      string `json:"seller_id" bson:"seller_id"`
-	StoreName     string `json:"store_name" binding:"required"`
-	StoreAddress  string `json:"store_address" binding:"required"`
-	StoreContact  string `json:"store_contact" binding:"required"`
-	NumberOfRacks int    `json:"number_of_racks" binding:"required,gte=0"`
+	WarehouseID  string `json:"warehouse_id" binding:"required"`
+	SellerID     string `json:"seller_id" bson:"seller_id"`
+	StoreName    string `json:"store_name" binding:"required"`
+	StoreAddress string `json:"store_address" binding:"required"`
+	StoreContact string `json:"store_contact" binding:"required"`
 }
 
 type UpdateStoreRequest struct {
-	StoreName     string `json:"store_name"`
-	StoreAddress  string `json:"store_address"`
-	StoreContact  string `json:"store_contact"`
-	NumberOfRacks int    `json:"number_of_racks" binding:"gte=0"`
-	OccupiedRacks int    `json:"occupied_racks" binding:"gte=0"`
+	StoreName    string `json:"store_name"`
+	StoreAddress string `json:"store_address"`
+	StoreContact string `json:"store_contact"`
 }
 
 type DeleteStoreRequest struct {
@@ -0,0 +1,61 @@
+package entities
+
+import "time"
+
+// VariantGroup ties metadata products that are variants of one product, such as the sizes of a butter. Axes name
+// what the variants differ by, and each member metadata carries one value per axis in its VariantAttributes.
+type VariantGroup struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	Name          string    `json:"name" bson:"name"`
+	Axes          []string  `json:"axes" bson:"axes"`
+	SubcategoryID string    `json:"subcategory_id" bson:"subcategory_id"`
+	CreatedBy     string    `json:"created_by" bson:"created_by"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// VariantGroupDetail is a variant group with its member metadata
+type VariantGroupDetail struct {
+	VariantGroup `bson:",inline"`
+	Variants     []*Metadata `json:"variants" bson:"variants"`
+}
+
+type VariantGroupSummary struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Axes []string `json:"axes"`
+}
+
+// ProductVariantOption is one variant offered by a store, shown in the size or flavour picker of a product
+type ProductVariantOption struct {
+	MetadataProductId  string            `json:"metadata_product_id"`
+	InventoryProductId string            `json:"inventory_product_id"`
+	Name               string            `json:"name"`
+	Image              string            `json:"image"`
+	Attributes         map[string]string `json:"attributes"`
+	Mrp                float64           `json:"mrp"`
+	ProductPrice       float64           `json:"price"`
+	WasPrice           *float64          `json:"was_price,omitempty"`
+	ProductQuantity    int               `json:"quantity"`
+	StoreName          string            `json:"store_name"`
+}
+
+type CreateVariantGroupRequest struct {
+	Name          string   `json:"name"`
+	Axes          []string `json:"axes"`
+	SubcategoryID string   `json:"subcategory_id"`
+	OperationalID string   `json:"operational_id" bson:"omitempty"`
+}
+
+type UpdateVariantGroupRequest struct {
+	Name           string   `json:"name"`
+	Axes           []string `json:"axes"`
+	VariantGroupID string   `json:"variant_group_id" bson:"omitempty"`
+}
+
+// AddVariantRequest puts a metadata product in a variant group with a value for every axis of the group
+type AddVariantRequest struct {
+	VariantGroupID    string            `json:"variant_group_id"`
+	MetadataProductID string            `json:"metadata_product_id"`
+	Attributes        map[string]string `json:"attributes"`
+}
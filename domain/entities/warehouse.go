@@ -3,17 +3,19 @@ package entities
 import "time"
 
 type Warehouse struct {
-	ID                        string    `json:"id" bson:"_id,omitempty"`
-	WarehouseName             string    `json:"warehouseName" bson:"warehouseName"`
-	WarehouseAddress          string    `json:"warehouseAddress" bson:"warehouseAddress"`
-	WarehouseLeaseDetails     string    `json:"warehouseLeaseDetails" bson:"warehouseLeaseDetails"`
-	WarehouseStorageCapacity  int       `json:"warehouse_storage_capacity" bson:"warehouse_storage_capacity"`
-	WarehouseOperationalGuyID string    `json:"warehouse_operational_guy_id" bson:"warehouse_operational_guy_id"`
-	WarehouseCreatedAt        time.Time `json:"warehouse_created_at" bson:"warehouse_created_at"`
-	WarehouseUpdatedAt        time.Time `json:"warehouse_updated_at" bson:"warehouse_updated_at"`
-	OwnerName                 string    `json:"ownerName" bson:"ownerName"`
-	OwnerAddress              string    `json:"ownerAddress" bson:"ownerAddress"`
-	OwnerPhoneNumber          string    `json:"ownerPhoneNumber" binding:"required,min=10"`
+	ID                        string     `json:"id" bson:"_id,omitempty"`
+	WarehouseName             string     `json:"warehouseName" bson:"warehouseName"`
+	WarehouseAddress          string     `json:"warehouseAddress" bson:"warehouseAddress"`
+	WarehouseLeaseDetails     string     `json:"warehouseLeaseDetails" bson:"warehouseLeaseDetails"`
+	WarehouseStorageCapacity  int        `json:"warehouse_storage_capacity" bson:"warehouse_storage_capacity"`
+	WarehouseOperationalGuyID string     `json:"warehouse_operational_guy_id" bson:"warehouse_operational_guy_id"`
+	WarehouseCreatedAt        time.Time  `json:"warehouse_created_at" bson:"warehouse_created_at"`
+	WarehouseUpdatedAt        time.Time  `json:"warehouse_updated_at" bson:"warehouse_updated_at"`
+	OwnerName                 string     `json:"ownerName" bson:"ownerName"`
+	OwnerAddress              string     `json:"ownerAddress" bson:"ownerAddress"`
+	OwnerPhoneNumber          string     `json:"ownerPhoneNumber" binding:"required,min=10"`
+	ArchivedAt                *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	ArchivedBy                string     `json:"archived_by,omitempty" bson:"archived_by,omitempty"`
 }
 
 type CreateWarehouseRequest struct {
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type ArchiveRepository interface {
+	GetDependencies(ctx context.Context, kind, id string, cascade bool) (*entities.DependencyReport, error)
+	GetArchivedRecords(ctx context.Context, kind string, limit, offset int64) ([]*entities.ArchivedRecord, int64, error)
+	RestoreRecord(ctx context.Context, kind, id string) (*entities.RestoreReport, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type AttributeSchemaRepository interface {
+	SubcategoryExists(ctx context.Context, subcategoryId string) (bool, error)
+	SaveAttributeSchema(ctx context.Context, schema *entities.AttributeSchema) (*entities.AttributeSchema, error)
+	GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error)
+	DeleteAttributeSchema(ctx context.Context, subcategoryId string) error
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type BarcodeRepository interface {
+	AddBarcodes(ctx context.Context, barcodes []*entities.Barcode) error
+	RemoveBarcode(ctx context.Context, gtin string) error
+	GetBarcodesForMetadata(ctx context.Context, metadataId string) ([]*entities.Barcode, error)
+	LookupBarcode(ctx context.Context, gtin, sellerId string) (*entities.BarcodeLookupResponse, error)
+}
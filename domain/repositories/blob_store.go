@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore keeps uploaded files by key
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type CatalogProposalRepository interface {
+	SubmitProposal(ctx context.Context, proposal *entities.CatalogProposal) (*entities.CatalogProposal, error)
+	GetProposalById(ctx context.Context, proposalId, sellerId string) (*entities.CatalogProposal, error)
+	GetSellerProposals(ctx context.Context, sellerId string) ([]*entities.CatalogProposal, error)
+	GetProposalQueue(ctx context.Context, status string, limit, offset int64) ([]*entities.CatalogProposal, int64, error)
+	WithdrawProposal(ctx context.Context, proposalId, sellerId string) error
+	ReviewProposal(ctx context.Context, review *entities.ReviewCatalogProposalRequest) (*entities.CatalogProposal, error)
+	GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error)
+	GetHSNCode(ctx context.Context, code string) (*entities.HSNCode, error)
+}
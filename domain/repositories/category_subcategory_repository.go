@@ -18,7 +18,7 @@ type CategorySubcategoryRepository interface {
 	GetSubcategoryByCategoryId(ctx context.Context, categoryId *string, limit, offset int64, search *string) ([]*entities.Subcategory, int64, error)
 	UpdateCategory(ctx context.Context, categoryId *string, request *entities.UpdateCategoryRequest) (*entities.MessageResponse, error)
 	UpdateSubcategory(ctx context.Context, subcategoryID string, request *entities.UpdateSubcategoryRequest) (*entities.MessageResponse, error)
-	DeleteCategory(ctx context.Context, categoryID string) (*entities.MessageResponse, error)
+	DeleteCategory(ctx context.Context, categoryID string, request *entities.ArchiveRequest) (*entities.DependencyReport, error)
 	DeleteSubcategory(ctx context.Context, subcategoryID string) (*entities.MessageResponse, error)
 	GetCategorySubCategoryForAllStoresInWarehouse(ctx context.Context, warehouseId string) ([]*entities.CategoryWithSubcategoriesResponse, error)
 	GetCategorySubCategoryForSpecificStore(ctx context.Context, storeID string) ([]*entities.CategoryWithSubcategoriesResponse, error)
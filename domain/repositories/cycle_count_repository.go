@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type CycleCountRepository interface {
+	CreateCycleCountSession(ctx context.Context, request *entities.CreateCycleCountSessionRequest) (*entities.CycleCountSession, error)
+	GetCycleCountSessionById(ctx context.Context, sessionId, operationalId string) (*entities.CycleCountSession, error)
+	GetCycleCountSessions(ctx context.Context, request *entities.GetCycleCountSessionsRequest) ([]*entities.CycleCountSession, int64, error)
+	SubmitCycleCounts(ctx context.Context, sessionId, operationalId string, counts []*entities.CycleCountEntry) (*entities.CycleCountSession, error)
+	UpdateCycleCountStatus(ctx context.Context, sessionId, operationalId string, fromStatuses []string, toStatus string) (*entities.CycleCountSession, error)
+	ApproveCycleCountSession(ctx context.Context, session *entities.CycleCountSession, operationalId string) (*entities.CycleCountSession, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type HSNRepository interface {
+	SaveHSNCodes(ctx context.Context, requests []*entities.SaveHSNCodeRequest) ([]*entities.HSNCode, error)
+	GetHSNCode(ctx context.Context, code string) (*entities.HSNCode, error)
+	GetHSNCodes(ctx context.Context, search string, limit, offset int64) ([]*entities.HSNCode, int64, error)
+	DeleteHSNCode(ctx context.Context, code string) error
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type InboundShipmentRepository interface {
+	CreateInboundShipment(ctx context.Context, shipment *entities.InboundShipment) (*entities.InboundShipment, error)
+	ReceiveInboundShipment(ctx context.Context, request *entities.ReceiveInboundShipmentRequest) (*entities.InboundShipment, error)
+	CancelInboundShipment(ctx context.Context, shipmentId, sellerId string) (*entities.InboundShipment, error)
+	GetInboundShipmentById(ctx context.Context, shipmentId string) (*entities.InboundShipment, error)
+	GetInboundShipments(ctx context.Context, request *entities.GetInboundShipmentsRequest) ([]*entities.InboundShipment, int64, error)
+	GetStoreById(ctx context.Context, storeId string) (*entities.Store, error)
+	GetMetadataByIds(ctx context.Context, metadataIds []string) ([]*entities.Metadata, error)
+}
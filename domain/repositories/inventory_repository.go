@@ -6,12 +6,20 @@ import (
 )
 
 type InventoryRepository interface {
-	GetAllInventory(ctx context.Context, seller_id string, offset, limit int64, search, sort string) ([]entities.GetAllInventoryResponse, int64, error)
+	GetAllInventory(ctx context.Context, seller_id string, offset, limit int64, search, sort string, filter *entities.InventoryListFilter) ([]entities.GetAllInventoryResponse, int64, *entities.InventoryFacets, error)
+	GetAllInventoryForExport(ctx context.Context, seller_id, search, sort string, filter *entities.InventoryListFilter) ([]entities.GetAllInventoryResponse, error)
 	CreateInventory(ctx context.Context, inventoryRequest *entities.AddInventoryRequest) (*entities.MessageResponse, error)
-	UpdateInventory(ctx context.Context, inventoryRequest entities.UpdateInventoryRequest) (*entities.MessageResponse, error)
+	UpdateInventory(ctx context.Context, inventoryRequest entities.UpdateInventoryRequest, priceChanges []*entities.PriceChangeRequest) (*entities.MessageResponse, error)
 	DeleteInventory(ctx context.Context, inventoryRequest entities.DeleteInventoryRequest) error
 	GetInventoryById(ctx context.Context, inventoryRequest string) (*entities.GetInventoryByIdResponse, error)
-	AddInventoryByExcel(ctx context.Context, inventoryRequest *entities.AddInventoryByExcelRequest) (*entities.MessageResponse, error)
-	GetAllInventoryRequests(ctx context.Context, seller_id string, offset, limit int64, search string) ([]*entities.GetAllInventoryRequestResponse, int64, error)
-	AcceptVisibility(ctx context.Context, productId string) (*entities.MessageResponse, error)
+	AddInventoryByExcel(ctx context.Context, inventoryRequest *entities.AddInventoryByExcelRequest, priceChanges []*entities.PriceChangeRequest) (*entities.MessageResponse, error)
+	GetAllInventoryRequests(ctx context.Context, seller_id string, offset, limit int64, search, status string) ([]*entities.GetAllInventoryRequestResponse, int64, error)
+	ReviewInventoryProduct(ctx context.Context, review *entities.InventoryReviewRequest) (*entities.MessageResponse, error)
+	GetInventoryReviewHistory(ctx context.Context, inventoryProductId, sellerId string) ([]*entities.InventoryReviewDecision, error)
+	GetPricingContexts(ctx context.Context, inventoryProductIds []string) ([]*entities.PricingContext, error)
+	GetMetadataPricingContexts(ctx context.Context, metadataIds []string) ([]*entities.PricingContext, error)
+	GetInventoryLedger(ctx context.Context, inventoryProductId, sellerId string, offset, limit int64) ([]*entities.InventoryLedgerEntry, int64, error)
+	GetBulkInventoryTargets(ctx context.Context, seller_id string, inventoryProductIds []string, filter *entities.BulkInventoryFilter) ([]*entities.BulkInventoryTarget, error)
+	BulkUpdateInventory(ctx context.Context, sellerId string, updates []*entities.BulkInventoryItemResult, priceChanges []*entities.PriceChangeRequest, atomic bool) error
+	GetMetadataForSheet(ctx context.Context, metadataIds, hsnCodes []string) ([]*entities.Metadata, error)
 }
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"time"
+)
+
+type InventorySnapshotRepository interface {
+	CreateInventorySnapshot(ctx context.Context, snapshotDate string, asOf time.Time) (*entities.InventorySnapshotSummary, error)
+	GetInventorySnapshots(ctx context.Context) ([]*entities.InventorySnapshotSummary, error)
+	HasInventorySnapshot(ctx context.Context, snapshotDate string) (bool, error)
+	GetInventoryValuation(ctx context.Context, request *entities.InventoryValuationRequest, snapshotDate string) ([]*entities.InventoryValuationGroup, error)
+	AcquireSnapshotLock(ctx context.Context, holder string, lease time.Duration) (bool, error)
+	ReleaseSnapshotLock(ctx context.Context, holder string) error
+}
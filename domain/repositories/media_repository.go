@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type MediaRepository interface {
+	GetMediaOwnerSeller(ctx context.Context, ownerType, ownerId string) (string, error)
+	CreateMedia(ctx context.Context, media *entities.Media, primary bool) (*entities.Media, []*entities.Media, error)
+	GetMediaById(ctx context.Context, mediaId string) (*entities.Media, error)
+	GetMediaForOwner(ctx context.Context, ownerType, ownerId string) ([]*entities.Media, error)
+	SetPrimaryMedia(ctx context.Context, media *entities.Media, editedBy string) error
+	DeleteMedia(ctx context.Context, media *entities.Media, editedBy string) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"time"
+)
+
+type MetadataImportRepository interface {
+	CreateImportJob(ctx context.Context, job *entities.MetadataImportJob) (*entities.MetadataImportJob, error)
+	UpdateImportJob(ctx context.Context, job *entities.MetadataImportJob) error
+	GetImportJob(ctx context.Context, jobId string) (*entities.MetadataImportJob, error)
+	GetImportJobs(ctx context.Context, limit, offset int64) ([]*entities.MetadataImportJob, int64, error)
+	FailStaleImportJobs(ctx context.Context, savedBefore time.Time, failure string) (int64, error)
+	GetCategoriesWithSubcategories(ctx context.Context) ([]*entities.Category, []*entities.Subcategory, error)
+	GetMetadataByHSNCodes(ctx context.Context, hsnCodes []string) ([]*entities.Metadata, error)
+	ResolveHSNCodes(ctx context.Context, hsnCodes []string) (map[string]*entities.HSNCode, error)
+	GetMetadataInSubcategories(ctx context.Context, subcategoryIds []string) ([]*entities.Metadata, error)
+}
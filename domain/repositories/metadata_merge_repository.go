@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type MetadataMergeRepository interface {
+	GetMetadataForDuplicateScan(ctx context.Context, subcategoryId string) ([]*entities.Metadata, error)
+	GetDismissedDuplicates(ctx context.Context) ([]*entities.DismissedDuplicate, error)
+	DismissDuplicate(ctx context.Context, dismissal *entities.DismissedDuplicate) error
+	MergeMetadata(ctx context.Context, request *entities.MergeMetadataRequest) (*entities.MetadataMerge, error)
+	GetMetadataMerges(ctx context.Context, metadataId string, limit, offset int64) ([]*entities.MetadataMerge, int64, error)
+}
@@ -12,8 +12,13 @@ type MetadataRepository interface {
 	GetMetadataByID(ctx context.Context, id string) (*entities.MetadataResponse, error)
 	CreateMetadata(ctx context.Context, metadata *entities.Metadata) (*entities.MetadataApiResponse, error)
 	UpdateMetadata(ctx context.Context, id string, metadata *entities.Metadata) (*entities.MetadataApiResponse, error)
-	DeleteMetadata(ctx context.Context, id string) (*entities.MetadataApiResponse, error)
+	DeleteMetadata(ctx context.Context, id string, request *entities.ArchiveRequest) (*entities.DependencyReport, error)
 	AddReview(ctx context.Context, req *entities.AddReviewRequest) error
 	CreateReview(ctx context.Context, id string) (*entities.MetadataApiResponse, error)
 	GetMetadataForSubcategories(ctx context.Context, subCategoryIds []string) ([]*entities.GetMetadataForSubcategoryResponse, error)
+	GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error)
+	GetHSNCode(ctx context.Context, code string) (*entities.HSNCode, error)
+	GetMetadataRevisions(ctx context.Context, id string, limit, offset int64) ([]*entities.MetadataRevision, int64, error)
+	GetMetadataRevision(ctx context.Context, id string, revision int) (*entities.MetadataRevision, error)
+	RollbackMetadata(ctx context.Context, id string, revision int, editedBy string) (*entities.Metadata, error)
 }
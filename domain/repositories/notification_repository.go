@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type NotificationRepository interface {
+	GetNotifications(ctx context.Context, userId string, offset, limit int64, unreadOnly bool) ([]*entities.Notification, int64, int64, error)
+	MarkNotificationRead(ctx context.Context, userId, notificationId string) error
+	MarkAllNotificationsRead(ctx context.Context, userId string) (int64, error)
+}
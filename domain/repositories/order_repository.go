@@ -8,6 +8,8 @@ import (
 
 type OrderRepository interface {
 	GetAllOrders(ctx context.Context, requestData *entities.GetAllOrdersRequest) ([]*entities.GetAllOrdersReturn, int, error)
+	GetEffectivePrices(ctx context.Context, productIds []string) (map[string]*entities.EffectiveProductPrice, error)
+	GetGSTRates(ctx context.Context, hsnCodes []string) (map[string]float64, error)
 	CreateNewOrder(ctx context.Context, requestOrder *entities.CreateOrderRequest, OrderID string, OrderedAt time.Time) error
 	CreateNewOrderProducts(ctx context.Context, requestOrder *entities.CreateOrderRequest, OrderID string) error
 	GetOrderByOrderID(ctx context.Context, orderId *string) (*entities.GetAllOrdersReturn, error)
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type PricingRepository interface {
+	GetPricingGuardrails(ctx context.Context) ([]*entities.PricingGuardrail, error)
+	UpsertPricingGuardrail(ctx context.Context, guardrail *entities.PricingGuardrail) (*entities.PricingGuardrail, error)
+	DeletePricingGuardrail(ctx context.Context, subcategoryId string) error
+	GetPriceHistory(ctx context.Context, inventoryProductId, sellerId string, offset, limit int64) ([]*entities.PriceHistoryEntry, int64, error)
+	GetPriceChangeRequests(ctx context.Context, request *entities.GetPriceChangeRequestsRequest) ([]*entities.PriceChangeRequest, int64, error)
+	GetPriceChangeRequestById(ctx context.Context, requestId string) (*entities.PriceChangeRequest, error)
+	GetPricingContexts(ctx context.Context, inventoryProductIds []string) ([]*entities.PricingContext, error)
+	ReviewPriceChangeRequest(ctx context.Context, review *entities.ReviewPriceChangeRequest) (*entities.PriceChangeRequest, error)
+	CreateScheduledPrice(ctx context.Context, scheduledPrice *entities.ScheduledPrice) (*entities.ScheduledPrice, error)
+	GetScheduledPrices(ctx context.Context, inventoryProductId, sellerId string, includeEnded bool) ([]*entities.ScheduledPrice, error)
+	CancelScheduledPrice(ctx context.Context, scheduledPriceId, sellerId string) (*entities.ScheduledPrice, error)
+}
@@ -12,8 +12,11 @@ type ProductRepository interface {
 	GetProductsForAllStores(ctx context.Context, allStores *[]entities.Store) ([]*entities.GetProductsForAllStoresResponse, error)
 	FetchSellerId(ctx context.Context, storeID string) (string, error)
 	GetAllStores(ctx context.Context, warehouseID string) (*[]entities.Store, error)
-	GetProductsForStoreSubcategory(ctx context.Context, storeId, subcategoryId string) ([]*entities.GetProductsForStoreSubcategory, error)
-	GetProductsForAllStoresSubcategory(ctx context.Context, warehouseId, subcategoryId string) ([]*entities.GetProductsForStoreSubcategory, error)
+	GetProductsForStoreSubcategory(ctx context.Context, storeId, subcategoryId string, filters []*entities.AttributeFilter) ([]*entities.GetProductsForStoreSubcategory, error)
+	GetProductsForAllStoresSubcategory(ctx context.Context, warehouseId, subcategoryId string, filters []*entities.AttributeFilter) ([]*entities.GetProductsForStoreSubcategory, error)
+	GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error)
+	SearchProducts(ctx context.Context, storeId, warehouseId, search string, limit int) ([]*entities.GetProductsForStoreSubcategory, error)
+	GetAutocompleteSuggestions(ctx context.Context, warehouseId, query string, limit int) (*entities.AutocompleteResponse, error)
 	GetBasicDetailsForProduct(ctx context.Context, inventoryProductID string) (*entities.GetBasicDetailsForProductResponse, error)
 	GetProductComparisonByStore(ctx context.Context, warehouse_id string, inventoryProductID string) ([]*entities.GetProductComparisonByStoreResult, error)
 }
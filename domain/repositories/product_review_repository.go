@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type ProductReviewRepository interface {
+	SubmitReview(ctx context.Context, review *entities.ProductReview) (*entities.ProductReview, error)
+	GetProductReviews(ctx context.Context, metadataId string, limit, offset int64) (*entities.PaginatedProductReviews, error)
+	GetUserReviews(ctx context.Context, userId string) ([]*entities.ProductReview, error)
+	GetModerationQueue(ctx context.Context, status string, limit, offset int64) ([]*entities.ProductReview, int64, error)
+	ModerateReview(ctx context.Context, request *entities.ModerateProductReviewRequest) (*entities.ProductReview, error)
+	DeleteReview(ctx context.Context, reviewId, userId string) error
+}
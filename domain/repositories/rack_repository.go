@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type RackRepository interface {
+	CreateRack(ctx context.Context, request *entities.CreateRackRequest) (*entities.Rack, error)
+	UpdateRack(ctx context.Context, request *entities.UpdateRackRequest) (*entities.Rack, error)
+	DeleteRack(ctx context.Context, rackId, operationalId string) error
+	GetRacks(ctx context.Context, storeId, operationalId string) ([]*entities.Rack, error)
+	GetRackById(ctx context.Context, rackId, operationalId string) (*entities.Rack, error)
+	AssignRackBin(ctx context.Context, request *entities.AssignRackBinRequest) (*entities.InventoryProduct, error)
+	FindProductLocations(ctx context.Context, request *entities.FindProductLocationRequest) ([]*entities.ProductLocation, error)
+}
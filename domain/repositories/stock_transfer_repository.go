@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type StockTransferRepository interface {
+	CreateStockTransfer(ctx context.Context, request *entities.CreateStockTransferRequest) (*entities.StockTransfer, error)
+	ReceiveStockTransfer(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, error)
+	CancelStockTransfer(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, error)
+	GetStockTransferById(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, error)
+	GetStockTransfers(ctx context.Context, request *entities.GetStockTransfersRequest) ([]*entities.StockTransfer, int64, error)
+}
@@ -11,8 +11,7 @@ type StoreRepository interface {
 	GetStoreById(ctx context.Context, storeId string) (*entities.Store, error)
 	CreateStore(ctx context.Context, store *entities.CreateStoreRequest) (*entities.MessageResponse, error)
 	UpdateStore(ctx context.Context, storeId string, store *entities.Store) error
-	DeleteStore(ctx context.Context, storeId string) error
+	DeleteStore(ctx context.Context, storeId string, request *entities.ArchiveRequest) (*entities.DependencyReport, error)
 	GetStoreBySellerId(ctx context.Context, sellerId string) (*entities.Store, error)
 	GetStoresByWarehouseId(ctx context.Context, warehouseId string) ([]entities.Store, error)
-	UpdateStoreRacks(ctx context.Context, storeId string, occupiedRacks int) error
 }
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+)
+
+type VariantRepository interface {
+	CreateVariantGroup(ctx context.Context, group *entities.VariantGroup) (*entities.VariantGroup, error)
+	UpdateVariantGroup(ctx context.Context, request *entities.UpdateVariantGroupRequest) (*entities.VariantGroup, error)
+	DeleteVariantGroup(ctx context.Context, groupId string) error
+	GetVariantGroups(ctx context.Context, subcategoryId string) ([]*entities.VariantGroup, error)
+	GetVariantGroupById(ctx context.Context, groupId string) (*entities.VariantGroupDetail, error)
+	GetMetadataById(ctx context.Context, metadataId string) (*entities.Metadata, error)
+	SetMetadataVariant(ctx context.Context, metadataId, groupId string, attributes map[string]string) error
+}
@@ -12,5 +12,5 @@ type WarehouseRepository interface {
 	GetWarehouseById(ctx context.Context, id string) (*entities.Warehouse, error)
 	CreateWarehouse(ctx context.Context, warehouse *entities.CreateWarehouseRequest) (*entities.MessageResponse, error)
 	UpdateWarehouse(ctx context.Context, id string, warehouse *entities.UpdateWarehouseRequest) (*entities.MessageResponse, error)
-	DeleteWarehouse(ctx context.Context, id string) error
+	DeleteWarehouse(ctx context.Context, id string, request *entities.ArchiveRequest) (*entities.DependencyReport, error)
 }
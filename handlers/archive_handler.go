@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArchiveHandler struct {
+	archiveUseCase *usecase.ArchiveUseCase
+}
+
+func NewArchiveHandler(archiveUseCase *usecase.ArchiveUseCase) *ArchiveHandler {
+	return &ArchiveHandler{
+		archiveUseCase: archiveUseCase,
+	}
+}
+
+// archiveRequest reads the archive options of a delete, which only operations users may make
+func archiveRequest(c *gin.Context) (*entities.ArchiveRequest, bool) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return nil, false
+	}
+	var request entities.ArchiveRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid query parameters",
+		})
+		return nil, false
+	}
+	request.ArchivedBy = operational_id
+	return &request, true
+}
+
+// respondArchive answers a delete that archives a record. A delete blocked by its dependencies is a conflict
+// carrying the dependency report.
+func respondArchive(c *gin.Context, title string, report *entities.DependencyReport, err error) {
+	name := strings.ToLower(title)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to delete " + name,
+		})
+		return
+	}
+	if !report.Archived {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "other records still depend on this " + name,
+			"message": "Resolve the blocking dependencies or delete with cascade=true",
+			"data":    report,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": title + " Archived Successfully", "success": true, "data": report})
+}
+
+// GetDependencies reports what a delete of the record would block on or cascade to, without deleting it
+func (h *ArchiveHandler) GetDependencies(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	cascade, _ := strconv.ParseBool(c.DefaultQuery("cascade", "false"))
+
+	report, err := h.archiveUseCase.GetDependencies(c.Request.Context(), c.Param("kind"), c.Param("id"), cascade)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to check dependencies",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dependencies Fetched Successfully", "success": true, "data": report})
+}
+
+func (h *ArchiveHandler) GetArchivedRecords(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+
+	records, err := h.archiveUseCase.GetArchivedRecords(c.Request.Context(), c.Param("kind"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get archived records",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Archived Records Fetched Successfully", "success": true, "data": records})
+}
+
+// RestoreRecord brings back an archived record and whatever was archived along with it
+func (h *ArchiveHandler) RestoreRecord(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	report, err := h.archiveUseCase.RestoreRecord(c.Request.Context(), c.Param("kind"), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to restore record",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Record Restored Successfully", "success": true, "data": report})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttributeSchemaHandler struct {
+	attributeSchemaUseCase *usecase.AttributeSchemaUseCase
+}
+
+func NewAttributeSchemaHandler(attributeSchemaUseCase *usecase.AttributeSchemaUseCase) *AttributeSchemaHandler {
+	return &AttributeSchemaHandler{
+		attributeSchemaUseCase: attributeSchemaUseCase,
+	}
+}
+
+func (h *AttributeSchemaHandler) SaveAttributeSchema(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.SaveAttributeSchemaRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.SubcategoryID = c.Param("subcategoryId")
+	request.OperationalID = operational_id
+
+	schema, err := h.attributeSchemaUseCase.SaveAttributeSchema(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to save attribute schema",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Attribute Schema Saved Successfully", "success": true, "data": schema})
+}
+
+// GetAttributeSchema is open to every signed in user, sellers fill the attributes and customers filter on them
+func (h *AttributeSchemaHandler) GetAttributeSchema(c *gin.Context) {
+	schema, err := h.attributeSchemaUseCase.GetAttributeSchema(c.Request.Context(), c.Param("subcategoryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get attribute schema",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Attribute Schema Fetched Successfully", "success": true, "data": schema})
+}
+
+func (h *AttributeSchemaHandler) DeleteAttributeSchema(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	if err := h.attributeSchemaUseCase.DeleteAttributeSchema(c.Request.Context(), c.Param("subcategoryId")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to delete attribute schema",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Attribute Schema Deleted Successfully", "success": true})
+}
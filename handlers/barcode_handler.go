@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BarcodeHandler struct {
+	barcodeUseCase *usecase.BarcodeUseCase
+}
+
+func NewBarcodeHandler(barcodeUseCase *usecase.BarcodeUseCase) *BarcodeHandler {
+	return &BarcodeHandler{
+		barcodeUseCase: barcodeUseCase,
+	}
+}
+
+func (h *BarcodeHandler) AddBarcodes(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.AddBarcodesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.AddedBy = operational_id
+
+	barcodes, err := h.barcodeUseCase.AddBarcodes(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to add barcodes",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Barcodes Added Successfully", "success": true, "data": barcodes})
+}
+
+func (h *BarcodeHandler) RemoveBarcode(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	if err := h.barcodeUseCase.RemoveBarcode(c.Request.Context(), c.Param("code")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to remove barcode",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Barcode Removed Successfully", "success": true})
+}
+
+func (h *BarcodeHandler) GetBarcodesForMetadata(c *gin.Context) {
+	barcodes, err := h.barcodeUseCase.GetBarcodesForMetadata(c.Request.Context(), c.Param("metadataId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get barcodes",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Barcodes Fetched Successfully", "success": true, "data": barcodes})
+}
+
+// LookupBarcode resolves a scanned code to its product. Sellers also get their own listing of the product, to
+// update its stock, or none when they have yet to add it.
+func (h *BarcodeHandler) LookupBarcode(c *gin.Context) {
+	user_id, role, ok := mediaUser(c)
+	if !ok {
+		return
+	}
+	seller_id := ""
+	if role == "seller" {
+		seller_id = user_id
+	}
+
+	lookup, err := h.barcodeUseCase.LookupBarcode(c.Request.Context(), c.Param("code"), seller_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to look up barcode",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Barcode Found Successfully", "success": true, "data": lookup})
+}
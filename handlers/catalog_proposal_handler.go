@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogProposalHandler struct {
+	catalogProposalUseCase *usecase.CatalogProposalUseCase
+}
+
+func NewCatalogProposalHandler(catalogProposalUseCase *usecase.CatalogProposalUseCase) *CatalogProposalHandler {
+	return &CatalogProposalHandler{
+		catalogProposalUseCase: catalogProposalUseCase,
+	}
+}
+
+// sellerUser returns the user_id of the seller making the request, or writes the error response
+func sellerUser(c *gin.Context) (string, bool) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "seller" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only sellers can perform this action",
+		})
+		return "", false
+	}
+	seller_id := c.GetString("user_id")
+	if seller_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return "", false
+	}
+	return seller_id, true
+}
+
+func (h *CatalogProposalHandler) SubmitProposal(c *gin.Context) {
+	seller_id, ok := sellerUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.SubmitCatalogProposalRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.SellerID = seller_id
+
+	proposal, err := h.catalogProposalUseCase.SubmitProposal(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to submit proposal",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Proposal Submitted Successfully", "success": true, "data": proposal})
+}
+
+func (h *CatalogProposalHandler) GetMyProposals(c *gin.Context) {
+	seller_id, ok := sellerUser(c)
+	if !ok {
+		return
+	}
+
+	proposals, err := h.catalogProposalUseCase.GetSellerProposals(c.Request.Context(), seller_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get proposals",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Proposals Fetched Successfully", "success": true, "data": proposals})
+}
+
+// GetProposalById shows sellers their own proposals and operations any proposal
+func (h *CatalogProposalHandler) GetProposalById(c *gin.Context) {
+	user_id, role, ok := mediaUser(c)
+	if !ok {
+		return
+	}
+	switch role {
+	case "operations":
+		user_id = ""
+	case "seller":
+	default:
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view proposals",
+		})
+		return
+	}
+
+	proposal, err := h.catalogProposalUseCase.GetProposalById(c.Request.Context(), c.Param("id"), user_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get proposal",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Proposal Fetched Successfully", "success": true, "data": proposal})
+}
+
+func (h *CatalogProposalHandler) WithdrawProposal(c *gin.Context) {
+	seller_id, ok := sellerUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.catalogProposalUseCase.WithdrawProposal(c.Request.Context(), c.Param("id"), seller_id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to withdraw proposal",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Proposal Withdrawn Successfully", "success": true})
+}
+
+func (h *CatalogProposalHandler) GetProposalQueue(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+
+	proposals, err := h.catalogProposalUseCase.GetProposalQueue(c.Request.Context(), c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get proposal queue",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Proposal Queue Fetched Successfully", "success": true, "data": proposals})
+}
+
+func (h *CatalogProposalHandler) ReviewProposal(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.ReviewCatalogProposalRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.ProposalID = c.Param("id")
+	request.ReviewerID = operational_id
+
+	proposal, err := h.catalogProposalUseCase.ReviewProposal(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to review proposal",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Proposal Reviewed Successfully", "success": true, "data": proposal})
+}
@@ -411,20 +411,13 @@ func (h *CategorySubcategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	response, _ := h.categorySubcategoryUseCase.DeleteCategory(c.Request.Context(), categoryID)
-	if !response.Success {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": response.Success,
-			"error":   response.Error,
-			"message": response.Message,
-		})
+	request, ok := archiveRequest(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": response.Success,
-		"message": response.Message,
-	})
+	report, err := h.categorySubcategoryUseCase.DeleteCategory(c.Request.Context(), categoryID, request)
+	respondArchive(c, "Category", report, err)
 }
 func (h *CategorySubcategoryHandler) DeleteSubcategory(c *gin.Context) {
 	role, isPresent := c.Get("role")
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CycleCountHandler struct {
+	cycleCountUseCase *usecase.CycleCountUseCase
+}
+
+func NewCycleCountHandler(cycleCountUseCase *usecase.CycleCountUseCase) *CycleCountHandler {
+	return &CycleCountHandler{
+		cycleCountUseCase: cycleCountUseCase,
+	}
+}
+
+func (h *CycleCountHandler) CreateCycleCountSession(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.CreateCycleCountSessionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.OperationalID = operational_id
+
+	session, err := h.cycleCountUseCase.CreateCycleCountSession(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to open count session",
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Count Session Opened Successfully", "success": true, "data": session})
+}
+
+func (h *CycleCountHandler) GetCycleCountSession(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.cycleCountUseCase.GetCycleCountSession(c.Request.Context(), c.Param("id"), operational_id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get count session",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": session, "success": true})
+}
+
+func (h *CycleCountHandler) GetCycleCountSessions(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+	limitStr := c.DefaultQuery("limit", "10")
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid limit parameter",
+			"message": "Limit parameter is invalid",
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid offset parameter",
+			"message": "Offset parameter is invalid",
+		})
+		return
+	}
+
+	sessions, err := h.cycleCountUseCase.GetCycleCountSessions(c.Request.Context(), &entities.GetCycleCountSessionsRequest{
+		OperationalID: operational_id,
+		StoreID:       c.Query("store_id"),
+		Status:        c.Query("status"),
+		Offset:        offset,
+		Limit:         limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false, "message": "Failed to get count sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sessions, "success": true})
+}
+
+func (h *CycleCountHandler) SubmitCycleCounts(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.SubmitCycleCountsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	session, err := h.cycleCountUseCase.SubmitCycleCounts(c.Request.Context(), c.Param("id"), operational_id, &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to submit counts",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Counts Submitted Successfully", "success": true, "data": session})
+}
+
+func (h *CycleCountHandler) SubmitCycleCountForReview(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.cycleCountUseCase.SubmitCycleCountForReview(c.Request.Context(), c.Param("id"), operational_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to submit count session for review",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Count Session Submitted For Review", "success": true, "data": session})
+}
+
+func (h *CycleCountHandler) ReopenCycleCountSession(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.cycleCountUseCase.ReopenCycleCountSession(c.Request.Context(), c.Param("id"), operational_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to reopen count session",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Count Session Reopened", "success": true, "data": session})
+}
+
+func (h *CycleCountHandler) CancelCycleCountSession(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.cycleCountUseCase.CancelCycleCountSession(c.Request.Context(), c.Param("id"), operational_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to cancel count session",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Count Session Cancelled", "success": true, "data": session})
+}
+
+func (h *CycleCountHandler) ApproveCycleCountSession(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.ApproveCycleCountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	session, err := h.cycleCountUseCase.ApproveCycleCountSession(c.Request.Context(), c.Param("id"), operational_id, &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to approve count session",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Count Adjustments Posted Successfully", "success": true, "data": session})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HSNHandler struct {
+	hsnUseCase *usecase.HSNUseCase
+}
+
+func NewHSNHandler(hsnUseCase *usecase.HSNUseCase) *HSNHandler {
+	return &HSNHandler{
+		hsnUseCase: hsnUseCase,
+	}
+}
+
+// SaveHSNCode adds an HSN code to the master or a GST rate to an existing code
+func (h *HSNHandler) SaveHSNCode(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.SaveHSNCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.UpdatedBy = operational_id
+
+	hsnCode, err := h.hsnUseCase.SaveHSNCode(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to save hsn code",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "HSN Code Saved Successfully", "success": true, "data": hsnCode})
+}
+
+// ImportHSNCodes loads the .xlsx/.csv sheet in the 'file' form field into the HSN master
+func (h *HSNHandler) ImportHSNCodes(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "File is required",
+			"message": "Upload the sheet in the 'file' form field",
+		})
+		return
+	}
+	if fileHeader.Size > maxMetadataSheetSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "File too large",
+			"message": "Sheet must be smaller than 10 MB",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Unable to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.hsnUseCase.ImportHSNCodes(c.Request.Context(), operational_id, fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Unable to import hsn codes",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "HSN Codes Imported Successfully", "success": true, "data": result})
+}
+
+// GetHSNCode looks up the master entry rating a code, which may be its 6 or 4 digit heading
+func (h *HSNHandler) GetHSNCode(c *gin.Context) {
+	hsnCode, err := h.hsnUseCase.GetHSNCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get hsn code",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "HSN Code Fetched Successfully", "success": true, "data": hsnCode})
+}
+
+func (h *HSNHandler) GetHSNCodes(c *gin.Context) {
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+
+	codes, err := h.hsnUseCase.GetHSNCodes(c.Request.Context(), c.Query("search"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get hsn codes",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "HSN Codes Fetched Successfully", "success": true, "data": codes})
+}
+
+func (h *HSNHandler) DeleteHSNCode(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	if err := h.hsnUseCase.DeleteHSNCode(c.Request.Context(), c.Param("code")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to delete hsn code",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "HSN Code Deleted Successfully", "success": true})
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InboundShipmentHandler struct {
+	inboundShipmentUseCase *usecase.InboundShipmentUseCase
+}
+
+func NewInboundShipmentHandler(inboundShipmentUseCase *usecase.InboundShipmentUseCase) *InboundShipmentHandler {
+	return &InboundShipmentHandler{
+		inboundShipmentUseCase: inboundShipmentUseCase,
+	}
+}
+
+// shipmentViewer returns the seller id a shipment lookup is limited to, empty for operations users
+func shipmentViewer(c *gin.Context) (string, bool) {
+	role, isPresent := c.Get("role")
+	user_id := c.GetString("user_id")
+	if !isPresent || user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return "", false
+	}
+	switch role {
+	case "operations":
+		return "", true
+	case "seller":
+		return user_id, true
+	default:
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view shipments",
+		})
+		return "", false
+	}
+}
+
+func (h *InboundShipmentHandler) CreateInboundShipment(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "seller" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only sellers can declare shipments",
+		})
+		return
+	}
+
+	var request entities.CreateInboundShipmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.SellerID = c.GetString("user_id")
+
+	shipment, err := h.inboundShipmentUseCase.CreateInboundShipment(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to declare shipment",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shipment Declared Successfully", "success": true, "data": shipment})
+}
+
+func (h *InboundShipmentHandler) ReceiveInboundShipment(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.ReceiveInboundShipmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.ShipmentID = c.Param("id")
+	request.OperationalID = operational_id
+
+	shipment, err := h.inboundShipmentUseCase.ReceiveInboundShipment(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to receive shipment",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shipment Received Successfully", "success": true, "data": shipment})
+}
+
+func (h *InboundShipmentHandler) CancelInboundShipment(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "seller" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only sellers can cancel shipments",
+		})
+		return
+	}
+
+	shipment, err := h.inboundShipmentUseCase.CancelInboundShipment(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to cancel shipment",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shipment Cancelled Successfully", "success": true, "data": shipment})
+}
+
+func (h *InboundShipmentHandler) GetInboundShipmentById(c *gin.Context) {
+	sellerId, ok := shipmentViewer(c)
+	if !ok {
+		return
+	}
+
+	shipment, err := h.inboundShipmentUseCase.GetInboundShipmentById(c.Request.Context(), c.Param("id"), sellerId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false, "message": "Failed to get shipment"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": shipment, "success": true})
+}
+
+func (h *InboundShipmentHandler) GetInboundShipments(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "10")
+	offsetStr := c.DefaultQuery("offset", "0")
+	sellerId, ok := shipmentViewer(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid limit parameter",
+			"message": "Limit parameter is invalid",
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid offset parameter",
+			"message": "Offset parameter is invalid",
+		})
+		return
+	}
+
+	request := &entities.GetInboundShipmentsRequest{
+		SellerID: sellerId,
+		StoreID:  c.Query("store_id"),
+		Status:   c.Query("status"),
+		Offset:   offset,
+		Limit:    limit,
+	}
+	if sellerId == "" {
+		request.OperationalID = c.GetString("user_id")
+	}
+
+	shipments, err := h.inboundShipmentUseCase.GetInboundShipments(c.Request.Context(), request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false, "message": "Failed to get shipments"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": shipments, "success": true})
+}
+
+func (h *InboundShipmentHandler) DownloadGoodsReceivedNote(c *gin.Context) {
+	sellerId, ok := shipmentViewer(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "xlsx")
+
+	shipment, file, err := h.inboundShipmentUseCase.ExportGoodsReceivedNote(c.Request.Context(), c.Param("id"), sellerId, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false, "message": "Unable to download goods-received note"})
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", shipment.GRNNumber, format))
+	c.Data(http.StatusOK, contentType, file)
+}
@@ -1,15 +1,21 @@
 package handlers
 
 import (
+	"errors"
 	"espazeBackend/domain/entities"
 	"espazeBackend/usecase"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxInventorySheetSize caps spreadsheet uploads to AddInventoryByExcel at 5 MB
+const maxInventorySheetSize = 5 << 20
+
 type InventoryHandler struct {
 	inventoryUseCase *usecase.InventoryUseCaseInterface
 }
@@ -64,8 +70,22 @@ func (h *InventoryHandler) GetAllInventory(c *gin.Context) {
 		return
 	}
 
-	inventory, err := h.inventoryUseCase.GetAllInventory(c.Request.Context(), seller, offset, limit, search, sort)
+	filter, err := parseInventoryListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Filter parameters are invalid",
+		})
+		return
+	}
+
+	inventory, err := h.inventoryUseCase.GetAllInventory(c.Request.Context(), seller, offset, limit, search, sort, filter)
 
+	if errors.Is(err, usecase.ErrInvalidInventoryListing) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false})
 		return
@@ -73,6 +93,72 @@ func (h *InventoryHandler) GetAllInventory(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": inventory, "success": true})
 }
 
+// parseInventoryListFilter reads the listing filters from the query. Category and subcategory ids are comma
+// separated, dates are YYYY-MM-DD and expiring_within_days sets the end of the expiry window from today.
+func parseInventoryListFilter(c *gin.Context) (*entities.InventoryListFilter, error) {
+	filter := &entities.InventoryListFilter{}
+	splitIds := func(value string) []string {
+		var ids []string
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	filter.CategoryIDs = splitIds(c.Query("category_id"))
+	filter.SubcategoryIDs = splitIds(c.Query("subcategory_id"))
+
+	if value := c.Query("visibility"); value != "" {
+		visibility, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid visibility %q", value)
+		}
+		filter.Visibility = &visibility
+	}
+	for name, target := range map[string]**int{"min_quantity": &filter.MinQuantity, "max_quantity": &filter.MaxQuantity} {
+		if value := c.Query(name); value != "" {
+			quantity, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", name, value)
+			}
+			*target = &quantity
+		}
+	}
+	for name, target := range map[string]**float64{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		if value := c.Query(name); value != "" {
+			price, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", name, value)
+			}
+			*target = &price
+		}
+	}
+	for name, target := range map[string]**time.Time{"expiry_from": &filter.ExpiryFrom, "expiry_to": &filter.ExpiryTo} {
+		if value := c.Query(name); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q, expected YYYY-MM-DD", name, value)
+			}
+			*target = &date
+		}
+	}
+	if filter.ExpiryTo != nil {
+		// expiry_to includes the whole day
+		endOfDay := filter.ExpiryTo.Add(24*time.Hour - time.Nanosecond)
+		filter.ExpiryTo = &endOfDay
+	}
+	if value := c.Query("expiring_within_days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("invalid expiring_within_days %q", value)
+		}
+		expiryTo := time.Now().AddDate(0, 0, days)
+		filter.ExpiryTo = &expiryTo
+	}
+	return filter, nil
+}
+
 func (h *InventoryHandler) AddInventory(c *gin.Context) {
 	seller_id, isPresent := c.Get("user_id")
 	if !isPresent {
@@ -130,8 +216,21 @@ func (h *InventoryHandler) UpdateInventory(c *gin.Context) {
 		return
 	}
 
+	// Sellers can only update their own products
+	if role, _ := c.Get("role"); role == "seller" {
+		inventoryRequest.SellerID = c.GetString("user_id")
+	}
+
 	response, err := h.inventoryUseCase.UpdateInventory(c.Request.Context(), inventoryRequest)
 
+	if errors.Is(err, usecase.ErrPriceGuardrail) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": response.Success,
+			"error":   response.Error,
+			"message": response.Message,
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": response.Success,
@@ -196,6 +295,11 @@ func (h *InventoryHandler) AddInventoryByExcel(c *gin.Context) {
 		return
 	}
 
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		h.uploadInventorySheet(c, seller)
+		return
+	}
+
 	var inventoryRequest *entities.AddInventoryByExcelRequest
 	if err := c.ShouldBindJSON(&inventoryRequest); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -223,10 +327,189 @@ func (h *InventoryHandler) AddInventoryByExcel(c *gin.Context) {
 	})
 }
 
+// uploadInventorySheet handles a multipart .xlsx/.csv upload sent to AddInventoryByExcel
+func (h *InventoryHandler) uploadInventorySheet(c *gin.Context, seller string) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "File is required",
+			"message": "Upload the sheet in the 'file' form field",
+		})
+		return
+	}
+	if fileHeader.Size > maxInventorySheetSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "File too large",
+			"message": "Sheet must be smaller than 5 MB",
+		})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", c.DefaultPostForm("dry_run", "false")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid dry_run parameter",
+			"message": "dry_run must be true or false",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Unable to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	report, err := h.inventoryUseCase.UploadInventorySheet(c.Request.Context(), seller, fileHeader.Filename, file, dryRun)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Unable to process inventory sheet",
+		})
+		return
+	}
+	if report.InvalidRows > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Sheet contains invalid rows",
+			"message": "No inventory was added, fix the listed rows and upload again",
+			"data":    report,
+		})
+		return
+	}
+
+	message := "Inventory Added Successfully"
+	if report.DryRun {
+		message = "Sheet is valid, nothing was added (dry run)"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    report,
+	})
+}
+
+// BulkUpdateInventory applies price, quantity and visibility changes to many inventory products in one call
+func (h *InventoryHandler) BulkUpdateInventory(c *gin.Context) {
+	seller_id, isPresent := c.Get("user_id")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	seller, ok := seller_id.(string)
+	if !ok || seller == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	var inventoryRequest entities.BulkInventoryRequest
+	if err := c.ShouldBindJSON(&inventoryRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false,
+			"error":   err.Error(),
+			"message": "Invalid Request Body"})
+		return
+	}
+	inventoryRequest.SellerID = seller
+
+	response, err := h.inventoryUseCase.BulkUpdateInventory(c.Request.Context(), &inventoryRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Bulk update failed",
+		})
+		return
+	}
+
+	if !response.Committed && response.Failed > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Some items could not be updated",
+			"message": "No products were updated",
+			"data":    response,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Bulk update completed", "data": response})
+}
+
+// ExportInventory downloads the seller's inventory in the spreadsheet import template
+func (h *InventoryHandler) ExportInventory(c *gin.Context) {
+	format := c.DefaultQuery("format", "xlsx")
+	search := c.DefaultQuery("search", "")
+	sort := c.DefaultQuery("sort", "")
+	sellerInterface, isPresent := c.Get("user_id")
+
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	seller, ok := sellerInterface.(string)
+	if !ok || seller == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	filter, err := parseInventoryListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Filter parameters are invalid",
+		})
+		return
+	}
+
+	file, err := h.inventoryUseCase.ExportInventorySheet(c.Request.Context(), seller, format, search, sort, filter)
+	if errors.Is(err, usecase.ErrInvalidInventoryListing) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false, "message": "Unable to export inventory"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false, "message": "Unable to export inventory"})
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=inventory.%s", format))
+	c.Data(http.StatusOK, contentType, file)
+}
+
 func (h *InventoryHandler) GetAllInventoryRequests(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "10")
 	offsetStr := c.DefaultQuery("offset", "0")
 	search := c.DefaultQuery("search", "")
+	status := c.DefaultQuery("status", entities.ReviewStatusPending)
 	operationalGuyId, isPresent := c.Get("user_id")
 
 	if !isPresent {
@@ -268,7 +551,7 @@ func (h *InventoryHandler) GetAllInventoryRequests(c *gin.Context) {
 		return
 	}
 
-	inventory, err := h.inventoryUseCase.GetAllInventoryRequests(c.Request.Context(), operational_id, offset, limit, search)
+	inventory, err := h.inventoryUseCase.GetAllInventoryRequests(c.Request.Context(), operational_id, offset, limit, search, status)
 
 	if err != nil {
 		fmt.Print(err.Error())
@@ -278,37 +561,169 @@ func (h *InventoryHandler) GetAllInventoryRequests(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": inventory, "success": true})
 }
 
-func (h *InventoryHandler) AcceptVisibility(c *gin.Context) {
-	productId := c.Query("productId")
-	if productId == "" {
+func (h *InventoryHandler) ReviewInventoryProduct(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "operations" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only operations users can review products",
+		})
+		return
+	}
+
+	reviewerId, isPresent := c.Get("user_id")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	reviewer_id, ok := reviewerId.(string)
+	if !ok || reviewer_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	var review entities.InventoryReviewRequest
+	if err := c.ShouldBindJSON(&review); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	review.ReviewerID = reviewer_id
+	review.ReviewerName = c.GetString("name")
+
+	response, err := h.inventoryUseCase.ReviewInventoryProduct(c.Request.Context(), &review)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to review product",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": response.Message, "success": response.Success})
+}
+
+func (h *InventoryHandler) GetInventoryReviewHistory(c *gin.Context) {
+	inventoryProductId := c.Query("inventory_product_id")
+	role, isPresent := c.Get("role")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	// Sellers only see the history of their own products
+	sellerId := ""
+	if role == "seller" {
+		sellerId = c.GetString("user_id")
+		if sellerId == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid token",
+				"message": "Token is invalid",
+			})
+			return
+		}
+	} else if role != "operations" && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view review history",
+		})
+		return
+	}
+
+	history, err := h.inventoryUseCase.GetInventoryReviewHistory(c.Request.Context(), inventoryProductId, sellerId)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "ProductId is Invalid",
-			"message": "Invalid ProductId",
+			"error":   err.Error(),
+			"message": "Failed to get review history",
 		})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"data": history, "success": true})
+}
 
+func (h *InventoryHandler) GetInventoryLedger(c *gin.Context) {
+	inventoryProductId := c.Query("inventory_product_id")
+	limitStr := c.DefaultQuery("limit", "10")
+	offsetStr := c.DefaultQuery("offset", "0")
 	role, isPresent := c.Get("role")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
 
-	if !isPresent || role != "operations" {
+	// Sellers only see movements of their own products
+	sellerId := ""
+	if role == "seller" {
+		sellerId = c.GetString("user_id")
+		if sellerId == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid token",
+				"message": "Token is invalid",
+			})
+			return
+		}
+	} else if role != "operations" && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view the inventory ledger",
+		})
+		return
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "Invalid token or user role",
-			"message": "Token or User Role is invalid ",
+			"error":   "Invalid limit parameter",
+			"message": "Limit parameter is invalid",
 		})
 		return
 	}
 
-	response, err := h.inventoryUseCase.AcceptVisibility(c.Request.Context(), productId)
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid offset parameter",
+			"message": "Offset parameter is invalid",
+		})
+		return
+	}
 
+	ledger, err := h.inventoryUseCase.GetInventoryLedger(c.Request.Context(), inventoryProductId, sellerId, offset, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"error":   err.Error(),
-			"message": "Internal server error",
+			"message": "Failed to get inventory ledger",
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": response.Message, "success": response.Success})
+	c.JSON(http.StatusOK, gin.H{"data": ledger, "success": true})
 }
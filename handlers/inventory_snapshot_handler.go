@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventorySnapshotHandler struct {
+	inventorySnapshotUseCase *usecase.InventorySnapshotUseCase
+}
+
+func NewInventorySnapshotHandler(inventorySnapshotUseCase *usecase.InventorySnapshotUseCase) *InventorySnapshotHandler {
+	return &InventorySnapshotHandler{
+		inventorySnapshotUseCase: inventorySnapshotUseCase,
+	}
+}
+
+// valuationRequest reads the report filters and scopes them to the caller: admins see everything, operations
+// their warehouses and sellers their own stock
+func valuationRequest(c *gin.Context) (*entities.InventoryValuationRequest, bool) {
+	role, isPresent := c.Get("role")
+	user_id := c.GetString("user_id")
+	if !isPresent || user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return nil, false
+	}
+
+	request := &entities.InventoryValuationRequest{
+		Date:        c.Query("date"),
+		CompareDate: c.Query("compare_date"),
+		GroupBy:     c.Query("group_by"),
+		WarehouseID: c.Query("warehouse_id"),
+		StoreID:     c.Query("store_id"),
+		SellerID:    c.Query("seller_id"),
+		CategoryID:  c.Query("category_id"),
+	}
+	switch role {
+	case "admin":
+	case "operations":
+		request.OperationalID = user_id
+	case "seller":
+		request.SellerID = user_id
+	default:
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view inventory valuation",
+		})
+		return nil, false
+	}
+	return request, true
+}
+
+func (h *InventorySnapshotHandler) TakeInventorySnapshot(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only admins can take inventory snapshots",
+		})
+		return
+	}
+
+	summary, err := h.inventorySnapshotUseCase.TakeInventorySnapshot(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false, "message": "Failed to take inventory snapshot"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Inventory Snapshot Taken Successfully", "success": true, "data": summary})
+}
+
+func (h *InventorySnapshotHandler) GetInventorySnapshots(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || (role != "admin" && role != "operations" && role != "seller") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Token or User Role is invalid",
+		})
+		return
+	}
+
+	snapshots, err := h.inventorySnapshotUseCase.GetInventorySnapshots(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false, "message": "Failed to get inventory snapshots"})
+		return
+	}
+	// Totals span every seller and warehouse, so only the dates are shown to others
+	if role != "admin" {
+		dates := make([]string, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			dates = append(dates, snapshot.SnapshotDate)
+		}
+		c.JSON(http.StatusOK, gin.H{"data": dates, "success": true})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": snapshots, "success": true})
+}
+
+func (h *InventorySnapshotHandler) GetInventoryValuation(c *gin.Context) {
+	request, ok := valuationRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.inventorySnapshotUseCase.GetInventoryValuation(c.Request.Context(), request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get inventory valuation",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report, "success": true})
+}
+
+func (h *InventorySnapshotHandler) ExportInventoryValuation(c *gin.Context) {
+	request, ok := valuationRequest(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "xlsx")
+
+	file, err := h.inventorySnapshotUseCase.ExportInventoryValuation(c.Request.Context(), request, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false, "message": "Unable to export inventory valuation"})
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=inventory_valuation_%s.%s", request.Date, format))
+	c.Data(http.StatusOK, contentType, file)
+}
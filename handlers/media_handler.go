@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"espazeBackend/utils"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHandler struct {
+	mediaUseCase *usecase.MediaUseCase
+}
+
+func NewMediaHandler(mediaUseCase *usecase.MediaUseCase) *MediaHandler {
+	return &MediaHandler{
+		mediaUseCase: mediaUseCase,
+	}
+}
+
+// mediaUser returns the id and role of the user managing images
+func mediaUser(c *gin.Context) (string, string, bool) {
+	role, isPresent := c.Get("role")
+	user_id := c.GetString("user_id")
+	if !isPresent || user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return "", "", false
+	}
+	roleName, _ := role.(string)
+	return user_id, roleName, true
+}
+
+// UploadMedia handles a multipart upload with the image in the 'file' field and owner_type, owner_id and
+// optionally primary as form fields
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	user_id, role, ok := mediaUser(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "File is required",
+			"message": "Upload the image in the 'file' form field",
+		})
+		return
+	}
+	if fileHeader.Size > utils.MaxImageUploadBytes {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "File too large",
+			"message": "Image must be smaller than 10 MB",
+		})
+		return
+	}
+	primary, err := strconv.ParseBool(c.DefaultPostForm("primary", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid primary parameter",
+			"message": "primary must be true or false",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Unable to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, utils.MaxImageUploadBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Unable to read uploaded file",
+		})
+		return
+	}
+
+	media, err := h.mediaUseCase.UploadMedia(c.Request.Context(), &entities.UploadMediaRequest{
+		OwnerType:    c.PostForm("owner_type"),
+		OwnerID:      c.PostForm("owner_id"),
+		Primary:      primary,
+		FileName:     fileHeader.Filename,
+		Data:         data,
+		UploadedBy:   user_id,
+		UploaderRole: role,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to upload image",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Image Uploaded Successfully", "success": true, "data": media})
+}
+
+// GetMediaFile serves an image by id. Stored images never change, so clients may cache them indefinitely.
+func (h *MediaHandler) GetMediaFile(c *gin.Context) {
+	file, rendition, err := h.mediaUseCase.GetMediaFile(c.Request.Context(), c.Param("id"), c.Query("size"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Image not found",
+		})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.DataFromReader(http.StatusOK, int64(rendition.Bytes), rendition.ContentType, file, nil)
+}
+
+func (h *MediaHandler) GetGallery(c *gin.Context) {
+	media, err := h.mediaUseCase.GetGallery(c.Request.Context(), c.Query("owner_type"), c.Query("owner_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get images",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Images Fetched Successfully", "success": true, "data": media})
+}
+
+func (h *MediaHandler) SetPrimaryMedia(c *gin.Context) {
+	user_id, role, ok := mediaUser(c)
+	if !ok {
+		return
+	}
+
+	media, err := h.mediaUseCase.SetPrimaryMedia(c.Request.Context(), c.Param("id"), user_id, role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to set main image",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Main Image Updated Successfully", "success": true, "data": media})
+}
+
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	user_id, role, ok := mediaUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mediaUseCase.DeleteMedia(c.Request.Context(), c.Param("id"), user_id, role); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to delete image",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Image Deleted Successfully", "success": true})
+}
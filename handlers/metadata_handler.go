@@ -143,6 +143,15 @@ func (h *MetadataHandler) GetMetadataByID(c *gin.Context) {
 		})
 		return
 	}
+	// archived metadata is only shown to operations users, who can restore it
+	if result.ArchivedAt != nil && c.GetString("role") != "operations" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Metadata not found: metadata is archived",
+			"message": "Metadata not found",
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -170,6 +179,8 @@ func (h *MetadataHandler) CreateMetadata(c *gin.Context) {
 		return
 	}
 
+	req.UpdatedBy = c.GetString("user_id")
+
 	response, err := h.metadataUseCase.CreateMetadata(c.Request.Context(), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -224,6 +235,8 @@ func (h *MetadataHandler) UpdateMetadata(c *gin.Context) {
 		return
 	}
 
+	req.UpdatedBy = c.GetString("user_id")
+
 	result, err := h.metadataUseCase.UpdateMetadata(c.Request.Context(), id, &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -252,7 +265,7 @@ func (h *MetadataHandler) UpdateMetadata(c *gin.Context) {
 
 }
 
-// DeleteMetadata deletes a metadata by ID
+// DeleteMetadata archives a metadata by ID. cascade=true archives its listings with it.
 func (h *MetadataHandler) DeleteMetadata(c *gin.Context) {
 	role, isPresent := c.Get("role")
 	if role != "operations" || !isPresent {
@@ -272,31 +285,13 @@ func (h *MetadataHandler) DeleteMetadata(c *gin.Context) {
 		return
 	}
 
-	result, err := h.metadataUseCase.DeleteMetadata(c.Request.Context(), id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to delete metadata: " + err.Error(),
-			"message": "Some Internal Server Error Occured",
-		})
-		return
-	}
-	if result.Success {
-		c.JSON(http.StatusOK, gin.H{
-			"success": result.Success,
-			"message": result.Message,
-		})
-		return
-
-	} else {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": result.Success,
-			"message": result.Message,
-			"error":   result.Error,
-		})
+	request, ok := archiveRequest(c)
+	if !ok {
 		return
 	}
 
+	report, err := h.metadataUseCase.DeleteMetadata(c.Request.Context(), id, request)
+	respondArchive(c, "Metadata", report, err)
 }
 
 func (h *MetadataHandler) AddReview(c *gin.Context) {
@@ -307,6 +302,16 @@ func (h *MetadataHandler) AddReview(c *gin.Context) {
 			"error":   "Invalid request body: " + err.Error(),
 			"message": "Request body is invalid",
 		})
+		return
+	}
+	req.UserID = c.GetString("user_id")
+	if req.UserID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
 	}
 	if req.Rating < 1 || req.Rating > 5 {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -323,6 +328,7 @@ func (h *MetadataHandler) AddReview(c *gin.Context) {
 			"error":   "Failed to add review: " + err.Error(),
 			"message": "Some Internal Server Error Occured",
 		})
+		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -359,3 +365,79 @@ func (h *MetadataHandler) GetMetadataForSubcategories(c *gin.Context) {
 		"data":    result,
 	})
 }
+
+// GetMetadataRevisions lists the edit history of a metadata, newest first
+func (h *MetadataHandler) GetMetadataRevisions(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+
+	revisions, err := h.metadataUseCase.GetMetadataRevisions(c.Request.Context(), c.Param("id"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get revisions",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Revisions Fetched Successfully", "success": true, "data": revisions})
+}
+
+func (h *MetadataHandler) GetMetadataRevision(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "revision must be a number",
+			"message": "Invalid revision",
+		})
+		return
+	}
+
+	metadataRevision, err := h.metadataUseCase.GetMetadataRevision(c.Request.Context(), c.Param("id"), revision)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get revision",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Revision Fetched Successfully", "success": true, "data": metadataRevision})
+}
+
+// RollbackMetadata restores a metadata to one of its revisions
+func (h *MetadataHandler) RollbackMetadata(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.RollbackMetadataRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.EditedBy = operational_id
+
+	metadata, err := h.metadataUseCase.RollbackMetadata(c.Request.Context(), c.Param("id"), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to roll back metadata",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Metadata Rolled Back Successfully", "success": true, "data": metadata})
+}
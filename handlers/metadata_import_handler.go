@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"espazeBackend/usecase"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxMetadataSheetSize caps metadata import sheets at 10 MB
+const maxMetadataSheetSize = 10 << 20
+
+type MetadataImportHandler struct {
+	metadataImportUseCase *usecase.MetadataImportUseCase
+}
+
+func NewMetadataImportHandler(metadataImportUseCase *usecase.MetadataImportUseCase) *MetadataImportHandler {
+	return &MetadataImportHandler{
+		metadataImportUseCase: metadataImportUseCase,
+	}
+}
+
+// ImportMetadata starts a background import of the .xlsx/.csv sheet in the 'file' form field. mode is create,
+// the default, or upsert to also update the metadata already holding an HSN code.
+func (h *MetadataImportHandler) ImportMetadata(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "File is required",
+			"message": "Upload the sheet in the 'file' form field",
+		})
+		return
+	}
+	if fileHeader.Size > maxMetadataSheetSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "File too large",
+			"message": "Sheet must be smaller than 10 MB",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Unable to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	job, err := h.metadataImportUseCase.StartImport(c.Request.Context(), operational_id, fileHeader.Filename, file, c.DefaultQuery("mode", c.PostForm("mode")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Unable to start metadata import",
+		})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Metadata Import Started", "success": true, "data": job})
+}
+
+func (h *MetadataImportHandler) GetImportJobs(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+
+	jobs, total, err := h.metadataImportUseCase.GetImportJobs(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get import jobs",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Import Jobs Fetched Successfully", "success": true, "data": jobs, "total": total})
+}
+
+func (h *MetadataImportHandler) GetImportJob(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	job, err := h.metadataImportUseCase.GetImportJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get import job",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Import Job Fetched Successfully", "success": true, "data": job})
+}
+
+func (h *MetadataImportHandler) ExportImportErrors(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "xlsx")
+
+	file, err := h.metadataImportUseCase.ExportImportErrors(c.Request.Context(), c.Param("id"), format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false, "message": "Unable to export import errors"})
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=metadata_import_errors_%s.%s", c.Param("id"), format))
+	c.Data(http.StatusOK, contentType, file)
+}
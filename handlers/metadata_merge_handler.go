@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetadataMergeHandler struct {
+	metadataMergeUseCase *usecase.MetadataMergeUseCase
+}
+
+func NewMetadataMergeHandler(metadataMergeUseCase *usecase.MetadataMergeUseCase) *MetadataMergeHandler {
+	return &MetadataMergeHandler{
+		metadataMergeUseCase: metadataMergeUseCase,
+	}
+}
+
+// GetDuplicates lists likely duplicate metadata, optionally within subcategory_id, scoring at least min_score
+func (h *MetadataMergeHandler) GetDuplicates(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	minScore, _ := strconv.ParseFloat(c.DefaultQuery("min_score", "0"), 64)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+
+	candidates, err := h.metadataMergeUseCase.FindDuplicates(c.Request.Context(), &entities.FindDuplicatesRequest{
+		SubcategoryID: c.Query("subcategory_id"),
+		MinScore:      minScore,
+		Limit:         limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to find duplicates",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Duplicates Fetched Successfully", "success": true, "data": candidates})
+}
+
+func (h *MetadataMergeHandler) DismissDuplicate(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.DismissDuplicateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.DismissedBy = operational_id
+
+	if err := h.metadataMergeUseCase.DismissDuplicate(c.Request.Context(), &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to dismiss duplicate",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Duplicate Dismissed Successfully", "success": true})
+}
+
+// MergeMetadata folds merged_id into survivor_id and deletes merged_id
+func (h *MetadataMergeHandler) MergeMetadata(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.MergeMetadataRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.MergedBy = operational_id
+
+	merge, err := h.metadataMergeUseCase.MergeMetadata(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to merge metadata",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Metadata Merged Successfully", "success": true, "data": merge})
+}
+
+func (h *MetadataMergeHandler) GetMerges(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+
+	merges, total, err := h.metadataMergeUseCase.GetMetadataMerges(c.Request.Context(), c.Query("metadata_id"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get merges",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Merges Fetched Successfully", "success": true, "data": merges, "total": total})
+}
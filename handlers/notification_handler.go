@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationUseCase *usecase.NotificationUseCase
+}
+
+func NewNotificationHandler(notificationUseCase *usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "10")
+	offsetStr := c.DefaultQuery("offset", "0")
+	unreadOnly := c.Query("unread") == "true"
+	userId, isPresent := c.Get("user_id")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	user_id, ok := userId.(string)
+	if !ok || user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid limit parameter",
+			"message": "Limit parameter is invalid",
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid offset parameter",
+			"message": "Offset parameter is invalid",
+		})
+		return
+	}
+
+	notifications, err := h.notificationUseCase.GetNotifications(c.Request.Context(), user_id, offset, limit, unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false, "message": "Failed to get notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": notifications, "success": true})
+}
+
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userId, isPresent := c.Get("user_id")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	user_id, ok := userId.(string)
+	if !ok || user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	err := h.notificationUseCase.MarkNotificationRead(c.Request.Context(), user_id, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false, "message": "Failed to update notification"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read", "success": true})
+}
+
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userId, isPresent := c.Get("user_id")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	user_id, ok := userId.(string)
+	if !ok || user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	updated, err := h.notificationUseCase.MarkAllNotificationsRead(c.Request.Context(), user_id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false, "message": "Failed to update notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read", "success": true, "data": gin.H{"updated": updated}})
+}
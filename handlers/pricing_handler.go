@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PricingHandler struct {
+	pricingUseCase *usecase.PricingUseCase
+}
+
+func NewPricingHandler(pricingUseCase *usecase.PricingUseCase) *PricingHandler {
+	return &PricingHandler{
+		pricingUseCase: pricingUseCase,
+	}
+}
+
+func (h *PricingHandler) GetPricingGuardrails(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || (role != "admin" && role != "operations") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Token or User Role is invalid",
+		})
+		return
+	}
+
+	guardrails, err := h.pricingUseCase.GetPricingGuardrails(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false, "message": "Failed to get pricing guardrails"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": guardrails, "success": true})
+}
+
+func (h *PricingHandler) UpsertPricingGuardrail(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only admins can change pricing guardrails",
+		})
+		return
+	}
+
+	var guardrail entities.PricingGuardrail
+	if err := c.ShouldBindJSON(&guardrail); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	guardrail.UpdatedBy = c.GetString("user_id")
+
+	updated, err := h.pricingUseCase.UpsertPricingGuardrail(c.Request.Context(), &guardrail)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to save pricing guardrail",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Pricing Guardrail Saved Successfully", "success": true, "data": updated})
+}
+
+func (h *PricingHandler) DeletePricingGuardrail(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only admins can change pricing guardrails",
+		})
+		return
+	}
+
+	// An empty subcategory_id deletes the default guardrail
+	err := h.pricingUseCase.DeletePricingGuardrail(c.Request.Context(), c.Query("subcategory_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to delete pricing guardrail",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Pricing Guardrail Deleted Successfully", "success": true})
+}
+
+func (h *PricingHandler) GetPriceHistory(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "10")
+	offsetStr := c.DefaultQuery("offset", "0")
+	role, isPresent := c.Get("role")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	// Sellers only see the history of their own products
+	sellerId := ""
+	if role == "seller" {
+		sellerId = c.GetString("user_id")
+	} else if role != "operations" && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view price history",
+		})
+		return
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid limit parameter",
+			"message": "Limit parameter is invalid",
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid offset parameter",
+			"message": "Offset parameter is invalid",
+		})
+		return
+	}
+
+	history, err := h.pricingUseCase.GetPriceHistory(c.Request.Context(), c.Query("inventory_product_id"), sellerId, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get price history",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": history, "success": true})
+}
+
+func (h *PricingHandler) GetPriceChangeRequests(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "10")
+	offsetStr := c.DefaultQuery("offset", "0")
+	role, isPresent := c.Get("role")
+	user_id := c.GetString("user_id")
+	if !isPresent || user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid limit parameter",
+			"message": "Limit parameter is invalid",
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid offset parameter",
+			"message": "Offset parameter is invalid",
+		})
+		return
+	}
+
+	request := &entities.GetPriceChangeRequestsRequest{
+		Status: c.DefaultQuery("status", entities.PriceChangePending),
+		Offset: offset,
+		Limit:  limit,
+	}
+	switch role {
+	case "operations":
+		request.OperationalID = user_id
+	case "seller":
+		request.SellerID = user_id
+	default:
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view price change requests",
+		})
+		return
+	}
+
+	requests, err := h.pricingUseCase.GetPriceChangeRequests(c.Request.Context(), request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "success": false, "message": "Failed to get price change requests"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": requests, "success": true})
+}
+
+func (h *PricingHandler) ReviewPriceChangeRequest(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var review entities.ReviewPriceChangeRequest
+	if err := c.ShouldBindJSON(&review); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	review.RequestID = c.Param("id")
+	review.ReviewerID = operational_id
+
+	request, err := h.pricingUseCase.ReviewPriceChangeRequest(c.Request.Context(), &review)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to review price change request",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Price Change Request Reviewed Successfully", "success": true, "data": request})
+}
+
+func (h *PricingHandler) CreateScheduledPrice(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "seller" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only sellers can schedule prices",
+		})
+		return
+	}
+
+	var request entities.CreateScheduledPriceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.SellerID = c.GetString("user_id")
+
+	scheduledPrice, err := h.pricingUseCase.CreateScheduledPrice(c.Request.Context(), &request)
+	if err != nil {
+		message := "Failed to schedule price"
+		if errors.Is(err, usecase.ErrPriceGuardrail) {
+			message = "Scheduled price breaks the pricing guardrails"
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": message,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Price Scheduled Successfully", "success": true, "data": scheduledPrice})
+}
+
+func (h *PricingHandler) GetScheduledPrices(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	// Sellers only see the scheduled prices of their own products
+	sellerId := ""
+	if role == "seller" {
+		sellerId = c.GetString("user_id")
+	} else if role != "operations" && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view scheduled prices",
+		})
+		return
+	}
+
+	scheduledPrices, err := h.pricingUseCase.GetScheduledPrices(c.Request.Context(), c.Query("inventory_product_id"), sellerId, c.Query("include_ended") == "true")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get scheduled prices",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": scheduledPrices, "success": true})
+}
+
+func (h *PricingHandler) CancelScheduledPrice(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "seller" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only sellers can cancel scheduled prices",
+		})
+		return
+	}
+
+	scheduledPrice, err := h.pricingUseCase.CancelScheduledPrice(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to cancel scheduled price",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Scheduled Price Cancelled Successfully", "success": true, "data": scheduledPrice})
+}
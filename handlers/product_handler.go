@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"espazeBackend/domain/entities"
 	"espazeBackend/usecase"
@@ -87,7 +88,7 @@ func (h *ProductHandler) GetAllProductsForSubcategory(c *gin.Context) {
 		})
 	}
 
-	response, err := h.productUseCase.GetAllProductsForSubcategory(c.Request.Context(), store_id, warehouse_id, subcategory_id)
+	response, err := h.productUseCase.GetAllProductsForSubcategory(c.Request.Context(), store_id, warehouse_id, subcategory_id, c.Request.URL.Query())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -103,6 +104,44 @@ func (h *ProductHandler) GetAllProductsForSubcategory(c *gin.Context) {
 	})
 }
 
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	response, err := h.productUseCase.SearchProducts(c.Request.Context(), c.Query("storeId"), c.Query("warehouseId"), c.Query("search"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to search products",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+func (h *ProductHandler) Autocomplete(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+
+	response, err := h.productUseCase.Autocomplete(c.Request.Context(), c.Query("warehouseId"), c.Query("q"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get suggestions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
 func (h *ProductHandler) GetBasicDetailsForProduct(c *gin.Context) {
 	inventory_product_id := c.Query("inventory_product_id")
 	if inventory_product_id == "" {
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductReviewHandler struct {
+	productReviewUseCase *usecase.ProductReviewUseCase
+}
+
+func NewProductReviewHandler(productReviewUseCase *usecase.ProductReviewUseCase) *ProductReviewHandler {
+	return &ProductReviewHandler{
+		productReviewUseCase: productReviewUseCase,
+	}
+}
+
+// customerUser returns the user_id of the customer making the request, or writes the error response
+func customerUser(c *gin.Context) (string, bool) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "customer" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only customers can perform this action",
+		})
+		return "", false
+	}
+	user_id := c.GetString("user_id")
+	if user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return "", false
+	}
+	return user_id, true
+}
+
+func (h *ProductReviewHandler) SubmitReview(c *gin.Context) {
+	user_id, ok := customerUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.SubmitProductReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.UserID = user_id
+
+	review, err := h.productReviewUseCase.SubmitReview(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to submit review",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Review Submitted Successfully", "success": true, "data": review})
+}
+
+func (h *ProductReviewHandler) GetProductReviews(c *gin.Context) {
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+
+	reviews, err := h.productReviewUseCase.GetProductReviews(c.Request.Context(), c.Param("metadataId"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get reviews",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Reviews Fetched Successfully", "success": true, "data": reviews})
+}
+
+func (h *ProductReviewHandler) GetMyReviews(c *gin.Context) {
+	user_id, ok := customerUser(c)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.productReviewUseCase.GetUserReviews(c.Request.Context(), user_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get reviews",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Reviews Fetched Successfully", "success": true, "data": reviews})
+}
+
+func (h *ProductReviewHandler) GetModerationQueue(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+
+	reviews, total, err := h.productReviewUseCase.GetModerationQueue(c.Request.Context(), c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get moderation queue",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Moderation Queue Fetched Successfully", "success": true, "data": reviews, "total": total})
+}
+
+func (h *ProductReviewHandler) ModerateReview(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.ModerateProductReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.ReviewID = c.Param("id")
+	request.ModeratorID = operational_id
+
+	review, err := h.productReviewUseCase.ModerateReview(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to moderate review",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Review Moderated Successfully", "success": true, "data": review})
+}
+
+// DeleteReview lets customers delete their own reviews and operations any review
+func (h *ProductReviewHandler) DeleteReview(c *gin.Context) {
+	user_id, role, ok := mediaUser(c)
+	if !ok {
+		return
+	}
+	switch role {
+	case "operations":
+		user_id = ""
+	case "customer":
+	default:
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to delete reviews",
+		})
+		return
+	}
+
+	if err := h.productReviewUseCase.DeleteReview(c.Request.Context(), c.Param("id"), user_id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to delete review",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Review Deleted Successfully", "success": true})
+}
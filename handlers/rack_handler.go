@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RackHandler struct {
+	rackUseCase *usecase.RackUseCase
+}
+
+func NewRackHandler(rackUseCase *usecase.RackUseCase) *RackHandler {
+	return &RackHandler{
+		rackUseCase: rackUseCase,
+	}
+}
+
+func (h *RackHandler) CreateRack(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.CreateRackRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.OperationalID = operational_id
+
+	rack, err := h.rackUseCase.CreateRack(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to create rack",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Rack Created Successfully", "success": true, "data": rack})
+}
+
+func (h *RackHandler) UpdateRack(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.UpdateRackRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.RackID = c.Param("id")
+	request.OperationalID = operational_id
+
+	rack, err := h.rackUseCase.UpdateRack(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to update rack",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Rack Updated Successfully", "success": true, "data": rack})
+}
+
+func (h *RackHandler) DeleteRack(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.rackUseCase.DeleteRack(c.Request.Context(), c.Param("id"), operational_id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to delete rack",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Rack Deleted Successfully", "success": true})
+}
+
+func (h *RackHandler) GetRacks(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	racks, err := h.rackUseCase.GetRacks(c.Request.Context(), c.Query("store_id"), operational_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get racks",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Racks Fetched Successfully", "success": true, "data": racks})
+}
+
+func (h *RackHandler) GetRackById(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	rack, err := h.rackUseCase.GetRackById(c.Request.Context(), c.Param("id"), operational_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get rack",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Rack Fetched Successfully", "success": true, "data": rack})
+}
+
+func (h *RackHandler) AssignRackBin(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.AssignRackBinRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.OperationalID = operational_id
+
+	product, err := h.rackUseCase.AssignRackBin(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to assign product to rack",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Product Assigned Successfully", "success": true, "data": product})
+}
+
+func (h *RackHandler) FindProductLocations(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	request := entities.FindProductLocationRequest{
+		WarehouseID:   c.Query("warehouse_id"),
+		Search:        c.Query("search"),
+		OperationalID: operational_id,
+	}
+	locations, err := h.rackUseCase.FindProductLocations(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to find product",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Product Locations Fetched Successfully", "success": true, "data": locations})
+}
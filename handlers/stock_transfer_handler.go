@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockTransferHandler struct {
+	stockTransferUseCase *usecase.StockTransferUseCase
+}
+
+func NewStockTransferHandler(stockTransferUseCase *usecase.StockTransferUseCase) *StockTransferHandler {
+	return &StockTransferHandler{
+		stockTransferUseCase: stockTransferUseCase,
+	}
+}
+
+// operationalUser returns the id of the authenticated operations user, writing the error response when there is none
+func operationalUser(c *gin.Context) (string, bool) {
+	role, isPresent := c.Get("role")
+	if !isPresent || role != "operations" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Only operations users can perform this action",
+		})
+		return "", false
+	}
+	operational_id := c.GetString("user_id")
+	if operational_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return "", false
+	}
+	return operational_id, true
+}
+
+func (h *StockTransferHandler) CreateStockTransfer(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.CreateStockTransferRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.OperationalID = operational_id
+
+	transfer, err := h.stockTransferUseCase.CreateStockTransfer(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to create transfer",
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Transfer Dispatched Successfully", "success": true, "data": transfer})
+}
+
+func (h *StockTransferHandler) ReceiveStockTransfer(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.stockTransferUseCase.ReceiveStockTransfer(c.Request.Context(), c.Param("id"), operational_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to receive transfer",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Transfer Received Successfully", "success": true, "data": transfer})
+}
+
+func (h *StockTransferHandler) CancelStockTransfer(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.stockTransferUseCase.CancelStockTransfer(c.Request.Context(), c.Param("id"), operational_id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to cancel transfer",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Transfer Cancelled Successfully", "success": true, "data": transfer})
+}
+
+func (h *StockTransferHandler) GetStockTransferById(c *gin.Context) {
+	role, isPresent := c.Get("role")
+	if !isPresent || (role != "operations" && role != "seller") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid token or user role",
+			"message": "Token or User Role is invalid",
+		})
+		return
+	}
+	sellerId, operationalId := "", ""
+	if role == "seller" {
+		sellerId = c.GetString("user_id")
+	} else {
+		operationalId = c.GetString("user_id")
+	}
+
+	transfer, err := h.stockTransferUseCase.GetStockTransferById(c.Request.Context(), c.Param("id"), sellerId, operationalId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get transfer",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": transfer, "success": true})
+}
+
+func (h *StockTransferHandler) GetStockTransfers(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "10")
+	offsetStr := c.DefaultQuery("offset", "0")
+	role, isPresent := c.Get("role")
+	user_id := c.GetString("user_id")
+	if !isPresent || user_id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid token",
+			"message": "Token is invalid",
+		})
+		return
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid limit parameter",
+			"message": "Limit parameter is invalid",
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid offset parameter",
+			"message": "Offset parameter is invalid",
+		})
+		return
+	}
+
+	request := &entities.GetStockTransfersRequest{
+		StoreID: c.Query("store_id"),
+		Status:  c.Query("status"),
+		Offset:  offset,
+		Limit:   limit,
+	}
+	switch role {
+	case "operations":
+		request.OperationalID = user_id
+	case "seller":
+		request.SellerID = user_id
+	default:
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Invalid user role",
+			"message": "User role is not allowed to view transfers",
+		})
+		return
+	}
+
+	transfers, err := h.stockTransferUseCase.GetStockTransfers(c.Request.Context(), request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false, "message": "Failed to get transfers"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": transfers, "success": true})
+}
@@ -171,7 +171,7 @@ func (h *StoreHandler) UpdateStore(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// DeleteStore handles DELETE /stores/:id - Delete a store
+// DeleteStore handles DELETE /stores/:id?cascade=true - Archive a store
 func (h *StoreHandler) DeleteStore(c *gin.Context) {
 	storeId := c.Param("id")
 	if storeId == "" {
@@ -183,17 +183,13 @@ func (h *StoreHandler) DeleteStore(c *gin.Context) {
 		return
 	}
 
-	response, err := h.storeUseCase.DeleteStore(c.Request.Context(), storeId)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Failed to delete store",
-			"message": err.Error(),
-		})
+	request, ok := archiveRequest(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	report, err := h.storeUseCase.DeleteStore(c.Request.Context(), storeId, request)
+	respondArchive(c, "Store", report, err)
 }
 
 // GetStoreBySellerId handles GET /stores/seller/:seller_id - Get store by seller ID
@@ -220,44 +216,3 @@ func (h *StoreHandler) GetStoreBySellerId(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
-
-// UpdateStoreRacks handles PATCH /stores/:id/racks - Update store occupied racks
-func (h *StoreHandler) UpdateStoreRacks(c *gin.Context) {
-	storeId := c.Param("id")
-	if storeId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "store_id is required",
-			"message": "Store ID must be provided in the URL path",
-		})
-		return
-	}
-
-	var request struct {
-		OccupiedRacks int `json:"occupied_racks" binding:"required,min=0"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Validation error",
-			"message": err.Error(),
-		})
-		return
-	}
-
-	err := h.storeUseCase.UpdateStoreRacks(c.Request.Context(), storeId, request.OccupiedRacks)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Failed to update store racks",
-			"message": err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Store racks updated successfully",
-	})
-}
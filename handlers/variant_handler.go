@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"espazeBackend/domain/entities"
+	"espazeBackend/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VariantHandler struct {
+	variantUseCase *usecase.VariantUseCase
+}
+
+func NewVariantHandler(variantUseCase *usecase.VariantUseCase) *VariantHandler {
+	return &VariantHandler{
+		variantUseCase: variantUseCase,
+	}
+}
+
+func (h *VariantHandler) CreateVariantGroup(c *gin.Context) {
+	operational_id, ok := operationalUser(c)
+	if !ok {
+		return
+	}
+
+	var request entities.CreateVariantGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.OperationalID = operational_id
+
+	group, err := h.variantUseCase.CreateVariantGroup(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to create variant group",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Variant Group Created Successfully", "success": true, "data": group})
+}
+
+func (h *VariantHandler) UpdateVariantGroup(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	var request entities.UpdateVariantGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+	request.VariantGroupID = c.Param("id")
+
+	group, err := h.variantUseCase.UpdateVariantGroup(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to update variant group",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Variant Group Updated Successfully", "success": true, "data": group})
+}
+
+func (h *VariantHandler) DeleteVariantGroup(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	if err := h.variantUseCase.DeleteVariantGroup(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to delete variant group",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Variant Group Deleted Successfully", "success": true})
+}
+
+func (h *VariantHandler) GetVariantGroups(c *gin.Context) {
+	groups, err := h.variantUseCase.GetVariantGroups(c.Request.Context(), c.Query("subcategory_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get variant groups",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Variant Groups Fetched Successfully", "success": true, "data": groups})
+}
+
+func (h *VariantHandler) GetVariantGroupById(c *gin.Context) {
+	group, err := h.variantUseCase.GetVariantGroupById(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to get variant group",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Variant Group Fetched Successfully", "success": true, "data": group})
+}
+
+func (h *VariantHandler) AddVariant(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	var request entities.AddVariantRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	group, err := h.variantUseCase.AddVariant(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to add variant",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Variant Added Successfully", "success": true, "data": group})
+}
+
+func (h *VariantHandler) RemoveVariant(c *gin.Context) {
+	if _, ok := operationalUser(c); !ok {
+		return
+	}
+
+	if err := h.variantUseCase.RemoveVariant(c.Request.Context(), c.Param("metadataId")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Failed to remove variant",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Variant Removed Successfully", "success": true})
+}
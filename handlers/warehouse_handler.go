@@ -140,18 +140,11 @@ func (h *WarehouseHandler) DeleteWarehouse(c *gin.Context) {
 		return
 	}
 
-	err := h.warehouseUseCase.DeleteWarehouse(c.Request.Context(), id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Internal server error",
-			"message": "Failed to delete warehouse",
-		})
+	request, ok := archiveRequest(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Warehouse deleted successfully",
-	})
+	report, err := h.warehouseUseCase.DeleteWarehouse(c.Request.Context(), id, request)
+	respondArchive(c, "Warehouse", report, err)
 }
@@ -0,0 +1,429 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ArchiveRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewArchiveRepositoryMongoDB(db *mongo.Database) repositories.ArchiveRepository {
+	return &ArchiveRepositoryMongoDB{db: db}
+}
+
+// archiveCollections maps each archive kind to its collection and the field holding the record's name
+var archiveCollections = map[string]struct{ collection, nameField string }{
+	entities.ArchiveKindMetadata:  {"metadata", "metadata_name"},
+	entities.ArchiveKindCategory:  {"categories", "category_name"},
+	entities.ArchiveKindStore:     {"stores", "store_name"},
+	entities.ArchiveKindWarehouse: {"warehouses", "warehouseName"},
+}
+
+// archiveCascadeCollections are the collections a cascade archives records of
+var archiveCascadeCollections = []string{"subcategories", "metadata", "stores", "inventory_product"}
+
+// withoutArchived leaves archived records out of a filter
+func withoutArchived(filter bson.M) bson.M {
+	filter["archived_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// archivePlan is what archiving a record involves: the records referencing it and the ones a cascade archives
+type archivePlan struct {
+	dependencies []*entities.Dependency
+	cascades     []*archiveCascade
+}
+
+type archiveCascade struct {
+	collection string
+	filter     bson.M
+}
+
+// add counts the records of collection matching filter as a dependency of the plan
+func (p *archivePlan) add(ctx context.Context, db *mongo.Database, collection string, filter bson.M, effect, reason string) error {
+	count, err := db.Collection(collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+	p.dependencies = append(p.dependencies, &entities.Dependency{Collection: collection, Count: count, Effect: effect, Reason: reason})
+	if effect == entities.DependencyCascades {
+		p.cascades = append(p.cascades, &archiveCascade{collection: collection, filter: filter})
+	}
+	return nil
+}
+
+// distinctIds lists the ids of the records of collection matching filter as hex strings
+func distinctIds(ctx context.Context, db *mongo.Database, collection string, filter bson.M) ([]string, error) {
+	values, err := db.Collection(collection).Distinct(ctx, "_id", filter)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		switch id := value.(type) {
+		case primitive.ObjectID:
+			ids = append(ids, id.Hex())
+		case string:
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func planMetadataArchive(ctx context.Context, db *mongo.Database, plan *archivePlan, metadataIds []string) error {
+	stocked := withoutArchived(bson.M{"metadata_product_id": bson.M{"$in": metadataIds}, "product_quantity": bson.M{"$gt": 0}})
+	if err := plan.add(ctx, db, "inventory_product", stocked, entities.DependencyBlocks, "seller listings with stock on hand, to be sold, transferred or written off first"); err != nil {
+		return err
+	}
+	empty := withoutArchived(bson.M{"metadata_product_id": bson.M{"$in": metadataIds}, "product_quantity": bson.M{"$lte": 0}})
+	if err := plan.add(ctx, db, "inventory_product", empty, entities.DependencyCascades, "seller listings without stock, hidden from customers once archived"); err != nil {
+		return err
+	}
+	// order lines placed before they recorded their metadata id only point at the listing
+	listingIds, err := distinctIds(ctx, db, "inventory_product", bson.M{"metadata_product_id": bson.M{"$in": metadataIds}})
+	if err != nil {
+		return err
+	}
+	orderLines := bson.M{"$or": bson.A{
+		bson.M{"metadata_product_id": bson.M{"$in": metadataIds}},
+		bson.M{"product_id": bson.M{"$in": listingIds}},
+	}}
+	if err := plan.add(ctx, db, "orderedItems", orderLines, entities.DependencyKept, "order lines keep the product they were placed for"); err != nil {
+		return err
+	}
+	return plan.add(ctx, db, "barcodes", bson.M{"metadata_product_id": bson.M{"$in": metadataIds}}, entities.DependencyKept, "barcodes stay reserved for the product but stop resolving in lookups")
+}
+
+func planCategoryArchive(ctx context.Context, db *mongo.Database, plan *archivePlan, categoryId string) error {
+	if err := plan.add(ctx, db, "subcategories", withoutArchived(bson.M{"category_id": categoryId}), entities.DependencyCascades, "subcategories of the category"); err != nil {
+		return err
+	}
+	metadataFilter := withoutArchived(bson.M{"metadata_category_id": categoryId})
+	if err := plan.add(ctx, db, "metadata", metadataFilter, entities.DependencyCascades, "products catalogued under the category"); err != nil {
+		return err
+	}
+	metadataIds, err := distinctIds(ctx, db, "metadata", metadataFilter)
+	if err != nil || len(metadataIds) == 0 {
+		return err
+	}
+	return planMetadataArchive(ctx, db, plan, metadataIds)
+}
+
+func planStoreArchive(ctx context.Context, db *mongo.Database, plan *archivePlan, storeIds []string) error {
+	inventoryIds, err := distinctIds(ctx, db, "inventory", bson.M{"store_id": bson.M{"$in": storeIds}})
+	if err != nil {
+		return err
+	}
+	if len(inventoryIds) > 0 {
+		stocked := withoutArchived(bson.M{"inventory_id": bson.M{"$in": inventoryIds}, "product_quantity": bson.M{"$gt": 0}})
+		if err := plan.add(ctx, db, "inventory_product", stocked, entities.DependencyBlocks, "listings with stock on hand, to be transferred or written off first"); err != nil {
+			return err
+		}
+		empty := withoutArchived(bson.M{"inventory_id": bson.M{"$in": inventoryIds}, "product_quantity": bson.M{"$lte": 0}})
+		if err := plan.add(ctx, db, "inventory_product", empty, entities.DependencyCascades, "listings without stock, hidden from customers once archived"); err != nil {
+			return err
+		}
+	}
+
+	stores := bson.M{"$in": storeIds}
+	if err := plan.add(ctx, db, "inbound_shipments", bson.M{"store_id": stores, "status": entities.InboundShipmentDeclared}, entities.DependencyBlocks, "shipments declared into the store and not received yet"); err != nil {
+		return err
+	}
+	transfers := bson.M{
+		"$or":    bson.A{bson.M{"source_store_id": stores}, bson.M{"destination_store_id": stores}},
+		"status": entities.StockTransferDispatched,
+	}
+	if err := plan.add(ctx, db, "stock_transfers", transfers, entities.DependencyBlocks, "transfers in transit from or to the store"); err != nil {
+		return err
+	}
+	counts := bson.M{"store_id": stores, "status": bson.M{"$in": bson.A{entities.CycleCountOpen, entities.CycleCountInReview}}}
+	if err := plan.add(ctx, db, "cycle_count_sessions", counts, entities.DependencyBlocks, "cycle counts still running in the store"); err != nil {
+		return err
+	}
+	return plan.add(ctx, db, "racks", bson.M{"store_id": stores}, entities.DependencyKept, "racks of the store")
+}
+
+func planWarehouseArchive(ctx context.Context, db *mongo.Database, plan *archivePlan, warehouseId string) error {
+	storeFilter := withoutArchived(bson.M{"warehouse_id": warehouseId})
+	if err := plan.add(ctx, db, "stores", storeFilter, entities.DependencyCascades, "stores in the warehouse"); err != nil {
+		return err
+	}
+	storeIds, err := distinctIds(ctx, db, "stores", storeFilter)
+	if err != nil {
+		return err
+	}
+	if len(storeIds) > 0 {
+		if err := planStoreArchive(ctx, db, plan, storeIds); err != nil {
+			return err
+		}
+	}
+	if err := plan.add(ctx, db, "operational_guys", bson.M{"warehouseId": warehouseId}, entities.DependencyKept, "operations users assigned to the warehouse"); err != nil {
+		return err
+	}
+	return plan.add(ctx, db, "order", bson.M{"warehouse_id": warehouseId}, entities.DependencyKept, "orders placed from the warehouse")
+}
+
+// findArchivable reads the archived state of a record, failing when the record does not exist
+func findArchivable(ctx context.Context, db *mongo.Database, kind, id string) (primitive.ObjectID, *entities.ArchivedRecord, error) {
+	target, ok := archiveCollections[kind]
+	if !ok {
+		return primitive.NilObjectID, nil, fmt.Errorf("records of kind %s cannot be archived", kind)
+	}
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, nil, fmt.Errorf("invalid %s id", kind)
+	}
+	var record entities.ArchivedRecord
+	err = db.Collection(target.collection).FindOne(ctx, bson.M{"_id": objectId}, options.FindOne().SetProjection(bson.M{
+		"archived_at":   1,
+		"archived_by":   1,
+		"archived_with": 1,
+	})).Decode(&record)
+	if err == mongo.ErrNoDocuments {
+		return primitive.NilObjectID, nil, fmt.Errorf("%s not found", kind)
+	}
+	if err != nil {
+		return primitive.NilObjectID, nil, err
+	}
+	record.Kind = kind
+	return objectId, &record, nil
+}
+
+// dependencyReport checks what references a record and whether it can be archived
+func dependencyReport(ctx context.Context, db *mongo.Database, kind, id string, cascade bool) (*entities.DependencyReport, *archivePlan, error) {
+	_, record, err := findArchivable(ctx, db, kind, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !record.ArchivedAt.IsZero() {
+		return nil, nil, fmt.Errorf("%s is already archived", kind)
+	}
+
+	plan := &archivePlan{}
+	switch kind {
+	case entities.ArchiveKindMetadata:
+		err = planMetadataArchive(ctx, db, plan, []string{id})
+	case entities.ArchiveKindCategory:
+		err = planCategoryArchive(ctx, db, plan, id)
+	case entities.ArchiveKindStore:
+		err = planStoreArchive(ctx, db, plan, []string{id})
+	case entities.ArchiveKindWarehouse:
+		err = planWarehouseArchive(ctx, db, plan, id)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &entities.DependencyReport{Kind: kind, ID: id, Cascade: cascade, Dependencies: plan.dependencies}
+	if report.Dependencies == nil {
+		report.Dependencies = []*entities.Dependency{}
+	}
+	for _, dependency := range plan.dependencies {
+		if dependency.Effect == entities.DependencyBlocks || (dependency.Effect == entities.DependencyCascades && !cascade) {
+			report.Blocked = true
+		}
+	}
+	return report, plan, nil
+}
+
+// archiveRecord soft deletes a record once its dependency check passes, archiving the records that cascade with
+// it. Cascaded records are tagged with the record they went with so a restore brings them back. A blocked
+// report is returned as is, with nothing archived.
+func archiveRecord(ctx context.Context, db *mongo.Database, kind, id string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		report, plan, err := dependencyReport(sc, db, kind, id, request.Cascade)
+		if err != nil {
+			return nil, err
+		}
+		if report.Blocked {
+			return report, nil
+		}
+
+		now := time.Now()
+		objectId, _ := primitive.ObjectIDFromHex(id)
+		archived := bson.M{"archived_at": now, "archived_by": request.ArchivedBy}
+		if _, err := db.Collection(archiveCollections[kind].collection).UpdateByID(sc, objectId, bson.M{"$set": archived}); err != nil {
+			return nil, err
+		}
+
+		cascaded := bson.M{"archived_at": now, "archived_by": request.ArchivedBy, "archived_with": kind + ":" + id}
+		for _, cascade := range plan.cascades {
+			var update interface{} = bson.M{"$set": cascaded}
+			if cascade.collection == "inventory_product" {
+				// listings are hidden the way every product read already expects, keeping their visibility for restore
+				update = mongo.Pipeline{{{Key: "$set", Value: bson.M{
+					"archived_at":         now,
+					"archived_by":         request.ArchivedBy,
+					"archived_with":       kind + ":" + id,
+					"archived_visibility": "$product_visibility",
+					"product_visibility":  false,
+				}}}}
+			}
+			if _, err := db.Collection(cascade.collection).UpdateMany(sc, cascade.filter, update); err != nil {
+				return nil, err
+			}
+		}
+		report.Archived = true
+		return report, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report := result.(*entities.DependencyReport)
+	if report.Archived && (kind == entities.ArchiveKindMetadata || kind == entities.ArchiveKindCategory) {
+		invalidateCatalogSearch()
+	}
+	return report, nil
+}
+
+func (r *ArchiveRepositoryMongoDB) GetDependencies(ctx context.Context, kind, id string, cascade bool) (*entities.DependencyReport, error) {
+	report, _, err := dependencyReport(ctx, r.db, kind, id, cascade)
+	return report, err
+}
+
+func (r *ArchiveRepositoryMongoDB) GetArchivedRecords(ctx context.Context, kind string, limit, offset int64) ([]*entities.ArchivedRecord, int64, error) {
+	target, ok := archiveCollections[kind]
+	if !ok {
+		return nil, 0, fmt.Errorf("records of kind %s cannot be archived", kind)
+	}
+	collection := r.db.Collection(target.collection)
+	filter := bson.M{"archived_at": bson.M{"$exists": true}}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.M{"archived_at": -1}}},
+		{{Key: "$skip", Value: offset}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{
+			"_id":           bson.M{"$toString": "$_id"},
+			"name":          "$" + target.nameField,
+			"archived_at":   1,
+			"archived_by":   1,
+			"archived_with": 1,
+		}}},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	records := []*entities.ArchivedRecord{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, 0, err
+	}
+	for _, record := range records {
+		record.Kind = kind
+	}
+	return records, total, nil
+}
+
+// checkArchiveParents refuses to restore a record while the records it belongs to are still archived
+func checkArchiveParents(ctx context.Context, db *mongo.Database, kind string, objectId primitive.ObjectID) error {
+	type parent struct{ collection, field, name string }
+	var parents []parent
+	switch kind {
+	case entities.ArchiveKindMetadata:
+		parents = []parent{{"categories", "metadata_category_id", "category"}, {"subcategories", "metadata_subcategory_id", "subcategory"}}
+	case entities.ArchiveKindStore:
+		parents = []parent{{"warehouses", "warehouse_id", "warehouse"}}
+	default:
+		return nil
+	}
+
+	var record bson.M
+	if err := db.Collection(archiveCollections[kind].collection).FindOne(ctx, bson.M{"_id": objectId}).Decode(&record); err != nil {
+		return err
+	}
+	for _, p := range parents {
+		parentId, _ := record[p.field].(string)
+		parentObjectId, err := primitive.ObjectIDFromHex(parentId)
+		if err != nil {
+			continue
+		}
+		count, err := db.Collection(p.collection).CountDocuments(ctx, bson.M{"_id": parentObjectId, "archived_at": bson.M{"$exists": true}})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("the %s of this %s is archived, restore it first", p.name, kind)
+		}
+	}
+	return nil
+}
+
+// RestoreRecord brings back an archived record along with the records archived by its cascade
+func (r *ArchiveRepositoryMongoDB) RestoreRecord(ctx context.Context, kind, id string) (*entities.RestoreReport, error) {
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		objectId, record, err := findArchivable(sc, r.db, kind, id)
+		if err != nil {
+			return nil, err
+		}
+		if record.ArchivedAt.IsZero() {
+			return nil, fmt.Errorf("%s is not archived", kind)
+		}
+		if err := checkArchiveParents(sc, r.db, kind, objectId); err != nil {
+			return nil, err
+		}
+
+		unset := bson.M{"$unset": bson.M{"archived_at": "", "archived_by": "", "archived_with": ""}}
+		if _, err := r.db.Collection(archiveCollections[kind].collection).UpdateByID(sc, objectId, unset); err != nil {
+			return nil, err
+		}
+
+		report := &entities.RestoreReport{Kind: kind, ID: id, Restored: map[string]int64{}}
+		filter := bson.M{"archived_with": kind + ":" + id}
+		for _, collection := range archiveCascadeCollections {
+			var update interface{} = unset
+			if collection == "inventory_product" {
+				update = mongo.Pipeline{
+					{{Key: "$set", Value: bson.M{"product_visibility": bson.M{"$ifNull": bson.A{"$archived_visibility", "$product_visibility"}}}}},
+					{{Key: "$unset", Value: bson.A{"archived_at", "archived_by", "archived_with", "archived_visibility"}}},
+				}
+			}
+			restored, err := r.db.Collection(collection).UpdateMany(sc, filter, update)
+			if err != nil {
+				return nil, err
+			}
+			if restored.ModifiedCount > 0 {
+				report.Restored[collection] = restored.ModifiedCount
+			}
+		}
+		return report, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if kind == entities.ArchiveKindMetadata || kind == entities.ArchiveKindCategory {
+		invalidateCatalogSearch()
+	}
+	return result.(*entities.RestoreReport), nil
+}
@@ -0,0 +1,80 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AttributeSchemaRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewAttributeSchemaRepositoryMongoDB(db *mongo.Database) repositories.AttributeSchemaRepository {
+	return &AttributeSchemaRepositoryMongoDB{db: db}
+}
+
+// getAttributeSchema returns the attribute schema of a subcategory, or an empty schema if it has none
+func getAttributeSchema(ctx context.Context, db *mongo.Database, subcategoryId string) (*entities.AttributeSchema, error) {
+	var schema entities.AttributeSchema
+	err := db.Collection("attribute_schemas").FindOne(ctx, bson.M{"subcategory_id": subcategoryId}).Decode(&schema)
+	if err == mongo.ErrNoDocuments {
+		return &entities.AttributeSchema{SubcategoryID: subcategoryId, Attributes: []*entities.AttributeDefinition{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (r *AttributeSchemaRepositoryMongoDB) SubcategoryExists(ctx context.Context, subcategoryId string) (bool, error) {
+	objectId, err := primitive.ObjectIDFromHex(subcategoryId)
+	if err != nil {
+		return false, fmt.Errorf("invalid subcategory id")
+	}
+	count, err := r.db.Collection("subcategories").CountDocuments(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SaveAttributeSchema creates or replaces the schema of a subcategory
+func (r *AttributeSchemaRepositoryMongoDB) SaveAttributeSchema(ctx context.Context, schema *entities.AttributeSchema) (*entities.AttributeSchema, error) {
+	now := time.Now()
+	var saved entities.AttributeSchema
+	err := r.db.Collection("attribute_schemas").FindOneAndUpdate(ctx,
+		bson.M{"subcategory_id": schema.SubcategoryID},
+		bson.M{
+			"$set":         bson.M{"attributes": schema.Attributes, "updated_by": schema.UpdatedBy, "updated_at": now},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (r *AttributeSchemaRepositoryMongoDB) GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error) {
+	return getAttributeSchema(ctx, r.db, subcategoryId)
+}
+
+func (r *AttributeSchemaRepositoryMongoDB) DeleteAttributeSchema(ctx context.Context, subcategoryId string) error {
+	result, err := r.db.Collection("attribute_schemas").DeleteOne(ctx, bson.M{"subcategory_id": subcategoryId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("subcategory has no attribute schema")
+	}
+	return nil
+}
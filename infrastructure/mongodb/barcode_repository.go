@@ -0,0 +1,167 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BarcodeRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewBarcodeRepositoryMongoDB(db *mongo.Database) repositories.BarcodeRepository {
+	return &BarcodeRepositoryMongoDB{db: db}
+}
+
+// getBarcodeCodes lists the barcodes of a metadata product as entered
+func getBarcodeCodes(ctx context.Context, db *mongo.Database, metadataId string) ([]string, error) {
+	cursor, err := db.Collection("barcodes").Find(ctx, bson.M{"metadata_product_id": metadataId}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var barcodes []*entities.Barcode
+	if err := cursor.All(ctx, &barcodes); err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		codes = append(codes, barcode.Code)
+	}
+	return codes, nil
+}
+
+// AddBarcodes ties the barcodes to their metadata product. Barcodes the product already has are skipped, and a
+// barcode of another product fails the whole request.
+func (r *BarcodeRepositoryMongoDB) AddBarcodes(ctx context.Context, barcodes []*entities.Barcode) error {
+	if len(barcodes) == 0 {
+		return nil
+	}
+	metadataObjectId, err := primitive.ObjectIDFromHex(barcodes[0].MetadataProductID)
+	if err != nil {
+		return fmt.Errorf("invalid metadata id")
+	}
+	count, err := r.db.Collection("metadata").CountDocuments(ctx, bson.M{"_id": metadataObjectId})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("metadata not found")
+	}
+
+	gtins := make([]string, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		gtins = append(gtins, barcode.GTIN)
+	}
+	cursor, err := r.db.Collection("barcodes").Find(ctx, bson.M{"_id": bson.M{"$in": gtins}})
+	if err != nil {
+		return err
+	}
+	var existing []*entities.Barcode
+	if err := cursor.All(ctx, &existing); err != nil {
+		return err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, barcode := range existing {
+		if barcode.MetadataProductID != barcodes[0].MetadataProductID {
+			return fmt.Errorf("barcode %s already belongs to another product", barcode.Code)
+		}
+		taken[barcode.GTIN] = true
+	}
+
+	documents := []interface{}{}
+	for _, barcode := range barcodes {
+		if !taken[barcode.GTIN] {
+			documents = append(documents, barcode)
+		}
+	}
+	if len(documents) == 0 {
+		return nil
+	}
+	if _, err := r.db.Collection("barcodes").InsertMany(ctx, documents); err != nil {
+		// another request took one of the codes in the meantime
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("a barcode already belongs to another product")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *BarcodeRepositoryMongoDB) RemoveBarcode(ctx context.Context, gtin string) error {
+	result, err := r.db.Collection("barcodes").DeleteOne(ctx, bson.M{"_id": gtin})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("barcode not found")
+	}
+	return nil
+}
+
+func (r *BarcodeRepositoryMongoDB) GetBarcodesForMetadata(ctx context.Context, metadataId string) ([]*entities.Barcode, error) {
+	cursor, err := r.db.Collection("barcodes").Find(ctx, bson.M{"metadata_product_id": metadataId}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	barcodes := []*entities.Barcode{}
+	if err := cursor.All(ctx, &barcodes); err != nil {
+		return nil, err
+	}
+	return barcodes, nil
+}
+
+// LookupBarcode resolves a barcode to its metadata product and, for a seller, to the seller's own listing of it
+func (r *BarcodeRepositoryMongoDB) LookupBarcode(ctx context.Context, gtin, sellerId string) (*entities.BarcodeLookupResponse, error) {
+	var barcode entities.Barcode
+	if err := r.db.Collection("barcodes").FindOne(ctx, bson.M{"_id": gtin}).Decode(&barcode); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("no product found for this barcode")
+		}
+		return nil, err
+	}
+
+	metadataObjectId, err := primitive.ObjectIDFromHex(barcode.MetadataProductID)
+	if err != nil {
+		return nil, err
+	}
+	var metadata entities.Metadata
+	if err := r.db.Collection("metadata").FindOne(ctx, withoutArchived(bson.M{"_id": metadataObjectId})).Decode(&metadata); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("no product found for this barcode")
+		}
+		return nil, err
+	}
+	response := &entities.BarcodeLookupResponse{Barcode: &barcode, Metadata: &metadata}
+	if sellerId == "" {
+		return response, nil
+	}
+
+	var inventory entities.Inventory
+	err = r.db.Collection("inventory").FindOne(ctx, bson.M{"seller_id": sellerId}).Decode(&inventory)
+	if err == mongo.ErrNoDocuments {
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var inventoryProduct entities.InventoryProduct
+	err = r.db.Collection("inventory_product").FindOne(ctx, bson.M{
+		"inventory_id":        inventory.InventoryID,
+		"metadata_product_id": barcode.MetadataProductID,
+	}).Decode(&inventoryProduct)
+	if err == mongo.ErrNoDocuments {
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	response.InventoryProduct = &inventoryProduct
+	return response, nil
+}
@@ -0,0 +1,351 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CatalogProposalRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewCatalogProposalRepositoryMongoDB(db *mongo.Database) repositories.CatalogProposalRepository {
+	return &CatalogProposalRepositoryMongoDB{db: db}
+}
+
+// listMetadataForSeller adds metadata to the seller's inventory as a hidden listing without stock, price or dates,
+// which the seller fills in when stocking it. It creates the inventory on the seller's first product. A listing the
+// seller already has for the metadata is returned as is.
+func listMetadataForSeller(ctx context.Context, db *mongo.Database, sellerId, metadataId string) (*entities.InventoryProduct, error) {
+	var inventory entities.Inventory
+	err := db.Collection("inventory").FindOne(ctx, bson.M{"seller_id": sellerId}).Decode(&inventory)
+	if err == mongo.ErrNoDocuments {
+		sellerObjectId, err := primitive.ObjectIDFromHex(sellerId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seller id")
+		}
+		var seller entities.Seller
+		if err := db.Collection("sellers").FindOne(ctx, bson.M{"_id": sellerObjectId}).Decode(&seller); err != nil {
+			return nil, fmt.Errorf("seller not found")
+		}
+		inventory = entities.Inventory{SellerID: sellerId, StoreId: seller.StoreID}
+		result, err := db.Collection("inventory").InsertOne(ctx, inventory)
+		if err != nil {
+			return nil, err
+		}
+		inventory.InventoryID = result.InsertedID.(primitive.ObjectID).Hex()
+	} else if err != nil {
+		return nil, err
+	}
+
+	var product entities.InventoryProduct
+	err = db.Collection("inventory_product").FindOne(ctx, bson.M{"inventory_id": inventory.InventoryID, "metadata_product_id": metadataId}).Decode(&product)
+	if err == nil {
+		return &product, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	result, err := db.Collection("inventory_product").InsertOne(ctx, bson.M{
+		"inventory_id":        inventory.InventoryID,
+		"metadata_product_id": metadataId,
+		"product_visibility":  false,
+		"product_quantity":    0,
+	})
+	if err != nil {
+		return nil, err
+	}
+	product = entities.InventoryProduct{
+		InventoryProductID: result.InsertedID.(primitive.ObjectID).Hex(),
+		InventoryID:        inventory.InventoryID,
+		MetadataProductID:  metadataId,
+	}
+	return &product, nil
+}
+
+func (r *CatalogProposalRepositoryMongoDB) SubmitProposal(ctx context.Context, proposal *entities.CatalogProposal) (*entities.CatalogProposal, error) {
+	subcategoryObjectId, err := primitive.ObjectIDFromHex(proposal.SubcategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subcategory id")
+	}
+	var subcategory entities.Subcategory
+	if err := r.db.Collection("subcategories").FindOne(ctx, bson.M{"_id": subcategoryObjectId}).Decode(&subcategory); err != nil {
+		return nil, fmt.Errorf("subcategory not found")
+	}
+	if subcategory.CategoryID != proposal.CategoryID {
+		return nil, fmt.Errorf("subcategory does not belong to the category")
+	}
+
+	var existing entities.Metadata
+	err = r.db.Collection("metadata").FindOne(ctx, bson.M{"hsn_code": proposal.HsnCode}).Decode(&existing)
+	if err == nil {
+		return nil, fmt.Errorf("%s is already in the catalog with this hsn code, add it to your inventory instead", existing.MetadataName)
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	count, err := r.db.Collection("catalog_proposals").CountDocuments(ctx, bson.M{
+		"seller_id": proposal.SellerID,
+		"hsn_code":  proposal.HsnCode,
+		"status":    entities.CatalogProposalPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("you already have a pending proposal for this hsn code")
+	}
+
+	now := time.Now()
+	proposal.Status = entities.CatalogProposalPending
+	proposal.CreatedAt = now
+	proposal.UpdatedAt = now
+	result, err := r.db.Collection("catalog_proposals").InsertOne(ctx, proposal)
+	if err != nil {
+		return nil, err
+	}
+	proposal.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return proposal, nil
+}
+
+// GetProposalById finds a proposal. sellerId limits it to the seller's own proposals and is empty for operations.
+func (r *CatalogProposalRepositoryMongoDB) GetProposalById(ctx context.Context, proposalId, sellerId string) (*entities.CatalogProposal, error) {
+	objectId, err := primitive.ObjectIDFromHex(proposalId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposal id")
+	}
+	filter := bson.M{"_id": objectId}
+	if sellerId != "" {
+		filter["seller_id"] = sellerId
+	}
+
+	var proposal entities.CatalogProposal
+	err = r.db.Collection("catalog_proposals").FindOne(ctx, filter).Decode(&proposal)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("proposal not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+func (r *CatalogProposalRepositoryMongoDB) GetSellerProposals(ctx context.Context, sellerId string) ([]*entities.CatalogProposal, error) {
+	cursor, err := r.db.Collection("catalog_proposals").Find(ctx, bson.M{"seller_id": sellerId}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	proposals := []*entities.CatalogProposal{}
+	if err := cursor.All(ctx, &proposals); err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+// GetProposalQueue lists proposals in a status, oldest first
+func (r *CatalogProposalRepositoryMongoDB) GetProposalQueue(ctx context.Context, status string, limit, offset int64) ([]*entities.CatalogProposal, int64, error) {
+	collection := r.db.Collection("catalog_proposals")
+	filter := bson.M{"status": status}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"created_at": 1}).
+		SetSkip(offset).
+		SetLimit(limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	proposals := []*entities.CatalogProposal{}
+	if err := cursor.All(ctx, &proposals); err != nil {
+		return nil, 0, err
+	}
+	return proposals, total, nil
+}
+
+func (r *CatalogProposalRepositoryMongoDB) WithdrawProposal(ctx context.Context, proposalId, sellerId string) error {
+	objectId, err := primitive.ObjectIDFromHex(proposalId)
+	if err != nil {
+		return fmt.Errorf("invalid proposal id")
+	}
+	result, err := r.db.Collection("catalog_proposals").DeleteOne(ctx, bson.M{
+		"_id":       objectId,
+		"seller_id": sellerId,
+		"status":    entities.CatalogProposalPending,
+	})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("no pending proposal found to withdraw")
+	}
+	return nil
+}
+
+// ReviewProposal applies the admin's decision in one transaction: an approval creates the metadata, a merge
+// points at existing metadata, and both list the product in the seller's inventory before the seller is notified
+func (r *CatalogProposalRepositoryMongoDB) ReviewProposal(ctx context.Context, review *entities.ReviewCatalogProposalRequest) (*entities.CatalogProposal, error) {
+	objectId, err := primitive.ObjectIDFromHex(review.ProposalID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposal id")
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var proposal entities.CatalogProposal
+		if err := r.db.Collection("catalog_proposals").FindOne(sc, bson.M{"_id": objectId}).Decode(&proposal); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("proposal not found")
+			}
+			return nil, err
+		}
+		if proposal.Status != entities.CatalogProposalPending {
+			return nil, fmt.Errorf("proposal is already %s", proposal.Status)
+		}
+
+		now := time.Now()
+		var metadataName string
+		switch review.Decision {
+		case entities.CatalogProposalApprove:
+			count, err := r.db.Collection("metadata").CountDocuments(sc, bson.M{"hsn_code": proposal.HsnCode})
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				return nil, fmt.Errorf("metadata for this hsn code already exists, merge the proposal into it instead")
+			}
+			metadata := &entities.Metadata{
+				MetadataName:          proposal.Name,
+				MetadataHSNCode:       proposal.HsnCode,
+				MetadataDescription:   proposal.Description,
+				MetadataCategoryID:    proposal.CategoryID,
+				MetadataSubcategoryID: proposal.SubcategoryID,
+				MetadataMRP:           proposal.MRP,
+				MetadataGallery:       proposal.Images,
+				MetadataAttributes:    proposal.Attributes,
+				MetadataCreatedAt:     now,
+				MetadataUpdatedAt:     now,
+			}
+			if len(proposal.Images) > 0 {
+				metadata.MetadataImage = proposal.Images[0]
+			}
+			inserted, err := r.db.Collection("metadata").InsertOne(sc, metadata)
+			if err != nil {
+				return nil, err
+			}
+			proposal.MetadataProductID = inserted.InsertedID.(primitive.ObjectID).Hex()
+			metadataName = proposal.Name
+			metadata.MetadataProductID = proposal.MetadataProductID
+			if err := recordMetadataRevision(sc, r.db, nil, metadata, entities.MetadataRevisionProposal, review.ReviewerID, nil); err != nil {
+				return nil, err
+			}
+			if _, err := r.db.Collection("reviews").InsertOne(sc, entities.Review{MetadataProductID: proposal.MetadataProductID}); err != nil {
+				return nil, err
+			}
+			proposal.Status = entities.CatalogProposalApproved
+		case entities.CatalogProposalMerge:
+			metadataObjectId, err := primitive.ObjectIDFromHex(review.MetadataProductID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid metadata product id")
+			}
+			var metadata entities.Metadata
+			if err := r.db.Collection("metadata").FindOne(sc, withoutArchived(bson.M{"_id": metadataObjectId})).Decode(&metadata); err != nil {
+				if err == mongo.ErrNoDocuments {
+					return nil, fmt.Errorf("metadata to merge into not found or archived")
+				}
+				return nil, err
+			}
+			proposal.MetadataProductID = metadata.MetadataProductID
+			metadataName = metadata.MetadataName
+			proposal.Status = entities.CatalogProposalMerged
+		default:
+			proposal.Status = entities.CatalogProposalRejected
+		}
+
+		if proposal.MetadataProductID != "" {
+			product, err := listMetadataForSeller(sc, r.db, proposal.SellerID, proposal.MetadataProductID)
+			if err != nil {
+				return nil, err
+			}
+			proposal.InventoryProductID = product.InventoryProductID
+		}
+
+		proposal.ReviewedBy = review.ReviewerID
+		proposal.ReviewNote = review.Note
+		proposal.ReviewedAt = &now
+		proposal.UpdatedAt = now
+		_, err = r.db.Collection("catalog_proposals").UpdateByID(sc, objectId, bson.M{"$set": bson.M{
+			"status":               proposal.Status,
+			"metadata_product_id":  proposal.MetadataProductID,
+			"inventory_product_id": proposal.InventoryProductID,
+			"reviewed_by":          proposal.ReviewedBy,
+			"review_note":          proposal.ReviewNote,
+			"reviewed_at":          proposal.ReviewedAt,
+			"updated_at":           proposal.UpdatedAt,
+		}})
+		if err != nil {
+			return nil, err
+		}
+
+		var title, message string
+		switch proposal.Status {
+		case entities.CatalogProposalApproved:
+			title = "Product proposal approved"
+			message = fmt.Sprintf("%s was added to the catalog and your inventory", proposal.Name)
+		case entities.CatalogProposalMerged:
+			title = "Product proposal matched"
+			message = fmt.Sprintf("%s is in the catalog as %s and was added to your inventory", proposal.Name, metadataName)
+		default:
+			title = "Product proposal rejected"
+			message = fmt.Sprintf("%s: %s", proposal.Name, review.Note)
+		}
+		if err := insertNotification(sc, r.db, &entities.Notification{
+			UserID:      proposal.SellerID,
+			Type:        entities.NotificationTypeCatalogProposal,
+			Title:       title,
+			Message:     message,
+			ReferenceID: proposal.ID,
+		}); err != nil {
+			return nil, err
+		}
+		return &proposal, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Decision == entities.CatalogProposalApprove {
+		invalidateCatalogSearch()
+	}
+	return result.(*entities.CatalogProposal), nil
+}
+
+func (r *CatalogProposalRepositoryMongoDB) GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error) {
+	return getAttributeSchema(ctx, r.db, subcategoryId)
+}
+
+func (r *CatalogProposalRepositoryMongoDB) GetHSNCode(ctx context.Context, code string) (*entities.HSNCode, error) {
+	return getHSNCode(ctx, r.db, code)
+}
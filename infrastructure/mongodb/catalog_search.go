@@ -0,0 +1,251 @@
+package mongodb
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Catalog search is an in-memory index over metadata name, description, category, subcategory and HSN code.
+// It is built from the database on first use and rebuilt once it is older than catalogSearchTTL or after a
+// catalog write invalidates it, so every instance converges on the catalog within a few minutes.
+
+const catalogSearchTTL = 5 * time.Minute
+
+const (
+	searchFieldName = iota
+	searchFieldHSN
+	searchFieldSubcategory
+	searchFieldCategory
+	searchFieldDescription
+	searchFieldCount
+)
+
+// searchFieldWeights ranks a match on the product name above one on its HSN code, subcategory, category and description
+var searchFieldWeights = [searchFieldCount]float64{10, 6, 4, 3, 1}
+
+const (
+	exactMatchQuality  = 1.0
+	prefixMatchQuality = 0.75
+	fuzzyMatchQuality  = 0.6
+	namePrefixBonus    = 5.0
+)
+
+type catalogSearchEntry struct {
+	metadataId string
+	fields     [searchFieldCount][]string
+}
+
+type catalogSearchIndex struct {
+	entries    []*catalogSearchEntry
+	vocabulary map[string]struct{}
+}
+
+// catalogSearchHit is a metadata document matching a search, ordered by Matched then Score
+type catalogSearchHit struct {
+	MetadataID string
+	Matched    int
+	Score      float64
+}
+
+// catalogSearch holds the index under the empty key
+var catalogSearch = newRebuildCache[*catalogSearchIndex](catalogSearchTTL)
+
+// invalidateCatalogSearch drops the index so the next search rebuilds it from the catalog
+func invalidateCatalogSearch() {
+	catalogSearch.invalidate()
+}
+
+// tokenizeSearchText lowercases text and splits it into letter and digit runs
+func tokenizeSearchText(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func getCatalogSearchIndex(ctx context.Context, db *mongo.Database) (*catalogSearchIndex, error) {
+	return catalogSearch.get(ctx, "", func(ctx context.Context) (*catalogSearchIndex, error) {
+		return buildCatalogSearchIndex(ctx, db)
+	})
+}
+
+func buildCatalogSearchIndex(ctx context.Context, db *mongo.Database) (*catalogSearchIndex, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: withoutArchived(bson.M{})}},
+		{{Key: "$addFields", Value: bson.M{
+			"category_oid":    toObjectIdOrNull("$metadata_category_id"),
+			"subcategory_oid": toObjectIdOrNull("$metadata_subcategory_id"),
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": "categories", "localField": "category_oid", "foreignField": "_id", "as": "category_info"}}},
+		{{Key: "$lookup", Value: bson.M{"from": "subcategories", "localField": "subcategory_oid", "foreignField": "_id", "as": "subcategory_info"}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":              bson.M{"$toString": "$_id"},
+			"name":             "$metadata_name",
+			"description":      "$metadata_description",
+			"hsn_code":         "$hsn_code",
+			"category_name":    bson.M{"$first": "$category_info.category_name"},
+			"subcategory_name": bson.M{"$first": "$subcategory_info.subcategory_name"},
+		}}},
+	}
+	cursor, err := db.Collection("metadata").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var documents []struct {
+		ID              string `bson:"_id"`
+		Name            string `bson:"name"`
+		Description     string `bson:"description"`
+		HSNCode         string `bson:"hsn_code"`
+		CategoryName    string `bson:"category_name"`
+		SubcategoryName string `bson:"subcategory_name"`
+	}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	index := &catalogSearchIndex{
+		entries:    make([]*catalogSearchEntry, 0, len(documents)),
+		vocabulary: make(map[string]struct{}),
+	}
+	for _, document := range documents {
+		entry := &catalogSearchEntry{metadataId: document.ID}
+		entry.fields[searchFieldName] = tokenizeSearchText(document.Name)
+		entry.fields[searchFieldHSN] = tokenizeSearchText(document.HSNCode)
+		entry.fields[searchFieldSubcategory] = tokenizeSearchText(document.SubcategoryName)
+		entry.fields[searchFieldCategory] = tokenizeSearchText(document.CategoryName)
+		entry.fields[searchFieldDescription] = tokenizeSearchText(document.Description)
+		for _, tokens := range entry.fields {
+			for _, token := range tokens {
+				index.vocabulary[token] = struct{}{}
+			}
+		}
+		index.entries = append(index.entries, entry)
+	}
+	return index, nil
+}
+
+// allowedEdits is the typo tolerance for a query term, none for short terms and codes
+func allowedEdits(term string) int {
+	if strings.IndexFunc(term, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+		return 0
+	}
+	switch length := len([]rune(term)); {
+	case length >= 8:
+		return 2
+	case length >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// editDistance is the Levenshtein distance between a and b, giving up once it exceeds max
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > max || -diff > max {
+		return max + 1
+	}
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// expandSearchTerm returns the indexed words a query term matches and how well: exactly, as a prefix or within
+// its typo tolerance
+func (index *catalogSearchIndex) expandSearchTerm(term string) map[string]float64 {
+	matches := make(map[string]float64)
+	edits := allowedEdits(term)
+	for word := range index.vocabulary {
+		switch {
+		case word == term:
+			matches[word] = exactMatchQuality
+		case strings.HasPrefix(word, term):
+			matches[word] = prefixMatchQuality
+		case edits > 0:
+			if distance := editDistance(term, word, edits); distance <= edits {
+				matches[word] = fuzzyMatchQuality / float64(distance)
+			}
+		}
+	}
+	return matches
+}
+
+// searchCatalog ranks the catalog against a free-text search. Documents matching more of the search terms come
+// first, then those whose best matches are on heavier fields and closer to the typed term.
+func searchCatalog(ctx context.Context, db *mongo.Database, search string) ([]*catalogSearchHit, error) {
+	terms := tokenizeSearchText(search)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	index, err := getCatalogSearchIndex(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	expansions := make([]map[string]float64, len(terms))
+	for i, term := range terms {
+		expansions[i] = index.expandSearchTerm(term)
+	}
+	phrase := strings.Join(terms, " ")
+
+	hits := []*catalogSearchHit{}
+	for _, entry := range index.entries {
+		hit := &catalogSearchHit{MetadataID: entry.metadataId}
+		for _, matches := range expansions {
+			best := 0.0
+			for field, tokens := range entry.fields {
+				for _, token := range tokens {
+					if quality, ok := matches[token]; ok {
+						best = max(best, quality*searchFieldWeights[field])
+					}
+				}
+			}
+			if best > 0 {
+				hit.Matched++
+				hit.Score += best
+			}
+		}
+		if hit.Matched == 0 {
+			continue
+		}
+		if strings.HasPrefix(strings.Join(entry.fields[searchFieldName], " "), phrase) {
+			hit.Score += namePrefixBonus
+		}
+		hits = append(hits, hit)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Matched != hits[j].Matched {
+			return hits[i].Matched > hits[j].Matched
+		}
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].MetadataID < hits[j].MetadataID
+	})
+	return hits, nil
+}
@@ -0,0 +1,30 @@
+package mongodb
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{a: "milk", b: "milk", max: 2, want: 0},
+		{a: "milk", b: "mlik", max: 2, want: 2},
+		{a: "biscuit", b: "biscit", max: 1, want: 1},
+		{a: "shampoo", b: "shampo", max: 2, want: 1},
+		{a: "rice", b: "ricee", max: 1, want: 1},
+		{a: "", b: "tea", max: 3, want: 3},
+		{a: "café", b: "cafe", max: 1, want: 1},
+		// beyond the cap the distance is reported as max+1
+		{a: "sugar", b: "salt", max: 2, want: 3},
+		{a: "oil", b: "oilseeds", max: 2, want: 3},
+		{a: "kitten", b: "sitting", max: 2, want: 3},
+		{a: "kitten", b: "sitting", max: 3, want: 3},
+		{a: "abc", b: "xyz", max: 0, want: 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.max); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.max, got, tt.want)
+		}
+	}
+}
@@ -31,6 +31,7 @@ func (r *CategorySubcategoryRepositoryMongoDB) GetCategories(ctx context.Context
 			"category_name": bson.M{"$regex": search, "$options": "i"},
 		}
 	}
+	withoutArchived(filter)
 	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
@@ -57,7 +58,7 @@ func (r *CategorySubcategoryRepositoryMongoDB) GetCategories(ctx context.Context
 
 func (r *CategorySubcategoryRepositoryMongoDB) GetAllCategories(ctx context.Context) ([]*entities.Category, error) {
 	collection := r.db.Collection("categories")
-	filter := bson.M{}
+	filter := withoutArchived(bson.M{})
 
 	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
@@ -76,9 +77,9 @@ func (r *CategorySubcategoryRepositoryMongoDB) GetAllCategories(ctx context.Cont
 // Subcategory operations
 func (r *CategorySubcategoryRepositoryMongoDB) GetAllSubcategories(ctx context.Context, categoryId, search string) ([]*entities.Subcategory, error) {
 	collection := r.db.Collection("subcategories")
-	filter := bson.M{"category_id": categoryId,
+	filter := withoutArchived(bson.M{"category_id": categoryId,
 		"subcategory_name": bson.M{"$regex": search, "$options": "i"},
-	}
+	})
 
 	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
@@ -102,6 +103,7 @@ func (r *CategorySubcategoryRepositoryMongoDB) GetSubcategories(ctx context.Cont
 			"subcategory_name": bson.M{"$regex": search, "$options": "i"},
 		}
 	}
+	withoutArchived(filter)
 	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
@@ -135,6 +137,7 @@ func (r *CategorySubcategoryRepositoryMongoDB) GetSubcategoryByCategoryId(ctx co
 			"category_id":      categoryId,
 		}
 	}
+	withoutArchived(filter)
 	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
@@ -274,6 +277,7 @@ func (r *CategorySubcategoryRepositoryMongoDB) UpdateCategory(ctx context.Contex
 			Error:   "No Document Matched",
 		}, err
 	}
+	invalidateCatalogSearch()
 	return &entities.MessageResponse{
 		Success: true,
 		Message: "Category Updated Successfully",
@@ -316,6 +320,7 @@ func (r *CategorySubcategoryRepositoryMongoDB) UpdateSubcategory(ctx context.Con
 			Error:   "No Document Matched",
 		}, err
 	}
+	invalidateCatalogSearch()
 	return &entities.MessageResponse{
 		Success: true,
 		Message: "Category Updated Successfully",
@@ -339,61 +344,9 @@ func (r *CategorySubcategoryRepositoryMongoDB) UpdateSubcategory(ctx context.Con
 // 	return &category, nil
 // }
 
-func (r *CategorySubcategoryRepositoryMongoDB) DeleteCategory(ctx context.Context, categoryID string) (*entities.MessageResponse, error) {
-	categoryCollection := r.db.Collection("categories")
-	SubCollection := r.db.Collection("subcategories")
-
-	filter := bson.M{"category_id": categoryID}
-
-	var subcategory *entities.Subcategory
-
-	err := SubCollection.FindOne(ctx, filter).Decode(&subcategory)
-	if err == nil {
-		return &entities.MessageResponse{
-			Success: false,
-			Message: "This category contains some subcategories so DELETION is not possible",
-			Error:   "Subcategoy exists for category",
-		}, nil
-	}
-
-	if err != mongo.ErrNoDocuments {
-		return &entities.MessageResponse{
-			Success: false,
-			Message: "Database error",
-			Error:   "error fetching suubcategories",
-		}, err
-	}
-
-	objectID, err := primitive.ObjectIDFromHex(categoryID)
-	if err != nil {
-		return &entities.MessageResponse{
-			Success: false,
-			Message: "Error creating object from category Id",
-			Error:   "object ID from hex error",
-		}, nil
-	}
-
-	result, err := categoryCollection.DeleteOne(ctx, bson.M{"_id": objectID})
-	if err != nil {
-		return &entities.MessageResponse{
-			Success: false,
-			Message: "Database error",
-			Error:   "error deleting category",
-		}, nil
-	}
-
-	if result.DeletedCount == 0 {
-		return &entities.MessageResponse{
-			Success: false,
-			Message: "No matching entry found",
-			Error:   "no matching document in db ",
-		}, nil
-	}
-	return &entities.MessageResponse{
-		Success: true,
-		Message: "Category deleted successfully",
-	}, nil
-
+// DeleteCategory archives a category, along with its subcategories and products on cascade
+func (r *CategorySubcategoryRepositoryMongoDB) DeleteCategory(ctx context.Context, categoryID string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
+	return archiveRecord(ctx, r.db, entities.ArchiveKindCategory, categoryID, request)
 }
 
 func (r *CategorySubcategoryRepositoryMongoDB) DeleteSubcategory(ctx context.Context, subcategoryID string) (*entities.MessageResponse, error) {
@@ -531,7 +484,7 @@ func (r *CategorySubcategoryRepositoryMongoDB) GetCategorySubCategoryForAllStore
 	collection := r.db.Collection("stores")
 
 	// Fix: Query stores by warehouse_id field, not _id
-	cursor, err := collection.Find(ctx, bson.M{"warehouse_id": warehouseId})
+	cursor, err := collection.Find(ctx, withoutArchived(bson.M{"warehouse_id": warehouseId}))
 	if err != nil {
 		return nil, err
 	}
@@ -623,7 +576,7 @@ func (r *CategorySubcategoryRepositoryMongoDB) GetSubCategoryForSpecificStoreCat
 func (r *CategorySubcategoryRepositoryMongoDB) GetSubCategoryForAllStoresCategoryInWarehouse(ctx context.Context, warehouseId, categoryId string) ([]*entities.Subcategory, error) {
 	storeCollection := r.db.Collection("stores")
 
-	cursor, err := storeCollection.Find(ctx, bson.M{"warehouse_id": warehouseId})
+	cursor, err := storeCollection.Find(ctx, withoutArchived(bson.M{"warehouse_id": warehouseId}))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,464 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CycleCountRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewCycleCountRepositoryMongoDB(db *mongo.Database) repositories.CycleCountRepository {
+	return &CycleCountRepositoryMongoDB{db: db}
+}
+
+// cycleCountLines captures the current quantity of inventory products as count lines
+func (r *CycleCountRepositoryMongoDB) cycleCountLines(ctx context.Context, match bson.M) ([]*entities.CycleCountLine, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"metadataObjectId": bson.M{"$toObjectId": "$metadata_product_id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "metadata", "localField": "metadataObjectId", "foreignField": "_id", "as": "metadataInfo"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$metadataInfo", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":                  0,
+			"inventory_product_id": bson.M{"$toString": "$_id"},
+			"metadata_product_id":  1,
+			"metadata_name":        "$metadataInfo.metadata_name",
+			"product_price":        1,
+			"system_quantity":      "$product_quantity",
+		}}},
+		{{Key: "$sort", Value: bson.M{"metadata_name": 1}}},
+	}
+
+	cursor, err := r.db.Collection("inventory_product").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	lines := []*entities.CycleCountLine{}
+	if err := cursor.All(ctx, &lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+func (r *CycleCountRepositoryMongoDB) CreateCycleCountSession(ctx context.Context, request *entities.CreateCycleCountSessionRequest) (*entities.CycleCountSession, error) {
+	store, err := getOperatedStore(ctx, r.db, request.StoreID, request.OperationalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var inventory entities.Inventory
+	if err := r.db.Collection("inventory").FindOne(ctx, bson.M{"store_id": store.StoreID}).Decode(&inventory); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("store has no inventory to count")
+		}
+		return nil, err
+	}
+
+	// a rack code limits the count to the stock assigned to that rack of the store
+	match := bson.M{"inventory_id": inventory.InventoryID}
+	var rack entities.Rack
+	if request.Rack != "" {
+		err = r.db.Collection("racks").FindOne(ctx, bson.M{"store_id": store.StoreID, "code": strings.ToUpper(request.Rack)}).Decode(&rack)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("rack not found")
+			}
+			return nil, err
+		}
+	}
+	if rack.ID != "" {
+		match["rack_id"] = rack.ID
+	}
+
+	// Sessions covering the same stock would adjust it twice: a whole-store count overlaps every other
+	// session of the store, a rack count overlaps whole-store counts and counts of the same rack
+	collection := r.db.Collection("cycle_count_sessions")
+	inProgress := bson.M{
+		"store_id": store.StoreID,
+		"status":   bson.M{"$in": bson.A{entities.CycleCountOpen, entities.CycleCountInReview}},
+	}
+	if rack.ID != "" {
+		inProgress["rack_id"] = bson.M{"$in": bson.A{rack.ID, "", nil}}
+	}
+	openCount, err := collection.CountDocuments(ctx, inProgress)
+	if err != nil {
+		return nil, err
+	}
+	if openCount > 0 {
+		return nil, fmt.Errorf("a count session covering this stock is already in progress")
+	}
+
+	lines, err := r.cycleCountLines(ctx, match)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &entities.CycleCountSession{
+		WarehouseID: store.WarehouseID,
+		StoreID:     store.StoreID,
+		SellerID:    store.SellerID,
+		InventoryID: inventory.InventoryID,
+		Rack:        request.Rack,
+		RackID:      rack.ID,
+		Status:      entities.CycleCountOpen,
+		Lines:       lines,
+		CreatedBy:   request.OperationalID,
+		CreatedAt:   time.Now(),
+	}
+	result, err := collection.InsertOne(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	insertedId, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("error in getting inserted session id")
+	}
+	session.ID = insertedId.Hex()
+	return session, nil
+}
+
+func (r *CycleCountRepositoryMongoDB) GetCycleCountSessionById(ctx context.Context, sessionId, operationalId string) (*entities.CycleCountSession, error) {
+	objectId, err := primitive.ObjectIDFromHex(sessionId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session id")
+	}
+	var session entities.CycleCountSession
+	if err := r.db.Collection("cycle_count_sessions").FindOne(ctx, bson.M{"_id": objectId}).Decode(&session); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("count session not found")
+		}
+		return nil, err
+	}
+	if operationalId != "" {
+		if err := checkWarehouseOperator(ctx, r.db, session.WarehouseID, operationalId); err != nil {
+			return nil, err
+		}
+	}
+	return &session, nil
+}
+
+func (r *CycleCountRepositoryMongoDB) GetCycleCountSessions(ctx context.Context, request *entities.GetCycleCountSessionsRequest) ([]*entities.CycleCountSession, int64, error) {
+	collection := r.db.Collection("cycle_count_sessions")
+
+	var warehouses []*entities.Warehouse
+	cursor, err := r.db.Collection("warehouses").Find(ctx, bson.M{"warehouse_operational_guy_id": request.OperationalID})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := cursor.All(ctx, &warehouses); err != nil {
+		return nil, 0, err
+	}
+	warehouseIds := bson.A{}
+	for _, warehouse := range warehouses {
+		warehouseIds = append(warehouseIds, warehouse.ID)
+	}
+
+	filter := bson.M{"warehouse_id": bson.M{"$in": warehouseIds}}
+	if request.StoreID != "" {
+		filter["store_id"] = request.StoreID
+	}
+	if request.Status != "" {
+		filter["status"] = request.Status
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Lines are left out of the listing, they are fetched per session
+	findOptions := options.Find().SetSort(bson.M{"created_at": -1}).SetSkip(request.Offset * request.Limit).SetLimit(request.Limit).SetProjection(bson.M{"lines": 0})
+	cursor, err = collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*entities.CycleCountSession{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, 0, err
+	}
+	return sessions, total, nil
+}
+
+func (r *CycleCountRepositoryMongoDB) SubmitCycleCounts(ctx context.Context, sessionId, operationalId string, counts []*entities.CycleCountEntry) (*entities.CycleCountSession, error) {
+	session, err := r.GetCycleCountSessionById(ctx, sessionId, operationalId)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != entities.CycleCountOpen {
+		return nil, fmt.Errorf("count session is %s, counts can no longer be submitted", session.Status)
+	}
+
+	lines := make(map[string]*entities.CycleCountLine)
+	for _, line := range session.Lines {
+		lines[line.InventoryProductID] = line
+	}
+	var added, counted []*entities.CycleCountLine
+
+	for _, count := range counts {
+		line, ok := lines[count.InventoryProductID]
+		if !ok {
+			// Stock found on the shelf that was added to the store after the session opened
+			objectId, err := primitive.ObjectIDFromHex(count.InventoryProductID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid inventory product id %s", count.InventoryProductID)
+			}
+			newLines, err := r.cycleCountLines(ctx, bson.M{"_id": objectId, "inventory_id": session.InventoryID})
+			if err != nil {
+				return nil, err
+			}
+			if len(newLines) == 0 {
+				return nil, fmt.Errorf("inventory product %s does not belong to this store", count.InventoryProductID)
+			}
+			line = newLines[0]
+			lines[line.InventoryProductID] = line
+			added = append(added, newLines[0])
+		}
+
+		// Late deliveries of an older offline batch do not overwrite a newer count
+		if line.CountedAt != nil && count.CountedAt.Before(*line.CountedAt) {
+			continue
+		}
+		countedQuantity := count.CountedQuantity
+		countedAt := count.CountedAt
+		counted = append(counted, &entities.CycleCountLine{
+			InventoryProductID: line.InventoryProductID,
+			CountedQuantity:    &countedQuantity,
+			CountedAt:          &countedAt,
+			CountedBy:          operationalId,
+		})
+		line.CountedAt = &countedAt
+	}
+	if err := r.captureSystemQuantities(ctx, counted); err != nil {
+		return nil, err
+	}
+
+	// Counters submit the same session concurrently, so each line is written on its own instead of replacing
+	// the whole array, and a count only lands if no newer one for the line was stored meanwhile
+	dbSession, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer dbSession.EndSession(ctx)
+
+	objectId, _ := primitive.ObjectIDFromHex(session.ID)
+	collection := r.db.Collection("cycle_count_sessions")
+	_, err = dbSession.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		for _, line := range added {
+			_, err := collection.UpdateOne(sc,
+				bson.M{"_id": objectId, "status": entities.CycleCountOpen, "lines.inventory_product_id": bson.M{"$ne": line.InventoryProductID}},
+				bson.M{"$push": bson.M{"lines": line}},
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, line := range counted {
+			result, err := collection.UpdateOne(sc,
+				bson.M{"_id": objectId, "status": entities.CycleCountOpen},
+				bson.M{"$set": bson.M{
+					"lines.$[line].counted_quantity": line.CountedQuantity,
+					"lines.$[line].counted_at":       line.CountedAt,
+					"lines.$[line].counted_by":       line.CountedBy,
+					"lines.$[line].system_quantity":  line.SystemQuantity,
+					"lines.$[line].variance":         line.Variance,
+				}},
+				options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{
+					"line.inventory_product_id": line.InventoryProductID,
+					"line.counted_at":           bson.M{"$not": bson.M{"$gt": line.CountedAt}},
+				}}}),
+			)
+			if err != nil {
+				return nil, err
+			}
+			if result.MatchedCount == 0 {
+				return nil, fmt.Errorf("count session is no longer open")
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetCycleCountSessionById(ctx, sessionId, operationalId)
+}
+
+// captureSystemQuantities sets the system quantity of counted lines to what the system held when each was counted:
+// the current quantity less the ledger movements recorded after the count. Sales, transfers and receipts between
+// opening the session and counting are so not mistaken for variance, nor are those between counting and submitting.
+func (r *CycleCountRepositoryMongoDB) captureSystemQuantities(ctx context.Context, lines []*entities.CycleCountLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(lines))
+	objectIds := make([]primitive.ObjectID, 0, len(lines))
+	earliest := *lines[0].CountedAt
+	for _, line := range lines {
+		objectId, err := primitive.ObjectIDFromHex(line.InventoryProductID)
+		if err != nil {
+			return fmt.Errorf("invalid inventory product id %s", line.InventoryProductID)
+		}
+		ids = append(ids, line.InventoryProductID)
+		objectIds = append(objectIds, objectId)
+		if line.CountedAt.Before(earliest) {
+			earliest = *line.CountedAt
+		}
+	}
+
+	cursor, err := r.db.Collection("inventory_product").Find(ctx, bson.M{"_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		return err
+	}
+	var products []*entities.InventoryProduct
+	if err := cursor.All(ctx, &products); err != nil {
+		return err
+	}
+	quantities := make(map[string]int, len(products))
+	for _, product := range products {
+		quantities[product.InventoryProductID] = product.ProductQuantity
+	}
+
+	cursor, err = r.db.Collection("inventory_ledger").Find(ctx, bson.M{
+		"inventory_product_id": bson.M{"$in": ids},
+		"created_at":           bson.M{"$gt": earliest},
+	})
+	if err != nil {
+		return err
+	}
+	var movements []*entities.InventoryLedgerEntry
+	if err := cursor.All(ctx, &movements); err != nil {
+		return err
+	}
+
+	setCountedSystemQuantities(lines, quantities, movements)
+	return nil
+}
+
+// setCountedSystemQuantities sets the system quantity of each counted line to its current quantity less the
+// movements recorded after it was counted, and its variance to what was counted beyond that
+func setCountedSystemQuantities(lines []*entities.CycleCountLine, quantities map[string]int, movements []*entities.InventoryLedgerEntry) {
+	for _, line := range lines {
+		systemQuantity := quantities[line.InventoryProductID]
+		for _, movement := range movements {
+			if movement.InventoryProductID == line.InventoryProductID && movement.CreatedAt.After(*line.CountedAt) {
+				systemQuantity -= movement.QuantityChange
+			}
+		}
+		line.SystemQuantity = systemQuantity
+		line.Variance = *line.CountedQuantity - systemQuantity
+	}
+}
+
+func (r *CycleCountRepositoryMongoDB) UpdateCycleCountStatus(ctx context.Context, sessionId, operationalId string, fromStatuses []string, toStatus string) (*entities.CycleCountSession, error) {
+	session, err := r.GetCycleCountSessionById(ctx, sessionId, operationalId)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"status": toStatus}
+	now := time.Now()
+	if toStatus == entities.CycleCountInReview {
+		update["submitted_at"] = now
+		session.SubmittedAt = &now
+	}
+
+	objectId, _ := primitive.ObjectIDFromHex(session.ID)
+	result, err := r.db.Collection("cycle_count_sessions").UpdateOne(ctx,
+		bson.M{"_id": objectId, "status": bson.M{"$in": fromStatuses}},
+		bson.M{"$set": update},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("count session is %s", session.Status)
+	}
+	session.Status = toStatus
+	return session, nil
+}
+
+func (r *CycleCountRepositoryMongoDB) ApproveCycleCountSession(ctx context.Context, session *entities.CycleCountSession, operationalId string) (*entities.CycleCountSession, error) {
+	objectId, err := primitive.ObjectIDFromHex(session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session id")
+	}
+
+	dbSession, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer dbSession.EndSession(ctx)
+
+	_, err = dbSession.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if err := checkWarehouseOperator(sc, r.db, session.WarehouseID, operationalId); err != nil {
+			return nil, err
+		}
+
+		for _, line := range session.Lines {
+			if !line.Approved || line.Variance == 0 {
+				continue
+			}
+			product, err := adjustInventoryProductQuantity(sc, r.db, line.InventoryProductID, line.Variance)
+			if err != nil {
+				return nil, err
+			}
+			quantityAfter := product.ProductQuantity
+			line.QuantityAfter = &quantityAfter
+
+			err = insertInventoryLedgerEntry(sc, r.db, &entities.InventoryLedgerEntry{
+				InventoryProductID: line.InventoryProductID,
+				InventoryID:        session.InventoryID,
+				StoreID:            session.StoreID,
+				SellerID:           session.SellerID,
+				MetadataProductID:  line.MetadataProductID,
+				Type:               entities.LedgerTypeCycleCount,
+				QuantityChange:     line.Variance,
+				QuantityAfter:      quantityAfter,
+				ReferenceID:        session.ID,
+				Reason:             line.Reason,
+				CreatedBy:          operationalId,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		now := time.Now()
+		session.Status = entities.CycleCountCompleted
+		session.ApprovedBy = operationalId
+		session.ApprovedAt = &now
+		result, err := r.db.Collection("cycle_count_sessions").UpdateOne(sc,
+			bson.M{"_id": objectId, "status": entities.CycleCountInReview},
+			bson.M{"$set": bson.M{
+				"status":      session.Status,
+				"lines":       session.Lines,
+				"summary":     session.Summary,
+				"approved_by": operationalId,
+				"approved_at": now,
+			}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, fmt.Errorf("count session is no longer in review")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,61 @@
+package mongodb
+
+import (
+	"espazeBackend/domain/entities"
+	"testing"
+	"time"
+)
+
+func TestSetCountedSystemQuantities(t *testing.T) {
+	countedAt := time.Date(2026, time.March, 10, 11, 0, 0, 0, time.UTC)
+	movement := func(id string, minutes, change int) *entities.InventoryLedgerEntry {
+		return &entities.InventoryLedgerEntry{InventoryProductID: id, QuantityChange: change, CreatedAt: countedAt.Add(time.Duration(minutes) * time.Minute)}
+	}
+	tests := []struct {
+		name      string
+		counted   int
+		current   int
+		movements []*entities.InventoryLedgerEntry
+		system    int
+		variance  int
+	}{
+		{name: "nothing moved", counted: 10, current: 10, system: 10},
+		{name: "shortage", counted: 8, current: 10, system: 10, variance: -2},
+		{name: "excess", counted: 12, current: 10, system: 10, variance: 2},
+		{
+			name: "sales after the count are not variance", counted: 10, current: 7,
+			movements: []*entities.InventoryLedgerEntry{movement("product", 5, -2), movement("product", 30, -1)},
+			system:    10,
+		},
+		{
+			name: "sales before the count are already off the shelf", counted: 7, current: 7,
+			movements: []*entities.InventoryLedgerEntry{movement("product", -30, -3)},
+			system:    7,
+		},
+		{
+			name: "a movement at the counted time is before it", counted: 9, current: 9,
+			movements: []*entities.InventoryLedgerEntry{movement("product", 0, -1)},
+			system:    9,
+		},
+		{
+			name: "receipt after the count with a shortage", counted: 4, current: 25,
+			movements: []*entities.InventoryLedgerEntry{movement("product", 10, 20)},
+			system:    5, variance: -1,
+		},
+		{
+			name: "movements of other products are ignored", counted: 10, current: 10,
+			movements: []*entities.InventoryLedgerEntry{movement("other", 10, -4)},
+			system:    10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counted := tt.counted
+			line := &entities.CycleCountLine{InventoryProductID: "product", CountedQuantity: &counted, CountedAt: &countedAt}
+			setCountedSystemQuantities([]*entities.CycleCountLine{line}, map[string]int{"product": tt.current}, tt.movements)
+			if line.SystemQuantity != tt.system || line.Variance != tt.variance {
+				t.Errorf("system quantity %d and variance %d, want %d and %d", line.SystemQuantity, line.Variance, tt.system, tt.variance)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type HSNRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewHSNRepositoryMongoDB(db *mongo.Database) repositories.HSNRepository {
+	return &HSNRepositoryMongoDB{db: db}
+}
+
+// hsnLookupCodes lists the master entries an HSN code can be rated by, most specific first. GST rates are often
+// notified for a whole heading, so an 8 digit code falls back to its 6 and 4 digit prefixes.
+func hsnLookupCodes(code string) []string {
+	codes := []string{code}
+	for _, length := range []int{6, 4} {
+		if len(code) > length {
+			codes = append(codes, code[:length])
+		}
+	}
+	return codes
+}
+
+// currentGSTRate is the last rate of an HSN code that took effect by at
+func currentGSTRate(hsnCode *entities.HSNCode, at time.Time) *float64 {
+	var current *float64
+	for _, rate := range hsnCode.Rates {
+		if rate.EffectiveFrom.After(at) {
+			break
+		}
+		current = &rate.Rate
+	}
+	return current
+}
+
+// resolveHSNCodes maps every given code to the most specific master entry covering it, leaving out codes the
+// master does not know. An entry whose rates all take effect later falls back to the closest parent rated today.
+func resolveHSNCodes(ctx context.Context, db *mongo.Database, codes []string) (map[string]*entities.HSNCode, error) {
+	lookup := []string{}
+	for _, code := range codes {
+		if code != "" {
+			lookup = append(lookup, hsnLookupCodes(code)...)
+		}
+	}
+	resolved := make(map[string]*entities.HSNCode)
+	if len(lookup) == 0 {
+		return resolved, nil
+	}
+
+	cursor, err := db.Collection("hsn_codes").Find(ctx, bson.M{"_id": bson.M{"$in": lookup}})
+	if err != nil {
+		return nil, err
+	}
+	var entries []*entities.HSNCode
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*entities.HSNCode, len(entries))
+	now := time.Now()
+	for _, entry := range entries {
+		entry.CurrentRate = currentGSTRate(entry, now)
+		byCode[entry.Code] = entry
+	}
+
+	for _, code := range codes {
+		for _, candidate := range hsnLookupCodes(code) {
+			entry, ok := byCode[candidate]
+			if !ok {
+				continue
+			}
+			if _, found := resolved[code]; !found || entry.CurrentRate != nil {
+				resolved[code] = entry
+			}
+			if entry.CurrentRate != nil {
+				break
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// getHSNCode resolves a single HSN code against the master
+func getHSNCode(ctx context.Context, db *mongo.Database, code string) (*entities.HSNCode, error) {
+	resolved, err := resolveHSNCodes(ctx, db, []string{code})
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := resolved[code]
+	if !ok {
+		return nil, fmt.Errorf("hsn code %s is not in the HSN master", code)
+	}
+	return entry, nil
+}
+
+// getGSTRates is the GST rate in force today for each given HSN code the master can rate
+func getGSTRates(ctx context.Context, db *mongo.Database, codes []string) (map[string]float64, error) {
+	resolved, err := resolveHSNCodes(ctx, db, codes)
+	if err != nil {
+		return nil, err
+	}
+	rates := make(map[string]float64, len(resolved))
+	for code, entry := range resolved {
+		if entry.CurrentRate != nil {
+			rates[code] = *entry.CurrentRate
+		}
+	}
+	return rates, nil
+}
+
+// setMetadataGSTRates fills the GST rate of metadata listing rows
+func setMetadataGSTRates(ctx context.Context, db *mongo.Database, metadata []*entities.GetAllMetadata) error {
+	codes := make([]string, 0, len(metadata))
+	for _, m := range metadata {
+		codes = append(codes, m.HsnCode)
+	}
+	rates, err := getGSTRates(ctx, db, codes)
+	if err != nil {
+		return err
+	}
+	for _, m := range metadata {
+		if rate, ok := rates[m.HsnCode]; ok {
+			m.GSTRate = &rate
+		}
+	}
+	return nil
+}
+
+// setProductGSTRates fills the GST rate of product listings
+func setProductGSTRates(ctx context.Context, db *mongo.Database, products []*entities.GetProductsForStoreSubcategory) error {
+	codes := make([]string, 0, len(products))
+	for _, product := range products {
+		codes = append(codes, product.HsnCode)
+	}
+	rates, err := getGSTRates(ctx, db, codes)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		if rate, ok := rates[product.HsnCode]; ok {
+			product.GSTRate = &rate
+		}
+	}
+	return nil
+}
+
+// SaveHSNCodes upserts the given codes, adding each rate to the code's rate history
+func (r *HSNRepositoryMongoDB) SaveHSNCodes(ctx context.Context, requests []*entities.SaveHSNCodeRequest) ([]*entities.HSNCode, error) {
+	collection := r.db.Collection("hsn_codes")
+	codes := make([]string, 0, len(requests))
+	for _, request := range requests {
+		codes = append(codes, request.Code)
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": codes}})
+	if err != nil {
+		return nil, err
+	}
+	var existing []*entities.HSNCode
+	if err := cursor.All(ctx, &existing); err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*entities.HSNCode, len(existing))
+	for _, entry := range existing {
+		byCode[entry.Code] = entry
+	}
+
+	now := time.Now()
+	saved := []*entities.HSNCode{}
+	touched := make(map[string]bool, len(requests))
+	for _, request := range requests {
+		entry, ok := byCode[request.Code]
+		if !ok {
+			entry = &entities.HSNCode{Code: request.Code, Rates: []*entities.GSTRate{}}
+			byCode[request.Code] = entry
+		}
+		if !touched[request.Code] {
+			touched[request.Code] = true
+			saved = append(saved, entry)
+		}
+		if request.Description != "" {
+			entry.Description = request.Description
+		}
+
+		rates := make([]*entities.GSTRate, 0, len(entry.Rates)+1)
+		for _, rate := range entry.Rates {
+			if !rate.EffectiveFrom.Equal(*request.EffectiveFrom) {
+				rates = append(rates, rate)
+			}
+		}
+		rates = append(rates, &entities.GSTRate{Rate: *request.GSTRate, EffectiveFrom: *request.EffectiveFrom})
+		sort.Slice(rates, func(i, j int) bool { return rates[i].EffectiveFrom.Before(rates[j].EffectiveFrom) })
+		entry.Rates = rates
+		entry.UpdatedBy = request.UpdatedBy
+		entry.UpdatedAt = now
+	}
+
+	models := make([]mongo.WriteModel, 0, len(saved))
+	for _, entry := range saved {
+		entry.CurrentRate = currentGSTRate(entry, now)
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": entry.Code}).
+			SetReplacement(entry).
+			SetUpsert(true))
+	}
+	if len(models) > 0 {
+		if _, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return nil, err
+		}
+	}
+	return saved, nil
+}
+
+func (r *HSNRepositoryMongoDB) GetHSNCode(ctx context.Context, code string) (*entities.HSNCode, error) {
+	return getHSNCode(ctx, r.db, code)
+}
+
+// GetHSNCodes lists master entries by code, searching code prefixes and descriptions
+func (r *HSNRepositoryMongoDB) GetHSNCodes(ctx context.Context, search string, limit, offset int64) ([]*entities.HSNCode, int64, error) {
+	collection := r.db.Collection("hsn_codes")
+	filter := bson.M{}
+	if search != "" {
+		filter["$or"] = bson.A{
+			bson.M{"_id": bson.M{"$regex": "^" + regexp.QuoteMeta(search)}},
+			bson.M{"description": bson.M{"$regex": regexp.QuoteMeta(search), "$options": "i"}},
+		}
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetSkip(offset).
+		SetLimit(limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	codes := []*entities.HSNCode{}
+	if err := cursor.All(ctx, &codes); err != nil {
+		return nil, 0, err
+	}
+	now := time.Now()
+	for _, code := range codes {
+		code.CurrentRate = currentGSTRate(code, now)
+	}
+	return codes, total, nil
+}
+
+// DeleteHSNCode removes a code from the master unless metadata holds it or a code under it
+func (r *HSNRepositoryMongoDB) DeleteHSNCode(ctx context.Context, code string) error {
+	inUse, err := r.db.Collection("metadata").CountDocuments(ctx, bson.M{"hsn_code": bson.M{"$regex": "^" + regexp.QuoteMeta(code)}})
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return fmt.Errorf("hsn code %s is used by %d metadata", code, inUse)
+	}
+
+	result, err := r.db.Collection("hsn_codes").DeleteOne(ctx, bson.M{"_id": code})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("hsn code not found")
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InboundShipmentRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewInboundShipmentRepositoryMongoDB(db *mongo.Database) repositories.InboundShipmentRepository {
+	return &InboundShipmentRepositoryMongoDB{db: db}
+}
+
+func (r *InboundShipmentRepositoryMongoDB) GetStoreById(ctx context.Context, storeId string) (*entities.Store, error) {
+	objectId, err := primitive.ObjectIDFromHex(storeId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store id %s", storeId)
+	}
+	var store entities.Store
+	if err := r.db.Collection("stores").FindOne(ctx, bson.M{"_id": objectId}).Decode(&store); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("store %s not found", storeId)
+		}
+		return nil, err
+	}
+	return &store, nil
+}
+
+func (r *InboundShipmentRepositoryMongoDB) GetMetadataByIds(ctx context.Context, metadataIds []string) ([]*entities.Metadata, error) {
+	objectIds := make([]primitive.ObjectID, 0, len(metadataIds))
+	for _, id := range metadataIds {
+		objectId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata product id %s", id)
+		}
+		objectIds = append(objectIds, objectId)
+	}
+	cursor, err := r.db.Collection("metadata").Find(ctx, bson.M{"_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metadata []*entities.Metadata
+	if err := cursor.All(ctx, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (r *InboundShipmentRepositoryMongoDB) CreateInboundShipment(ctx context.Context, shipment *entities.InboundShipment) (*entities.InboundShipment, error) {
+	shipment.Status = entities.InboundShipmentDeclared
+	shipment.DeclaredAt = time.Now()
+	result, err := r.db.Collection("inbound_shipments").InsertOne(ctx, shipment)
+	if err != nil {
+		return nil, err
+	}
+	insertedId, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("error in getting inserted shipment id")
+	}
+	shipment.ID = insertedId.Hex()
+	return shipment, nil
+}
+
+// ReceiveInboundShipment records the counts of a declared shipment, posts the accepted quantities to the store's
+// inventory with ledger entries, issues the GRN number and notifies the seller, all in one transaction
+func (r *InboundShipmentRepositoryMongoDB) ReceiveInboundShipment(ctx context.Context, request *entities.ReceiveInboundShipmentRequest) (*entities.InboundShipment, error) {
+	objectId, err := primitive.ObjectIDFromHex(request.ShipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shipment id")
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var shipment entities.InboundShipment
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		collection := r.db.Collection("inbound_shipments")
+		if err := collection.FindOne(sc, bson.M{"_id": objectId}).Decode(&shipment); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("shipment not found")
+			}
+			return nil, err
+		}
+		if shipment.Status != entities.InboundShipmentDeclared {
+			return nil, fmt.Errorf("shipment is already %s", shipment.Status)
+		}
+		store, err := getOperatedStore(sc, r.db, shipment.StoreID, request.OperationalID)
+		if err != nil {
+			return nil, err
+		}
+		if len(request.Items) != len(shipment.Items) {
+			return nil, fmt.Errorf("counts are required for all %d lines of the shipment", len(shipment.Items))
+		}
+		inventory, err := getOrCreateStoreInventory(sc, r.db, store)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, counts := range request.Items {
+			if counts.Line < 0 || counts.Line >= len(shipment.Items) {
+				return nil, fmt.Errorf("line %d does not exist on the shipment", counts.Line)
+			}
+			item := shipment.Items[counts.Line]
+			item.AcceptedQuantity = counts.AcceptedQuantity
+			item.ShortQuantity = counts.ShortQuantity
+			item.DamagedQuantity = counts.DamagedQuantity
+			item.Remark = strings.TrimSpace(counts.Remark)
+			if item.AcceptedQuantity == 0 {
+				continue
+			}
+
+			product, err := addToInventoryBatch(sc, r.db, &entities.InventoryProduct{
+				InventoryID:              inventory.InventoryID,
+				MetadataProductID:        item.MetadataProductID,
+				ProductPrice:             item.ProductPrice,
+				ProductExpiryDate:        item.ProductExpiryDate,
+				ProductManufacturingDate: item.ProductManufacturingDate,
+			}, item.AcceptedQuantity, &entities.PriceHistoryEntry{
+				SellerID:    shipment.SellerID,
+				Source:      entities.PriceSourceReceipt,
+				ReferenceID: shipment.ID,
+				ChangedBy:   request.OperationalID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			item.InventoryProductID = product.InventoryProductID
+
+			// the received units are averaged into the cost of the stock already on the batch
+			var unitCostBefore *float64
+			if item.UnitCost > 0 {
+				previousCost := product.UnitCost
+				unitCost := averageUnitCost(product.ProductQuantity-item.AcceptedQuantity, previousCost, item.AcceptedQuantity, item.UnitCost)
+				if unitCost != previousCost {
+					productObjectId, err := primitive.ObjectIDFromHex(product.InventoryProductID)
+					if err != nil {
+						return nil, err
+					}
+					if _, err := r.db.Collection("inventory_product").UpdateByID(sc, productObjectId, bson.M{"$set": bson.M{"unit_cost": unitCost}}); err != nil {
+						return nil, err
+					}
+					unitCostBefore = &previousCost
+				}
+			}
+
+			err = insertInventoryLedgerEntry(sc, r.db, &entities.InventoryLedgerEntry{
+				InventoryProductID: product.InventoryProductID,
+				InventoryID:        inventory.InventoryID,
+				StoreID:            shipment.StoreID,
+				SellerID:           shipment.SellerID,
+				MetadataProductID:  item.MetadataProductID,
+				Type:               entities.LedgerTypeGoodsReceipt,
+				QuantityChange:     item.AcceptedQuantity,
+				QuantityAfter:      product.ProductQuantity,
+				ReferenceID:        shipment.ID,
+				UnitCostBefore:     unitCostBefore,
+				CreatedBy:          request.OperationalID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		now := time.Now()
+		shipment.Status = entities.InboundShipmentReceived
+		shipment.GRNNumber = fmt.Sprintf("GRN-%s-%s", now.Format("20060102"), strings.ToUpper(shipment.ID[len(shipment.ID)-6:]))
+		shipment.ReceiveNote = strings.TrimSpace(request.Note)
+		shipment.ReceivedBy = request.OperationalID
+		shipment.ReceivedAt = &now
+		result, err := collection.UpdateOne(sc,
+			bson.M{"_id": objectId, "status": entities.InboundShipmentDeclared},
+			bson.M{"$set": bson.M{
+				"status":       shipment.Status,
+				"grn_number":   shipment.GRNNumber,
+				"items":        shipment.Items,
+				"receive_note": shipment.ReceiveNote,
+				"received_by":  shipment.ReceivedBy,
+				"received_at":  now,
+			}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, fmt.Errorf("shipment is no longer awaiting receipt")
+		}
+
+		accepted, short, damaged := 0, 0, 0
+		for _, item := range shipment.Items {
+			accepted += item.AcceptedQuantity
+			short += item.ShortQuantity
+			damaged += item.DamagedQuantity
+		}
+		err = insertNotification(sc, r.db, &entities.Notification{
+			UserID:      shipment.SellerID,
+			Type:        entities.NotificationTypeGoodsReceipt,
+			Title:       "Shipment received",
+			Message:     fmt.Sprintf("%s issued for %s: %d accepted, %d short, %d damaged", shipment.GRNNumber, shipment.StoreName, accepted, short, damaged),
+			ReferenceID: shipment.ID,
+		})
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+// averageUnitCost is the cost per unit of quantity units at cost once received units at receivedCost join them.
+// Stock with no cost yet, or none left, takes the cost of the received units.
+func averageUnitCost(quantity int, cost float64, received int, receivedCost float64) float64 {
+	if quantity <= 0 || cost == 0 {
+		return receivedCost
+	}
+	total := float64(quantity)*cost + float64(received)*receivedCost
+	return math.Round(total/float64(quantity+received)*100) / 100
+}
+
+// CancelInboundShipment withdraws a shipment the seller declared but ops have not received yet
+func (r *InboundShipmentRepositoryMongoDB) CancelInboundShipment(ctx context.Context, shipmentId, sellerId string) (*entities.InboundShipment, error) {
+	objectId, err := primitive.ObjectIDFromHex(shipmentId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shipment id")
+	}
+
+	now := time.Now()
+	var shipment entities.InboundShipment
+	err = r.db.Collection("inbound_shipments").FindOneAndUpdate(ctx,
+		bson.M{"_id": objectId, "seller_id": sellerId, "status": entities.InboundShipmentDeclared},
+		bson.M{"$set": bson.M{"status": entities.InboundShipmentCancelled, "cancelled_by": sellerId, "cancelled_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&shipment)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("shipment not found or already received")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+func (r *InboundShipmentRepositoryMongoDB) GetInboundShipmentById(ctx context.Context, shipmentId string) (*entities.InboundShipment, error) {
+	objectId, err := primitive.ObjectIDFromHex(shipmentId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shipment id")
+	}
+	var shipment entities.InboundShipment
+	if err := r.db.Collection("inbound_shipments").FindOne(ctx, bson.M{"_id": objectId}).Decode(&shipment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("shipment not found")
+		}
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+func (r *InboundShipmentRepositoryMongoDB) GetInboundShipments(ctx context.Context, request *entities.GetInboundShipmentsRequest) ([]*entities.InboundShipment, int64, error) {
+	collection := r.db.Collection("inbound_shipments")
+
+	filter := bson.M{}
+	if request.OperationalID != "" {
+		var warehouses []*entities.Warehouse
+		cursor, err := r.db.Collection("warehouses").Find(ctx, bson.M{"warehouse_operational_guy_id": request.OperationalID})
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := cursor.All(ctx, &warehouses); err != nil {
+			return nil, 0, err
+		}
+		warehouseIds := bson.A{}
+		for _, warehouse := range warehouses {
+			warehouseIds = append(warehouseIds, warehouse.ID)
+		}
+		filter["warehouse_id"] = bson.M{"$in": warehouseIds}
+	}
+	if request.SellerID != "" {
+		filter["seller_id"] = request.SellerID
+	}
+	if request.StoreID != "" {
+		filter["store_id"] = request.StoreID
+	}
+	if request.Status != "" {
+		filter["status"] = request.Status
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"declared_at": -1}).SetSkip(request.Offset * request.Limit).SetLimit(request.Limit)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	shipments := []*entities.InboundShipment{}
+	if err := cursor.All(ctx, &shipments); err != nil {
+		return nil, 0, err
+	}
+	return shipments, total, nil
+}
@@ -2,16 +2,19 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"espazeBackend/domain/entities"
 	"espazeBackend/domain/repositories"
 	"fmt"
 	"log"
+	"strings"
 
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type InventoryRepositoryMongoDB struct {
@@ -22,7 +25,7 @@ func NewInventoryRepositoryMongoDB(db *mongo.Database) repositories.InventoryRep
 	return &InventoryRepositoryMongoDB{db: db}
 }
 
-func (r *InventoryRepositoryMongoDB) GetAllInventory(ctx context.Context, sellerID string, offset, limit int64, search, sort string) ([]entities.GetAllInventoryResponse, int64, error) {
+func (r *InventoryRepositoryMongoDB) GetAllInventory(ctx context.Context, sellerID string, offset, limit int64, search, sort string, filter *entities.InventoryListFilter) ([]entities.GetAllInventoryResponse, int64, *entities.InventoryFacets, error) {
 	collectionInventory := r.db.Collection("inventory")
 	collectionProduct := r.db.Collection("inventory_product")
 
@@ -30,16 +33,68 @@ func (r *InventoryRepositoryMongoDB) GetAllInventory(ctx context.Context, seller
 	var inventory entities.Inventory
 	err := collectionInventory.FindOne(ctx, bson.M{"seller_id": sellerID}).Decode(&inventory)
 	if err == mongo.ErrNoDocuments {
-		return nil, 0, nil
+		return nil, 0, &entities.InventoryFacets{}, nil
 	}
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, nil, err
 	}
 
 	// 2. Build aggregation pipeline on inventory_product with lookups and search
+	basePipeline := inventoryListPipeline(inventory.InventoryID, search)
+	pipeline := append(append(mongo.Pipeline{}, basePipeline...), bson.D{{Key: "$match", Value: inventoryFilterMatch(filter)}})
+
+	// 3. Count total
+	countPipeline := append(append(mongo.Pipeline{}, pipeline...), bson.D{{Key: "$count", Value: "total"}})
+	countCursor, err := collectionProduct.Aggregate(ctx, countPipeline)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	var countResult []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := countCursor.All(ctx, &countResult); err != nil {
+		return nil, 0, nil, err
+	}
+	var total int64
+	if len(countResult) > 0 {
+		total = countResult[0].Total
+	}
+
+	// 4. Data fetch with sorting, pagination and projection
+	dataPipeline := append(pipeline,
+		bson.D{{Key: "$sort", Value: inventorySortStage(sort)}},
+		bson.D{{Key: "$skip", Value: offset * limit}},
+		bson.D{{Key: "$limit", Value: limit}},
+	)
+	dataPipeline = append(dataPipeline, activeScheduledPriceStages(bson.M{"$toString": "$_id"}, "$metadata_info.metadata_mrp")...)
+	dataPipeline = append(dataPipeline, bson.D{{Key: "$project", Value: inventoryListProjection}})
+
+	cursor, err := collectionProduct.Aggregate(ctx, dataPipeline)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []entities.GetAllInventoryResponse
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, nil, err
+	}
+
+	// 5. Facet counts over the searched inventory
+	facets, err := r.inventoryFacets(ctx, basePipeline)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return results, total, facets, nil
+}
+
+// inventoryListPipeline joins a seller's inventory products with their metadata, category and
+// subcategory, optionally filtered by a search on metadata and subcategory name
+func inventoryListPipeline(inventoryID, search string) mongo.Pipeline {
 	pipeline := mongo.Pipeline{
 		// Filter for this seller's inventory
-		{{Key: "$match", Value: bson.M{"inventory_id": inventory.InventoryID}}},
+		{{Key: "$match", Value: withoutArchived(bson.M{"inventory_id": inventoryID})}},
 		// Convert metadata_product_id (string) to ObjectId for lookup
 		{{Key: "$addFields", Value: bson.M{
 			"metadata_oid": bson.M{"$toObjectId": "$metadata_product_id"},
@@ -74,7 +129,7 @@ func (r *InventoryRepositoryMongoDB) GetAllInventory(ctx context.Context, seller
 		{{Key: "$unwind", Value: "$subcategory_info"}},
 	}
 
-	// 3. Apply search on metadata name and subcategory name
+	// Apply search on metadata name and subcategory name
 	if search != "" && search != `""` {
 		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{
 			"$or": []bson.M{
@@ -84,24 +139,26 @@ func (r *InventoryRepositoryMongoDB) GetAllInventory(ctx context.Context, seller
 		}}})
 	}
 
-	// 4. Count total
-	countPipeline := append(append(mongo.Pipeline{}, pipeline...), bson.D{{Key: "$count", Value: "total"}})
-	countCursor, err := collectionProduct.Aggregate(ctx, countPipeline)
-	if err != nil {
-		return nil, 0, err
-	}
-	var countResult []struct {
-		Total int64 `bson:"total"`
-	}
-	if err := countCursor.All(ctx, &countResult); err != nil {
-		return nil, 0, err
-	}
-	var total int64
-	if len(countResult) > 0 {
-		total = countResult[0].Total
-	}
+	return pipeline
+}
 
-	// 5. Sorting
+// inventorySortPaths maps the sortable listing fields to document paths after inventoryListPipeline
+var inventorySortPaths = map[string]string{
+	"name":               "metadata_info.metadata_name",
+	"price":              "product_price",
+	"mrp":                "metadata_info.metadata_mrp",
+	"quantity":           "product_quantity",
+	"expiry_date":        "product_expiry_date",
+	"manufacturing_date": "product_manufacturing_date",
+	"category":           "category_info.category_name",
+	"subcategory":        "subcategory_info.subcategory_name",
+	"visibility":         "product_visibility",
+	"created_at":         "metadata_info.metadata_created_at",
+}
+
+// inventorySortStage maps the listing sort option to a stable sort stage. Besides the legacy options it accepts
+// a comma separated list of fields, each optionally prefixed with "-" for descending order.
+func inventorySortStage(sort string) bson.D {
 	sortStage := bson.D{{Key: "metadata_info.metadata_created_at", Value: -1}, {Key: "_id", Value: 1}}
 	switch sort {
 	case "asc":
@@ -112,60 +169,190 @@ func (r *InventoryRepositoryMongoDB) GetAllInventory(ctx context.Context, seller
 		sortStage = bson.D{{Key: "metadata_info.metadata_mrp", Value: 1}, {Key: "_id", Value: 1}}
 	case "mrp_desc":
 		sortStage = bson.D{{Key: "metadata_info.metadata_mrp", Value: -1}, {Key: "_id", Value: 1}}
+	case "":
+	default:
+		fields := bson.D{}
+		for _, field := range strings.Split(sort, ",") {
+			field = strings.TrimSpace(field)
+			direction := 1
+			if strings.HasPrefix(field, "-") {
+				direction = -1
+				field = field[1:]
+			}
+			if path, ok := inventorySortPaths[field]; ok {
+				fields = append(fields, bson.E{Key: path, Value: direction})
+			}
+		}
+		if len(fields) > 0 {
+			sortStage = append(fields, bson.E{Key: "_id", Value: 1})
+		}
+	}
 
+	return sortStage
+}
+
+// inventoryFilterMatch builds the match stage for the listing filters on top of inventoryListPipeline
+func inventoryFilterMatch(filter *entities.InventoryListFilter) bson.M {
+	match := bson.M{}
+	if filter == nil {
+		return match
+	}
+	if len(filter.CategoryIDs) > 0 {
+		match["metadata_info.metadata_category_id"] = bson.M{"$in": filter.CategoryIDs}
+	}
+	if len(filter.SubcategoryIDs) > 0 {
+		match["metadata_info.metadata_subcategory_id"] = bson.M{"$in": filter.SubcategoryIDs}
+	}
+	if filter.Visibility != nil {
+		match["product_visibility"] = *filter.Visibility
+	}
+	quantity := bson.M{}
+	if filter.MinQuantity != nil {
+		quantity["$gte"] = *filter.MinQuantity
 	}
+	if filter.MaxQuantity != nil {
+		quantity["$lte"] = *filter.MaxQuantity
+	}
+	if len(quantity) > 0 {
+		match["product_quantity"] = quantity
+	}
+	price := bson.M{}
+	if filter.MinPrice != nil {
+		price["$gte"] = *filter.MinPrice
+	}
+	if filter.MaxPrice != nil {
+		price["$lte"] = *filter.MaxPrice
+	}
+	if len(price) > 0 {
+		match["product_price"] = price
+	}
+	expiry := bson.M{}
+	if filter.ExpiryFrom != nil {
+		expiry["$gte"] = *filter.ExpiryFrom
+	}
+	if filter.ExpiryTo != nil {
+		expiry["$lte"] = *filter.ExpiryTo
+	}
+	if len(expiry) > 0 {
+		match["product_expiry_date"] = expiry
+	}
+	return match
+}
 
-	// 6. Data fetch with pagination and projection
-	dataPipeline := append(pipeline,
-		bson.D{{Key: "$sort", Value: sortStage}},
-		bson.D{{Key: "$skip", Value: offset * limit}},
-		bson.D{{Key: "$limit", Value: limit}},
-		bson.D{{Key: "$project", Value: bson.M{
-			"inventory_id":            "$inventory_id",
-			"inventory_product_id":    bson.M{"$toString": "$_id"},
-			"metadata_product_id":     bson.M{"$toString": "$metadata_info._id"},
-			"product_visibility":      "$product_visibility",
-			"product_price":           "$product_price",
-			"metadata_name":           "$metadata_info.metadata_name",
-			"metadata_description":    "$metadata_info.metadata_description",
-			"metadata_image":          "$metadata_info.metadata_image",
-			"metadata_category_id":    "$metadata_info.metadata_category_id",
-			"metadata_subcategory_id": "$metadata_info.metadata_subcategory_id",
-			"metadata_mrp":            "$metadata_info.metadata_mrp",
-			"metadata_hsn_code":       "$metadata_info.hsn_code",
-			"product_quantity":        "$product_quantity",
-			"product_expiry_date": bson.M{"$cond": bson.M{
-				"if":   bson.M{"$eq": []interface{}{bson.M{"$type": "$product_expiry_date"}, "date"}},
-				"then": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d %H:%M:%S", "date": "$product_expiry_date"}},
-				"else": "$product_expiry_date",
-			}},
-			"product_manufacturing_date": bson.M{"$cond": bson.M{
-				"if":   bson.M{"$eq": []interface{}{bson.M{"$type": "$product_manufacturing_date"}, "date"}},
-				"then": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d %H:%M:%S", "date": "$product_manufacturing_date"}},
-				"else": "$product_manufacturing_date",
-			}},
-			"metadata_created_at": bson.M{"$cond": bson.M{
-				"if":   bson.M{"$eq": []interface{}{bson.M{"$type": "$metadata_info.metadata_created_at"}, "date"}},
-				"then": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d %H:%M:%S", "date": "$metadata_info.metadata_created_at"}},
-				"else": "$metadata_info.metadata_created_at",
+// inventoryFacets counts the products of a listing pipeline per category, subcategory, visibility and stock
+func (r *InventoryRepositoryMongoDB) inventoryFacets(ctx context.Context, pipeline mongo.Pipeline) (*entities.InventoryFacets, error) {
+	facetPipeline := append(append(mongo.Pipeline{}, pipeline...), bson.D{{Key: "$facet", Value: bson.M{
+		"categories": bson.A{
+			bson.M{"$group": bson.M{"_id": "$metadata_info.metadata_category_id", "name": bson.M{"$first": "$category_info.category_name"}, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.M{"name": 1}},
+		},
+		"subcategories": bson.A{
+			bson.M{"$group": bson.M{"_id": "$metadata_info.metadata_subcategory_id", "name": bson.M{"$first": "$subcategory_info.subcategory_name"}, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.M{"name": 1}},
+		},
+		"totals": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":          nil,
+				"visible":      bson.M{"$sum": bson.M{"$cond": bson.A{"$product_visibility", 1, 0}}},
+				"hidden":       bson.M{"$sum": bson.M{"$cond": bson.A{"$product_visibility", 0, 1}}},
+				"in_stock":     bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$product_quantity", 0}}, 1, 0}}},
+				"out_of_stock": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$product_quantity", 0}}, 0, 1}}},
 			}},
-			"metadata_category_name":    "$category_info.category_name",
-			"metadata_subcategory_name": "$subcategory_info.subcategory_name",
-		}}},
+		},
+	}}})
+
+	cursor, err := r.db.Collection("inventory_product").Aggregate(ctx, facetPipeline)
+	if err != nil {
+		return nil, err
+	}
+	var result []struct {
+		Categories    []*entities.InventoryFacetCount `bson:"categories"`
+		Subcategories []*entities.InventoryFacetCount `bson:"subcategories"`
+		Totals        []*entities.InventoryFacets     `bson:"totals"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	facets := &entities.InventoryFacets{Categories: []*entities.InventoryFacetCount{}, Subcategories: []*entities.InventoryFacetCount{}}
+	if len(result) == 0 {
+		return facets, nil
+	}
+	if len(result[0].Totals) > 0 {
+		facets = result[0].Totals[0]
+	}
+	facets.Categories = result[0].Categories
+	facets.Subcategories = result[0].Subcategories
+	return facets, nil
+}
+
+// inventoryListProjection shapes the joined documents into GetAllInventoryResponse
+var inventoryListProjection = bson.M{
+	"inventory_id":            "$inventory_id",
+	"inventory_product_id":    bson.M{"$toString": "$_id"},
+	"metadata_product_id":     bson.M{"$toString": "$metadata_info._id"},
+	"product_visibility":      "$product_visibility",
+	"product_price":           "$product_price",
+	"metadata_name":           "$metadata_info.metadata_name",
+	"metadata_description":    "$metadata_info.metadata_description",
+	"metadata_image":          "$metadata_info.metadata_image",
+	"metadata_category_id":    "$metadata_info.metadata_category_id",
+	"metadata_subcategory_id": "$metadata_info.metadata_subcategory_id",
+	"metadata_mrp":            "$metadata_info.metadata_mrp",
+	"metadata_hsn_code":       "$metadata_info.hsn_code",
+	"product_quantity":        "$product_quantity",
+	"product_expiry_date": bson.M{"$cond": bson.M{
+		"if":   bson.M{"$eq": []interface{}{bson.M{"$type": "$product_expiry_date"}, "date"}},
+		"then": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d %H:%M:%S", "date": "$product_expiry_date"}},
+		"else": "$product_expiry_date",
+	}},
+	"product_manufacturing_date": bson.M{"$cond": bson.M{
+		"if":   bson.M{"$eq": []interface{}{bson.M{"$type": "$product_manufacturing_date"}, "date"}},
+		"then": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d %H:%M:%S", "date": "$product_manufacturing_date"}},
+		"else": "$product_manufacturing_date",
+	}},
+	"metadata_created_at": bson.M{"$cond": bson.M{
+		"if":   bson.M{"$eq": []interface{}{bson.M{"$type": "$metadata_info.metadata_created_at"}, "date"}},
+		"then": bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d %H:%M:%S", "date": "$metadata_info.metadata_created_at"}},
+		"else": "$metadata_info.metadata_created_at",
+	}},
+	"metadata_category_name":    "$category_info.category_name",
+	"metadata_subcategory_name": "$subcategory_info.subcategory_name",
+	// Present only on pages that joined the running sale
+	"sale_price":   "$active_sale.price",
+	"sale_ends_at": "$active_sale.ends_at",
+}
+
+func (r *InventoryRepositoryMongoDB) GetAllInventoryForExport(ctx context.Context, sellerID, search, sort string, filter *entities.InventoryListFilter) ([]entities.GetAllInventoryResponse, error) {
+	collectionInventory := r.db.Collection("inventory")
+	collectionProduct := r.db.Collection("inventory_product")
+
+	var inventory entities.Inventory
+	err := collectionInventory.FindOne(ctx, bson.M{"seller_id": sellerID}).Decode(&inventory)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := append(inventoryListPipeline(inventory.InventoryID, search),
+		bson.D{{Key: "$match", Value: inventoryFilterMatch(filter)}},
+		bson.D{{Key: "$sort", Value: inventorySortStage(sort)}},
+		bson.D{{Key: "$project", Value: inventoryListProjection}},
 	)
 
-	cursor, err := collectionProduct.Aggregate(ctx, dataPipeline)
+	cursor, err := collectionProduct.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var results []entities.GetAllInventoryResponse
 	if err := cursor.All(ctx, &results); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-
-	return results, total, nil
+	return results, nil
 }
 
 func (r *InventoryRepositoryMongoDB) CreateInventory(ctx context.Context, inventoryRequest *entities.AddInventoryRequest) (*entities.MessageResponse, error) {
@@ -290,7 +477,8 @@ func (r *InventoryRepositoryMongoDB) CreateInventory(ctx context.Context, invent
 
 }
 
-func (r *InventoryRepositoryMongoDB) UpdateInventory(ctx context.Context, inventoryRequest entities.UpdateInventoryRequest) (*entities.MessageResponse, error) {
+// UpdateInventory updates an inventory product, queuing priceChanges for approval in the same transaction
+func (r *InventoryRepositoryMongoDB) UpdateInventory(ctx context.Context, inventoryRequest entities.UpdateInventoryRequest, priceChanges []*entities.PriceChangeRequest) (*entities.MessageResponse, error) {
 	collection := r.db.Collection("inventory_product")
 
 	objectId, err := primitive.ObjectIDFromHex(inventoryRequest.InventoryProductID)
@@ -302,9 +490,10 @@ func (r *InventoryRepositoryMongoDB) UpdateInventory(ctx context.Context, invent
 		}, err
 	}
 
-	filter := bson.M{
+	// archived listings stay hidden until their product, store or warehouse is restored
+	filter := withoutArchived(bson.M{
 		"_id": objectId,
-	}
+	})
 
 	// Parse date strings to time.Time
 	var expiryDate, manufacturingDate time.Time
@@ -326,23 +515,20 @@ func (r *InventoryRepositoryMongoDB) UpdateInventory(ctx context.Context, invent
 		}
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"product_visibility": inventoryRequest.ProductVisibility,
-			"product_quantity":   inventoryRequest.ProductQuantity,
-			"product_price":      inventoryRequest.ProductPrice,
-		},
+	// Visibility follows the review, sellers cannot publish a product themselves
+	set := bson.M{
+		"product_quantity": inventoryRequest.ProductQuantity,
 	}
 
 	// Only add date fields if they were successfully parsed
 	if err1 == nil && !expiryDate.IsZero() {
-		update["$set"].(bson.M)["product_expiry_date"] = expiryDate
+		set["product_expiry_date"] = expiryDate
 	}
 	if err2 == nil && !manufacturingDate.IsZero() {
-		update["$set"].(bson.M)["product_manufacturing_date"] = manufacturingDate
+		set["product_manufacturing_date"] = manufacturingDate
 	}
 
-	response, err := collection.UpdateOne(ctx, filter, update)
+	session, err := r.db.Client().StartSession()
 	if err != nil {
 		return &entities.MessageResponse{
 			Success: false,
@@ -350,12 +536,63 @@ func (r *InventoryRepositoryMongoDB) UpdateInventory(ctx context.Context, invent
 			Error:   "Db Error",
 		}, err
 	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var current entities.InventoryProduct
+		if err := collection.FindOne(sc, filter).Decode(&current); err != nil {
+			return nil, err
+		}
+		// Editing a rejected product or one with requested changes sends it back to the review queue
+		if current.ReviewStatus == entities.ReviewStatusRejected || current.ReviewStatus == entities.ReviewStatusChangesRequested {
+			set["product_visibility"] = false
+			set["review_status"] = entities.ReviewStatusPending
+		}
+		inventory, err := findInventory(sc, r.db, current.InventoryID)
+		if err != nil {
+			return nil, err
+		}
+		previous, err := setInventoryProductPrice(sc, r.db, filter, set, &entities.PriceHistoryEntry{
+			SellerID:  inventory.SellerID,
+			NewPrice:  inventoryRequest.ProductPrice,
+			Source:    entities.PriceSourceUpdate,
+			ChangedBy: inventoryRequest.SellerID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := insertPriceChangeRequests(sc, r.db, priceChanges); err != nil {
+			return nil, err
+		}
+		if previous.ProductQuantity == inventoryRequest.ProductQuantity {
+			return nil, nil
+		}
 
-	if response.MatchedCount == 0 {
+		return nil, insertInventoryLedgerEntry(sc, r.db, &entities.InventoryLedgerEntry{
+			InventoryProductID: inventoryRequest.InventoryProductID,
+			InventoryID:        previous.InventoryID,
+			StoreID:            inventory.StoreId,
+			SellerID:           inventory.SellerID,
+			MetadataProductID:  previous.MetadataProductID,
+			Type:               entities.LedgerTypeSellerUpdate,
+			QuantityChange:     inventoryRequest.ProductQuantity - previous.ProductQuantity,
+			QuantityAfter:      inventoryRequest.ProductQuantity,
+			Reason:             "inventory update",
+			CreatedBy:          inventoryRequest.SellerID,
+		})
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return &entities.MessageResponse{
 			Success: false,
 			Message: "Database Error",
 			Error:   "No Matching Document ",
+		}, nil
+	}
+	if err != nil {
+		return &entities.MessageResponse{
+			Success: false,
+			Message: "Database Error",
+			Error:   "Db Error",
 		}, err
 	}
 
@@ -365,6 +602,22 @@ func (r *InventoryRepositoryMongoDB) UpdateInventory(ctx context.Context, invent
 	}, nil
 }
 
+// findInventory reads an inventory by id
+func findInventory(ctx context.Context, db *mongo.Database, inventoryId string) (*entities.Inventory, error) {
+	objectId, err := primitive.ObjectIDFromHex(inventoryId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inventory id %s", inventoryId)
+	}
+	var inventory entities.Inventory
+	if err := db.Collection("inventory").FindOne(ctx, bson.M{"_id": objectId}).Decode(&inventory); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("inventory %s not found", inventoryId)
+		}
+		return nil, err
+	}
+	return &inventory, nil
+}
+
 func (r *InventoryRepositoryMongoDB) DeleteInventory(ctx context.Context, inventoryRequest entities.DeleteInventoryRequest) error {
 	collection := r.db.Collection("inventory_product")
 
@@ -429,7 +682,8 @@ func (r *InventoryRepositoryMongoDB) GetInventoryById(ctx context.Context, Inven
 	return result, nil
 }
 
-func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, inventoryRequest *entities.AddInventoryByExcelRequest) (*entities.MessageResponse, error) {
+// AddInventoryByExcel applies the products of a sheet, queuing priceChanges for approval in the same transaction
+func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, inventoryRequest *entities.AddInventoryByExcelRequest, priceChanges []*entities.PriceChangeRequest) (*entities.MessageResponse, error) {
 	collection := r.db.Collection("inventory")
 	inventoryCollection := r.db.Collection("inventory_product")
 
@@ -482,6 +736,9 @@ func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, in
 			}
 			var allInventoryProducts []*entities.InventoryProduct
 			for _, mp := range inventoryRequest.MetadataProducts {
+				if mp.InventoryProductID != "" {
+					return nil, fmt.Errorf("inventory product %s not found in your inventory", mp.InventoryProductID)
+				}
 				expiryDate, perr := time.Parse(layout, mp.ProductExpiryDate)
 				if perr != nil {
 					log.Printf("error parsing expiry date: %v", perr)
@@ -497,17 +754,18 @@ func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, in
 					MetadataProductID:        mp.ProductMetadataId,
 					ProductVisibility:        false,
 					ProductQuantity:          mp.ProductQuantity,
+					ProductPrice:             mp.ProductPrice,
 					ProductExpiryDate:        expiryDate,
 					ProductManufacturingDate: manufacturingDate,
 				}
 				allInventoryProducts = append(allInventoryProducts, p)
 			}
-			docs := make([]interface{}, len(allInventoryProducts))
-			for i, v := range allInventoryProducts {
-				docs[i] = v
+			if err := insertInventoryProducts(sc, r.db, allInventoryProducts, sheetPriceChange(inventoryRequest.SellerID)); err != nil {
+				return nil, err
 			}
-			if len(docs) > 0 {
-				if _, err := inventoryCollection.InsertMany(sc, docs); err != nil {
+			inventoryData.InventoryID = inventoryId.Hex()
+			for _, product := range allInventoryProducts {
+				if err := recordSheetStock(sc, r.db, inventoryData, product.InventoryProductID, product.MetadataProductID, product.ProductQuantity, product.ProductQuantity); err != nil {
 					return nil, err
 				}
 			}
@@ -526,6 +784,13 @@ func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, in
 				manufacturingDate = time.Time{}
 			}
 
+			if mp.InventoryProductID != "" {
+				if err := updateListingFromSheet(sc, r.db, inventory, mp, expiryDate, manufacturingDate); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
 			var inventoryProductData []*entities.InventoryProduct
 			cursor, err := inventoryCollection.Find(sc, bson.M{"metadata_product_id": mp.ProductMetadataId, "inventory_id": inventory.InventoryID})
 			if err != nil {
@@ -534,7 +799,6 @@ func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, in
 			if err := cursor.All(sc, &inventoryProductData); err != nil {
 				return nil, err
 			}
-			fmt.Print(inventoryProductData, mp)
 			updatedOrInserted := false
 			for _, inventoryProduct := range inventoryProductData {
 				if inventoryProduct.ProductQuantity == 0 && !inventoryProduct.ProductVisibility {
@@ -542,12 +806,17 @@ func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, in
 					if err != nil {
 						return nil, err
 					}
-					result, err := inventoryCollection.UpdateByID(sc, objectId, bson.M{"$set": bson.M{"product_quantity": mp.ProductQuantity, "product_price": mp.ProductPrice, "product_expiry_date": expiryDate, "product_manufacturing_date": manufacturingDate}})
+					change := sheetPriceChange(inventoryRequest.SellerID)
+					change.NewPrice = mp.ProductPrice
+					_, err = setInventoryProductPrice(sc, r.db, bson.M{"_id": objectId}, bson.M{"product_quantity": mp.ProductQuantity, "product_expiry_date": expiryDate, "product_manufacturing_date": manufacturingDate}, change)
+					if err == mongo.ErrNoDocuments {
+						return nil, fmt.Errorf("no data updated")
+					}
 					if err != nil {
 						return nil, err
 					}
-					if result.MatchedCount == 0 {
-						return nil, fmt.Errorf("no data updated")
+					if err := recordSheetStock(sc, r.db, inventory, inventoryProduct.InventoryProductID, mp.ProductMetadataId, mp.ProductQuantity, mp.ProductQuantity); err != nil {
+						return nil, err
 					}
 					updatedOrInserted = true
 					break
@@ -563,6 +832,9 @@ func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, in
 					if result.MatchedCount == 0 {
 						return nil, fmt.Errorf("no data updated")
 					}
+					if err := recordSheetStock(sc, r.db, inventory, inventoryProduct.InventoryProductID, mp.ProductMetadataId, mp.ProductQuantity, inventoryProduct.ProductQuantity+mp.ProductQuantity); err != nil {
+						return nil, err
+					}
 					updatedOrInserted = true
 					break
 				}
@@ -577,11 +849,17 @@ func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, in
 					ProductManufacturingDate: manufacturingDate,
 					ProductPrice:             mp.ProductPrice,
 				}
-				if _, err := inventoryCollection.InsertOne(sc, newProduct); err != nil {
+				if err := insertInventoryProducts(sc, r.db, []*entities.InventoryProduct{newProduct}, sheetPriceChange(inventoryRequest.SellerID)); err != nil {
+					return nil, err
+				}
+				if err := recordSheetStock(sc, r.db, inventory, newProduct.InventoryProductID, mp.ProductMetadataId, mp.ProductQuantity, mp.ProductQuantity); err != nil {
 					return nil, err
 				}
 			}
 		}
+		if err := insertPriceChangeRequests(sc, r.db, priceChanges); err != nil {
+			return nil, err
+		}
 
 		resp = &entities.MessageResponse{Message: "Inventory Added Successfully", Success: true}
 		return resp, nil
@@ -598,7 +876,68 @@ func (r *InventoryRepositoryMongoDB) AddInventoryByExcel(ctx context.Context, in
 	return resp, nil
 }
 
-func (r *InventoryRepositoryMongoDB) GetAllInventoryRequests(ctx context.Context, operational_id string, offset, limit int64, search string) ([]*entities.GetAllInventoryRequestResponse, int64, error) {
+// updateListingFromSheet sets the quantity, price and dates of a listing of the inventory to those of a sheet row,
+// recording the quantity change in the ledger
+func updateListingFromSheet(sc mongo.SessionContext, db *mongo.Database, inventory *entities.Inventory, row entities.AddInventoryByExcelProduct, expiryDate, manufacturingDate time.Time) error {
+	objectId, err := primitive.ObjectIDFromHex(row.InventoryProductID)
+	if err != nil {
+		return fmt.Errorf("invalid inventory product id %s", row.InventoryProductID)
+	}
+	filter := withoutArchived(bson.M{"_id": objectId, "inventory_id": inventory.InventoryID})
+	if row.ProductMetadataId != "" {
+		filter["metadata_product_id"] = row.ProductMetadataId
+	}
+	set := bson.M{
+		"product_quantity": row.ProductQuantity,
+	}
+	if !expiryDate.IsZero() {
+		set["product_expiry_date"] = expiryDate
+	}
+	if !manufacturingDate.IsZero() {
+		set["product_manufacturing_date"] = manufacturingDate
+	}
+
+	change := sheetPriceChange(inventory.SellerID)
+	change.NewPrice = row.ProductPrice
+	previous, err := setInventoryProductPrice(sc, db, filter, set, change)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("inventory product %s not found in your inventory", row.InventoryProductID)
+	}
+	if err != nil {
+		return err
+	}
+	return recordSheetStock(sc, db, inventory, row.InventoryProductID, previous.MetadataProductID, row.ProductQuantity-previous.ProductQuantity, row.ProductQuantity)
+}
+
+// sheetPriceChange describes a price a seller set through the inventory sheet
+func sheetPriceChange(sellerId string) *entities.PriceHistoryEntry {
+	return &entities.PriceHistoryEntry{
+		SellerID:  sellerId,
+		Source:    entities.PriceSourceSheet,
+		ChangedBy: sellerId,
+	}
+}
+
+// recordSheetStock records the quantity a sheet row changed a listing by in the ledger
+func recordSheetStock(sc mongo.SessionContext, db *mongo.Database, inventory *entities.Inventory, inventoryProductId, metadataProductId string, change, quantityAfter int) error {
+	if change == 0 {
+		return nil
+	}
+	return insertInventoryLedgerEntry(sc, db, &entities.InventoryLedgerEntry{
+		InventoryProductID: inventoryProductId,
+		InventoryID:        inventory.InventoryID,
+		StoreID:            inventory.StoreId,
+		SellerID:           inventory.SellerID,
+		MetadataProductID:  metadataProductId,
+		Type:               entities.LedgerTypeSellerUpdate,
+		QuantityChange:     change,
+		QuantityAfter:      quantityAfter,
+		Reason:             "inventory sheet upload",
+		CreatedBy:          inventory.SellerID,
+	})
+}
+
+func (r *InventoryRepositoryMongoDB) GetAllInventoryRequests(ctx context.Context, operational_id string, offset, limit int64, search, status string) ([]*entities.GetAllInventoryRequestResponse, int64, error) {
 	warehouseCollection := r.db.Collection("warehouses")
 
 	session, err := r.db.Client().StartSession()
@@ -636,7 +975,7 @@ func (r *InventoryRepositoryMongoDB) GetAllInventoryRequests(ctx context.Context
 
 			{{Key: "$unwind", Value: bson.M{"path": "$productInfo", "preserveNullAndEmptyArrays": true}}},
 
-			{{Key: "$match", Value: bson.M{"productInfo.product_visibility": false}}},
+			{{Key: "$match", Value: inventoryReviewStatusMatch(status)}},
 
 			{{Key: "$addFields", Value: bson.M{"metadataObjectId": bson.M{"$toObjectId": "$productInfo.metadata_product_id"}}}},
 
@@ -673,6 +1012,8 @@ func (r *InventoryRepositoryMongoDB) GetAllInventoryRequests(ctx context.Context
 				"price":                "$productInfo.product_price",
 				"expiry_date":          "$productInfo.product_expiry_date",
 				"manufacturing_date":   "$productInfo.product_manufacturing_date",
+				"review_status":        bson.M{"$ifNull": bson.A{"$productInfo.review_status", entities.ReviewStatusPending}},
+				"review_reason":        "$productInfo.review_reason",
 			}}},
 		}
 
@@ -726,10 +1067,24 @@ func (r *InventoryRepositoryMongoDB) GetAllInventoryRequests(ctx context.Context
 	return result, total, err
 }
 
-func (r *InventoryRepositoryMongoDB) AcceptVisibility(ctx context.Context, productId string) (*entities.MessageResponse, error) {
-	collection := r.db.Collection("inventory_product")
+// inventoryReviewStatusMatch selects the review queue for a status. Pending products are hidden and not yet decided on.
+func inventoryReviewStatusMatch(status string) bson.M {
+	switch status {
+	case entities.ReviewStatusAll:
+		return bson.M{"productInfo": bson.M{"$exists": true}}
+	case entities.ReviewStatusApproved, entities.ReviewStatusRejected, entities.ReviewStatusChangesRequested:
+		return bson.M{"productInfo.review_status": status}
+	default:
+		// Products approved before reviews were recorded are visible without a status
+		return bson.M{
+			"productInfo.product_visibility": false,
+			"productInfo.review_status":      bson.M{"$in": bson.A{nil, "", entities.ReviewStatusPending}},
+		}
+	}
+}
 
-	objectId, err := primitive.ObjectIDFromHex(productId)
+func (r *InventoryRepositoryMongoDB) ReviewInventoryProduct(ctx context.Context, review *entities.InventoryReviewRequest) (*entities.MessageResponse, error) {
+	objectId, err := primitive.ObjectIDFromHex(review.InventoryProductID)
 	if err != nil {
 		return &entities.MessageResponse{
 			Success: false,
@@ -738,35 +1093,490 @@ func (r *InventoryRepositoryMongoDB) AcceptVisibility(ctx context.Context, produ
 		}, err
 	}
 
-	filter := bson.M{
-		"_id": objectId,
-	}
-
-	update := bson.M{
-		"$set": bson.M{
-			"product_visibility": true,
-		},
-	}
-
-	response, err := collection.UpdateOne(ctx, filter, update)
+	session, err := r.db.Client().StartSession()
 	if err != nil {
 		return &entities.MessageResponse{
 			Success: false,
 			Message: "Database Error",
-			Error:   "Db Error",
+			Error:   err.Error(),
 		}, err
 	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var product entities.InventoryProduct
+		if err := r.db.Collection("inventory_product").FindOne(sc, bson.M{"_id": objectId}).Decode(&product); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("inventory product not found")
+			}
+			return nil, err
+		}
+
+		inventoryObjectId, err := primitive.ObjectIDFromHex(product.InventoryID)
+		if err != nil {
+			return nil, err
+		}
+		var inventory entities.Inventory
+		if err := r.db.Collection("inventory").FindOne(sc, bson.M{"_id": inventoryObjectId}).Decode(&inventory); err != nil {
+			return nil, fmt.Errorf("inventory not found for product")
+		}
+
+		// Only the operations user of the warehouse the store belongs to may review its products
+		storeObjectId, err := primitive.ObjectIDFromHex(inventory.StoreId)
+		if err != nil {
+			return nil, err
+		}
+		var store entities.Store
+		if err := r.db.Collection("stores").FindOne(sc, bson.M{"_id": storeObjectId}).Decode(&store); err != nil {
+			return nil, fmt.Errorf("store not found for product")
+		}
+		warehouseObjectId, err := primitive.ObjectIDFromHex(store.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		warehouseCount, err := r.db.Collection("warehouses").CountDocuments(sc, bson.M{"_id": warehouseObjectId, "warehouse_operational_guy_id": review.ReviewerID})
+		if err != nil {
+			return nil, err
+		}
+		if warehouseCount == 0 {
+			return nil, fmt.Errorf("product does not belong to a warehouse you operate")
+		}
+
+		previousStatus := product.ReviewStatus
+		if previousStatus == "" {
+			previousStatus = entities.ReviewStatusPending
+		}
 
-	if response.MatchedCount == 0 {
+		_, err = r.db.Collection("inventory_product").UpdateByID(sc, objectId, bson.M{"$set": bson.M{
+			"review_status":      review.Decision,
+			"review_reason":      review.Reason,
+			"product_visibility": review.Decision == entities.ReviewStatusApproved,
+		}})
+		if err != nil {
+			return nil, err
+		}
+
+		decision := &entities.InventoryReviewDecision{
+			InventoryProductID: review.InventoryProductID,
+			SellerID:           inventory.SellerID,
+			Decision:           review.Decision,
+			PreviousStatus:     previousStatus,
+			Reason:             review.Reason,
+			ReviewerID:         review.ReviewerID,
+			ReviewerName:       review.ReviewerName,
+			CreatedAt:          time.Now(),
+		}
+		if _, err := r.db.Collection("inventory_reviews").InsertOne(sc, decision); err != nil {
+			return nil, err
+		}
+
+		productName := product.MetadataProductID
+		metadataObjectId, err := primitive.ObjectIDFromHex(product.MetadataProductID)
+		if err == nil {
+			var metadata entities.Metadata
+			if err := r.db.Collection("metadata").FindOne(sc, bson.M{"_id": metadataObjectId}).Decode(&metadata); err == nil {
+				productName = metadata.MetadataName
+			}
+		}
+
+		var title string
+		switch review.Decision {
+		case entities.ReviewStatusApproved:
+			title = "Product approved"
+		case entities.ReviewStatusRejected:
+			title = "Product rejected"
+		default:
+			title = "Changes requested for product"
+		}
+		return nil, insertNotification(sc, r.db, &entities.Notification{
+			UserID:      inventory.SellerID,
+			Type:        entities.NotificationTypeInventoryReview,
+			Title:       title,
+			Message:     fmt.Sprintf("%s: %s", productName, review.Reason),
+			ReferenceID: review.InventoryProductID,
+		})
+	})
+	if err != nil {
 		return &entities.MessageResponse{
 			Success: false,
-			Message: "Database Error",
-			Error:   "No Matching Document ",
+			Message: "Failed to review product",
+			Error:   err.Error(),
 		}, err
 	}
 
 	return &entities.MessageResponse{
 		Success: true,
-		Message: "Product Accepted Successfully",
+		Message: "Product Reviewed Successfully",
 	}, nil
 }
+
+func (r *InventoryRepositoryMongoDB) GetInventoryReviewHistory(ctx context.Context, inventoryProductId, sellerId string) ([]*entities.InventoryReviewDecision, error) {
+	filter := bson.M{"inventory_product_id": inventoryProductId}
+	if sellerId != "" {
+		filter["seller_id"] = sellerId
+	}
+
+	cursor, err := r.db.Collection("inventory_reviews").Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	history := []*entities.InventoryReviewDecision{}
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (r *InventoryRepositoryMongoDB) GetMetadataForSheet(ctx context.Context, metadataIds, hsnCodes []string) ([]*entities.Metadata, error) {
+	collection := r.db.Collection("metadata")
+
+	var conditions []bson.M
+	var objectIds []primitive.ObjectID
+	for _, id := range metadataIds {
+		objectId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			// invalid ids are reported per row by the caller
+			continue
+		}
+		objectIds = append(objectIds, objectId)
+	}
+	if len(objectIds) > 0 {
+		conditions = append(conditions, bson.M{"_id": bson.M{"$in": objectIds}})
+	}
+	if len(hsnCodes) > 0 {
+		conditions = append(conditions, bson.M{"hsn_code": bson.M{"$in": hsnCodes}})
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := collection.Find(ctx, withoutArchived(bson.M{"$or": conditions}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metadata []*entities.Metadata
+	if err := cursor.All(ctx, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (r *InventoryRepositoryMongoDB) GetBulkInventoryTargets(ctx context.Context, sellerID string, inventoryProductIds []string, filter *entities.BulkInventoryFilter) ([]*entities.BulkInventoryTarget, error) {
+	collectionInventory := r.db.Collection("inventory")
+	collectionProduct := r.db.Collection("inventory_product")
+
+	var inventory entities.Inventory
+	err := collectionInventory.FindOne(ctx, bson.M{"seller_id": sellerID}).Decode(&inventory)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	match := withoutArchived(bson.M{"inventory_id": inventory.InventoryID})
+	if inventoryProductIds != nil {
+		// invalid ids match nothing and are reported per item by the caller
+		objectIds := []primitive.ObjectID{}
+		for _, id := range inventoryProductIds {
+			objectId, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				continue
+			}
+			objectIds = append(objectIds, objectId)
+		}
+		match["_id"] = bson.M{"$in": objectIds}
+	}
+	metadataMatch := bson.M{}
+	if filter != nil {
+		if filter.ProductVisibility != nil {
+			match["product_visibility"] = *filter.ProductVisibility
+		}
+		quantity := bson.M{}
+		if filter.MinProductQuantity != nil {
+			quantity["$gte"] = *filter.MinProductQuantity
+		}
+		if filter.MaxProductQuantity != nil {
+			quantity["$lte"] = *filter.MaxProductQuantity
+		}
+		if len(quantity) > 0 {
+			match["product_quantity"] = quantity
+		}
+		if len(filter.MetadataProductIDs) > 0 {
+			match["metadata_product_id"] = bson.M{"$in": filter.MetadataProductIDs}
+		}
+		if filter.CategoryID != "" {
+			metadataMatch["metadata_info.metadata_category_id"] = filter.CategoryID
+		}
+		if filter.SubcategoryID != "" {
+			metadataMatch["metadata_info.metadata_subcategory_id"] = filter.SubcategoryID
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"metadata_oid": bson.M{"$toObjectId": "$metadata_product_id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "metadata", "localField": "metadata_oid", "foreignField": "_id", "as": "metadata_info"}}},
+		{{Key: "$unwind", Value: "$metadata_info"}},
+	}
+	if len(metadataMatch) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: metadataMatch}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.M{
+		"_id":                 bson.M{"$toString": "$_id"},
+		"metadata_product_id": "$metadata_product_id",
+		"metadata_name":       "$metadata_info.metadata_name",
+		"metadata_mrp":        "$metadata_info.metadata_mrp",
+		"product_price":       "$product_price",
+		"product_quantity":    "$product_quantity",
+		"product_visibility":  "$product_visibility",
+	}}})
+
+	cursor, err := collectionProduct.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var targets []*entities.BulkInventoryTarget
+	if err := cursor.All(ctx, &targets); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// BulkUpdateInventory writes the changed price, quantity and visibility of each update. A write only applies while the product
+// still has the values the update was computed from, so concurrent orders and edits are not overwritten.
+// Atomically all updates apply or none do; otherwise updates that fail or find the product changed are marked
+// failed and the rest apply. The price changes of the updates are queued for approval with them.
+func (r *InventoryRepositoryMongoDB) BulkUpdateInventory(ctx context.Context, sellerId string, updates []*entities.BulkInventoryItemResult, priceChanges []*entities.PriceChangeRequest, atomic bool) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	inventory, err := findSellerInventory(ctx, r.db, sellerId)
+	if err != nil {
+		return err
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	if !atomic {
+		// Each product is written in its own transaction, one failing does not hold back the others
+		changesById := make(map[string][]*entities.PriceChangeRequest, len(priceChanges))
+		for _, priceChange := range priceChanges {
+			changesById[priceChange.InventoryProductID] = append(changesById[priceChange.InventoryProductID], priceChange)
+		}
+		for _, update := range updates {
+			_, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+				if err := applyBulkUpdate(sc, r.db, inventory, update); err != nil {
+					return nil, err
+				}
+				return nil, insertPriceChangeRequests(sc, r.db, changesById[update.InventoryProductID])
+			})
+			if err == mongo.ErrNoDocuments {
+				update.Success = false
+				update.Error = "product changed while updating, try again"
+			} else if err != nil {
+				update.Success = false
+				update.Error = err.Error()
+			}
+		}
+		return nil
+	}
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		for _, update := range updates {
+			err := applyBulkUpdate(sc, r.db, inventory, update)
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("product %s changed while updating, nothing was applied", update.InventoryProductID)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, insertPriceChangeRequests(sc, r.db, priceChanges)
+	})
+	return err
+}
+
+// findSellerInventory reads the inventory of a seller
+func findSellerInventory(ctx context.Context, db *mongo.Database, sellerId string) (*entities.Inventory, error) {
+	var inventory entities.Inventory
+	if err := db.Collection("inventory").FindOne(ctx, bson.M{"seller_id": sellerId}).Decode(&inventory); err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// applyBulkUpdate writes one bulk update with its ledger entry and price history. It fails with mongo.ErrNoDocuments
+// when the product no longer holds the values the update was computed from, so concurrent changes are not overwritten.
+func applyBulkUpdate(sc mongo.SessionContext, db *mongo.Database, inventory *entities.Inventory, update *entities.BulkInventoryItemResult) error {
+	priceChanged := update.ProductPrice != update.PreviousPrice
+	quantityChanged := update.ProductQuantity != update.PreviousQuantity
+	visibilityChanged := update.ProductVisibility != update.PreviousVisibility
+	if !priceChanged && !quantityChanged && !visibilityChanged {
+		return nil
+	}
+	objectId, err := primitive.ObjectIDFromHex(update.InventoryProductID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objectId}
+	set := bson.M{}
+	if quantityChanged {
+		filter["product_quantity"] = update.PreviousQuantity
+		set["product_quantity"] = update.ProductQuantity
+	}
+	if visibilityChanged {
+		filter["product_visibility"] = update.PreviousVisibility
+		set["product_visibility"] = update.ProductVisibility
+	}
+
+	if priceChanged {
+		filter["product_price"] = update.PreviousPrice
+		_, err = setInventoryProductPrice(sc, db, filter, set, &entities.PriceHistoryEntry{
+			SellerID:  inventory.SellerID,
+			NewPrice:  update.ProductPrice,
+			Source:    entities.PriceSourceBulkUpdate,
+			ChangedBy: inventory.SellerID,
+		})
+	} else {
+		var result *mongo.UpdateResult
+		result, err = db.Collection("inventory_product").UpdateOne(sc, filter, bson.M{"$set": set})
+		if err == nil && result.MatchedCount == 0 {
+			err = mongo.ErrNoDocuments
+		}
+	}
+	if err != nil || !quantityChanged {
+		return err
+	}
+	return insertInventoryLedgerEntry(sc, db, &entities.InventoryLedgerEntry{
+		InventoryProductID: update.InventoryProductID,
+		InventoryID:        inventory.InventoryID,
+		StoreID:            inventory.StoreId,
+		SellerID:           inventory.SellerID,
+		MetadataProductID:  update.MetadataProductID,
+		Type:               entities.LedgerTypeSellerUpdate,
+		QuantityChange:     update.ProductQuantity - update.PreviousQuantity,
+		QuantityAfter:      update.ProductQuantity,
+		Reason:             "bulk inventory update",
+		CreatedBy:          inventory.SellerID,
+	})
+}
+
+// adjustInventoryProductQuantity changes the quantity of an inventory product by change and returns the updated product.
+// A decrease fails instead of taking the quantity below zero.
+func adjustInventoryProductQuantity(ctx context.Context, db *mongo.Database, inventoryProductId string, change int) (*entities.InventoryProduct, error) {
+	objectId, err := primitive.ObjectIDFromHex(inventoryProductId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inventory product id %s", inventoryProductId)
+	}
+	filter := bson.M{"_id": objectId}
+	if change < 0 {
+		filter["product_quantity"] = bson.M{"$gte": -change}
+	}
+
+	var product entities.InventoryProduct
+	err = db.Collection("inventory_product").FindOneAndUpdate(ctx, filter,
+		bson.M{"$inc": bson.M{"product_quantity": change}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&product)
+	if err == mongo.ErrNoDocuments {
+		count, countErr := db.Collection("inventory_product").CountDocuments(ctx, bson.M{"_id": objectId})
+		if countErr == nil && count > 0 {
+			return nil, fmt.Errorf("insufficient quantity for inventory product %s", inventoryProductId)
+		}
+		return nil, fmt.Errorf("inventory product %s not found", inventoryProductId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// addToInventoryBatch adds quantity to the batch of the inventory with the same product, price and expiry as batch.
+// Without such a batch, batch is inserted as a new hidden one holding the quantity and its price is recorded as opening.
+func addToInventoryBatch(sc mongo.SessionContext, db *mongo.Database, batch *entities.InventoryProduct, quantity int, opening *entities.PriceHistoryEntry) (*entities.InventoryProduct, error) {
+	collection := db.Collection("inventory_product")
+	var product entities.InventoryProduct
+	err := collection.FindOneAndUpdate(sc,
+		bson.M{
+			"inventory_id":        batch.InventoryID,
+			"metadata_product_id": batch.MetadataProductID,
+			"product_price":       batch.ProductPrice,
+			"product_expiry_date": batch.ProductExpiryDate,
+		},
+		bson.M{"$inc": bson.M{"product_quantity": quantity}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&product)
+	if err == nil {
+		return &product, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	product = entities.InventoryProduct{
+		InventoryID:              batch.InventoryID,
+		MetadataProductID:        batch.MetadataProductID,
+		ProductVisibility:        false,
+		ProductQuantity:          quantity,
+		ProductPrice:             batch.ProductPrice,
+		ProductExpiryDate:        batch.ProductExpiryDate,
+		ProductManufacturingDate: batch.ProductManufacturingDate,
+	}
+	if err := insertInventoryProducts(sc, db, []*entities.InventoryProduct{&product}, opening); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// insertInventoryLedgerEntry records a quantity movement with the given context, so callers can include it in their transaction
+func insertInventoryLedgerEntry(ctx context.Context, db *mongo.Database, entry *entities.InventoryLedgerEntry) error {
+	entry.CreatedAt = time.Now()
+	_, err := db.Collection("inventory_ledger").InsertOne(ctx, entry)
+	return err
+}
+
+func (r *InventoryRepositoryMongoDB) GetInventoryLedger(ctx context.Context, inventoryProductId, sellerId string, offset, limit int64) ([]*entities.InventoryLedgerEntry, int64, error) {
+	collection := r.db.Collection("inventory_ledger")
+
+	filter := bson.M{"inventory_product_id": inventoryProductId}
+	if sellerId != "" {
+		filter["seller_id"] = sellerId
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"created_at": -1}).SetSkip(offset * limit).SetLimit(limit)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*entities.InventoryLedgerEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *InventoryRepositoryMongoDB) GetPricingContexts(ctx context.Context, inventoryProductIds []string) ([]*entities.PricingContext, error) {
+	return getPricingContexts(ctx, r.db, inventoryProductIds)
+}
+
+func (r *InventoryRepositoryMongoDB) GetMetadataPricingContexts(ctx context.Context, metadataIds []string) ([]*entities.PricingContext, error) {
+	return getMetadataPricingContexts(ctx, r.db, metadataIds)
+}
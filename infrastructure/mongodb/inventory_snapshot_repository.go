@@ -0,0 +1,369 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InventorySnapshotRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewInventorySnapshotRepositoryMongoDB(db *mongo.Database) repositories.InventorySnapshotRepository {
+	return &InventorySnapshotRepositoryMongoDB{db: db}
+}
+
+// toObjectIdOrNull converts a hex id to an ObjectId, yielding null for empty or malformed ids so one bad
+// document does not fail a whole collection scan
+func toObjectIdOrNull(value interface{}) bson.M {
+	return bson.M{"$convert": bson.M{"input": value, "to": "objectId", "onError": nil, "onNull": nil}}
+}
+
+// valuationGroupFields maps a valuation grouping to the snapshot fields holding its id and name
+var valuationGroupFields = map[string][2]string{
+	entities.ValuationGroupStore:     {"$store_id", "$store_name"},
+	entities.ValuationGroupSeller:    {"$seller_id", "$seller_name"},
+	entities.ValuationGroupCategory:  {"$category_id", "$category_name"},
+	entities.ValuationGroupWarehouse: {"$warehouse_id", "$warehouse_name"},
+}
+
+// snapshotChunkSize is how many snapshot lines are inserted per write
+const snapshotChunkSize = 1000
+
+// inventorySnapshotLock is the id of the lock document of the daily snapshot
+const inventorySnapshotLock = "inventory_snapshot"
+
+// CreateInventorySnapshot records the stock of every inventory product as of asOf under the given date, replacing
+// any snapshot already taken that day. For a past asOf the current stock is rewound through the ledger movements,
+// price, cost and MRP changes recorded since, and products created after it are left out.
+// Lines are written in chunks under their taken_at and only become the snapshot of the date once its run record
+// is written, so readers never see a half written snapshot and a failed run leaves the previous one in place.
+func (r *InventorySnapshotRepositoryMongoDB) CreateInventorySnapshot(ctx context.Context, snapshotDate string, asOf time.Time) (*entities.InventorySnapshotSummary, error) {
+	lookup := func(from, localField, as string) []bson.D {
+		return []bson.D{
+			{{Key: "$lookup", Value: bson.M{"from": from, "localField": localField, "foreignField": "_id", "as": as}}},
+			{{Key: "$unwind", Value: bson.M{"path": "$" + as, "preserveNullAndEmptyArrays": true}}},
+		}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$lte": primitive.NewObjectIDFromTimestamp(asOf)}}}},
+		{{Key: "$addFields", Value: bson.M{
+			"inventoryObjectId": toObjectIdOrNull("$inventory_id"),
+			"metadataObjectId":  toObjectIdOrNull("$metadata_product_id"),
+		}}},
+	}
+	pipeline = append(pipeline, lookup("inventory", "inventoryObjectId", "inventoryInfo")...)
+	pipeline = append(pipeline, lookup("metadata", "metadataObjectId", "metadataInfo")...)
+	pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{
+		"storeObjectId":    toObjectIdOrNull("$inventoryInfo.store_id"),
+		"sellerObjectId":   toObjectIdOrNull("$inventoryInfo.seller_id"),
+		"categoryObjectId": toObjectIdOrNull("$metadataInfo.metadata_category_id"),
+	}}})
+	pipeline = append(pipeline, lookup("stores", "storeObjectId", "storeInfo")...)
+	pipeline = append(pipeline, lookup("sellers", "sellerObjectId", "sellerInfo")...)
+	pipeline = append(pipeline, lookup("categories", "categoryObjectId", "categoryInfo")...)
+	pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"warehouseObjectId": toObjectIdOrNull("$storeInfo.warehouse_id")}}})
+	pipeline = append(pipeline, lookup("warehouses", "warehouseObjectId", "warehouseInfo")...)
+	pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.M{
+		"_id":                  0,
+		"inventory_product_id": bson.M{"$toString": "$_id"},
+		"inventory_id":         1,
+		"store_id":             bson.M{"$ifNull": bson.A{"$inventoryInfo.store_id", ""}},
+		"store_name":           bson.M{"$ifNull": bson.A{"$storeInfo.store_name", ""}},
+		"seller_id":            bson.M{"$ifNull": bson.A{"$inventoryInfo.seller_id", ""}},
+		"seller_name":          bson.M{"$ifNull": bson.A{"$sellerInfo.name", ""}},
+		"warehouse_id":         bson.M{"$ifNull": bson.A{"$storeInfo.warehouse_id", ""}},
+		"warehouse_name":       bson.M{"$ifNull": bson.A{"$warehouseInfo.warehouseName", ""}},
+		"metadata_product_id":  1,
+		"metadata_name":        bson.M{"$ifNull": bson.A{"$metadataInfo.metadata_name", ""}},
+		"category_id":          bson.M{"$ifNull": bson.A{"$metadataInfo.metadata_category_id", ""}},
+		"category_name":        bson.M{"$ifNull": bson.A{"$categoryInfo.category_name", ""}},
+		"quantity":             bson.M{"$ifNull": bson.A{"$product_quantity", 0}},
+		"price":                bson.M{"$ifNull": bson.A{"$product_price", 0}},
+		"mrp":                  bson.M{"$ifNull": bson.A{"$metadataInfo.metadata_mrp", 0}},
+		"unit_cost":            bson.M{"$ifNull": bson.A{"$unit_cost", 0}},
+	}}})
+
+	cursor, err := r.db.Collection("inventory_product").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var lines []*entities.InventorySnapshotLine
+	if err := cursor.All(ctx, &lines); err != nil {
+		return nil, err
+	}
+	if time.Since(asOf) > time.Minute {
+		if err := r.rewindSnapshotLines(ctx, lines, asOf); err != nil {
+			return nil, err
+		}
+	}
+
+	takenAt := time.Now().Truncate(time.Millisecond)
+	summary := &entities.InventorySnapshotSummary{SnapshotDate: snapshotDate, TakenAt: takenAt}
+	documents := make([]interface{}, 0, len(lines))
+	for _, line := range lines {
+		line.SnapshotDate = snapshotDate
+		line.TakenAt = takenAt
+		valueSnapshotLine(line)
+		summary.Products++
+		summary.Quantity += int64(line.Quantity)
+		summary.CostValue += line.CostValue
+		summary.PriceValue += line.PriceValue
+		summary.MRPValue += line.MRPValue
+		documents = append(documents, line)
+	}
+	summary.CostValue = math.Round(summary.CostValue*100) / 100
+	summary.PriceValue = math.Round(summary.PriceValue*100) / 100
+	summary.MRPValue = math.Round(summary.MRPValue*100) / 100
+
+	collection := r.db.Collection("inventory_snapshots")
+	for start := 0; start < len(documents); start += snapshotChunkSize {
+		end := min(start+snapshotChunkSize, len(documents))
+		if _, err := collection.InsertMany(ctx, documents[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	_, err = r.db.Collection("inventory_snapshot_runs").ReplaceOne(ctx, bson.M{"_id": snapshotDate}, summary, options.Replace().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+	// Lines of the replaced run, or of runs that failed before publishing, are no longer read
+	if _, err := collection.DeleteMany(ctx, bson.M{"snapshot_date": snapshotDate, "taken_at": bson.M{"$ne": takenAt}}); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// valueSnapshotLine values the stock of a line at its unit cost, price and MRP
+func valueSnapshotLine(line *entities.InventorySnapshotLine) {
+	line.CostValue = math.Round(float64(line.Quantity)*line.UnitCost*100) / 100
+	line.PriceValue = math.Round(float64(line.Quantity)*line.Price*100) / 100
+	line.MRPValue = math.Round(float64(line.Quantity)*line.MRP*100) / 100
+}
+
+// snapshotRewind is what changed on inventory products after a snapshot's asOf: the net quantity moved, and the
+// price, unit cost and MRP held at asOf of those whose values changed since. MRPs are keyed by metadata id.
+type snapshotRewind struct {
+	quantityChanges map[string]int
+	prices          map[string]float64
+	unitCosts       map[string]float64
+	mrps            map[string]float64
+}
+
+// apply takes the lines back to their values at asOf
+func (rewind *snapshotRewind) apply(lines []*entities.InventorySnapshotLine) {
+	for _, line := range lines {
+		line.Quantity -= rewind.quantityChanges[line.InventoryProductID]
+		if price, ok := rewind.prices[line.InventoryProductID]; ok {
+			line.Price = price
+		}
+		if unitCost, ok := rewind.unitCosts[line.InventoryProductID]; ok {
+			line.UnitCost = unitCost
+		}
+		if mrp, ok := rewind.mrps[line.MetadataProductID]; ok {
+			line.MRP = mrp
+		}
+	}
+}
+
+// rewindSnapshotLines takes the quantity, price, unit cost and MRP of each line back to what they were at asOf,
+// undoing the ledger movements, price changes, goods receipts and metadata edits recorded after it
+func (r *InventorySnapshotRepositoryMongoDB) rewindSnapshotLines(ctx context.Context, lines []*entities.InventorySnapshotLine, asOf time.Time) error {
+	rewind := &snapshotRewind{}
+	cursor, err := r.db.Collection("inventory_ledger").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gt": asOf}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$inventory_product_id", "change": bson.M{"$sum": "$quantity_change"}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var movements []struct {
+		InventoryProductID string `bson:"_id"`
+		Change             int    `bson:"change"`
+	}
+	if err := cursor.All(ctx, &movements); err != nil {
+		return err
+	}
+	rewind.quantityChanges = make(map[string]int, len(movements))
+	for _, movement := range movements {
+		rewind.quantityChanges[movement.InventoryProductID] = movement.Change
+	}
+
+	// The old value of the first change after asOf is the value the product had at asOf
+	firstOldValues := func(collection string, changes mongo.Pipeline, timeField, key, oldValue string) (map[string]float64, error) {
+		pipeline := append(changes,
+			bson.D{{Key: "$sort", Value: bson.D{{Key: timeField, Value: 1}, {Key: "_id", Value: 1}}}},
+			bson.D{{Key: "$group", Value: bson.M{"_id": "$" + key, "value": bson.M{"$first": "$" + oldValue}}}},
+		)
+		cursor, err := r.db.Collection(collection).Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		var firsts []struct {
+			ID    string  `bson:"_id"`
+			Value float64 `bson:"value"`
+		}
+		if err := cursor.All(ctx, &firsts); err != nil {
+			return nil, err
+		}
+		values := make(map[string]float64, len(firsts))
+		for _, first := range firsts {
+			values[first.ID] = first.Value
+		}
+		return values, nil
+	}
+	rewind.prices, err = firstOldValues("price_history", mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"changed_at": bson.M{"$gt": asOf}}}},
+	}, "changed_at", "inventory_product_id", "old_price")
+	if err != nil {
+		return err
+	}
+	rewind.unitCosts, err = firstOldValues("inventory_ledger", mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gt": asOf}, "unit_cost_before": bson.M{"$exists": true}}}},
+	}, "created_at", "inventory_product_id", "unit_cost_before")
+	if err != nil {
+		return err
+	}
+	rewind.mrps, err = firstOldValues("metadata_revisions", mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"edited_at": bson.M{"$gt": asOf}, "changes.field": "mrp"}}},
+		{{Key: "$unwind", Value: "$changes"}},
+		{{Key: "$match", Value: bson.M{"changes.field": "mrp"}}},
+	}, "edited_at", "metadata_product_id", "changes.old_value")
+	if err != nil {
+		return err
+	}
+
+	rewind.apply(lines)
+	return nil
+}
+
+// GetInventorySnapshots lists the published snapshots, latest first
+func (r *InventorySnapshotRepositoryMongoDB) GetInventorySnapshots(ctx context.Context) ([]*entities.InventorySnapshotSummary, error) {
+	cursor, err := r.db.Collection("inventory_snapshot_runs").Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	snapshots := []*entities.InventorySnapshotSummary{}
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (r *InventorySnapshotRepositoryMongoDB) HasInventorySnapshot(ctx context.Context, snapshotDate string) (bool, error) {
+	count, err := r.db.Collection("inventory_snapshot_runs").CountDocuments(ctx, bson.M{"_id": snapshotDate}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AcquireSnapshotLock takes the lock document of the daily snapshot for holder until the lease runs out. It fails
+// while another holder's lease is running, so only one instance takes the snapshots.
+func (r *InventorySnapshotRepositoryMongoDB) AcquireSnapshotLock(ctx context.Context, holder string, lease time.Duration) (bool, error) {
+	now := time.Now()
+	_, err := r.db.Collection("job_locks").UpdateOne(ctx,
+		bson.M{"_id": inventorySnapshotLock, "$or": bson.A{
+			bson.M{"locked_until": bson.M{"$lte": now}},
+			bson.M{"holder": holder},
+		}},
+		bson.M{"$set": bson.M{"holder": holder, "locked_until": now.Add(lease)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseSnapshotLock ends the lease of holder, if it still holds the lock
+func (r *InventorySnapshotRepositoryMongoDB) ReleaseSnapshotLock(ctx context.Context, holder string) error {
+	_, err := r.db.Collection("job_locks").UpdateOne(ctx,
+		bson.M{"_id": inventorySnapshotLock, "holder": holder},
+		bson.M{"$set": bson.M{"locked_until": time.Now()}},
+	)
+	return err
+}
+
+// GetInventoryValuation totals a snapshot by the requested grouping. Operations users only see the warehouses
+// they run.
+func (r *InventorySnapshotRepositoryMongoDB) GetInventoryValuation(ctx context.Context, request *entities.InventoryValuationRequest, snapshotDate string) ([]*entities.InventoryValuationGroup, error) {
+	var run entities.InventorySnapshotSummary
+	if err := r.db.Collection("inventory_snapshot_runs").FindOne(ctx, bson.M{"_id": snapshotDate}).Decode(&run); err != nil {
+		return nil, err
+	}
+	match := bson.M{"snapshot_date": snapshotDate, "taken_at": run.TakenAt}
+	if request.WarehouseID != "" {
+		match["warehouse_id"] = request.WarehouseID
+	}
+	if request.StoreID != "" {
+		match["store_id"] = request.StoreID
+	}
+	if request.SellerID != "" {
+		match["seller_id"] = request.SellerID
+	}
+	if request.CategoryID != "" {
+		match["category_id"] = request.CategoryID
+	}
+	if request.OperationalID != "" {
+		cursor, err := r.db.Collection("warehouses").Find(ctx, bson.M{"warehouse_operational_guy_id": request.OperationalID})
+		if err != nil {
+			return nil, err
+		}
+		var warehouses []*entities.Warehouse
+		if err := cursor.All(ctx, &warehouses); err != nil {
+			return nil, err
+		}
+		warehouseIds := make([]string, 0, len(warehouses))
+		for _, warehouse := range warehouses {
+			if request.WarehouseID == "" || request.WarehouseID == warehouse.ID {
+				warehouseIds = append(warehouseIds, warehouse.ID)
+			}
+		}
+		match["warehouse_id"] = bson.M{"$in": warehouseIds}
+	}
+
+	groupFields := valuationGroupFields[request.GroupBy]
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         groupFields[0],
+			"group_name":  bson.M{"$first": groupFields[1]},
+			"products":    bson.M{"$sum": 1},
+			"quantity":    bson.M{"$sum": "$quantity"},
+			"cost_value":  bson.M{"$sum": "$cost_value"},
+			"price_value": bson.M{"$sum": "$price_value"},
+			"mrp_value":   bson.M{"$sum": "$mrp_value"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "price_value", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	cursor, err := r.db.Collection("inventory_snapshots").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	groups := []*entities.InventoryValuationGroup{}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		group.CostValue = math.Round(group.CostValue*100) / 100
+		group.PriceValue = math.Round(group.PriceValue*100) / 100
+		group.MRPValue = math.Round(group.MRPValue*100) / 100
+	}
+	return groups, nil
+}
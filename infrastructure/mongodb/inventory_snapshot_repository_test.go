@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"espazeBackend/domain/entities"
+	"testing"
+)
+
+func TestSnapshotRewind(t *testing.T) {
+	rewind := &snapshotRewind{
+		quantityChanges: map[string]int{"sold": -3, "received": 12},
+		prices:          map[string]float64{"repriced": 45},
+		unitCosts:       map[string]float64{"received": 30},
+		mrps:            map[string]float64{"mrp-raised": 60},
+	}
+	tests := []struct {
+		name     string
+		line     entities.InventorySnapshotLine
+		quantity int
+		price    float64
+		unitCost float64
+		mrp      float64
+	}{
+		{
+			name:     "unchanged since",
+			line:     entities.InventorySnapshotLine{InventoryProductID: "idle", MetadataProductID: "metadata", Quantity: 5, Price: 40, UnitCost: 25, MRP: 50},
+			quantity: 5, price: 40, unitCost: 25, mrp: 50,
+		},
+		{
+			name:     "sales are put back",
+			line:     entities.InventorySnapshotLine{InventoryProductID: "sold", MetadataProductID: "metadata", Quantity: 2, Price: 40, UnitCost: 25, MRP: 50},
+			quantity: 5, price: 40, unitCost: 25, mrp: 50,
+		},
+		{
+			name:     "receipts are taken out with the cost they averaged in",
+			line:     entities.InventorySnapshotLine{InventoryProductID: "received", MetadataProductID: "metadata", Quantity: 15, Price: 40, UnitCost: 34, MRP: 50},
+			quantity: 3, price: 40, unitCost: 30, mrp: 50,
+		},
+		{
+			name:     "price goes back to the one at the time",
+			line:     entities.InventorySnapshotLine{InventoryProductID: "repriced", MetadataProductID: "metadata", Quantity: 5, Price: 38, UnitCost: 25, MRP: 50},
+			quantity: 5, price: 45, unitCost: 25, mrp: 50,
+		},
+		{
+			name:     "mrp goes back by metadata",
+			line:     entities.InventorySnapshotLine{InventoryProductID: "idle", MetadataProductID: "mrp-raised", Quantity: 5, Price: 40, UnitCost: 25, MRP: 65},
+			quantity: 5, price: 40, unitCost: 25, mrp: 60,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := tt.line
+			rewind.apply([]*entities.InventorySnapshotLine{&line})
+			if line.Quantity != tt.quantity || line.Price != tt.price || line.UnitCost != tt.unitCost || line.MRP != tt.mrp {
+				t.Errorf("rewound to %d at %.2f, cost %.2f, mrp %.2f, want %d at %.2f, cost %.2f, mrp %.2f",
+					line.Quantity, line.Price, line.UnitCost, line.MRP, tt.quantity, tt.price, tt.unitCost, tt.mrp)
+			}
+		})
+	}
+}
+
+func TestValueSnapshotLine(t *testing.T) {
+	tests := []struct {
+		line                            entities.InventorySnapshotLine
+		costValue, priceValue, mrpValue float64
+	}{
+		{line: entities.InventorySnapshotLine{Quantity: 3, UnitCost: 10.333, Price: 12.5, MRP: 15}, costValue: 31, priceValue: 37.5, mrpValue: 45},
+		{line: entities.InventorySnapshotLine{Quantity: 7, Price: 19.99, MRP: 24.99}, priceValue: 139.93, mrpValue: 174.93},
+		{line: entities.InventorySnapshotLine{Quantity: 0, UnitCost: 10, Price: 12, MRP: 15}},
+		{line: entities.InventorySnapshotLine{Quantity: -2, UnitCost: 10, Price: 12, MRP: 15}, costValue: -20, priceValue: -24, mrpValue: -30},
+	}
+	for _, tt := range tests {
+		line := tt.line
+		valueSnapshotLine(&line)
+		if line.CostValue != tt.costValue || line.PriceValue != tt.priceValue || line.MRPValue != tt.mrpValue {
+			t.Errorf("valueSnapshotLine(%d units) = %.2f, %.2f, %.2f, want %.2f, %.2f, %.2f", tt.line.Quantity,
+				line.CostValue, line.PriceValue, line.MRPValue, tt.costValue, tt.priceValue, tt.mrpValue)
+		}
+	}
+}
+
+func TestAverageUnitCost(t *testing.T) {
+	tests := []struct {
+		quantity     int
+		cost         float64
+		received     int
+		receivedCost float64
+		want         float64
+	}{
+		{quantity: 10, cost: 20, received: 10, receivedCost: 30, want: 25},
+		{quantity: 3, cost: 10, received: 1, receivedCost: 11, want: 10.25},
+		{quantity: 2, cost: 10, received: 1, receivedCost: 10.01, want: 10},
+		{quantity: 0, cost: 20, received: 5, receivedCost: 30, want: 30},
+		{quantity: -2, cost: 20, received: 5, receivedCost: 30, want: 30},
+		{quantity: 10, cost: 0, received: 5, receivedCost: 30, want: 30},
+	}
+	for _, tt := range tests {
+		if got := averageUnitCost(tt.quantity, tt.cost, tt.received, tt.receivedCost); got != tt.want {
+			t.Errorf("averageUnitCost(%d, %.2f, %d, %.2f) = %.4f, want %.2f", tt.quantity, tt.cost, tt.received, tt.receivedCost, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MediaRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewMediaRepositoryMongoDB(db *mongo.Database) repositories.MediaRepository {
+	return &MediaRepositoryMongoDB{db: db}
+}
+
+// mediaFilePath is where uploaded images are served, followed by the media id
+const mediaFilePath = "/media/file/"
+
+// mediaOwners maps an owner type to its collection and the field holding its main image
+var mediaOwners = map[string]struct {
+	collection string
+	imageField string
+}{
+	entities.MediaOwnerMetadata:    {"metadata", "metadata_image"},
+	entities.MediaOwnerCategory:    {"categories", "category_image"},
+	entities.MediaOwnerSubcategory: {"subcategories", "subcategory_image"},
+	entities.MediaOwnerStore:       {"stores", "store_image"},
+}
+
+func mediaOwnerFilter(ownerType, ownerId string) (string, string, bson.M, error) {
+	owner, ok := mediaOwners[ownerType]
+	if !ok {
+		return "", "", nil, fmt.Errorf("invalid owner type %s", ownerType)
+	}
+	objectId, err := primitive.ObjectIDFromHex(ownerId)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid owner id")
+	}
+	return owner.collection, owner.imageField, bson.M{"_id": objectId}, nil
+}
+
+// GetMediaOwnerSeller checks the owner exists and returns the seller of a store owner, empty for other owners
+func (r *MediaRepositoryMongoDB) GetMediaOwnerSeller(ctx context.Context, ownerType, ownerId string) (string, error) {
+	collection, _, filter, err := mediaOwnerFilter(ownerType, ownerId)
+	if err != nil {
+		return "", err
+	}
+	var owner struct {
+		SellerID string `bson:"seller_id"`
+	}
+	if err := r.db.Collection(collection).FindOne(ctx, filter).Decode(&owner); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", fmt.Errorf("%s not found", ownerType)
+		}
+		return "", err
+	}
+	return owner.SellerID, nil
+}
+
+// mediaMetadataOwner reads the metadata an image belongs to, nil for other owners, so the change the image makes
+// can be recorded as a revision
+func mediaMetadataOwner(ctx context.Context, db *mongo.Database, media *entities.Media) (*entities.Metadata, error) {
+	if media.OwnerType != entities.MediaOwnerMetadata {
+		return nil, nil
+	}
+	metadata, err := findMetadata(ctx, db, media.OwnerID)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("metadata not found")
+	}
+	return metadata, err
+}
+
+// recordMediaRevision records the change an image made to the main image or gallery of a metadata
+func recordMediaRevision(ctx context.Context, db *mongo.Database, before *entities.Metadata, editedBy string) error {
+	after, err := findMetadata(ctx, db, before.MetadataProductID)
+	if err != nil {
+		return err
+	}
+	return recordMetadataRevision(ctx, db, before, after, entities.MetadataRevisionMedia, editedBy, nil)
+}
+
+// CreateMedia records an uploaded image and attaches it to its owner. Metadata keeps every image in its gallery;
+// other owners have a single image, which the upload replaces. The replaced image records are deleted and returned
+// so their files can be removed.
+func (r *MediaRepositoryMongoDB) CreateMedia(ctx context.Context, media *entities.Media, primary bool) (*entities.Media, []*entities.Media, error) {
+	collection, imageField, filter, err := mediaOwnerFilter(media.OwnerType, media.OwnerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var replaced []*entities.Media
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		replaced = nil
+		before, err := mediaMetadataOwner(sc, r.db, media)
+		if err != nil {
+			return nil, err
+		}
+		result, err := r.db.Collection("media").InsertOne(sc, media)
+		if err != nil {
+			return nil, err
+		}
+		insertedId, ok := result.InsertedID.(primitive.ObjectID)
+		if !ok {
+			return nil, fmt.Errorf("error in getting inserted media id")
+		}
+		media.ID = insertedId.Hex()
+		media.URL = mediaFilePath + media.ID
+		media.ThumbnailURL = media.URL + "?size=" + entities.MediaSizeThumbnail
+		_, err = r.db.Collection("media").UpdateOne(sc,
+			bson.M{"_id": insertedId},
+			bson.M{"$set": bson.M{"url": media.URL, "thumbnail_url": media.ThumbnailURL}},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		update := bson.M{"$set": bson.M{imageField: media.URL}}
+		if media.OwnerType == entities.MediaOwnerMetadata {
+			update = bson.M{"$push": bson.M{"metadata_gallery": media.URL}, "$set": bson.M{"metadata_updated_at": time.Now()}}
+			if primary {
+				update["$set"].(bson.M)[imageField] = media.URL
+			} else {
+				// the first image becomes the main one
+				_, err := r.db.Collection(collection).UpdateOne(sc,
+					bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{bson.M{imageField: ""}, bson.M{imageField: bson.M{"$exists": false}}}}}},
+					bson.M{"$set": bson.M{imageField: media.URL}},
+				)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		owner, err := r.db.Collection(collection).UpdateOne(sc, filter, update)
+		if err != nil {
+			return nil, err
+		}
+		if owner.MatchedCount == 0 {
+			return nil, fmt.Errorf("%s not found", media.OwnerType)
+		}
+		if before != nil {
+			return nil, recordMediaRevision(sc, r.db, before, media.UploadedBy)
+		}
+
+		previous := bson.M{"owner_type": media.OwnerType, "owner_id": media.OwnerID, "_id": bson.M{"$ne": insertedId}}
+		cursor, err := r.db.Collection("media").Find(sc, previous)
+		if err != nil {
+			return nil, err
+		}
+		if err := cursor.All(sc, &replaced); err != nil {
+			return nil, err
+		}
+		_, err = r.db.Collection("media").DeleteMany(sc, previous)
+		return nil, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return media, replaced, nil
+}
+
+func (r *MediaRepositoryMongoDB) GetMediaById(ctx context.Context, mediaId string) (*entities.Media, error) {
+	objectId, err := primitive.ObjectIDFromHex(mediaId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid media id")
+	}
+	var media entities.Media
+	if err := r.db.Collection("media").FindOne(ctx, bson.M{"_id": objectId}).Decode(&media); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("media not found")
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *MediaRepositoryMongoDB) GetMediaForOwner(ctx context.Context, ownerType, ownerId string) ([]*entities.Media, error) {
+	cursor, err := r.db.Collection("media").Find(ctx,
+		bson.M{"owner_type": ownerType, "owner_id": ownerId},
+		options.Find().SetSort(bson.M{"created_at": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	media := []*entities.Media{}
+	if err := cursor.All(ctx, &media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+func (r *MediaRepositoryMongoDB) SetPrimaryMedia(ctx context.Context, media *entities.Media, editedBy string) error {
+	collection, imageField, filter, err := mediaOwnerFilter(media.OwnerType, media.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		before, err := mediaMetadataOwner(sc, r.db, media)
+		if err != nil {
+			return nil, err
+		}
+		set := bson.M{imageField: media.URL}
+		if before != nil {
+			set["metadata_updated_at"] = time.Now()
+		}
+		if _, err := r.db.Collection(collection).UpdateOne(sc, filter, bson.M{"$set": set}); err != nil {
+			return nil, err
+		}
+		if before != nil {
+			return nil, recordMediaRevision(sc, r.db, before, editedBy)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// DeleteMedia removes an image record and detaches it from its owner. A deleted main image of a metadata product
+// is replaced by the next image in its gallery.
+func (r *MediaRepositoryMongoDB) DeleteMedia(ctx context.Context, media *entities.Media, editedBy string) error {
+	collection, imageField, filter, err := mediaOwnerFilter(media.OwnerType, media.OwnerID)
+	if err != nil {
+		return err
+	}
+	objectId, err := primitive.ObjectIDFromHex(media.ID)
+	if err != nil {
+		return fmt.Errorf("invalid media id")
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if _, err := r.db.Collection("media").DeleteOne(sc, bson.M{"_id": objectId}); err != nil {
+			return nil, err
+		}
+
+		if media.OwnerType != entities.MediaOwnerMetadata {
+			_, err := r.db.Collection(collection).UpdateOne(sc,
+				bson.M{"$and": bson.A{filter, bson.M{imageField: media.URL}}},
+				bson.M{"$set": bson.M{imageField: ""}},
+			)
+			return nil, err
+		}
+
+		before, err := findMetadata(sc, r.db, media.OwnerID)
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		gallery := []string{}
+		for _, url := range before.MetadataGallery {
+			if url != media.URL {
+				gallery = append(gallery, url)
+			}
+		}
+		set := bson.M{"metadata_gallery": gallery, "metadata_updated_at": time.Now()}
+		if before.MetadataImage == media.URL {
+			next := ""
+			if len(gallery) > 0 {
+				next = gallery[0]
+			}
+			set[imageField] = next
+		}
+		if _, err := r.db.Collection(collection).UpdateOne(sc, filter, bson.M{"$set": set}); err != nil {
+			return nil, err
+		}
+		return nil, recordMediaRevision(sc, r.db, before, editedBy)
+	})
+	return err
+}
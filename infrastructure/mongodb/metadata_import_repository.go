@@ -0,0 +1,172 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MetadataImportRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewMetadataImportRepositoryMongoDB(db *mongo.Database) repositories.MetadataImportRepository {
+	return &MetadataImportRepositoryMongoDB{db: db}
+}
+
+func (r *MetadataImportRepositoryMongoDB) CreateImportJob(ctx context.Context, job *entities.MetadataImportJob) (*entities.MetadataImportJob, error) {
+	result, err := r.db.Collection("metadata_import_jobs").InsertOne(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	job.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return job, nil
+}
+
+// UpdateImportJob saves the progress of a running job
+func (r *MetadataImportRepositoryMongoDB) UpdateImportJob(ctx context.Context, job *entities.MetadataImportJob) error {
+	objectId, err := primitive.ObjectIDFromHex(job.ID)
+	if err != nil {
+		return fmt.Errorf("invalid import job id")
+	}
+	_, err = r.db.Collection("metadata_import_jobs").UpdateOne(ctx, bson.M{"_id": objectId}, bson.M{"$set": bson.M{
+		"status":         job.Status,
+		"total_rows":     job.TotalRows,
+		"processed_rows": job.ProcessedRows,
+		"created_count":  job.CreatedCount,
+		"updated_count":  job.UpdatedCount,
+		"failed_count":   job.FailedCount,
+		"errors":         job.Errors,
+		"failure":        job.Failure,
+		"started_at":     job.StartedAt,
+		"finished_at":    job.FinishedAt,
+		"updated_at":     job.UpdatedAt,
+	}})
+	return err
+}
+
+// FailStaleImportJobs marks failed the queued and running jobs not saved since savedBefore, which were lost with
+// the server running them
+func (r *MetadataImportRepositoryMongoDB) FailStaleImportJobs(ctx context.Context, savedBefore time.Time, failure string) (int64, error) {
+	result, err := r.db.Collection("metadata_import_jobs").UpdateMany(ctx,
+		bson.M{
+			"status":     bson.M{"$in": bson.A{entities.MetadataImportQueued, entities.MetadataImportRunning}},
+			"updated_at": bson.M{"$lt": savedBefore},
+		},
+		bson.M{"$set": bson.M{
+			"status":      entities.MetadataImportFailed,
+			"failure":     failure,
+			"finished_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MetadataImportRepositoryMongoDB) GetImportJob(ctx context.Context, jobId string) (*entities.MetadataImportJob, error) {
+	objectId, err := primitive.ObjectIDFromHex(jobId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import job id")
+	}
+	var job entities.MetadataImportJob
+	err = r.db.Collection("metadata_import_jobs").FindOne(ctx, bson.M{"_id": objectId}).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("import job not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetImportJobs lists the newest jobs first, without their row errors which are fetched per job
+func (r *MetadataImportRepositoryMongoDB) GetImportJobs(ctx context.Context, limit, offset int64) ([]*entities.MetadataImportJob, int64, error) {
+	collection := r.db.Collection("metadata_import_jobs")
+	total, err := collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().
+		SetProjection(bson.M{"errors": 0}).
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(offset).
+		SetLimit(limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []*entities.MetadataImportJob{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+func (r *MetadataImportRepositoryMongoDB) GetCategoriesWithSubcategories(ctx context.Context) ([]*entities.Category, []*entities.Subcategory, error) {
+	var categories []*entities.Category
+	cursor, err := r.db.Collection("categories").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, nil, err
+	}
+
+	var subcategories []*entities.Subcategory
+	cursor, err = r.db.Collection("subcategories").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cursor.All(ctx, &subcategories); err != nil {
+		return nil, nil, err
+	}
+	return categories, subcategories, nil
+}
+
+func (r *MetadataImportRepositoryMongoDB) ResolveHSNCodes(ctx context.Context, hsnCodes []string) (map[string]*entities.HSNCode, error) {
+	return resolveHSNCodes(ctx, r.db, hsnCodes)
+}
+
+func (r *MetadataImportRepositoryMongoDB) GetMetadataByHSNCodes(ctx context.Context, hsnCodes []string) ([]*entities.Metadata, error) {
+	var metadata []*entities.Metadata
+	if len(hsnCodes) == 0 {
+		return metadata, nil
+	}
+	cursor, err := r.db.Collection("metadata").Find(ctx, bson.M{"hsn_code": bson.M{"$in": hsnCodes}})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (r *MetadataImportRepositoryMongoDB) GetMetadataInSubcategories(ctx context.Context, subcategoryIds []string) ([]*entities.Metadata, error) {
+	var metadata []*entities.Metadata
+	if len(subcategoryIds) == 0 {
+		return metadata, nil
+	}
+	cursor, err := r.db.Collection("metadata").Find(ctx,
+		bson.M{"metadata_subcategory_id": bson.M{"$in": subcategoryIds}},
+		options.Find().SetProjection(bson.M{"metadata_name": 1, "hsn_code": 1, "metadata_subcategory_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
@@ -0,0 +1,315 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MetadataMergeRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewMetadataMergeRepositoryMongoDB(db *mongo.Database) repositories.MetadataMergeRepository {
+	return &MetadataMergeRepositoryMongoDB{db: db}
+}
+
+// Collections whose documents embed items that point at metadata, keyed by the array holding them
+var metadataItemArrays = map[string]string{
+	"inbound_shipments":    "items",
+	"stock_transfers":      "items",
+	"cycle_count_sessions": "lines",
+}
+
+func (r *MetadataMergeRepositoryMongoDB) GetMetadataForDuplicateScan(ctx context.Context, subcategoryId string) ([]*entities.Metadata, error) {
+	filter := bson.M{}
+	if subcategoryId != "" {
+		filter["metadata_subcategory_id"] = subcategoryId
+	}
+	cursor, err := r.db.Collection("metadata").Find(ctx, filter, options.Find().SetProjection(bson.M{
+		"metadata_name":           1,
+		"hsn_code":                1,
+		"metadata_mrp":            1,
+		"metadata_image":          1,
+		"metadata_category_id":    1,
+		"metadata_subcategory_id": 1,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metadata []*entities.Metadata
+	if err := cursor.All(ctx, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (r *MetadataMergeRepositoryMongoDB) GetDismissedDuplicates(ctx context.Context) ([]*entities.DismissedDuplicate, error) {
+	cursor, err := r.db.Collection("metadata_duplicate_dismissals").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dismissals []*entities.DismissedDuplicate
+	if err := cursor.All(ctx, &dismissals); err != nil {
+		return nil, err
+	}
+	return dismissals, nil
+}
+
+func (r *MetadataMergeRepositoryMongoDB) DismissDuplicate(ctx context.Context, dismissal *entities.DismissedDuplicate) error {
+	_, err := r.db.Collection("metadata_duplicate_dismissals").UpdateOne(ctx,
+		bson.M{"left_id": dismissal.LeftID, "right_id": dismissal.RightID},
+		bson.M{"$set": bson.M{"dismissed_by": dismissal.DismissedBy, "dismissed_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// MergeMetadata folds the merged metadata into the survivor in one transaction. Listings, stock history, ordered items,
+// reviews, proposals, barcodes and images move to the survivor before the merged metadata is deleted; ordered items keep
+// the merged id their revision belongs to. A merge that would give an inventory listings of both products is refused,
+// the seller combines them first. Inventory snapshots are left as they were taken.
+func (r *MetadataMergeRepositoryMongoDB) MergeMetadata(ctx context.Context, request *entities.MergeMetadataRequest) (*entities.MetadataMerge, error) {
+	survivorObjectId, err := primitive.ObjectIDFromHex(request.SurvivorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid survivor id")
+	}
+	mergedObjectId, err := primitive.ObjectIDFromHex(request.MergedID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merged id")
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var survivor, merged entities.Metadata
+		if err := r.db.Collection("metadata").FindOne(sc, bson.M{"_id": survivorObjectId}).Decode(&survivor); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("survivor metadata not found")
+			}
+			return nil, err
+		}
+		if err := r.db.Collection("metadata").FindOne(sc, bson.M{"_id": mergedObjectId}).Decode(&merged); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("metadata to merge not found")
+			}
+			return nil, err
+		}
+
+		audit := &entities.MetadataMerge{
+			SurvivorID:     request.SurvivorID,
+			MergedID:       request.MergedID,
+			Survivor:       &survivor,
+			Merged:         &merged,
+			Repointed:      make(map[string]int64),
+			DroppedReviews: []*entities.ProductReview{},
+			Reason:         request.Reason,
+			MergedBy:       request.MergedBy,
+			CreatedAt:      time.Now(),
+		}
+		inventoryIds, err := r.db.Collection("inventory_product").Distinct(sc, "inventory_id", bson.M{"metadata_product_id": request.MergedID})
+		if err != nil {
+			return nil, err
+		}
+		if len(inventoryIds) > 0 {
+			shared, err := r.db.Collection("inventory_product").Distinct(sc, "inventory_id", bson.M{
+				"metadata_product_id": request.SurvivorID,
+				"inventory_id":        bson.M{"$in": inventoryIds},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(shared) > 0 {
+				return nil, fmt.Errorf("%d inventories list both products, combine their listings before merging: %v", len(shared), shared)
+			}
+		}
+
+		repoint := bson.M{"$set": bson.M{"metadata_product_id": request.SurvivorID}}
+
+		for _, collection := range []string{"inventory_product", "inventory_ledger", "catalog_proposals", "barcodes"} {
+			updated, err := r.db.Collection(collection).UpdateMany(sc, bson.M{"metadata_product_id": request.MergedID}, repoint)
+			if err != nil {
+				return nil, err
+			}
+			audit.Repointed[collection] = updated.ModifiedCount
+		}
+		// an item merged again keeps the id its revision was first recorded under
+		updated, err := r.db.Collection("orderedItems").UpdateMany(sc,
+			bson.M{"metadata_product_id": request.MergedID},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{
+				"metadata_product_id":     request.SurvivorID,
+				"merged_from_metadata_id": bson.M{"$ifNull": bson.A{"$merged_from_metadata_id", request.MergedID}},
+			}}}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		audit.Repointed["orderedItems"] = updated.ModifiedCount
+		for collection, array := range metadataItemArrays {
+			updated, err := r.db.Collection(collection).UpdateMany(sc,
+				bson.M{array + ".metadata_product_id": request.MergedID},
+				bson.M{"$set": bson.M{array + ".$[item].metadata_product_id": request.SurvivorID}},
+				options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"item.metadata_product_id": request.MergedID}}}),
+			)
+			if err != nil {
+				return nil, err
+			}
+			audit.Repointed[collection] = updated.ModifiedCount
+		}
+
+		updated, err = r.db.Collection("media").UpdateMany(sc,
+			bson.M{"owner_type": entities.MediaOwnerMetadata, "owner_id": request.MergedID},
+			bson.M{"$set": bson.M{"owner_id": request.SurvivorID}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		audit.Repointed["media"] = updated.ModifiedCount
+
+		// a customer keeps one review per product, the newer of the two when they reviewed both
+		cursor, err := r.db.Collection("product_reviews").Find(sc, bson.M{"metadata_product_id": request.MergedID})
+		if err != nil {
+			return nil, err
+		}
+		var mergedReviews []*entities.ProductReview
+		if err := cursor.All(sc, &mergedReviews); err != nil {
+			return nil, err
+		}
+		for _, review := range mergedReviews {
+			var kept entities.ProductReview
+			err := r.db.Collection("product_reviews").FindOne(sc, bson.M{"metadata_product_id": request.SurvivorID, "user_id": review.UserID}).Decode(&kept)
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			dropped := review
+			if review.UpdatedAt.After(kept.UpdatedAt) {
+				dropped = &kept
+			}
+			droppedObjectId, err := primitive.ObjectIDFromHex(dropped.ID)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := r.db.Collection("product_reviews").DeleteOne(sc, bson.M{"_id": droppedObjectId}); err != nil {
+				return nil, err
+			}
+			audit.DroppedReviews = append(audit.DroppedReviews, dropped)
+		}
+		updated, err = r.db.Collection("product_reviews").UpdateMany(sc, bson.M{"metadata_product_id": request.MergedID}, repoint)
+		if err != nil {
+			return nil, err
+		}
+		audit.Repointed["product_reviews"] = updated.ModifiedCount
+
+		survivorUpdate := bson.M{"metadata_updated_at": time.Now()}
+		if survivor.MetadataImage == "" && merged.MetadataImage != "" {
+			survivorUpdate["metadata_image"] = merged.MetadataImage
+		}
+		update := bson.M{"$set": survivorUpdate}
+		if len(merged.MetadataGallery) > 0 {
+			update["$addToSet"] = bson.M{"metadata_gallery": bson.M{"$each": merged.MetadataGallery}}
+		}
+		if _, err := r.db.Collection("metadata").UpdateByID(sc, survivorObjectId, update); err != nil {
+			return nil, err
+		}
+		mergedSurvivor, err := findMetadata(sc, r.db, request.SurvivorID)
+		if err != nil {
+			return nil, err
+		}
+		if err := recordMetadataRevision(sc, r.db, &survivor, mergedSurvivor, entities.MetadataRevisionMerge, request.MergedBy, nil); err != nil {
+			return nil, err
+		}
+
+		if _, err := r.db.Collection("metadata").DeleteOne(sc, bson.M{"_id": mergedObjectId}); err != nil {
+			return nil, err
+		}
+		// the baseline of the merged product moves to the survivor with its reviews
+		var mergedTotals entities.Review
+		err = r.db.Collection("reviews").FindOneAndUpdate(sc, bson.M{"_id": request.MergedID},
+			mongo.Pipeline{reviewBaselineStage},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&mergedTotals)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		if _, err := r.db.Collection("reviews").DeleteOne(sc, bson.M{"_id": request.MergedID}); err != nil {
+			return nil, err
+		}
+		_, err = r.db.Collection("reviews").UpdateOne(sc,
+			bson.M{"_id": request.SurvivorID},
+			mongo.Pipeline{
+				reviewBaselineStage,
+				{{Key: "$set", Value: bson.M{
+					"baseline_stars":   bson.M{"$add": bson.A{"$baseline_stars", mergedTotals.BaselineStars}},
+					"baseline_reviews": bson.M{"$add": bson.A{"$baseline_reviews", mergedTotals.BaselineReviews}},
+				}}},
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := refreshReviewAggregate(sc, r.db, request.SurvivorID); err != nil {
+			return nil, err
+		}
+
+		inserted, err := r.db.Collection("metadata_merges").InsertOne(sc, audit)
+		if err != nil {
+			return nil, err
+		}
+		audit.ID = inserted.InsertedID.(primitive.ObjectID).Hex()
+		return audit, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateCatalogSearch()
+	return result.(*entities.MetadataMerge), nil
+}
+
+// GetMetadataMerges lists the newest merges first, those a metadata took part in when metadataId is set
+func (r *MetadataMergeRepositoryMongoDB) GetMetadataMerges(ctx context.Context, metadataId string, limit, offset int64) ([]*entities.MetadataMerge, int64, error) {
+	collection := r.db.Collection("metadata_merges")
+	filter := bson.M{}
+	if metadataId != "" {
+		filter["$or"] = bson.A{bson.M{"survivor_id": metadataId}, bson.M{"merged_id": metadataId}}
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(offset).
+		SetLimit(limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	merges := []*entities.MetadataMerge{}
+	if err := cursor.All(ctx, &merges); err != nil {
+		return nil, 0, err
+	}
+	return merges, total, nil
+}
@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"espazeBackend/domain/entities"
@@ -11,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // MetadataRepositoryMongoDB implements the MetadataRepository interface using MongoDB
@@ -25,22 +27,130 @@ func NewMetadataRepositoryMongoDB(db *mongo.Database) repositories.MetadataRepos
 	}
 }
 
-// GetAllMetadata retrieves all metadata with pagination
+// rankedMetadataPage runs a catalog search and returns the requested page of matching metadata ids in relevance
+// order, along with the number of matches. Only metadata also matching filter is kept.
+func (r *MetadataRepositoryMongoDB) rankedMetadataPage(ctx context.Context, search string, filter bson.M, limit, offset int64) ([]primitive.ObjectID, int64, error) {
+	hits, err := searchCatalog(ctx, r.db, search)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(hits))
+	for _, hit := range hits {
+		if id, err := primitive.ObjectIDFromHex(hit.MetadataID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(filter) > 0 && len(ids) > 0 {
+		cursor, err := r.db.Collection("metadata").Find(ctx,
+			bson.M{"$and": bson.A{filter, bson.M{"_id": bson.M{"$in": ids}}}},
+			options.Find().SetProjection(bson.M{"_id": 1}),
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		var allowed []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &allowed); err != nil {
+			return nil, 0, err
+		}
+		allowedIds := make(map[primitive.ObjectID]bool, len(allowed))
+		for _, doc := range allowed {
+			allowedIds[doc.ID] = true
+		}
+		filtered := ids[:0]
+		for _, id := range ids {
+			if allowedIds[id] {
+				filtered = append(filtered, id)
+			}
+		}
+		ids = filtered
+	}
+
+	total := int64(len(ids))
+	start := min(offset*limit, total)
+	end := min(start+limit, total)
+	return ids[start:end], total, nil
+}
+
+// sortByRank orders metadata the same way as the ranked ids they were fetched by
+func sortByRank(metadata []*entities.GetAllMetadata, ids []primitive.ObjectID) {
+	rank := make(map[string]int, len(ids))
+	for i, id := range ids {
+		rank[id.Hex()] = i
+	}
+	sort.SliceStable(metadata, func(i, j int) bool {
+		return rank[metadata[i].ID] < rank[metadata[j].ID]
+	})
+}
+
+// getMetadataByIds fetches metadata listing rows for ranked ids, keeping their order
+func (r *MetadataRepositoryMongoDB) getMetadataByIds(ctx context.Context, ids []primitive.ObjectID) ([]*entities.GetAllMetadata, error) {
+	results := []*entities.GetAllMetadata{}
+	if len(ids) == 0 {
+		return results, nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": ids}}}},
+		{{Key: "$addFields", Value: bson.M{
+			"category_oid":    bson.M{"$toObjectId": "$metadata_category_id"},
+			"subcategory_oid": bson.M{"$toObjectId": "$metadata_subcategory_id"},
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": "categories", "localField": "category_oid", "foreignField": "_id", "as": "category_info"}}},
+		{{Key: "$unwind", Value: "$category_info"}},
+		{{Key: "$lookup", Value: bson.M{"from": "subcategories", "localField": "subcategory_oid", "foreignField": "_id", "as": "subcategory_info"}}},
+		{{Key: "$unwind", Value: "$subcategory_info"}},
+		{{Key: "$project", Value: bson.M{
+			"_id":              bson.M{"$toString": "$_id"},
+			"hsn_code":         "$hsn_code",
+			"name":             "$metadata_name",
+			"description":      "$metadata_description",
+			"image":            "$metadata_image",
+			"category_id":      "$metadata_category_id",
+			"category_name":    "$category_info.category_name",
+			"subcategory_id":   "$metadata_subcategory_id",
+			"subcategory_name": "$subcategory_info.subcategory_name",
+			"mrp":              "$metadata_mrp",
+			"created_at":       "$metadata_created_at",
+			"updated_at":       "$metadata_updated_at",
+		}}},
+	}
+	cursor, err := r.db.Collection("metadata").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if err := setMetadataGSTRates(ctx, r.db, results); err != nil {
+		return nil, err
+	}
+	sortByRank(results, ids)
+	return results, nil
+}
+
+// GetAllMetadata retrieves all metadata with pagination. A search ranks the results by relevance.
 func (r *MetadataRepositoryMongoDB) GetAllMetadata(ctx context.Context, limit, offset int64, search string) ([]*entities.GetAllMetadata, int64, error) {
 	metadataColl := r.db.Collection("metadata")
 
-	// Base match filter
-	match := bson.M{}
 	if search != "" && search != `""` {
-		regex := bson.M{"$regex": search, "$options": "i"}
-		match["$or"] = []bson.M{
-			{"metadata_name": regex},
-			{"subcategory_info.subcategory_name": regex}, // Will work after $lookup
+		ids, total, err := r.rankedMetadataPage(ctx, search, nil, limit, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		results, err := r.getMetadataByIds(ctx, ids)
+		if err != nil {
+			return nil, 0, err
 		}
+		return results, total, nil
 	}
 
 	// Aggregation pipeline
 	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: withoutArchived(bson.M{})}},
 		// convert string IDs to ObjectIDs for lookups
 		{{Key: "$addFields", Value: bson.M{
 			"category_oid":    bson.M{"$toObjectId": "$metadata_category_id"},
@@ -62,11 +172,6 @@ func (r *MetadataRepositoryMongoDB) GetAllMetadata(ctx context.Context, limit, o
 		{{Key: "$unwind", Value: "$subcategory_info"}},
 	}
 
-	// Apply search filter if provided
-	if len(match) > 0 {
-		pipeline = append(pipeline, bson.D{{Key: "$match", Value: match}})
-	}
-
 	// Count stage
 	countStage := append(pipeline, bson.D{{Key: "$count", Value: "total"}})
 	countCursor, err := metadataColl.Aggregate(ctx, countStage)
@@ -118,6 +223,9 @@ func (r *MetadataRepositoryMongoDB) GetAllMetadata(ctx context.Context, limit, o
 	if err := cursor.All(ctx, &results); err != nil {
 		return nil, 0, err
 	}
+	if err := setMetadataGSTRates(ctx, r.db, results); err != nil {
+		return nil, 0, err
+	}
 
 	return results, total, nil
 }
@@ -160,8 +268,8 @@ func (r *MetadataRepositoryMongoDB) GetAllMetadataForSeller(ctx context.Context,
 		}
 	}
 
-	// 3️⃣ Base filter for initial match (only exclusions)
-	initialMatch := bson.M{}
+	// 3️⃣ Base filter for initial match (exclusions and archived metadata)
+	initialMatch := withoutArchived(bson.M{})
 	if len(excludeIDs) > 0 {
 		initialMatch["_id"] = bson.M{"$nin": excludeIDs}
 	}
@@ -189,15 +297,17 @@ func (r *MetadataRepositoryMongoDB) GetAllMetadataForSeller(ctx context.Context,
 		{{Key: "$unwind", Value: "$subcategory_info"}},
 	}
 
-	// 5️⃣ Add search filter for both metadata name and subcategory name after lookups
+	// 5️⃣ A search ranks the metadata left after exclusions by relevance
 	if search != "" && search != `""` {
-		searchMatch := bson.M{
-			"$or": []bson.M{
-				{"metadata_name": bson.M{"$regex": search, "$options": "i"}},
-				{"subcategory_info.subcategory_name": bson.M{"$regex": search, "$options": "i"}},
-			},
+		ids, total, err := r.rankedMetadataPage(ctx, search, initialMatch, limit, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		results, err := r.getMetadataByIds(ctx, ids)
+		if err != nil {
+			return nil, 0, err
 		}
-		pipeline = append(pipeline, bson.D{{Key: "$match", Value: searchMatch}})
+		return results, total, nil
 	}
 
 	// Common sort
@@ -254,6 +364,9 @@ func (r *MetadataRepositoryMongoDB) GetAllMetadataForSeller(ctx context.Context,
 	if err := cursor.All(ctx, &results); err != nil {
 		return nil, 0, err
 	}
+	if err := setMetadataGSTRates(ctx, r.db, results); err != nil {
+		return nil, 0, err
+	}
 
 	return results, total, nil
 }
@@ -312,11 +425,22 @@ func (r *MetadataRepositoryMongoDB) GetMetadataByID(ctx context.Context, id stri
 		return nil, err
 	}
 
+	gstRates, err := getGSTRates(ctx, r.db, []string{metadata.MetadataHSNCode})
+	if err != nil {
+		return nil, err
+	}
+	barcodes, err := getBarcodeCodes(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+
 	metadataResponse := &entities.MetadataResponse{
 		ID:              metadata.MetadataProductID,
 		Name:            metadata.MetadataName,
 		Description:     metadata.MetadataDescription,
 		Image:           metadata.MetadataImage,
+		Gallery:         metadata.MetadataGallery,
+		Attributes:      metadata.MetadataAttributes,
 		CategoryID:      metadata.MetadataCategoryID,
 		SubcategoryID:   metadata.MetadataSubcategoryID,
 		CategoryName:    category.CategoryName,
@@ -327,6 +451,12 @@ func (r *MetadataRepositoryMongoDB) GetMetadataByID(ctx context.Context, id stri
 		TotalStars:      review.TotalStars,
 		TotalReviews:    review.TotalReviews,
 		HsnCode:         metadata.MetadataHSNCode,
+		Barcodes:        barcodes,
+		Revision:        metadata.MetadataRevision,
+		ArchivedAt:      metadata.ArchivedAt,
+	}
+	if rate, ok := gstRates[metadata.MetadataHSNCode]; ok {
+		metadataResponse.GSTRate = &rate
 	}
 	return metadataResponse, nil
 }
@@ -345,15 +475,29 @@ func (r *MetadataRepositoryMongoDB) CreateMetadata(ctx context.Context, metadata
 		return &entities.MetadataApiResponse{Success: false, Message: "Internal Server Error", Error: "DataBase Error"}, err
 	}
 
-	result, err := r.db.Collection("metadata").InsertOne(ctx, metadata)
+	session, err := r.db.Client().StartSession()
 	if err != nil {
 		return &entities.MetadataApiResponse{Success: false, Message: "Internal Server Error", Error: "DataBase Error"}, err
 	}
-	objectID, ok := result.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return &entities.MetadataApiResponse{Success: false, Message: "Error Creating Metadata", Error: "ObjectId fetch error"}, nil
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		metadata.MetadataProductID = ""
+		result, err := collection.InsertOne(sc, metadata)
+		if err != nil {
+			return nil, err
+		}
+		metadata.MetadataProductID = result.InsertedID.(primitive.ObjectID).Hex()
+		if err := checkMetadataReferences(sc, r.db, metadata); err != nil {
+			return nil, err
+		}
+		return nil, recordMetadataRevision(sc, r.db, nil, metadata, entities.MetadataRevisionCreate, metadata.MetadataUpdatedBy, nil)
+	})
+	if err != nil {
+		return &entities.MetadataApiResponse{Success: false, Message: "Error Creating Metadata", Error: err.Error()}, err
 	}
-	stringID := objectID.Hex()
+	stringID := metadata.MetadataProductID
+	invalidateCatalogSearch()
 	return &entities.MetadataApiResponse{Success: true, Message: "Metadata Created Successfully", Id: stringID}, nil
 }
 
@@ -392,13 +536,16 @@ func (r *MetadataRepositoryMongoDB) UpdateMetadata(ctx context.Context, id strin
 	if metadata.MetadataMRP > 0 {
 		updateDoc["metadata_mrp"] = metadata.MetadataMRP
 	}
+	if metadata.MetadataAttributes != nil {
+		updateDoc["metadata_attributes"] = metadata.MetadataAttributes
+	}
 	updateDoc["metadata_updated_at"] = time.Now()
 
-	// Execute update
+	// Execute update with its revision, checking the updated metadata still fits the catalog
 	filter := bson.M{"_id": objectID}
 	update := bson.M{"$set": updateDoc}
 
-	result, err := r.db.Collection("metadata").UpdateOne(ctx, filter, update)
+	session, err := r.db.Client().StartSession()
 	if err != nil {
 		return &entities.MetadataApiResponse{
 			Error:   "Database Error",
@@ -406,83 +553,60 @@ func (r *MetadataRepositoryMongoDB) UpdateMetadata(ctx context.Context, id strin
 			Success: false,
 		}, err
 	}
+	defer session.EndSession(ctx)
 
-	if result.MatchedCount == 0 {
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		before, err := findMetadata(sc, r.db, id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.db.Collection("metadata").UpdateOne(sc, filter, update); err != nil {
+			return nil, err
+		}
+		after, err := findMetadata(sc, r.db, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkMetadataReferences(sc, r.db, after); err != nil {
+			return nil, err
+		}
+		return nil, recordMetadataRevision(sc, r.db, before, after, entities.MetadataRevisionUpdate, metadata.MetadataUpdatedBy, nil)
+	})
+	if err == mongo.ErrNoDocuments {
 		return &entities.MetadataApiResponse{
 			Error:   "No matching document in db",
 			Message: "Metadata not found in db",
 			Success: false,
-		}, err
+		}, nil
 	}
-
-	return &entities.MetadataApiResponse{
-		Message: "Metadata updated successfully",
-		Success: true,
-	}, nil
-}
-
-// DeleteMetadata deletes a metadata by ID
-func (r *MetadataRepositoryMongoDB) DeleteMetadata(ctx context.Context, id string) (*entities.MetadataApiResponse, error) {
-	// Convert string ID to ObjectID
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return &entities.MetadataApiResponse{
-			Error:   "Error Transforming ObjectIDFromHex",
-			Message: "Error in transforming metadata id to object id",
-			Success: false,
-		}, err
-	}
-
-	filter := bson.M{"_id": objectID}
-	result, err := r.db.Collection("metadata").DeleteOne(ctx, filter)
 	if err != nil {
 		return &entities.MetadataApiResponse{
-			Error:   "Database Error",
-			Message: "Error in database while deleting",
-			Success: false,
-		}, err
-	}
-
-	if result.DeletedCount == 0 {
-		return &entities.MetadataApiResponse{
-			Error:   "No matching document in db",
-			Message: "Metadata not found in db",
+			Error:   "Failed to update metadata",
+			Message: err.Error(),
 			Success: false,
 		}, err
 	}
 
+	invalidateCatalogSearch()
 	return &entities.MetadataApiResponse{
-		Message: "Metadata deleted successfully",
+		Message: "Metadata updated successfully",
 		Success: true,
 	}, nil
 }
 
-func (r *MetadataRepositoryMongoDB) AddReview(ctx context.Context, req *entities.AddReviewRequest) error {
-	collection := r.db.Collection("reviews")
-	filter := bson.M{"metadata_product_id": req.MetadataProductID}
-
-	review := entities.Review{}
-	err := collection.FindOne(ctx, filter).Decode(&review)
-	if err != mongo.ErrNoDocuments {
-		return err
-	}
-
-	if err != nil {
-		review = entities.Review{
-			MetadataProductID: req.MetadataProductID,
-			TotalStars:        0,
-			TotalReviews:      0,
-		}
-	}
-	review.TotalStars += req.Rating
-	review.TotalReviews += 1
-
-	_, err = collection.UpdateOne(ctx, filter, bson.M{"$set": review})
-	if err != nil {
-		return err
-	}
+// DeleteMetadata archives a metadata, along with its listings on cascade
+func (r *MetadataRepositoryMongoDB) DeleteMetadata(ctx context.Context, id string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
+	return archiveRecord(ctx, r.db, entities.ArchiveKindMetadata, id, request)
+}
 
-	return nil
+// AddReview records a rating only review of the customer, replacing the one they left before on the product
+func (r *MetadataRepositoryMongoDB) AddReview(ctx context.Context, req *entities.AddReviewRequest) error {
+	_, err := upsertProductReview(ctx, r.db, &entities.ProductReview{
+		MetadataProductID: req.MetadataProductID,
+		UserID:            req.UserID,
+		Rating:            req.Rating,
+	})
+	return err
 }
 
 func (r *MetadataRepositoryMongoDB) CreateReview(ctx context.Context, id string) (*entities.MetadataApiResponse, error) {
@@ -506,7 +630,7 @@ func (r *MetadataRepositoryMongoDB) GetMetadataForSubcategories(ctx context.Cont
 	pipeline := mongo.Pipeline{
 		{{
 			Key:   "$match",
-			Value: bson.M{"metadata_subcategory_id": bson.M{"$in": subCategoryIds}},
+			Value: withoutArchived(bson.M{"metadata_subcategory_id": bson.M{"$in": subCategoryIds}}),
 		}},
 
 		{{Key: "$addFields", Value: bson.D{
@@ -571,3 +695,128 @@ func (r *MetadataRepositoryMongoDB) GetMetadataForSubcategories(ctx context.Cont
 
 	return allMetadata, nil
 }
+
+func (r *MetadataRepositoryMongoDB) GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error) {
+	return getAttributeSchema(ctx, r.db, subcategoryId)
+}
+
+func (r *MetadataRepositoryMongoDB) GetHSNCode(ctx context.Context, code string) (*entities.HSNCode, error) {
+	return getHSNCode(ctx, r.db, code)
+}
+
+// GetMetadataRevisions lists the revisions of a metadata, newest first
+func (r *MetadataRepositoryMongoDB) GetMetadataRevisions(ctx context.Context, id string, limit, offset int64) ([]*entities.MetadataRevision, int64, error) {
+	collection := r.db.Collection("metadata_revisions")
+	filter := bson.M{"metadata_product_id": id}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"revision": -1}).
+		SetSkip(offset).
+		SetLimit(limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []*entities.MetadataRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
+
+func (r *MetadataRepositoryMongoDB) GetMetadataRevision(ctx context.Context, id string, revision int) (*entities.MetadataRevision, error) {
+	var metadataRevision entities.MetadataRevision
+	err := r.db.Collection("metadata_revisions").FindOne(ctx, bson.M{"metadata_product_id": id, "revision": revision}).Decode(&metadataRevision)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("revision %d of this metadata not found", revision)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &metadataRevision, nil
+}
+
+// RollbackMetadata restores the tracked fields of a metadata to a revision. The rollback is itself recorded as
+// a new revision, so it can be undone the same way.
+func (r *MetadataRepositoryMongoDB) RollbackMetadata(ctx context.Context, id string, revision int, editedBy string) (*entities.Metadata, error) {
+	target, err := r.GetMetadataRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata id")
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	// the restored state goes through the same catalog checks as an update
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		before, err := findMetadata(sc, r.db, id)
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("metadata not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		snapshot, err := withoutDeletedMedia(sc, r.db, target.Snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffMetadata(before, snapshot)) == 0 {
+			return nil, fmt.Errorf("metadata already matches revision %d", revision)
+		}
+
+		set := bson.M{
+			"metadata_name":           snapshot.MetadataName,
+			"hsn_code":                snapshot.MetadataHSNCode,
+			"metadata_description":    snapshot.MetadataDescription,
+			"metadata_image":          snapshot.MetadataImage,
+			"metadata_category_id":    snapshot.MetadataCategoryID,
+			"metadata_subcategory_id": snapshot.MetadataSubcategoryID,
+			"metadata_mrp":            snapshot.MetadataMRP,
+			"metadata_updated_at":     time.Now(),
+		}
+		unset := bson.M{}
+		if len(snapshot.MetadataGallery) > 0 {
+			set["metadata_gallery"] = snapshot.MetadataGallery
+		} else {
+			unset["metadata_gallery"] = ""
+		}
+		if len(snapshot.MetadataAttributes) > 0 {
+			set["metadata_attributes"] = snapshot.MetadataAttributes
+		} else {
+			unset["metadata_attributes"] = ""
+		}
+		update := bson.M{"$set": set}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+		if _, err := r.db.Collection("metadata").UpdateByID(sc, objectId, update); err != nil {
+			return nil, err
+		}
+
+		after, err := findMetadata(sc, r.db, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkMetadataReferences(sc, r.db, after); err != nil {
+			return nil, fmt.Errorf("cannot roll back to revision %d: %v", revision, err)
+		}
+		return nil, recordMetadataRevision(sc, r.db, before, after, entities.MetadataRevisionRollback, editedBy, &revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateCatalogSearch()
+	return findMetadata(ctx, r.db, id)
+}
@@ -0,0 +1,221 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// metadataRevisionFields are the fields of a metadata a revision tracks, by their json name
+func metadataRevisionFields(metadata *entities.Metadata) map[string]interface{} {
+	return map[string]interface{}{
+		"name":           metadata.MetadataName,
+		"hsn_code":       metadata.MetadataHSNCode,
+		"description":    metadata.MetadataDescription,
+		"image":          metadata.MetadataImage,
+		"category_id":    metadata.MetadataCategoryID,
+		"subcategory_id": metadata.MetadataSubcategoryID,
+		"mrp":            metadata.MetadataMRP,
+		"gallery":        metadata.MetadataGallery,
+		"attributes":     metadata.MetadataAttributes,
+	}
+}
+
+var metadataRevisionFieldOrder = []string{"name", "hsn_code", "description", "image", "category_id", "subcategory_id", "mrp", "gallery", "attributes"}
+
+// diffMetadata lists the tracked fields that differ between two states of a metadata. A nil before is a
+// metadata that did not exist yet.
+func diffMetadata(before, after *entities.Metadata) []*entities.MetadataFieldChange {
+	if before == nil {
+		before = &entities.Metadata{}
+	}
+	old, current := metadataRevisionFields(before), metadataRevisionFields(after)
+	changes := []*entities.MetadataFieldChange{}
+	for _, field := range metadataRevisionFieldOrder {
+		if isEmptyRevisionValue(old[field]) && isEmptyRevisionValue(current[field]) {
+			continue
+		}
+		if !reflect.DeepEqual(old[field], current[field]) {
+			changes = append(changes, &entities.MetadataFieldChange{Field: field, OldValue: old[field], NewValue: current[field]})
+		}
+	}
+	return changes
+}
+
+// isEmptyRevisionValue treats nil and empty galleries and attributes alike
+func isEmptyRevisionValue(value interface{}) bool {
+	switch v := value.(type) {
+	case []string:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// recordMetadataRevision stores the edit that took a metadata from before to after as its next revision. Nothing
+// is stored when no tracked field changed. rolledBackTo is set for rollbacks.
+func recordMetadataRevision(ctx context.Context, db *mongo.Database, before, after *entities.Metadata, action, editedBy string, rolledBackTo *int) error {
+	changes := diffMetadata(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	objectId, err := primitive.ObjectIDFromHex(after.MetadataProductID)
+	if err != nil {
+		return err
+	}
+
+	// the counter on the metadata numbers concurrent edits apart
+	update := bson.M{"$inc": bson.M{"metadata_revision": 1}}
+	if editedBy != "" {
+		update["$set"] = bson.M{"metadata_updated_by": editedBy}
+	}
+	var numbered entities.Metadata
+	err = db.Collection("metadata").FindOneAndUpdate(ctx, bson.M{"_id": objectId}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&numbered)
+	if err != nil {
+		return err
+	}
+
+	revisions := db.Collection("metadata_revisions")
+	now := time.Now()
+	// the first edit of a metadata that predates revisions keeps its earlier state as revision 0
+	if before != nil && numbered.MetadataRevision == 1 {
+		if _, err := revisions.InsertOne(ctx, &entities.MetadataRevision{
+			MetadataProductID: after.MetadataProductID,
+			Revision:          0,
+			Action:            entities.MetadataRevisionBaseline,
+			Changes:           []*entities.MetadataFieldChange{},
+			Snapshot:          before,
+			EditedAt:          before.MetadataUpdatedAt,
+		}); err != nil {
+			return err
+		}
+	}
+
+	snapshot := *after
+	snapshot.MetadataRevision = numbered.MetadataRevision
+	snapshot.MetadataUpdatedBy = numbered.MetadataUpdatedBy
+	_, err = revisions.InsertOne(ctx, &entities.MetadataRevision{
+		MetadataProductID: after.MetadataProductID,
+		Revision:          numbered.MetadataRevision,
+		Action:            action,
+		Changes:           changes,
+		Snapshot:          &snapshot,
+		RolledBackTo:      rolledBackTo,
+		EditedBy:          editedBy,
+		EditedAt:          now,
+	})
+	return err
+}
+
+// checkMetadataReferences checks a metadata still fits the catalog: no other metadata holds its HSN code, and its
+// category and subcategory exist, are not archived and belong together
+func checkMetadataReferences(ctx context.Context, db *mongo.Database, metadata *entities.Metadata) error {
+	objectId, err := primitive.ObjectIDFromHex(metadata.MetadataProductID)
+	if err != nil {
+		return fmt.Errorf("invalid metadata id")
+	}
+	count, err := db.Collection("metadata").CountDocuments(ctx, bson.M{"hsn_code": metadata.MetadataHSNCode, "_id": bson.M{"$ne": objectId}})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("metadata for hsn code %s already exists", metadata.MetadataHSNCode)
+	}
+
+	categoryObjectId, err := primitive.ObjectIDFromHex(metadata.MetadataCategoryID)
+	if err != nil {
+		return fmt.Errorf("invalid category id")
+	}
+	count, err = db.Collection("categories").CountDocuments(ctx, withoutArchived(bson.M{"_id": categoryObjectId}))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("category not found or archived")
+	}
+
+	subcategoryObjectId, err := primitive.ObjectIDFromHex(metadata.MetadataSubcategoryID)
+	if err != nil {
+		return fmt.Errorf("invalid subcategory id")
+	}
+	var subcategory entities.Subcategory
+	err = db.Collection("subcategories").FindOne(ctx, withoutArchived(bson.M{"_id": subcategoryObjectId})).Decode(&subcategory)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("subcategory not found or archived")
+	}
+	if err != nil {
+		return err
+	}
+	if subcategory.CategoryID != metadata.MetadataCategoryID {
+		return fmt.Errorf("subcategory does not belong to the category")
+	}
+	return nil
+}
+
+// withoutDeletedMedia drops the uploaded images of a metadata snapshot that have been deleted since, putting the
+// first remaining gallery image in place of a deleted main image. Images hosted elsewhere are kept.
+func withoutDeletedMedia(ctx context.Context, db *mongo.Database, snapshot *entities.Metadata) (*entities.Metadata, error) {
+	ids := []primitive.ObjectID{}
+	for _, url := range append([]string{snapshot.MetadataImage}, snapshot.MetadataGallery...) {
+		if id, err := primitive.ObjectIDFromHex(strings.TrimPrefix(url, mediaFilePath)); err == nil && strings.HasPrefix(url, mediaFilePath) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return snapshot, nil
+	}
+
+	cursor, err := db.Collection("media").Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"url": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var media []*entities.Media
+	if err := cursor.All(ctx, &media); err != nil {
+		return nil, err
+	}
+	stored := make(map[string]bool, len(media))
+	for _, m := range media {
+		stored[m.URL] = true
+	}
+	kept := func(url string) bool {
+		return !strings.HasPrefix(url, mediaFilePath) || stored[url]
+	}
+
+	restored := *snapshot
+	restored.MetadataGallery = nil
+	for _, url := range snapshot.MetadataGallery {
+		if kept(url) {
+			restored.MetadataGallery = append(restored.MetadataGallery, url)
+		}
+	}
+	if !kept(restored.MetadataImage) {
+		restored.MetadataImage = ""
+		if len(restored.MetadataGallery) > 0 {
+			restored.MetadataImage = restored.MetadataGallery[0]
+		}
+	}
+	return &restored, nil
+}
+
+// findMetadata reads a metadata by its hex id
+func findMetadata(ctx context.Context, db *mongo.Database, id string) (*entities.Metadata, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var metadata entities.Metadata
+	if err := db.Collection("metadata").FindOne(ctx, bson.M{"_id": objectId}).Decode(&metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
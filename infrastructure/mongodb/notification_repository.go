@@ -0,0 +1,85 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewNotificationRepositoryMongoDB(db *mongo.Database) repositories.NotificationRepository {
+	return &NotificationRepositoryMongoDB{db: db}
+}
+
+// insertNotification writes a notification with the given context, so callers can include it in their transaction
+func insertNotification(ctx context.Context, db *mongo.Database, notification *entities.Notification) error {
+	notification.Read = false
+	notification.CreatedAt = time.Now()
+	_, err := db.Collection("notifications").InsertOne(ctx, notification)
+	return err
+}
+
+func (r *NotificationRepositoryMongoDB) GetNotifications(ctx context.Context, userId string, offset, limit int64, unreadOnly bool) ([]*entities.Notification, int64, int64, error) {
+	collection := r.db.Collection("notifications")
+
+	filter := bson.M{"user_id": userId}
+	if unreadOnly {
+		filter["read"] = false
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := collection.CountDocuments(ctx, bson.M{"user_id": userId, "read": false})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"created_at": -1}).SetSkip(offset * limit).SetLimit(limit)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []*entities.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, 0, 0, err
+	}
+	return notifications, total, unread, nil
+}
+
+func (r *NotificationRepositoryMongoDB) MarkNotificationRead(ctx context.Context, userId, notificationId string) error {
+	objectId, err := primitive.ObjectIDFromHex(notificationId)
+	if err != nil {
+		return fmt.Errorf("invalid notification id")
+	}
+
+	result, err := r.db.Collection("notifications").UpdateOne(ctx, bson.M{"_id": objectId, "user_id": userId}, bson.M{"$set": bson.M{"read": true}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+func (r *NotificationRepositoryMongoDB) MarkAllNotificationsRead(ctx context.Context, userId string) (int64, error) {
+	result, err := r.db.Collection("notifications").UpdateMany(ctx, bson.M{"user_id": userId, "read": false}, bson.M{"$set": bson.M{"read": true}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -64,6 +64,7 @@ func (r *OrderRepositoryMongoDB) GetAllOrders(ctx context.Context, requestData *
 			WarehouseID: order.WarehouseID,
 			Address:     order.Address,
 			OrderTotal:  order.OrderTotal,
+			TaxTotal:    order.TaxTotal,
 			OrderedAt:   order.OrderedAt,
 			Products:    allProducts,
 		}
@@ -75,6 +76,14 @@ func (r *OrderRepositoryMongoDB) GetAllOrders(ctx context.Context, requestData *
 
 }
 
+func (r *OrderRepositoryMongoDB) GetEffectivePrices(ctx context.Context, productIds []string) (map[string]*entities.EffectiveProductPrice, error) {
+	return getEffectivePrices(ctx, r.Database, productIds)
+}
+
+func (r *OrderRepositoryMongoDB) GetGSTRates(ctx context.Context, hsnCodes []string) (map[string]float64, error) {
+	return getGSTRates(ctx, r.Database, hsnCodes)
+}
+
 func (r *OrderRepositoryMongoDB) CreateNewOrder(ctx context.Context, requestOrder *entities.CreateOrderRequest, OrderID string, OrderedAt time.Time) error {
 	orderCollection := r.Database.Collection("order")
 
@@ -84,6 +93,7 @@ func (r *OrderRepositoryMongoDB) CreateNewOrder(ctx context.Context, requestOrde
 		WarehouseID: requestOrder.WarehouseID,
 		Address:     requestOrder.Address,
 		OrderTotal:  requestOrder.OrderTotal,
+		TaxTotal:    requestOrder.TaxTotal,
 		OrderedAt:   OrderedAt,
 	}
 
@@ -104,12 +114,18 @@ func (r *OrderRepositoryMongoDB) CreateNewOrderProducts(ctx context.Context, req
 	for _, product := range requestOrder.Products {
 
 		orderProductDetail := &entities.OrderedItems{
-			OrderID:   OrderID,
-			ProductID: product.ProductID,
-			Quantity:  product.Quantity,
-			Price:     product.Price,
-			MRP:       product.MRP,
-			SellerID:  product.SellerID,
+			OrderID:           OrderID,
+			ProductID:         product.ProductID,
+			Quantity:          product.Quantity,
+			Price:             product.Price,
+			MRP:               product.MRP,
+			SellerID:          product.SellerID,
+			HsnCode:           product.HsnCode,
+			GSTRate:           product.GSTRate,
+			TaxAmount:         product.TaxAmount,
+			TaxPending:        product.TaxPending,
+			MetadataProductID: product.MetadataProductID,
+			MetadataRevision:  product.MetadataRevision,
 		}
 		allProducts = append(allProducts, orderProductDetail)
 	}
@@ -154,6 +170,7 @@ func (r *OrderRepositoryMongoDB) GetOrderByOrderID(ctx context.Context, orderId
 		WarehouseID: order.WarehouseID,
 		Address:     order.Address,
 		OrderTotal:  order.OrderTotal,
+		TaxTotal:    order.TaxTotal,
 		OrderedAt:   order.OrderedAt,
 		Products:    allProducts,
 	}
@@ -201,6 +218,7 @@ func (r *OrderRepositoryMongoDB) GetOrderByUserID(ctx context.Context, userId *s
 			WarehouseID: order.WarehouseID,
 			Address:     order.Address,
 			OrderTotal:  order.OrderTotal,
+			TaxTotal:    order.TaxTotal,
 			OrderedAt:   order.OrderedAt,
 			Products:    allProducts,
 		}
@@ -260,6 +278,7 @@ func (r *OrderRepositoryMongoDB) GetOrderBySellerID(ctx context.Context, sellerI
 			WarehouseID: order.WarehouseID,
 			Address:     order.Address,
 			OrderTotal:  order.OrderTotal,
+			TaxTotal:    order.TaxTotal,
 			OrderedAt:   order.OrderedAt,
 			Products:    orderProducts,
 		})
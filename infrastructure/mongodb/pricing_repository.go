@@ -0,0 +1,668 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PricingRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewPricingRepositoryMongoDB(db *mongo.Database) repositories.PricingRepository {
+	return &PricingRepositoryMongoDB{db: db}
+}
+
+// insertPriceHistory records price changes with the given context, so callers can include them in their transaction
+func insertPriceHistory(ctx context.Context, db *mongo.Database, entries []*entities.PriceHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	now := time.Now()
+	documents := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		entry.ChangedAt = now
+		documents = append(documents, entry)
+	}
+	_, err := db.Collection("price_history").InsertMany(ctx, documents)
+	return err
+}
+
+// getPricingContexts loads the MRP, subcategory, owner, price at the start of the day and the applicable guardrail
+// of the given inventory products
+func getPricingContexts(ctx context.Context, db *mongo.Database, inventoryProductIds []string) ([]*entities.PricingContext, error) {
+	objectIds := make([]primitive.ObjectID, 0, len(inventoryProductIds))
+	for _, id := range inventoryProductIds {
+		objectId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inventory product id %s", id)
+		}
+		objectIds = append(objectIds, objectId)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": objectIds}}}},
+		{{Key: "$addFields", Value: bson.M{"metadataObjectId": bson.M{"$toObjectId": "$metadata_product_id"}, "inventoryObjectId": bson.M{"$toObjectId": "$inventory_id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "metadata", "localField": "metadataObjectId", "foreignField": "_id", "as": "metadataInfo"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$metadataInfo", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$lookup", Value: bson.M{"from": "inventory", "localField": "inventoryObjectId", "foreignField": "_id", "as": "inventoryInfo"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$inventoryInfo", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$addFields", Value: bson.M{"storeObjectId": bson.M{"$toObjectId": "$inventoryInfo.store_id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "stores", "localField": "storeObjectId", "foreignField": "_id", "as": "storeInfo"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$storeInfo", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":                  0,
+			"inventory_product_id": bson.M{"$toString": "$_id"},
+			"seller_id":            "$inventoryInfo.seller_id",
+			"warehouse_id":         "$storeInfo.warehouse_id",
+			"metadata_product_id":  1,
+			"metadata_name":        "$metadataInfo.metadata_name",
+			"subcategory_id":       "$metadataInfo.metadata_subcategory_id",
+			"metadata_mrp":         "$metadataInfo.metadata_mrp",
+			"current_price":        "$product_price",
+		}}},
+	}
+	cursor, err := db.Collection("inventory_product").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var pricingContexts []*entities.PricingContext
+	if err := cursor.All(ctx, &pricingContexts); err != nil {
+		return nil, err
+	}
+	if len(pricingContexts) == 0 {
+		return pricingContexts, nil
+	}
+
+	// The first change recorded today holds the price the day started with
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	historyCursor, err := db.Collection("price_history").Find(ctx,
+		bson.M{"inventory_product_id": bson.M{"$in": inventoryProductIds}, "changed_at": bson.M{"$gte": startOfDay}},
+		options.Find().SetSort(bson.M{"changed_at": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var history []*entities.PriceHistoryEntry
+	if err := historyCursor.All(ctx, &history); err != nil {
+		return nil, err
+	}
+	dayStartPrices := make(map[string]float64)
+	for _, entry := range history {
+		if _, ok := dayStartPrices[entry.InventoryProductID]; !ok {
+			dayStartPrices[entry.InventoryProductID] = entry.OldPrice
+		}
+	}
+
+	for _, pricingContext := range pricingContexts {
+		pricingContext.DayStartPrice = pricingContext.CurrentPrice
+		if price, ok := dayStartPrices[pricingContext.InventoryProductID]; ok {
+			pricingContext.DayStartPrice = price
+		}
+	}
+	return pricingContexts, setPricingGuardrails(ctx, db, pricingContexts)
+}
+
+// getMetadataPricingContexts loads what the price of a new batch of the given products is checked against.
+// A new batch has no price to change from, so only the MRP and the subcategory floor apply.
+func getMetadataPricingContexts(ctx context.Context, db *mongo.Database, metadataIds []string) ([]*entities.PricingContext, error) {
+	objectIds := make([]primitive.ObjectID, 0, len(metadataIds))
+	for _, id := range metadataIds {
+		objectId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata id %s", id)
+		}
+		objectIds = append(objectIds, objectId)
+	}
+	cursor, err := db.Collection("metadata").Find(ctx, bson.M{"_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		return nil, err
+	}
+	var metadata []*entities.Metadata
+	if err := cursor.All(ctx, &metadata); err != nil {
+		return nil, err
+	}
+
+	pricingContexts := make([]*entities.PricingContext, 0, len(metadata))
+	for _, m := range metadata {
+		pricingContexts = append(pricingContexts, &entities.PricingContext{
+			MetadataProductID: m.MetadataProductID,
+			MetadataName:      m.MetadataName,
+			SubcategoryID:     m.MetadataSubcategoryID,
+			MetadataMRP:       m.MetadataMRP,
+		})
+	}
+	return pricingContexts, setPricingGuardrails(ctx, db, pricingContexts)
+}
+
+// setPricingGuardrails sets the guardrail of each pricing context: the one of its subcategory, else the default
+func setPricingGuardrails(ctx context.Context, db *mongo.Database, pricingContexts []*entities.PricingContext) error {
+	if len(pricingContexts) == 0 {
+		return nil
+	}
+	cursor, err := db.Collection("pricing_guardrails").Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	var guardrails []*entities.PricingGuardrail
+	if err := cursor.All(ctx, &guardrails); err != nil {
+		return err
+	}
+	guardrailsBySubcategory := make(map[string]*entities.PricingGuardrail)
+	for _, guardrail := range guardrails {
+		guardrailsBySubcategory[guardrail.SubcategoryID] = guardrail
+	}
+
+	for _, pricingContext := range pricingContexts {
+		guardrail, ok := guardrailsBySubcategory[pricingContext.SubcategoryID]
+		if !ok {
+			guardrail = guardrailsBySubcategory[""]
+		}
+		if guardrail == nil {
+			guardrail = &entities.PricingGuardrail{ViolationAction: entities.PriceViolationReject}
+		}
+		pricingContext.Guardrail = guardrail
+	}
+	return nil
+}
+
+// setInventoryProductPrice applies set, with the new price of change, to the inventory product matching filter
+// and records the price change in the price history. Every price write goes through here or through
+// insertInventoryProducts, taking a session context so the price and its history commit together.
+// It returns the product as it was before the update.
+func setInventoryProductPrice(sc mongo.SessionContext, db *mongo.Database, filter, set bson.M, change *entities.PriceHistoryEntry) (*entities.InventoryProduct, error) {
+	set["product_price"] = change.NewPrice
+	var previous entities.InventoryProduct
+	if err := db.Collection("inventory_product").FindOneAndUpdate(sc, filter, bson.M{"$set": set}).Decode(&previous); err != nil {
+		return nil, err
+	}
+	if previous.ProductPrice == change.NewPrice {
+		return &previous, nil
+	}
+	change.InventoryProductID = previous.InventoryProductID
+	change.OldPrice = previous.ProductPrice
+	return &previous, insertPriceHistory(sc, db, []*entities.PriceHistoryEntry{change})
+}
+
+// insertInventoryProducts inserts new inventory products, setting their ids, and records the price each opens
+// at in the price history as a change from zero, copying the seller, source and author from opening
+func insertInventoryProducts(sc mongo.SessionContext, db *mongo.Database, products []*entities.InventoryProduct, opening *entities.PriceHistoryEntry) error {
+	if len(products) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, 0, len(products))
+	for _, product := range products {
+		documents = append(documents, product)
+	}
+	result, err := db.Collection("inventory_product").InsertMany(sc, documents)
+	if err != nil {
+		return err
+	}
+
+	var history []*entities.PriceHistoryEntry
+	for i, insertedId := range result.InsertedIDs {
+		objectId, ok := insertedId.(primitive.ObjectID)
+		if !ok {
+			return fmt.Errorf("error in getting inserted product id")
+		}
+		products[i].InventoryProductID = objectId.Hex()
+		if products[i].ProductPrice == 0 {
+			continue
+		}
+		entry := *opening
+		entry.InventoryProductID = products[i].InventoryProductID
+		entry.NewPrice = products[i].ProductPrice
+		history = append(history, &entry)
+	}
+	return insertPriceHistory(sc, db, history)
+}
+
+// insertPriceChangeRequests queues price changes for ops, replacing any request still pending for the same product
+func insertPriceChangeRequests(ctx context.Context, db *mongo.Database, requests []*entities.PriceChangeRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	collection := db.Collection("price_change_requests")
+	ids := make([]string, 0, len(requests))
+	documents := make([]interface{}, 0, len(requests))
+	now := time.Now()
+	for _, request := range requests {
+		ids = append(ids, request.InventoryProductID)
+		request.Status = entities.PriceChangePending
+		request.RequestedAt = now
+		documents = append(documents, request)
+	}
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"inventory_product_id": bson.M{"$in": ids}, "status": entities.PriceChangePending},
+		bson.M{"$set": bson.M{"status": entities.PriceChangeSuperseded}},
+	)
+	if err != nil {
+		return err
+	}
+	_, err = collection.InsertMany(ctx, documents)
+	return err
+}
+
+// activeScheduledPriceStages joins the scheduled price running now onto each document as active_sale.
+// inventoryProductId is an expression resolving to the hex id of the inventory product and mrp one resolving
+// to its current MRP; a sale priced above an MRP lowered after it was scheduled does not apply.
+func activeScheduledPriceStages(inventoryProductId, mrp interface{}) []bson.D {
+	return []bson.D{
+		{{Key: "$lookup", Value: bson.M{
+			"from": "scheduled_prices",
+			"let":  bson.M{"inventoryProductId": inventoryProductId, "mrp": mrp},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"cancelled_at": bson.M{"$exists": false},
+					"$expr": bson.M{"$and": bson.A{
+						bson.M{"$eq": bson.A{"$inventory_product_id", "$$inventoryProductId"}},
+						bson.M{"$lte": bson.A{"$starts_at", "$$NOW"}},
+						bson.M{"$gt": bson.A{"$ends_at", "$$NOW"}},
+						bson.M{"$lte": bson.A{"$price", "$$mrp"}},
+					}},
+				}}},
+				{{Key: "$sort", Value: bson.M{"starts_at": -1}}},
+				{{Key: "$limit", Value: 1}},
+			},
+			"as": "active_sale",
+		}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$active_sale", "preserveNullAndEmptyArrays": true}}},
+	}
+}
+
+// effectivePriceFields projects the price customers pay for the regular price at priceField: the running
+// scheduled price if there is one, with the regular price as was_price
+func effectivePriceFields(priceField string) bson.M {
+	onSale := bson.M{"$eq": bson.A{bson.M{"$type": "$active_sale"}, "object"}}
+	return bson.M{
+		"product_price": bson.M{"$cond": bson.A{onSale, "$active_sale.price", priceField}},
+		"was_price":     bson.M{"$cond": bson.A{onSale, priceField, nil}},
+		"sale_ends_at":  bson.M{"$cond": bson.A{onSale, "$active_sale.ends_at", nil}},
+		"sale_label":    bson.M{"$cond": bson.A{onSale, "$active_sale.label", ""}},
+	}
+}
+
+// getEffectivePrices resolves the current price of the given visible inventory products
+func getEffectivePrices(ctx context.Context, db *mongo.Database, inventoryProductIds []string) (map[string]*entities.EffectiveProductPrice, error) {
+	objectIds := make([]primitive.ObjectID, 0, len(inventoryProductIds))
+	for _, id := range inventoryProductIds {
+		objectId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inventory product id %s", id)
+		}
+		objectIds = append(objectIds, objectId)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": objectIds}, "product_visibility": true}}},
+		{{Key: "$addFields", Value: bson.M{"metadataObjectId": bson.M{"$toObjectId": "$metadata_product_id"}, "inventoryObjectId": bson.M{"$toObjectId": "$inventory_id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "metadata", "localField": "metadataObjectId", "foreignField": "_id", "as": "metadataInfo"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$metadataInfo", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$lookup", Value: bson.M{"from": "inventory", "localField": "inventoryObjectId", "foreignField": "_id", "as": "inventoryInfo"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$inventoryInfo", "preserveNullAndEmptyArrays": true}}},
+	}
+	pipeline = append(pipeline, activeScheduledPriceStages(bson.M{"$toString": "$_id"}, "$metadataInfo.metadata_mrp")...)
+	priceFields := effectivePriceFields("$product_price")
+	pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.M{
+		"_id":                  0,
+		"inventory_product_id": bson.M{"$toString": "$_id"},
+		"seller_id":            "$inventoryInfo.seller_id",
+		"price":                priceFields["product_price"],
+		"was_price":            priceFields["was_price"],
+		"mrp":                  "$metadataInfo.metadata_mrp",
+		"hsn_code":             "$metadataInfo.hsn_code",
+		"metadata_product_id":  "$metadata_product_id",
+		"metadata_revision":    "$metadataInfo.metadata_revision",
+	}}})
+
+	cursor, err := db.Collection("inventory_product").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var prices []*entities.EffectiveProductPrice
+	if err := cursor.All(ctx, &prices); err != nil {
+		return nil, err
+	}
+	pricesById := make(map[string]*entities.EffectiveProductPrice, len(prices))
+	for _, price := range prices {
+		pricesById[price.InventoryProductID] = price
+	}
+	return pricesById, nil
+}
+
+func (r *PricingRepositoryMongoDB) GetPricingContexts(ctx context.Context, inventoryProductIds []string) ([]*entities.PricingContext, error) {
+	return getPricingContexts(ctx, r.db, inventoryProductIds)
+}
+
+func (r *PricingRepositoryMongoDB) GetPricingGuardrails(ctx context.Context) ([]*entities.PricingGuardrail, error) {
+	cursor, err := r.db.Collection("pricing_guardrails").Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"subcategory_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	guardrails := []*entities.PricingGuardrail{}
+	if err := cursor.All(ctx, &guardrails); err != nil {
+		return nil, err
+	}
+	return guardrails, nil
+}
+
+func (r *PricingRepositoryMongoDB) UpsertPricingGuardrail(ctx context.Context, guardrail *entities.PricingGuardrail) (*entities.PricingGuardrail, error) {
+	if guardrail.SubcategoryID != "" {
+		subcategoryObjectId, err := primitive.ObjectIDFromHex(guardrail.SubcategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subcategory id")
+		}
+		count, err := r.db.Collection("subcategories").CountDocuments(ctx, bson.M{"_id": subcategoryObjectId})
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("subcategory not found")
+		}
+	}
+
+	guardrail.UpdatedAt = time.Now()
+	var updated entities.PricingGuardrail
+	err := r.db.Collection("pricing_guardrails").FindOneAndUpdate(ctx,
+		bson.M{"subcategory_id": guardrail.SubcategoryID},
+		bson.M{"$set": bson.M{
+			"max_daily_change_percent": guardrail.MaxDailyChangePercent,
+			"min_price_percent_of_mrp": guardrail.MinPricePercentOfMRP,
+			"violation_action":         guardrail.ViolationAction,
+			"updated_by":               guardrail.UpdatedBy,
+			"updated_at":               guardrail.UpdatedAt,
+		}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *PricingRepositoryMongoDB) DeletePricingGuardrail(ctx context.Context, subcategoryId string) error {
+	result, err := r.db.Collection("pricing_guardrails").DeleteOne(ctx, bson.M{"subcategory_id": subcategoryId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("guardrail not found")
+	}
+	return nil
+}
+
+func (r *PricingRepositoryMongoDB) GetPriceHistory(ctx context.Context, inventoryProductId, sellerId string, offset, limit int64) ([]*entities.PriceHistoryEntry, int64, error) {
+	collection := r.db.Collection("price_history")
+
+	filter := bson.M{"inventory_product_id": inventoryProductId}
+	if sellerId != "" {
+		filter["seller_id"] = sellerId
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"changed_at": -1}).SetSkip(offset * limit).SetLimit(limit)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	history := []*entities.PriceHistoryEntry{}
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, 0, err
+	}
+	return history, total, nil
+}
+
+func (r *PricingRepositoryMongoDB) GetPriceChangeRequests(ctx context.Context, request *entities.GetPriceChangeRequestsRequest) ([]*entities.PriceChangeRequest, int64, error) {
+	collection := r.db.Collection("price_change_requests")
+
+	filter := bson.M{}
+	if request.OperationalID != "" {
+		var warehouses []*entities.Warehouse
+		cursor, err := r.db.Collection("warehouses").Find(ctx, bson.M{"warehouse_operational_guy_id": request.OperationalID})
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := cursor.All(ctx, &warehouses); err != nil {
+			return nil, 0, err
+		}
+		warehouseIds := bson.A{}
+		for _, warehouse := range warehouses {
+			warehouseIds = append(warehouseIds, warehouse.ID)
+		}
+		filter["warehouse_id"] = bson.M{"$in": warehouseIds}
+	}
+	if request.SellerID != "" {
+		filter["seller_id"] = request.SellerID
+	}
+	if request.Status != "" {
+		filter["status"] = request.Status
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"requested_at": -1}).SetSkip(request.Offset * request.Limit).SetLimit(request.Limit)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	requests := []*entities.PriceChangeRequest{}
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, 0, err
+	}
+	return requests, total, nil
+}
+
+func (r *PricingRepositoryMongoDB) GetPriceChangeRequestById(ctx context.Context, requestId string) (*entities.PriceChangeRequest, error) {
+	objectId, err := primitive.ObjectIDFromHex(requestId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request id")
+	}
+	var request entities.PriceChangeRequest
+	if err := r.db.Collection("price_change_requests").FindOne(ctx, bson.M{"_id": objectId}).Decode(&request); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("price change request not found")
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *PricingRepositoryMongoDB) ReviewPriceChangeRequest(ctx context.Context, review *entities.ReviewPriceChangeRequest) (*entities.PriceChangeRequest, error) {
+	objectId, err := primitive.ObjectIDFromHex(review.RequestID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request id")
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var request *entities.PriceChangeRequest
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		request, err = r.GetPriceChangeRequestById(sc, review.RequestID)
+		if err != nil {
+			return nil, err
+		}
+		if request.Status != entities.PriceChangePending {
+			return nil, fmt.Errorf("price change request is already %s", request.Status)
+		}
+		if err := checkWarehouseOperator(sc, r.db, request.WarehouseID, review.ReviewerID); err != nil {
+			return nil, err
+		}
+
+		status := entities.PriceChangeRejected
+		if review.Approve {
+			status = entities.PriceChangeApproved
+			productObjectId, err := primitive.ObjectIDFromHex(request.InventoryProductID)
+			if err != nil {
+				return nil, err
+			}
+			_, err = setInventoryProductPrice(sc, r.db, bson.M{"_id": productObjectId}, bson.M{}, &entities.PriceHistoryEntry{
+				SellerID:    request.SellerID,
+				NewPrice:    request.RequestedPrice,
+				Source:      entities.PriceSourceReview,
+				ReferenceID: request.ID,
+				ChangedBy:   review.ReviewerID,
+			})
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("inventory product not found")
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		now := time.Now()
+		request.Status = status
+		request.ReviewedBy = review.ReviewerID
+		request.ReviewReason = review.Reason
+		request.ReviewedAt = &now
+		_, err = r.db.Collection("price_change_requests").UpdateOne(sc, bson.M{"_id": objectId}, bson.M{"$set": bson.M{
+			"status":        status,
+			"reviewed_by":   review.ReviewerID,
+			"review_reason": review.Reason,
+			"reviewed_at":   now,
+		}})
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, insertNotification(sc, r.db, &entities.Notification{
+			UserID:      request.SellerID,
+			Type:        entities.NotificationTypePriceChange,
+			Title:       fmt.Sprintf("Price change %s", status),
+			Message:     fmt.Sprintf("%s at %.2f: %s", request.MetadataName, request.RequestedPrice, review.Reason),
+			ReferenceID: request.InventoryProductID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (r *PricingRepositoryMongoDB) CreateScheduledPrice(ctx context.Context, scheduledPrice *entities.ScheduledPrice) (*entities.ScheduledPrice, error) {
+	collection := r.db.Collection("scheduled_prices")
+	productObjectId, err := primitive.ObjectIDFromHex(scheduledPrice.InventoryProductID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inventory product id %s", scheduledPrice.InventoryProductID)
+	}
+
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		// Bumping the product's schedule version makes concurrent schedules of one product conflict,
+		// so the second one retries and sees the first in the overlap check
+		_, err := r.db.Collection("inventory_product").UpdateByID(sc, productObjectId, bson.M{"$inc": bson.M{"price_schedule_version": 1}})
+		if err != nil {
+			return nil, err
+		}
+
+		// Windows of one product must not overlap, otherwise the effective price would be ambiguous
+		var overlapping entities.ScheduledPrice
+		err = collection.FindOne(sc, bson.M{
+			"inventory_product_id": scheduledPrice.InventoryProductID,
+			"cancelled_at":         bson.M{"$exists": false},
+			"starts_at":            bson.M{"$lt": scheduledPrice.EndsAt},
+			"ends_at":              bson.M{"$gt": scheduledPrice.StartsAt},
+		}).Decode(&overlapping)
+		if err == nil {
+			return nil, fmt.Errorf("overlaps the scheduled price from %s to %s",
+				overlapping.StartsAt.Format(time.RFC3339), overlapping.EndsAt.Format(time.RFC3339))
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+
+		scheduledPrice.ID = ""
+		scheduledPrice.CreatedAt = time.Now()
+		result, err := collection.InsertOne(sc, scheduledPrice)
+		if err != nil {
+			return nil, err
+		}
+		scheduledPrice.ID = result.InsertedID.(primitive.ObjectID).Hex()
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scheduledPrice, nil
+}
+
+func (r *PricingRepositoryMongoDB) GetScheduledPrices(ctx context.Context, inventoryProductId, sellerId string, includeEnded bool) ([]*entities.ScheduledPrice, error) {
+	filter := bson.M{"inventory_product_id": inventoryProductId}
+	if sellerId != "" {
+		filter["seller_id"] = sellerId
+	}
+	if !includeEnded {
+		filter["cancelled_at"] = bson.M{"$exists": false}
+		filter["ends_at"] = bson.M{"$gt": time.Now()}
+	}
+
+	cursor, err := r.db.Collection("scheduled_prices").Find(ctx, filter, options.Find().SetSort(bson.M{"starts_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	scheduledPrices := []*entities.ScheduledPrice{}
+	if err := cursor.All(ctx, &scheduledPrices); err != nil {
+		return nil, err
+	}
+	return scheduledPrices, nil
+}
+
+// CancelScheduledPrice withdraws a scheduled price that has not ended yet. A running sale stops immediately.
+func (r *PricingRepositoryMongoDB) CancelScheduledPrice(ctx context.Context, scheduledPriceId, sellerId string) (*entities.ScheduledPrice, error) {
+	objectId, err := primitive.ObjectIDFromHex(scheduledPriceId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduled price id")
+	}
+
+	now := time.Now()
+	filter := bson.M{"_id": objectId, "cancelled_at": bson.M{"$exists": false}, "ends_at": bson.M{"$gt": now}}
+	if sellerId != "" {
+		filter["seller_id"] = sellerId
+	}
+	var cancelled entities.ScheduledPrice
+	err = r.db.Collection("scheduled_prices").FindOneAndUpdate(ctx,
+		filter,
+		bson.M{"$set": bson.M{"cancelled_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&cancelled)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("scheduled price not found or already ended")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cancelled, nil
+}
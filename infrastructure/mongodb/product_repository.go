@@ -5,6 +5,7 @@ import (
 	"espazeBackend/domain/entities"
 	"espazeBackend/domain/repositories"
 	"espazeBackend/utils"
+	"sort"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -73,23 +74,31 @@ func (r *ProductRepositoryMongoDB) GetProductsForSpecificStore(ctx context.Conte
 			{Key: "as", Value: "md"},
 		}}},
 		bson.D{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$md"}, {Key: "preserveNullAndEmptyArrays", Value: false}}}},
+	}
+	pipeline = append(pipeline, activeScheduledPriceStages(bson.D{{Key: "$toString", Value: "$ip._id"}}, "$md.metadata_mrp")...)
+	priceFields := effectivePriceFields("$ip.product_price")
+	pipeline = append(pipeline,
 		bson.D{{Key: "$project", Value: bson.D{
 			{Key: "inventory_id", Value: "$_id_str"},
 			{Key: "inventory_product_id", Value: bson.D{{Key: "$toString", Value: "$ip._id"}}},
 			{Key: "metadata_product_id", Value: bson.D{{Key: "$toString", Value: "$md._id"}}},
 			{Key: "product_visibility", Value: "$ip.product_visibility"},
-			{Key: "product_price", Value: "$ip.product_price"},
+			{Key: "product_price", Value: priceFields["product_price"]},
+			{Key: "was_price", Value: priceFields["was_price"]},
+			{Key: "sale_ends_at", Value: priceFields["sale_ends_at"]},
+			{Key: "sale_label", Value: priceFields["sale_label"]},
 			{Key: "metadata_name", Value: "$md.metadata_name"},
 			{Key: "metadata_description", Value: "$md.metadata_description"},
 			{Key: "metadata_image", Value: "$md.metadata_image"},
 			{Key: "metadata_category_id", Value: "$md.metadata_category_id"},
 			{Key: "metadata_subcategory_id", Value: "$md.metadata_subcategory_id"},
 			{Key: "metadata_mrp", Value: "$md.metadata_mrp"},
+			{Key: "hsn_code", Value: "$md.hsn_code"},
 			{Key: "product_quantity", Value: "$ip.product_quantity"},
 			{Key: "product_expiry_date", Value: "$ip.product_expiry_date"},
 			{Key: "product_manufacturing_date", Value: "$ip.product_manufacturing_date"},
 		}}},
-	}
+	)
 
 	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -98,20 +107,24 @@ func (r *ProductRepositoryMongoDB) GetProductsForSpecificStore(ctx context.Conte
 	defer cursor.Close(ctx)
 
 	type aggResult struct {
-		InventoryId              string    `bson:"inventory_id"`
-		InventoryProductId       string    `bson:"inventory_product_id"`
-		MetadataProductId        string    `bson:"metadata_product_id"`
-		ProductVisibility        bool      `bson:"product_visibility"`
-		ProductPrice             float64   `bson:"product_price"`
-		MetadataName             string    `bson:"metadata_name"`
-		MetadataDescription      string    `bson:"metadata_description"`
-		MetadataImage            string    `bson:"metadata_image"`
-		MetadataCategoryId       string    `bson:"metadata_category_id"`
-		MetadataSubcategoryId    string    `bson:"metadata_subcategory_id"`
-		MetadataMrp              float64   `bson:"metadata_mrp"`
-		ProductQuantity          int       `bson:"product_quantity"`
-		ProductExpiryDate        time.Time `bson:"product_expiry_date"`
-		ProductManufacturingDate time.Time `bson:"product_manufacturing_date"`
+		InventoryId              string     `bson:"inventory_id"`
+		InventoryProductId       string     `bson:"inventory_product_id"`
+		MetadataProductId        string     `bson:"metadata_product_id"`
+		ProductVisibility        bool       `bson:"product_visibility"`
+		ProductPrice             float64    `bson:"product_price"`
+		MetadataName             string     `bson:"metadata_name"`
+		MetadataDescription      string     `bson:"metadata_description"`
+		MetadataImage            string     `bson:"metadata_image"`
+		MetadataCategoryId       string     `bson:"metadata_category_id"`
+		MetadataSubcategoryId    string     `bson:"metadata_subcategory_id"`
+		MetadataMrp              float64    `bson:"metadata_mrp"`
+		ProductQuantity          int        `bson:"product_quantity"`
+		ProductExpiryDate        time.Time  `bson:"product_expiry_date"`
+		ProductManufacturingDate time.Time  `bson:"product_manufacturing_date"`
+		WasPrice                 *float64   `bson:"was_price"`
+		SaleEndsAt               *time.Time `bson:"sale_ends_at"`
+		SaleLabel                string     `bson:"sale_label"`
+		HsnCode                  string     `bson:"hsn_code"`
 	}
 
 	var results []aggResult
@@ -119,6 +132,15 @@ func (r *ProductRepositoryMongoDB) GetProductsForSpecificStore(ctx context.Conte
 		return nil, err
 	}
 
+	hsnCodes := make([]string, 0, len(results))
+	for _, rdoc := range results {
+		hsnCodes = append(hsnCodes, rdoc.HsnCode)
+	}
+	gstRates, err := getGSTRates(ctx, r.db, hsnCodes)
+	if err != nil {
+		return nil, err
+	}
+
 	var responseData []*entities.GetProductsForSpecificStoreResponse
 	for _, rdoc := range results {
 		// build response inline so we don't create mismatched anonymous struct types
@@ -141,6 +163,14 @@ func (r *ProductRepositoryMongoDB) GetProductsForSpecificStore(ctx context.Conte
 			ProductSubCategoryName:   "",
 			TotalStars:               "",
 			TotalReviews:             "",
+			WasPrice:                 rdoc.WasPrice,
+			SaleEndsAt:               rdoc.SaleEndsAt,
+			SaleLabel:                rdoc.SaleLabel,
+			HsnCode:                  rdoc.HsnCode,
+		}
+
+		if rate, ok := gstRates[rdoc.HsnCode]; ok {
+			resp.GSTRate = &rate
 		}
 
 		responseData = append(responseData, resp)
@@ -151,9 +181,9 @@ func (r *ProductRepositoryMongoDB) GetProductsForSpecificStore(ctx context.Conte
 
 func (r *ProductRepositoryMongoDB) GetAllStores(ctx context.Context, warehouseID string) (*[]entities.Store, error) {
 	collection := r.db.Collection("stores")
-	filter := bson.M{
+	filter := withoutArchived(bson.M{
 		"warehouse_id": warehouseID,
-	}
+	})
 	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -197,7 +227,16 @@ func (r *ProductRepositoryMongoDB) GetProductsForAllStores(ctx context.Context,
 	return response, nil
 }
 
-func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Context, storeId, subcategoryId string) ([]*entities.GetProductsForStoreSubcategory, error) {
+func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Context, storeId, subcategoryId string, filters []*entities.AttributeFilter) ([]*entities.GetProductsForStoreSubcategory, error) {
+	products, err := r.getStoreProducts(ctx, storeId, subcategoryMatch(subcategoryId, filters))
+	if err != nil {
+		return nil, err
+	}
+	return groupProductVariants(ctx, r.db, products)
+}
+
+// getStoreProducts lists the visible products of a store whose joined metadata matches metadataMatch
+func (r *ProductRepositoryMongoDB) getStoreProducts(ctx context.Context, storeId string, metadataMatch bson.M) ([]*entities.GetProductsForStoreSubcategory, error) {
 	inventoryCollection := r.db.Collection("inventory")
 	inventoryProductsCollection := r.db.Collection("inventory_product")
 	storeCollection := r.db.Collection("stores")
@@ -207,7 +246,7 @@ func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Co
 		return nil, err
 	}
 	var storeData entities.Store
-	err = storeCollection.FindOne(ctx, bson.M{"_id": objectId}).Decode(&storeData)
+	err = storeCollection.FindOne(ctx, withoutArchived(bson.M{"_id": objectId})).Decode(&storeData)
 	if err != nil {
 		return nil, err
 	}
@@ -240,9 +279,8 @@ func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Co
 			"as":           "metadata",
 		}}},
 		{{Key: "$unwind", Value: "$metadata"}},
-		{{Key: "$match", Value: bson.M{
-			"metadata.metadata_subcategory_id": subcategoryId,
-		}}},
+		{{Key: "$match", Value: bson.M{"metadata.archived_at": bson.M{"$exists": false}}}},
+		{{Key: "$match", Value: metadataMatch}},
 		{{Key: "$addFields", Value: bson.M{
 			"metadata_category_objectId": bson.M{"$toObjectId": "$metadata.metadata_category_id"},
 		}}},
@@ -253,6 +291,7 @@ func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Co
 			"as":           "category",
 		}}},
 		{{Key: "$unwind", Value: "$category"}},
+		{{Key: "$match", Value: bson.M{"category.archived_at": bson.M{"$exists": false}}}},
 		{{Key: "$addFields", Value: bson.M{
 			"metadata_subcategory_objectId": bson.M{"$toObjectId": "$metadata.metadata_subcategory_id"},
 		}}},
@@ -263,6 +302,7 @@ func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Co
 			"as":           "subcategory",
 		}}},
 		{{Key: "$unwind", Value: "$subcategory"}},
+		{{Key: "$match", Value: bson.M{"subcategory.archived_at": bson.M{"$exists": false}}}},
 		{{Key: "$lookup", Value: bson.M{
 			"from":         "reviews",
 			"localField":   "metadata_product_id",
@@ -270,7 +310,11 @@ func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Co
 			"as":           "review",
 		}}},
 		{{Key: "$unwind", Value: "$review"}},
-		{{Key: "$project", Value: bson.M{
+	}
+	pipeline = append(pipeline, activeScheduledPriceStages(bson.M{"$toString": "$_id"}, "$metadata.metadata_mrp")...)
+	priceFields := effectivePriceFields("$product_price")
+	pipeline = append(pipeline,
+		bson.D{{Key: "$project", Value: bson.M{
 			"metadata_id":                "$metadata._id",
 			"metadata_name":              "$metadata.metadata_name",
 			"metadata_description":       "$metadata.metadata_description",
@@ -285,11 +329,18 @@ func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Co
 			"_id":                        1,
 			"inventory_id":               1,
 			"product_quantity":           1,
-			"product_price":              1,
+			"product_price":              priceFields["product_price"],
+			"was_price":                  priceFields["was_price"],
+			"sale_ends_at":               priceFields["sale_ends_at"],
+			"sale_label":                 priceFields["sale_label"],
 			"product_expiry_date":        1,
 			"product_manufacturing_date": 1,
+			"variant_group_id":           "$metadata.variant_group_id",
+			"variant_attributes":         "$metadata.variant_attributes",
+			"metadata_attributes":        "$metadata.metadata_attributes",
+			"hsn_code":                   "$metadata.hsn_code",
 		}}},
-	}
+	)
 
 	cursor, err := inventoryProductsCollection.Aggregate(ctx, pipeline)
 	if err == mongo.ErrNoDocuments {
@@ -301,23 +352,30 @@ func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Co
 	defer cursor.Close(ctx)
 
 	type aggResult struct {
-		MetadataProductId        string    `bson:"metadata_id"`
-		MetadataName             string    `bson:"metadata_name"`
-		MetadataDescription      string    `bson:"metadata_description"`
-		MetadataImage            string    `bson:"metadata_image"`
-		MetadataCategoryId       string    `bson:"metadata_category_id"`
-		MetadataSubcategoryId    string    `bson:"metadata_subcategory_id"`
-		MetadataMrp              float64   `bson:"metadata_mrp"`
-		ProductCategoryName      string    `bson:"category_name"`
-		ProductSubCategoryName   string    `bson:"subcategory_name"`
-		TotalStars               int       `bson:"total_stars"`
-		TotalReviews             int       `bson:"total_reviews"`
-		InventoryProductId       string    `bson:"_id"`
-		InventoryId              string    `bson:"inventory_id"`
-		ProductPrice             float64   `bson:"product_price"`
-		ProductQuantity          int       `bson:"product_quantity"`
-		ProductExpiryDate        time.Time `bson:"product_expiry_date"`
-		ProductManufacturingDate time.Time `bson:"product_manufacturing_date"`
+		MetadataProductId        string                 `bson:"metadata_id"`
+		MetadataName             string                 `bson:"metadata_name"`
+		MetadataDescription      string                 `bson:"metadata_description"`
+		MetadataImage            string                 `bson:"metadata_image"`
+		MetadataCategoryId       string                 `bson:"metadata_category_id"`
+		MetadataSubcategoryId    string                 `bson:"metadata_subcategory_id"`
+		MetadataMrp              float64                `bson:"metadata_mrp"`
+		ProductCategoryName      string                 `bson:"category_name"`
+		ProductSubCategoryName   string                 `bson:"subcategory_name"`
+		TotalStars               int                    `bson:"total_stars"`
+		TotalReviews             int                    `bson:"total_reviews"`
+		InventoryProductId       string                 `bson:"_id"`
+		InventoryId              string                 `bson:"inventory_id"`
+		ProductPrice             float64                `bson:"product_price"`
+		ProductQuantity          int                    `bson:"product_quantity"`
+		ProductExpiryDate        time.Time              `bson:"product_expiry_date"`
+		ProductManufacturingDate time.Time              `bson:"product_manufacturing_date"`
+		WasPrice                 *float64               `bson:"was_price"`
+		SaleEndsAt               *time.Time             `bson:"sale_ends_at"`
+		SaleLabel                string                 `bson:"sale_label"`
+		VariantGroupID           string                 `bson:"variant_group_id"`
+		VariantAttributes        map[string]string      `bson:"variant_attributes"`
+		MetadataAttributes       map[string]interface{} `bson:"metadata_attributes"`
+		HsnCode                  string                 `bson:"hsn_code"`
 	}
 	var cursorResults []*aggResult
 	err = cursor.All(ctx, &cursorResults)
@@ -350,21 +408,63 @@ func (r *ProductRepositoryMongoDB) GetProductsForStoreSubcategory(ctx context.Co
 				}
 				return float64(metadataData.TotalStars) / float64(metadataData.TotalReviews)
 			}(),
-			StoreName: storeData.StoreName,
+			StoreName:          storeData.StoreName,
+			WasPrice:           metadataData.WasPrice,
+			SaleEndsAt:         metadataData.SaleEndsAt,
+			SaleLabel:          metadataData.SaleLabel,
+			VariantGroupID:     metadataData.VariantGroupID,
+			VariantAttributes:  metadataData.VariantAttributes,
+			MetadataAttributes: metadataData.MetadataAttributes,
+			HsnCode:            metadataData.HsnCode,
 		},
 		)
 
 	}
+	if err := setProductGSTRates(ctx, r.db, results); err != nil {
+		return nil, err
+	}
 
 	return results, nil
 }
 
-func (r *ProductRepositoryMongoDB) GetProductsForAllStoresSubcategory(ctx context.Context, warehouseId, subcategoryId string) ([]*entities.GetProductsForStoreSubcategory, error) {
+func (r *ProductRepositoryMongoDB) GetProductsForAllStoresSubcategory(ctx context.Context, warehouseId, subcategoryId string, filters []*entities.AttributeFilter) ([]*entities.GetProductsForStoreSubcategory, error) {
+	products, err := r.getWarehouseProducts(ctx, warehouseId, subcategoryMatch(subcategoryId, filters))
+	if err != nil {
+		return nil, err
+	}
+	return groupProductVariants(ctx, r.db, products)
+}
 
+// subcategoryMatch matches the metadata of a subcategory whose attributes pass every filter
+func subcategoryMatch(subcategoryId string, filters []*entities.AttributeFilter) bson.M {
+	match := bson.M{"metadata.metadata_subcategory_id": subcategoryId}
+	for _, filter := range filters {
+		condition := bson.M{}
+		if len(filter.Values) > 0 {
+			condition["$in"] = filter.Values
+		}
+		if filter.Min != nil {
+			condition["$gte"] = *filter.Min
+		}
+		if filter.Max != nil {
+			condition["$lte"] = *filter.Max
+		}
+		match["metadata.metadata_attributes."+filter.Key] = condition
+	}
+	return match
+}
+
+func (r *ProductRepositoryMongoDB) GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error) {
+	return getAttributeSchema(ctx, r.db, subcategoryId)
+}
+
+// getWarehouseProducts picks, for every product matching metadataMatch in the warehouse, the cheapest offer with
+// the longest expiry across its stores
+func (r *ProductRepositoryMongoDB) getWarehouseProducts(ctx context.Context, warehouseId string, metadataMatch bson.M) ([]*entities.GetProductsForStoreSubcategory, error) {
 	storesCollection := r.db.Collection("stores")
 
 	var storesData []*entities.Store
-	cursor, err := storesCollection.Find(ctx, bson.M{"warehouse_id": warehouseId})
+	cursor, err := storesCollection.Find(ctx, withoutArchived(bson.M{"warehouse_id": warehouseId}))
 	if err != nil {
 		return nil, err
 	}
@@ -377,7 +477,7 @@ func (r *ProductRepositoryMongoDB) GetProductsForAllStoresSubcategory(ctx contex
 	groupedByMetadata := make(map[string][]*entities.GetProductsForStoreSubcategory)
 
 	for _, store := range storesData {
-		apiResult, err := r.GetProductsForStoreSubcategory(ctx, store.StoreID, subcategoryId)
+		apiResult, err := r.getStoreProducts(ctx, store.StoreID, metadataMatch)
 		if err != nil {
 			return nil, err
 		}
@@ -420,6 +520,53 @@ func (r *ProductRepositoryMongoDB) GetProductsForAllStoresSubcategory(ctx contex
 	return result, nil
 }
 
+// SearchProducts ranks the products of a store, or of all stores in the warehouse when storeId is "0", against a
+// free-text search over the catalog
+func (r *ProductRepositoryMongoDB) SearchProducts(ctx context.Context, storeId, warehouseId, search string, limit int) ([]*entities.GetProductsForStoreSubcategory, error) {
+	hits, err := searchCatalog(ctx, r.db, search)
+	if err != nil {
+		return nil, err
+	}
+	rank := make(map[string]int, len(hits))
+	metadataIds := make([]primitive.ObjectID, 0, len(hits))
+	for i, hit := range hits {
+		id, err := primitive.ObjectIDFromHex(hit.MetadataID)
+		if err != nil {
+			continue
+		}
+		rank[hit.MetadataID] = i
+		metadataIds = append(metadataIds, id)
+	}
+	if len(metadataIds) == 0 {
+		return []*entities.GetProductsForStoreSubcategory{}, nil
+	}
+
+	metadataMatch := bson.M{"metadata._id": bson.M{"$in": metadataIds}}
+	var results []*entities.GetProductsForStoreSubcategory
+	if storeId == "0" {
+		results, err = r.getWarehouseProducts(ctx, warehouseId, metadataMatch)
+	} else {
+		results, err = r.getStoreProducts(ctx, storeId, metadataMatch)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return rank[results[i].MetadataProductId] < rank[results[j].MetadataProductId]
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// GetAutocompleteSuggestions suggests products in stock in the warehouse, and their categories and subcategories,
+// for a partially typed query
+func (r *ProductRepositoryMongoDB) GetAutocompleteSuggestions(ctx context.Context, warehouseId, query string, limit int) (*entities.AutocompleteResponse, error) {
+	return suggestCatalog(ctx, r.db, warehouseId, query, limit)
+}
+
 func (r *ProductRepositoryMongoDB) GetBasicDetailsForProduct(ctx context.Context, inventoryProductID string) (*entities.GetBasicDetailsForProductResponse, error) {
 	inventoryProductsCollection := r.db.Collection("inventory_product")
 
@@ -466,7 +613,17 @@ func (r *ProductRepositoryMongoDB) GetBasicDetailsForProduct(ctx context.Context
 
 		{{Key: "$unwind", Value: bson.M{"path": "$store", "preserveNullAndEmptyArrays": true}}},
 
-		{{Key: "$project", Value: bson.M{
+		{{Key: "$match", Value: bson.M{
+			"metadata.archived_at":    bson.M{"$exists": false},
+			"category.archived_at":    bson.M{"$exists": false},
+			"subcategory.archived_at": bson.M{"$exists": false},
+			"store.archived_at":       bson.M{"$exists": false},
+		}}},
+	}
+	pipeline = append(pipeline, activeScheduledPriceStages(bson.M{"$toString": "$_id"}, "$metadata.metadata_mrp")...)
+	priceFields := effectivePriceFields("$product_price")
+	pipeline = append(pipeline,
+		bson.D{{Key: "$project", Value: bson.M{
 			"metadata_id":                "$metadata._id",
 			"metadata_name":              "$metadata.metadata_name",
 			"metadata_description":       "$metadata.metadata_description",
@@ -481,12 +638,21 @@ func (r *ProductRepositoryMongoDB) GetBasicDetailsForProduct(ctx context.Context
 			"_id":                        1,
 			"inventory_id":               1,
 			"product_quantity":           1,
-			"product_price":              1,
+			"product_price":              priceFields["product_price"],
+			"was_price":                  priceFields["was_price"],
+			"sale_ends_at":               priceFields["sale_ends_at"],
+			"sale_label":                 priceFields["sale_label"],
 			"product_expiry_date":        1,
 			"product_manufacturing_date": 1,
 			"store_name":                 "$store.store_name",
+			"store_id":                   "$inventory.store_id",
+			"metadata_gallery":           "$metadata.metadata_gallery",
+			"variant_group_id":           "$metadata.variant_group_id",
+			"variant_attributes":         "$metadata.variant_attributes",
+			"metadata_attributes":        "$metadata.metadata_attributes",
+			"hsn_code":                   "$metadata.hsn_code",
 		}}},
-	}
+	)
 
 	cursor, err := inventoryProductsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -495,30 +661,42 @@ func (r *ProductRepositoryMongoDB) GetBasicDetailsForProduct(ctx context.Context
 	defer cursor.Close(ctx)
 
 	type aggResult struct {
-		MetadataProductId        string    `bson:"metadata_id"`
-		MetadataName             string    `bson:"metadata_name"`
-		MetadataDescription      string    `bson:"metadata_description"`
-		MetadataImage            string    `bson:"metadata_image"`
-		MetadataCategoryId       string    `bson:"metadata_category_id"`
-		MetadataSubcategoryId    string    `bson:"metadata_subcategory_id"`
-		MetadataMrp              float64   `bson:"metadata_mrp"`
-		ProductCategoryName      string    `bson:"category_name"`
-		ProductSubCategoryName   string    `bson:"subcategory_name"`
-		TotalStars               int       `bson:"total_stars"`
-		TotalReviews             int       `bson:"total_reviews"`
-		InventoryProductId       string    `bson:"_id"`
-		InventoryId              string    `bson:"inventory_id"`
-		ProductPrice             float64   `bson:"product_price"`
-		ProductQuantity          int       `bson:"product_quantity"`
-		ProductExpiryDate        time.Time `bson:"product_expiry_date"`
-		ProductManufacturingDate time.Time `bson:"product_manufacturing_date"`
-		StoreName                string    `bson:"store_name"`
+		MetadataProductId        string                 `bson:"metadata_id"`
+		MetadataName             string                 `bson:"metadata_name"`
+		MetadataDescription      string                 `bson:"metadata_description"`
+		MetadataImage            string                 `bson:"metadata_image"`
+		MetadataCategoryId       string                 `bson:"metadata_category_id"`
+		MetadataSubcategoryId    string                 `bson:"metadata_subcategory_id"`
+		MetadataMrp              float64                `bson:"metadata_mrp"`
+		ProductCategoryName      string                 `bson:"category_name"`
+		ProductSubCategoryName   string                 `bson:"subcategory_name"`
+		TotalStars               int                    `bson:"total_stars"`
+		TotalReviews             int                    `bson:"total_reviews"`
+		InventoryProductId       string                 `bson:"_id"`
+		InventoryId              string                 `bson:"inventory_id"`
+		ProductPrice             float64                `bson:"product_price"`
+		ProductQuantity          int                    `bson:"product_quantity"`
+		ProductExpiryDate        time.Time              `bson:"product_expiry_date"`
+		ProductManufacturingDate time.Time              `bson:"product_manufacturing_date"`
+		StoreName                string                 `bson:"store_name"`
+		StoreID                  string                 `bson:"store_id"`
+		MetadataGallery          []string               `bson:"metadata_gallery"`
+		WasPrice                 *float64               `bson:"was_price"`
+		SaleEndsAt               *time.Time             `bson:"sale_ends_at"`
+		SaleLabel                string                 `bson:"sale_label"`
+		VariantGroupID           string                 `bson:"variant_group_id"`
+		VariantAttributes        map[string]string      `bson:"variant_attributes"`
+		MetadataAttributes       map[string]interface{} `bson:"metadata_attributes"`
+		HsnCode                  string                 `bson:"hsn_code"`
 	}
 
 	var products []*aggResult
 	if err := cursor.All(ctx, &products); err != nil {
 		return nil, err
 	}
+	if len(products) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
 	metadataData := products[0]
 	result := &entities.GetBasicDetailsForProductResponse{
 		MetadataProductId:        metadataData.MetadataProductId,
@@ -544,7 +722,33 @@ func (r *ProductRepositoryMongoDB) GetBasicDetailsForProduct(ctx context.Context
 			}
 			return float64(metadataData.TotalStars) / float64(metadataData.TotalReviews)
 		}(),
-		StoreName: metadataData.StoreName,
+		StoreName:          metadataData.StoreName,
+		WasPrice:           metadataData.WasPrice,
+		SaleEndsAt:         metadataData.SaleEndsAt,
+		SaleLabel:          metadataData.SaleLabel,
+		VariantGroupID:     metadataData.VariantGroupID,
+		VariantAttributes:  metadataData.VariantAttributes,
+		MetadataGallery:    metadataData.MetadataGallery,
+		MetadataAttributes: metadataData.MetadataAttributes,
+		HsnCode:            metadataData.HsnCode,
+	}
+
+	if err := setProductGSTRates(ctx, r.db, []*entities.GetProductsForStoreSubcategory{result}); err != nil {
+		return nil, err
+	}
+
+	// the picker lists the variants the same store has
+	if result.VariantGroupID != "" && metadataData.StoreID != "" {
+		groups, err := getVariantGroupSummaries(ctx, r.db, []string{result.VariantGroupID})
+		if err != nil {
+			return nil, err
+		}
+		siblings, err := r.getStoreProducts(ctx, metadataData.StoreID, bson.M{"metadata.variant_group_id": result.VariantGroupID})
+		if err != nil {
+			return nil, err
+		}
+		result.VariantGroup = groups[result.VariantGroupID]
+		result.Variants = variantOptions(siblings)
 	}
 
 	return result, nil
@@ -565,6 +769,14 @@ func (r *ProductRepositoryMongoDB) GetProductComparisonByStore(ctx context.Conte
 	if err != nil {
 		return nil, err
 	}
+	metadataObjectID, err := primitive.ObjectIDFromHex(productDetails.MetadataProductID)
+	if err != nil {
+		return nil, err
+	}
+	var metadata entities.Metadata
+	if err := r.db.Collection("metadata").FindOne(ctx, bson.M{"_id": metadataObjectID}).Decode(&metadata); err != nil {
+		return nil, err
+	}
 
 	pipeline := mongo.Pipeline{
 
@@ -583,18 +795,24 @@ func (r *ProductRepositoryMongoDB) GetProductComparisonByStore(ctx context.Conte
 		{{Key: "$unwind", Value: bson.M{"path": "$inventoryProduct", "preserveNullAndEmptyArrays": true}}},
 
 		{{Key: "$match", Value: bson.M{"inventoryProduct.metadata_product_id": productDetails.MetadataProductID, "inventoryProduct.product_visibility": true}}},
-
-		{{Key: "$project", Value: bson.M{
+	}
+	pipeline = append(pipeline, activeScheduledPriceStages(bson.M{"$toString": "$inventoryProduct._id"}, metadata.MetadataMRP)...)
+	priceFields := effectivePriceFields("$inventoryProduct.product_price")
+	pipeline = append(pipeline,
+		bson.D{{Key: "$project", Value: bson.M{
 			"storeName":                  "$store_name",
 			"_id":                        "$inventoryProduct._id",
 			"inventory_id":               "$inventoryProduct.inventory_id",
 			"metadata_product_id":        "$inventoryProduct.metadata_product_id",
 			"product_manufacturing_date": "$inventoryProduct.product_manufacturing_date",
 			"product_quantity":           "$inventoryProduct.product_quantity",
-			"product_price":              "$inventoryProduct.product_price",
+			"product_price":              priceFields["product_price"],
+			"was_price":                  priceFields["was_price"],
+			"sale_ends_at":               priceFields["sale_ends_at"],
+			"sale_label":                 priceFields["sale_label"],
 			"product_expiry_date":        "$inventoryProduct.product_expiry_date",
 		}}},
-	}
+	)
 
 	cursor, err := storeCollection.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -610,3 +828,99 @@ func (r *ProductRepositoryMongoDB) GetProductComparisonByStore(ctx context.Conte
 	return result, nil
 
 }
+
+// getVariantGroupSummaries loads the name and axes of variant groups by id
+func getVariantGroupSummaries(ctx context.Context, db *mongo.Database, groupIds []string) (map[string]*entities.VariantGroupSummary, error) {
+	objectIds := make([]primitive.ObjectID, 0, len(groupIds))
+	for _, id := range groupIds {
+		if objectId, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIds = append(objectIds, objectId)
+		}
+	}
+	cursor, err := db.Collection("variant_groups").Find(ctx, bson.M{"_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		return nil, err
+	}
+	var groups []*entities.VariantGroup
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	summaries := make(map[string]*entities.VariantGroupSummary, len(groups))
+	for _, group := range groups {
+		summaries[group.ID] = &entities.VariantGroupSummary{ID: group.ID, Name: group.Name, Axes: group.Axes}
+	}
+	return summaries, nil
+}
+
+// variantOptions lists products as variant picker options, smallest MRP first
+func variantOptions(products []*entities.GetProductsForStoreSubcategory) []*entities.ProductVariantOption {
+	options := make([]*entities.ProductVariantOption, 0, len(products))
+	for _, product := range products {
+		options = append(options, &entities.ProductVariantOption{
+			MetadataProductId:  product.MetadataProductId,
+			InventoryProductId: product.InventoryProductId,
+			Name:               product.MetadataName,
+			Image:              product.MetadataImage,
+			Attributes:         product.VariantAttributes,
+			Mrp:                product.MetadataMrp,
+			ProductPrice:       product.ProductPrice,
+			WasPrice:           product.WasPrice,
+			ProductQuantity:    product.ProductQuantity,
+			StoreName:          product.StoreName,
+		})
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].Mrp != options[j].Mrp {
+			return options[i].Mrp < options[j].Mrp
+		}
+		return options[i].ProductPrice < options[j].ProductPrice
+	})
+	return options
+}
+
+// groupProductVariants collapses the variants of a group into one listing entry, the cheapest variant, which
+// carries every variant as an option. Products outside a variant group are left as they are.
+func groupProductVariants(ctx context.Context, db *mongo.Database, products []*entities.GetProductsForStoreSubcategory) ([]*entities.GetProductsForStoreSubcategory, error) {
+	members := make(map[string][]*entities.GetProductsForStoreSubcategory)
+	groupIds := []string{}
+	for _, product := range products {
+		if product.VariantGroupID == "" {
+			continue
+		}
+		if _, ok := members[product.VariantGroupID]; !ok {
+			groupIds = append(groupIds, product.VariantGroupID)
+		}
+		members[product.VariantGroupID] = append(members[product.VariantGroupID], product)
+	}
+	if len(groupIds) == 0 {
+		return products, nil
+	}
+	groups, err := getVariantGroupSummaries(ctx, db, groupIds)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make([]*entities.GetProductsForStoreSubcategory, 0, len(products))
+	listed := make(map[string]bool, len(groupIds))
+	for _, product := range products {
+		if product.VariantGroupID == "" {
+			grouped = append(grouped, product)
+			continue
+		}
+		if listed[product.VariantGroupID] {
+			continue
+		}
+		listed[product.VariantGroupID] = true
+		variants := members[product.VariantGroupID]
+		leader := variants[0]
+		for _, variant := range variants[1:] {
+			if variant.ProductPrice < leader.ProductPrice {
+				leader = variant
+			}
+		}
+		leader.VariantGroup = groups[product.VariantGroupID]
+		leader.Variants = variantOptions(variants)
+		grouped = append(grouped, leader)
+	}
+	return grouped, nil
+}
@@ -0,0 +1,298 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProductReviewRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewProductReviewRepositoryMongoDB(db *mongo.Database) repositories.ProductReviewRepository {
+	return &ProductReviewRepositoryMongoDB{db: db}
+}
+
+// isVerifiedPurchase reports whether one of the user's orders has a listing of the metadata product in it
+func isVerifiedPurchase(ctx context.Context, db *mongo.Database, userId, metadataId string) (bool, error) {
+	productIds, err := db.Collection("inventory_product").Distinct(ctx, "_id", bson.M{"metadata_product_id": metadataId})
+	if err != nil {
+		return false, err
+	}
+	if len(productIds) == 0 {
+		return false, nil
+	}
+	productHexIds := make(bson.A, 0, len(productIds))
+	for _, id := range productIds {
+		if objectId, ok := id.(primitive.ObjectID); ok {
+			productHexIds = append(productHexIds, objectId.Hex())
+		}
+	}
+
+	orderIds, err := db.Collection("order").Distinct(ctx, "order_id", bson.M{"user_id": userId})
+	if err != nil {
+		return false, err
+	}
+	if len(orderIds) == 0 {
+		return false, nil
+	}
+
+	count, err := db.Collection("orderedItems").CountDocuments(ctx, bson.M{
+		"order_id":   bson.M{"$in": orderIds},
+		"product_id": bson.M{"$in": productHexIds},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// refreshReviewAggregate recomputes the star totals of a metadata product from its approved reviews, so the
+// aggregate the listings join always agrees with the reviews stored
+func refreshReviewAggregate(ctx context.Context, db *mongo.Database, metadataId string) error {
+	cursor, err := db.Collection("product_reviews").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"metadata_product_id": metadataId, "status": entities.ProductReviewApproved}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           nil,
+			"total_stars":   bson.M{"$sum": "$rating"},
+			"total_reviews": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var totals struct {
+		TotalStars   int `bson:"total_stars"`
+		TotalReviews int `bson:"total_reviews"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&totals); err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Collection("reviews").UpdateOne(ctx,
+		bson.M{"_id": metadataId},
+		mongo.Pipeline{
+			reviewBaselineStage,
+			{{Key: "$set", Value: bson.M{
+				"total_stars":   bson.M{"$add": bson.A{"$baseline_stars", totals.TotalStars}},
+				"total_reviews": bson.M{"$add": bson.A{"$baseline_reviews", totals.TotalReviews}},
+			}}},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// reviewBaselineStage keeps the totals of a product counted before reviews were stored one by one as its baseline,
+// the first time its totals are recomputed
+var reviewBaselineStage = bson.D{{Key: "$set", Value: bson.M{
+	"baseline_stars":   bson.M{"$ifNull": bson.A{"$baseline_stars", bson.M{"$ifNull": bson.A{"$total_stars", 0}}}},
+	"baseline_reviews": bson.M{"$ifNull": bson.A{"$baseline_reviews", bson.M{"$ifNull": bson.A{"$total_reviews", 0}}}},
+}}}
+
+// upsertProductReview stores the review of a customer, replacing the one they left before on the same product.
+// A review with only a rating is published straight away, one with text or photos waits for moderation.
+func upsertProductReview(ctx context.Context, db *mongo.Database, review *entities.ProductReview) (*entities.ProductReview, error) {
+	metadataObjectId, err := primitive.ObjectIDFromHex(review.MetadataProductID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata product id")
+	}
+	count, err := db.Collection("metadata").CountDocuments(ctx, bson.M{"_id": metadataObjectId})
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("metadata product not found")
+	}
+
+	verified, err := isVerifiedPurchase(ctx, db, review.UserID, review.MetadataProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := entities.ProductReviewApproved
+	if review.Title != "" || review.Text != "" || len(review.Photos) > 0 {
+		status = entities.ProductReviewPending
+	}
+
+	now := time.Now()
+	var saved entities.ProductReview
+	err = db.Collection("product_reviews").FindOneAndUpdate(ctx,
+		bson.M{"metadata_product_id": review.MetadataProductID, "user_id": review.UserID},
+		bson.M{
+			"$set": bson.M{
+				"rating":            review.Rating,
+				"title":             review.Title,
+				"text":              review.Text,
+				"photos":            review.Photos,
+				"verified_purchase": verified,
+				"status":            status,
+				"updated_at":        now,
+			},
+			"$unset":       bson.M{"moderated_by": "", "moderation_note": "", "moderated_at": ""},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := refreshReviewAggregate(ctx, db, review.MetadataProductID); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (r *ProductReviewRepositoryMongoDB) SubmitReview(ctx context.Context, review *entities.ProductReview) (*entities.ProductReview, error) {
+	return upsertProductReview(ctx, r.db, review)
+}
+
+// GetProductReviews pages through the published reviews of a product, verified purchases first and newest first
+func (r *ProductReviewRepositoryMongoDB) GetProductReviews(ctx context.Context, metadataId string, limit, offset int64) (*entities.PaginatedProductReviews, error) {
+	collection := r.db.Collection("product_reviews")
+	filter := bson.M{"metadata_product_id": metadataId, "status": entities.ProductReviewApproved}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "verified_purchase", Value: -1}, {Key: "updated_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []*entities.ProductReview{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+
+	var aggregate entities.Review
+	err = r.db.Collection("reviews").FindOne(ctx, bson.M{"_id": metadataId}).Decode(&aggregate)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	result := &entities.PaginatedProductReviews{
+		Reviews:      reviews,
+		TotalStars:   aggregate.TotalStars,
+		TotalReviews: aggregate.TotalReviews,
+		Total:        total,
+		Limit:        limit,
+		Offset:       offset,
+	}
+	if aggregate.TotalReviews > 0 {
+		result.Rating = float64(aggregate.TotalStars) / float64(aggregate.TotalReviews)
+	}
+	return result, nil
+}
+
+func (r *ProductReviewRepositoryMongoDB) GetUserReviews(ctx context.Context, userId string) ([]*entities.ProductReview, error) {
+	cursor, err := r.db.Collection("product_reviews").Find(ctx, bson.M{"user_id": userId}, options.Find().SetSort(bson.M{"updated_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []*entities.ProductReview{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetModerationQueue lists reviews in a status, oldest first so the longest waiting are decided first
+func (r *ProductReviewRepositoryMongoDB) GetModerationQueue(ctx context.Context, status string, limit, offset int64) ([]*entities.ProductReview, int64, error) {
+	collection := r.db.Collection("product_reviews")
+	filter := bson.M{"status": status}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"updated_at": 1}).
+		SetSkip(offset).
+		SetLimit(limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []*entities.ProductReview{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *ProductReviewRepositoryMongoDB) ModerateReview(ctx context.Context, request *entities.ModerateProductReviewRequest) (*entities.ProductReview, error) {
+	objectId, err := primitive.ObjectIDFromHex(request.ReviewID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid review id")
+	}
+
+	now := time.Now()
+	var review entities.ProductReview
+	err = r.db.Collection("product_reviews").FindOneAndUpdate(ctx,
+		bson.M{"_id": objectId},
+		bson.M{"$set": bson.M{
+			"status":          request.Decision,
+			"moderated_by":    request.ModeratorID,
+			"moderation_note": request.Note,
+			"moderated_at":    now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&review)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("review not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := refreshReviewAggregate(ctx, r.db, review.MetadataProductID); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// DeleteReview removes a review. userId limits it to the customer's own reviews and is empty for operations.
+func (r *ProductReviewRepositoryMongoDB) DeleteReview(ctx context.Context, reviewId, userId string) error {
+	objectId, err := primitive.ObjectIDFromHex(reviewId)
+	if err != nil {
+		return fmt.Errorf("invalid review id")
+	}
+	filter := bson.M{"_id": objectId}
+	if userId != "" {
+		filter["user_id"] = userId
+	}
+
+	var review entities.ProductReview
+	err = r.db.Collection("product_reviews").FindOneAndDelete(ctx, filter).Decode(&review)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("review not found")
+	}
+	if err != nil {
+		return err
+	}
+	return refreshReviewAggregate(ctx, r.db, review.MetadataProductID)
+}
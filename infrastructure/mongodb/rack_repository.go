@@ -0,0 +1,376 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RackRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewRackRepositoryMongoDB(db *mongo.Database) repositories.RackRepository {
+	return &RackRepositoryMongoDB{db: db}
+}
+
+// getOperatedRack returns the rack if its warehouse is run by the given operations user
+func getOperatedRack(ctx context.Context, db *mongo.Database, rackId, operationalId string) (*entities.Rack, primitive.ObjectID, error) {
+	objectId, err := primitive.ObjectIDFromHex(rackId)
+	if err != nil {
+		return nil, objectId, fmt.Errorf("invalid rack id")
+	}
+	var rack entities.Rack
+	if err := db.Collection("racks").FindOne(ctx, bson.M{"_id": objectId}).Decode(&rack); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, objectId, fmt.Errorf("rack not found")
+		}
+		return nil, objectId, err
+	}
+	if err := checkWarehouseOperator(ctx, db, rack.WarehouseID, operationalId); err != nil {
+		return nil, objectId, err
+	}
+	return &rack, objectId, nil
+}
+
+// rackOccupancies sums the stock assigned to each of the given racks
+func rackOccupancies(ctx context.Context, db *mongo.Database, rackIds []string) (map[string]*entities.RackOccupancy, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"rack_id": bson.M{"$in": rackIds}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$rack_id",
+			"products": bson.M{"$sum": 1},
+			"quantity": bson.M{"$sum": "$product_quantity"},
+			"bins":     bson.M{"$addToSet": "$bin"},
+		}}},
+		{{Key: "$project", Value: bson.M{"products": 1, "quantity": 1, "occupied_bins": bson.M{"$size": "$bins"}}}},
+	}
+	cursor, err := db.Collection("inventory_product").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		RackID                 string `bson:"_id"`
+		entities.RackOccupancy `bson:",inline"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	occupancies := make(map[string]*entities.RackOccupancy, len(results))
+	for i := range results {
+		occupancies[results[i].RackID] = &results[i].RackOccupancy
+	}
+	return occupancies, nil
+}
+
+// withOccupancy fills in the computed occupancy of racks
+func withOccupancy(ctx context.Context, db *mongo.Database, racks []*entities.Rack) error {
+	rackIds := make([]string, 0, len(racks))
+	for _, rack := range racks {
+		rackIds = append(rackIds, rack.ID)
+	}
+	occupancies, err := rackOccupancies(ctx, db, rackIds)
+	if err != nil {
+		return err
+	}
+	for _, rack := range racks {
+		occupancy, ok := occupancies[rack.ID]
+		if !ok {
+			occupancy = &entities.RackOccupancy{}
+		}
+		if rack.Capacity > 0 {
+			occupancy.OccupancyPercent = math.Round(float64(occupancy.Quantity)/float64(rack.Capacity)*10000) / 100
+		}
+		rack.Occupancy = occupancy
+	}
+	return nil
+}
+
+// withRackCounts fills in the number of racks of stores and how many of them hold stock, computed on read from
+// the racks and the stock assigned to them like the occupancy of a rack, so the counts cannot go stale
+func withRackCounts(ctx context.Context, db *mongo.Database, stores []*entities.Store) error {
+	if len(stores) == 0 {
+		return nil
+	}
+	storeIds := make([]string, 0, len(stores))
+	for _, store := range stores {
+		storeIds = append(storeIds, store.StoreID)
+	}
+	cursor, err := db.Collection("racks").Find(ctx, bson.M{"store_id": bson.M{"$in": storeIds}})
+	if err != nil {
+		return err
+	}
+	var racks []*entities.Rack
+	if err := cursor.All(ctx, &racks); err != nil {
+		return err
+	}
+	if err := withOccupancy(ctx, db, racks); err != nil {
+		return err
+	}
+	rackCounts := make(map[string]int, len(stores))
+	occupiedCounts := make(map[string]int, len(stores))
+	for _, rack := range racks {
+		rackCounts[rack.StoreID]++
+		if rack.Occupancy.Quantity > 0 {
+			occupiedCounts[rack.StoreID]++
+		}
+	}
+	for _, store := range stores {
+		store.NumberOfRacks = rackCounts[store.StoreID]
+		store.OccupiedRacks = occupiedCounts[store.StoreID]
+	}
+	return nil
+}
+
+func (r *RackRepositoryMongoDB) CreateRack(ctx context.Context, request *entities.CreateRackRequest) (*entities.Rack, error) {
+	store, err := getOperatedStore(ctx, r.db, request.StoreID, request.OperationalID)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := r.db.Collection("racks")
+	count, err := collection.CountDocuments(ctx, bson.M{"store_id": store.StoreID, "code": request.Code})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("rack %s already exists in this store", request.Code)
+	}
+
+	now := time.Now()
+	rack := &entities.Rack{
+		WarehouseID: store.WarehouseID,
+		StoreID:     store.StoreID,
+		Code:        request.Code,
+		Description: request.Description,
+		BinCount:    request.BinCount,
+		Capacity:    request.Capacity,
+		CreatedBy:   request.OperationalID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	result, err := collection.InsertOne(ctx, rack)
+	if err != nil {
+		return nil, err
+	}
+	insertedId, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("error in getting inserted rack id")
+	}
+	rack.ID = insertedId.Hex()
+	rack.Occupancy = &entities.RackOccupancy{}
+	return rack, nil
+}
+
+// UpdateRack changes the description, bins and capacity of a rack. Bins in use and stock already on the rack
+// cannot be removed by shrinking it.
+func (r *RackRepositoryMongoDB) UpdateRack(ctx context.Context, request *entities.UpdateRackRequest) (*entities.Rack, error) {
+	rack, objectId, err := getOperatedRack(ctx, r.db, request.RackID, request.OperationalID)
+	if err != nil {
+		return nil, err
+	}
+
+	productCollection := r.db.Collection("inventory_product")
+	var highestBin entities.InventoryProduct
+	err = productCollection.FindOne(ctx, bson.M{"rack_id": rack.ID}, options.FindOne().SetSort(bson.M{"bin": -1})).Decode(&highestBin)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	if highestBin.Bin > request.BinCount {
+		return nil, fmt.Errorf("bin %d is in use, bin_count cannot be lower", highestBin.Bin)
+	}
+	if err := withOccupancy(ctx, r.db, []*entities.Rack{rack}); err != nil {
+		return nil, err
+	}
+	if request.Capacity > 0 && int64(request.Capacity) < rack.Occupancy.Quantity {
+		return nil, fmt.Errorf("rack already holds %d units, capacity cannot be lower", rack.Occupancy.Quantity)
+	}
+
+	rack.Description = request.Description
+	rack.BinCount = request.BinCount
+	rack.Capacity = request.Capacity
+	rack.UpdatedAt = time.Now()
+	_, err = r.db.Collection("racks").UpdateOne(ctx,
+		bson.M{"_id": objectId},
+		bson.M{"$set": bson.M{"description": rack.Description, "bin_count": rack.BinCount, "capacity": rack.Capacity, "updated_at": rack.UpdatedAt}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := withOccupancy(ctx, r.db, []*entities.Rack{rack}); err != nil {
+		return nil, err
+	}
+	return rack, nil
+}
+
+func (r *RackRepositoryMongoDB) DeleteRack(ctx context.Context, rackId, operationalId string) error {
+	rack, objectId, err := getOperatedRack(ctx, r.db, rackId, operationalId)
+	if err != nil {
+		return err
+	}
+	assigned, err := r.db.Collection("inventory_product").CountDocuments(ctx, bson.M{"rack_id": rack.ID})
+	if err != nil {
+		return err
+	}
+	if assigned > 0 {
+		return fmt.Errorf("rack still has %d products assigned", assigned)
+	}
+	_, err = r.db.Collection("racks").DeleteOne(ctx, bson.M{"_id": objectId})
+	return err
+}
+
+func (r *RackRepositoryMongoDB) GetRacks(ctx context.Context, storeId, operationalId string) ([]*entities.Rack, error) {
+	if _, err := getOperatedStore(ctx, r.db, storeId, operationalId); err != nil {
+		return nil, err
+	}
+	cursor, err := r.db.Collection("racks").Find(ctx, bson.M{"store_id": storeId}, options.Find().SetSort(bson.M{"code": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	racks := []*entities.Rack{}
+	if err := cursor.All(ctx, &racks); err != nil {
+		return nil, err
+	}
+	if err := withOccupancy(ctx, r.db, racks); err != nil {
+		return nil, err
+	}
+	return racks, nil
+}
+
+func (r *RackRepositoryMongoDB) GetRackById(ctx context.Context, rackId, operationalId string) (*entities.Rack, error) {
+	rack, _, err := getOperatedRack(ctx, r.db, rackId, operationalId)
+	if err != nil {
+		return nil, err
+	}
+	if err := withOccupancy(ctx, r.db, []*entities.Rack{rack}); err != nil {
+		return nil, err
+	}
+	return rack, nil
+}
+
+// AssignRackBin moves an inventory product to a bin of a rack in its own store, refusing moves that overfill the rack
+func (r *RackRepositoryMongoDB) AssignRackBin(ctx context.Context, request *entities.AssignRackBinRequest) (*entities.InventoryProduct, error) {
+	productObjectId, err := primitive.ObjectIDFromHex(request.InventoryProductID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inventory product id")
+	}
+	productCollection := r.db.Collection("inventory_product")
+	var product entities.InventoryProduct
+	if err := productCollection.FindOne(ctx, bson.M{"_id": productObjectId}).Decode(&product); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("inventory product not found")
+		}
+		return nil, err
+	}
+	inventoryObjectId, err := primitive.ObjectIDFromHex(product.InventoryID)
+	if err != nil {
+		return nil, fmt.Errorf("inventory product has an invalid inventory id")
+	}
+	var inventory entities.Inventory
+	if err := r.db.Collection("inventory").FindOne(ctx, bson.M{"_id": inventoryObjectId}).Decode(&inventory); err != nil {
+		return nil, fmt.Errorf("inventory of the product not found")
+	}
+	if _, err := getOperatedStore(ctx, r.db, inventory.StoreId, request.OperationalID); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$unset": bson.M{"rack_id": "", "bin": ""}}
+	if request.RackID != "" {
+		rack, _, err := getOperatedRack(ctx, r.db, request.RackID, request.OperationalID)
+		if err != nil {
+			return nil, err
+		}
+		if rack.StoreID != inventory.StoreId {
+			return nil, fmt.Errorf("rack %s is not in the product's store", rack.Code)
+		}
+		if request.Bin < 1 || request.Bin > rack.BinCount {
+			return nil, fmt.Errorf("bin must be between 1 and %d on rack %s", rack.BinCount, rack.Code)
+		}
+		if rack.Capacity > 0 && product.RackID != rack.ID {
+			if err := withOccupancy(ctx, r.db, []*entities.Rack{rack}); err != nil {
+				return nil, err
+			}
+			if rack.Occupancy.Quantity+int64(product.ProductQuantity) > int64(rack.Capacity) {
+				return nil, fmt.Errorf("rack %s has room for %d more units, the product has %d",
+					rack.Code, int64(rack.Capacity)-rack.Occupancy.Quantity, product.ProductQuantity)
+			}
+		}
+		update = bson.M{"$set": bson.M{"rack_id": rack.ID, "bin": request.Bin}}
+	}
+
+	err = productCollection.FindOneAndUpdate(ctx, bson.M{"_id": productObjectId}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&product)
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// FindProductLocations lists where the stock matching a search is kept in a warehouse. The search matches the
+// product name, or exactly an inventory product or metadata id.
+func (r *RackRepositoryMongoDB) FindProductLocations(ctx context.Context, request *entities.FindProductLocationRequest) ([]*entities.ProductLocation, error) {
+	if err := checkWarehouseOperator(ctx, r.db, request.WarehouseID, request.OperationalID); err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"warehouse_id": request.WarehouseID}}},
+		{{Key: "$addFields", Value: bson.M{"storeId": bson.M{"$toString": "$_id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "inventory", "localField": "storeId", "foreignField": "store_id", "as": "inventoryInfo"}}},
+		{{Key: "$unwind", Value: "$inventoryInfo"}},
+		{{Key: "$addFields", Value: bson.M{"inventoryId": bson.M{"$toString": "$inventoryInfo._id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "inventory_product", "localField": "inventoryId", "foreignField": "inventory_id", "as": "productInfo"}}},
+		{{Key: "$unwind", Value: "$productInfo"}},
+		{{Key: "$addFields", Value: bson.M{
+			"metadataObjectId": toObjectIdOrNull("$productInfo.metadata_product_id"),
+			"rackObjectId":     toObjectIdOrNull("$productInfo.rack_id"),
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": "metadata", "localField": "metadataObjectId", "foreignField": "_id", "as": "metadataInfo"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$metadataInfo", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"metadataInfo.metadata_name": bson.M{"$regex": regexp.QuoteMeta(request.Search), "$options": "i"}},
+			bson.M{"productInfo.metadata_product_id": request.Search},
+			bson.M{"$expr": bson.M{"$eq": bson.A{bson.M{"$toString": "$productInfo._id"}, request.Search}}},
+		}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "racks", "localField": "rackObjectId", "foreignField": "_id", "as": "rackInfo"}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$rackInfo", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":                  0,
+			"inventory_product_id": bson.M{"$toString": "$productInfo._id"},
+			"metadata_product_id":  "$productInfo.metadata_product_id",
+			"metadata_name":        "$metadataInfo.metadata_name",
+			"store_id":             "$storeId",
+			"store_name":           "$store_name",
+			"rack_id":              "$productInfo.rack_id",
+			"rack_code":            "$rackInfo.code",
+			"bin":                  "$productInfo.bin",
+			"product_quantity":     "$productInfo.product_quantity",
+			"product_expiry_date":  "$productInfo.product_expiry_date",
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "metadata_name", Value: 1}, {Key: "store_name", Value: 1}, {Key: "rack_code", Value: 1}, {Key: "bin", Value: 1}}}},
+		{{Key: "$limit", Value: 100}},
+	}
+	cursor, err := r.db.Collection("stores").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	locations := []*entities.ProductLocation{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
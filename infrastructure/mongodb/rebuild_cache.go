@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rebuildCache keeps values that are expensive to build, one per key, and rebuilds a value once it is older than
+// ttl. Builds run outside the lock and only once per key at a time: callers arriving during a build keep reading
+// the stale value, or wait for the build when there is none.
+type rebuildCache[T any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	entries    map[string]*rebuildCacheEntry[T]
+	builds     map[string]*rebuildCall[T]
+	generation int
+}
+
+type rebuildCacheEntry[T any] struct {
+	value   T
+	builtAt time.Time
+}
+
+// rebuildCall is a build in progress, done is closed once value and err are set
+type rebuildCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func newRebuildCache[T any](ttl time.Duration) *rebuildCache[T] {
+	return &rebuildCache[T]{
+		ttl:     ttl,
+		entries: make(map[string]*rebuildCacheEntry[T]),
+		builds:  make(map[string]*rebuildCall[T]),
+	}
+}
+
+// get returns the value of key, building it when missing or expired. The build is not cancelled with ctx since
+// other callers may be waiting on it.
+func (c *rebuildCache[T]) get(ctx context.Context, key string, build func(ctx context.Context) (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, cached := c.entries[key]
+	if cached && time.Since(entry.builtAt) < c.ttl {
+		c.mu.Unlock()
+		return entry.value, nil
+	}
+	call, building := c.builds[key]
+	if !building {
+		call = &rebuildCall[T]{done: make(chan struct{})}
+		c.builds[key] = call
+		go c.run(context.WithoutCancel(ctx), key, call, c.generation, build)
+	}
+	c.mu.Unlock()
+	if cached {
+		return entry.value, nil
+	}
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (c *rebuildCache[T]) run(ctx context.Context, key string, call *rebuildCall[T], generation int, build func(ctx context.Context) (T, error)) {
+	call.value, call.err = build(ctx)
+
+	c.mu.Lock()
+	if c.builds[key] == call {
+		delete(c.builds, key)
+	}
+	// A value built before an invalidation may miss the change that invalidated it
+	if call.err == nil && generation == c.generation {
+		c.entries[key] = &rebuildCacheEntry[T]{value: call.value, builtAt: time.Now()}
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// invalidate drops every value, so the next get of each key builds it again
+func (c *rebuildCache[T]) invalidate() {
+	c.mu.Lock()
+	c.entries = make(map[string]*rebuildCacheEntry[T])
+	c.builds = make(map[string]*rebuildCall[T])
+	c.generation++
+	c.mu.Unlock()
+}
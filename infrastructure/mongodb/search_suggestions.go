@@ -0,0 +1,216 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Autocomplete suggests the products a warehouse has in stock and their categories and subcategories. The list of
+// candidates with their order counts is loaded per warehouse and kept for suggestionSourceTTL.
+
+const suggestionSourceTTL = 5 * time.Minute
+
+type suggestionProduct struct {
+	MetadataID      string `bson:"_id"`
+	Name            string `bson:"name"`
+	Image           string `bson:"image"`
+	CategoryID      string `bson:"category_id"`
+	CategoryName    string `bson:"category_name"`
+	SubcategoryID   string `bson:"subcategory_id"`
+	SubcategoryName string `bson:"subcategory_name"`
+	Orders          int64  `bson:"orders"`
+	tokens          []string
+}
+
+type suggestionSource struct {
+	products []*suggestionProduct
+}
+
+// suggestionSources holds the suggestion source of each warehouse
+var suggestionSources = newRebuildCache[*suggestionSource](suggestionSourceTTL)
+
+func getSuggestionSource(ctx context.Context, db *mongo.Database, warehouseId string) (*suggestionSource, error) {
+	return suggestionSources.get(ctx, warehouseId, func(ctx context.Context) (*suggestionSource, error) {
+		return buildSuggestionSource(ctx, db, warehouseId)
+	})
+}
+
+// buildSuggestionSource loads the products in stock in a warehouse with the number of units ordered from it
+func buildSuggestionSource(ctx context.Context, db *mongo.Database, warehouseId string) (*suggestionSource, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"warehouse_id": warehouseId}}},
+		{{Key: "$addFields", Value: bson.M{"storeId": bson.M{"$toString": "$_id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "inventory", "localField": "storeId", "foreignField": "store_id", "as": "inventoryInfo"}}},
+		{{Key: "$unwind", Value: "$inventoryInfo"}},
+		{{Key: "$addFields", Value: bson.M{"inventoryId": bson.M{"$toString": "$inventoryInfo._id"}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "inventory_product", "localField": "inventoryId", "foreignField": "inventory_id", "as": "productInfo"}}},
+		{{Key: "$unwind", Value: "$productInfo"}},
+		{{Key: "$match", Value: bson.M{"productInfo.product_visibility": true, "productInfo.product_quantity": bson.M{"$gt": 0}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$productInfo.metadata_product_id"}}},
+		{{Key: "$addFields", Value: bson.M{"metadataObjectId": toObjectIdOrNull("$_id")}}},
+		{{Key: "$lookup", Value: bson.M{"from": "metadata", "localField": "metadataObjectId", "foreignField": "_id", "as": "metadataInfo"}}},
+		{{Key: "$unwind", Value: "$metadataInfo"}},
+		{{Key: "$addFields", Value: bson.M{
+			"categoryObjectId":    toObjectIdOrNull("$metadataInfo.metadata_category_id"),
+			"subcategoryObjectId": toObjectIdOrNull("$metadataInfo.metadata_subcategory_id"),
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": "categories", "localField": "categoryObjectId", "foreignField": "_id", "as": "categoryInfo"}}},
+		{{Key: "$lookup", Value: bson.M{"from": "subcategories", "localField": "subcategoryObjectId", "foreignField": "_id", "as": "subcategoryInfo"}}},
+		{{Key: "$project", Value: bson.M{
+			"name":             "$metadataInfo.metadata_name",
+			"image":            "$metadataInfo.metadata_image",
+			"category_id":      "$metadataInfo.metadata_category_id",
+			"category_name":    bson.M{"$first": "$categoryInfo.category_name"},
+			"subcategory_id":   "$metadataInfo.metadata_subcategory_id",
+			"subcategory_name": bson.M{"$first": "$subcategoryInfo.subcategory_name"},
+		}}},
+	}
+	cursor, err := db.Collection("stores").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var products []*suggestionProduct
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	orders, err := metadataOrderCounts(ctx, db, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range products {
+		product.Orders = orders[product.MetadataID]
+		product.tokens = tokenizeSearchText(product.Name)
+	}
+
+	return &suggestionSource{products: products}, nil
+}
+
+// metadataOrderCounts sums the units of each product ordered from a warehouse
+func metadataOrderCounts(ctx context.Context, db *mongo.Database, warehouseId string) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"warehouse_id": warehouseId}}},
+		{{Key: "$lookup", Value: bson.M{"from": "orderedItems", "localField": "order_id", "foreignField": "order_id", "as": "items"}}},
+		{{Key: "$unwind", Value: "$items"}},
+		{{Key: "$group", Value: bson.M{"_id": "$items.product_id", "quantity": bson.M{"$sum": "$items.quantity"}}}},
+		{{Key: "$addFields", Value: bson.M{"productObjectId": toObjectIdOrNull("$_id")}}},
+		{{Key: "$lookup", Value: bson.M{"from": "inventory_product", "localField": "productObjectId", "foreignField": "_id", "as": "productInfo"}}},
+		{{Key: "$unwind", Value: "$productInfo"}},
+		{{Key: "$group", Value: bson.M{"_id": "$productInfo.metadata_product_id", "quantity": bson.M{"$sum": "$quantity"}}}},
+	}
+	cursor, err := db.Collection("order").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		MetadataID string `bson:"_id"`
+		Quantity   int64  `bson:"quantity"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(results))
+	for _, result := range results {
+		counts[result.MetadataID] = result.Quantity
+	}
+	return counts, nil
+}
+
+// matchesAsYouType reports whether every query term starts a word of the text, so "pan tik" matches
+// "Paneer Tikka"
+func matchesAsYouType(terms, words []string) bool {
+	for _, term := range terms {
+		matched := false
+		for _, word := range words {
+			if strings.HasPrefix(word, term) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// rankSuggestions orders suggestions by popularity, then those whose text starts with the query, then by text
+func rankSuggestions(suggestions []*entities.SearchSuggestion, phrase string, limit int) []*entities.SearchSuggestion {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Popularity != suggestions[j].Popularity {
+			return suggestions[i].Popularity > suggestions[j].Popularity
+		}
+		iStarts := strings.HasPrefix(strings.Join(tokenizeSearchText(suggestions[i].Text), " "), phrase)
+		jStarts := strings.HasPrefix(strings.Join(tokenizeSearchText(suggestions[j].Text), " "), phrase)
+		if iStarts != jStarts {
+			return iStarts
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// suggestCatalog builds product, category and subcategory suggestions for a partial query. Categories and
+// subcategories are as popular as the units ordered of their products in the warehouse.
+func suggestCatalog(ctx context.Context, db *mongo.Database, warehouseId, query string, limit int) (*entities.AutocompleteResponse, error) {
+	response := &entities.AutocompleteResponse{
+		Query:         query,
+		Products:      []*entities.SearchSuggestion{},
+		Categories:    []*entities.SearchSuggestion{},
+		Subcategories: []*entities.SearchSuggestion{},
+	}
+	terms := tokenizeSearchText(query)
+	if len(terms) == 0 {
+		return response, nil
+	}
+	source, err := getSuggestionSource(ctx, db, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]*entities.SearchSuggestion)
+	subcategories := make(map[string]*entities.SearchSuggestion)
+	for _, product := range source.products {
+		if matchesAsYouType(terms, product.tokens) {
+			response.Products = append(response.Products, &entities.SearchSuggestion{
+				ID:         product.MetadataID,
+				Text:       product.Name,
+				Image:      product.Image,
+				Popularity: product.Orders,
+			})
+		}
+		if product.CategoryName != "" && matchesAsYouType(terms, tokenizeSearchText(product.CategoryName)) {
+			if _, ok := categories[product.CategoryID]; !ok {
+				categories[product.CategoryID] = &entities.SearchSuggestion{ID: product.CategoryID, Text: product.CategoryName}
+			}
+			categories[product.CategoryID].Popularity += product.Orders
+		}
+		if product.SubcategoryName != "" && matchesAsYouType(terms, tokenizeSearchText(product.SubcategoryName)) {
+			if _, ok := subcategories[product.SubcategoryID]; !ok {
+				subcategories[product.SubcategoryID] = &entities.SearchSuggestion{ID: product.SubcategoryID, Text: product.SubcategoryName}
+			}
+			subcategories[product.SubcategoryID].Popularity += product.Orders
+		}
+	}
+	for _, category := range categories {
+		response.Categories = append(response.Categories, category)
+	}
+	for _, subcategory := range subcategories {
+		response.Subcategories = append(response.Subcategories, subcategory)
+	}
+
+	phrase := strings.Join(terms, " ")
+	response.Products = rankSuggestions(response.Products, phrase, limit)
+	response.Categories = rankSuggestions(response.Categories, phrase, limit)
+	response.Subcategories = rankSuggestions(response.Subcategories, phrase, limit)
+	return response, nil
+}
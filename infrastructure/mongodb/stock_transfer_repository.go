@@ -0,0 +1,418 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type StockTransferRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewStockTransferRepositoryMongoDB(db *mongo.Database) repositories.StockTransferRepository {
+	return &StockTransferRepositoryMongoDB{db: db}
+}
+
+// getOperatedStore returns the store if it belongs to a warehouse run by the given operations user
+func getOperatedStore(ctx context.Context, db *mongo.Database, storeId, operationalId string) (*entities.Store, error) {
+	storeObjectId, err := primitive.ObjectIDFromHex(storeId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store id %s", storeId)
+	}
+	var store entities.Store
+	if err := db.Collection("stores").FindOne(ctx, bson.M{"_id": storeObjectId}).Decode(&store); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("store %s not found", storeId)
+		}
+		return nil, err
+	}
+	if err := checkWarehouseOperator(ctx, db, store.WarehouseID, operationalId); err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+func checkWarehouseOperator(ctx context.Context, db *mongo.Database, warehouseId, operationalId string) error {
+	warehouseObjectId, err := primitive.ObjectIDFromHex(warehouseId)
+	if err != nil {
+		return fmt.Errorf("invalid warehouse id %s", warehouseId)
+	}
+	count, err := db.Collection("warehouses").CountDocuments(ctx, bson.M{"_id": warehouseObjectId, "warehouse_operational_guy_id": operationalId})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("warehouse is not operated by this user")
+	}
+	return nil
+}
+
+// getOrCreateStoreInventory returns the inventory of a store, creating it for the store's seller when missing
+func getOrCreateStoreInventory(ctx context.Context, db *mongo.Database, store *entities.Store) (*entities.Inventory, error) {
+	collection := db.Collection("inventory")
+	var inventory entities.Inventory
+	err := collection.FindOne(ctx, bson.M{"store_id": store.StoreID}).Decode(&inventory)
+	if err == nil {
+		return &inventory, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	inventory = entities.Inventory{SellerID: store.SellerID, StoreId: store.StoreID}
+	result, err := collection.InsertOne(ctx, inventory)
+	if err != nil {
+		return nil, err
+	}
+	insertedId, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("error in getting inserted inventory id")
+	}
+	inventory.InventoryID = insertedId.Hex()
+	return &inventory, nil
+}
+
+func (r *StockTransferRepositoryMongoDB) CreateStockTransfer(ctx context.Context, request *entities.CreateStockTransferRequest) (*entities.StockTransfer, error) {
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var transfer *entities.StockTransfer
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		sourceStore, err := getOperatedStore(sc, r.db, request.SourceStoreID, request.OperationalID)
+		if err != nil {
+			return nil, err
+		}
+		destinationStore, err := getOperatedStore(sc, r.db, request.DestinationStoreID, request.OperationalID)
+		if err != nil {
+			return nil, err
+		}
+		if sourceStore.WarehouseID != destinationStore.WarehouseID {
+			return nil, fmt.Errorf("stores must belong to the same warehouse")
+		}
+
+		var sourceInventory entities.Inventory
+		if err := r.db.Collection("inventory").FindOne(sc, bson.M{"store_id": sourceStore.StoreID}).Decode(&sourceInventory); err != nil {
+			return nil, fmt.Errorf("source store has no inventory")
+		}
+
+		transfer = &entities.StockTransfer{
+			WarehouseID:         sourceStore.WarehouseID,
+			SourceStoreID:       sourceStore.StoreID,
+			SourceSellerID:      sourceStore.SellerID,
+			DestinationStoreID:  destinationStore.StoreID,
+			DestinationSellerID: destinationStore.SellerID,
+			Status:              entities.StockTransferDispatched,
+			Note:                request.Note,
+			DispatchedBy:        request.OperationalID,
+			DispatchedAt:        time.Now(),
+		}
+
+		var afterQuantities []int
+		for _, item := range request.Items {
+			product, err := adjustInventoryProductQuantity(sc, r.db, item.InventoryProductID, -item.Quantity)
+			if err != nil {
+				return nil, err
+			}
+			if product.InventoryID != sourceInventory.InventoryID {
+				return nil, fmt.Errorf("inventory product %s does not belong to the source store", item.InventoryProductID)
+			}
+			transfer.Items = append(transfer.Items, &entities.StockTransferItem{
+				SourceInventoryProductID: item.InventoryProductID,
+				MetadataProductID:        product.MetadataProductID,
+				Quantity:                 item.Quantity,
+				ProductPrice:             product.ProductPrice,
+				ProductExpiryDate:        product.ProductExpiryDate,
+				ProductManufacturingDate: product.ProductManufacturingDate,
+			})
+			afterQuantities = append(afterQuantities, product.ProductQuantity)
+		}
+
+		result, err := r.db.Collection("stock_transfers").InsertOne(sc, transfer)
+		if err != nil {
+			return nil, err
+		}
+		insertedId, ok := result.InsertedID.(primitive.ObjectID)
+		if !ok {
+			return nil, fmt.Errorf("error in getting inserted transfer id")
+		}
+		transfer.ID = insertedId.Hex()
+
+		for i, item := range transfer.Items {
+			err := insertInventoryLedgerEntry(sc, r.db, &entities.InventoryLedgerEntry{
+				InventoryProductID: item.SourceInventoryProductID,
+				InventoryID:        sourceInventory.InventoryID,
+				StoreID:            transfer.SourceStoreID,
+				SellerID:           transfer.SourceSellerID,
+				MetadataProductID:  item.MetadataProductID,
+				Type:               entities.LedgerTypeTransferOut,
+				QuantityChange:     -item.Quantity,
+				QuantityAfter:      afterQuantities[i],
+				ReferenceID:        transfer.ID,
+				CreatedBy:          request.OperationalID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// loadDispatchedTransfer fetches a transfer that is still in transit and checks the user operates its warehouse
+func (r *StockTransferRepositoryMongoDB) loadDispatchedTransfer(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, primitive.ObjectID, error) {
+	objectId, err := primitive.ObjectIDFromHex(transferId)
+	if err != nil {
+		return nil, objectId, fmt.Errorf("invalid transfer id")
+	}
+	var transfer entities.StockTransfer
+	if err := r.db.Collection("stock_transfers").FindOne(ctx, bson.M{"_id": objectId}).Decode(&transfer); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, objectId, fmt.Errorf("transfer not found")
+		}
+		return nil, objectId, err
+	}
+	if transfer.Status != entities.StockTransferDispatched {
+		return nil, objectId, fmt.Errorf("transfer is already %s", transfer.Status)
+	}
+	if err := checkWarehouseOperator(ctx, r.db, transfer.WarehouseID, operationalId); err != nil {
+		return nil, objectId, err
+	}
+	return &transfer, objectId, nil
+}
+
+func (r *StockTransferRepositoryMongoDB) ReceiveStockTransfer(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, error) {
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var transfer *entities.StockTransfer
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var objectId primitive.ObjectID
+		var err error
+		transfer, objectId, err = r.loadDispatchedTransfer(sc, transferId, operationalId)
+		if err != nil {
+			return nil, err
+		}
+
+		destinationStore, err := getOperatedStore(sc, r.db, transfer.DestinationStoreID, operationalId)
+		if err != nil {
+			return nil, err
+		}
+		inventory, err := getOrCreateStoreInventory(sc, r.db, destinationStore)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range transfer.Items {
+			product, err := addToInventoryBatch(sc, r.db, &entities.InventoryProduct{
+				InventoryID:              inventory.InventoryID,
+				MetadataProductID:        item.MetadataProductID,
+				ProductPrice:             item.ProductPrice,
+				ProductExpiryDate:        item.ProductExpiryDate,
+				ProductManufacturingDate: item.ProductManufacturingDate,
+			}, item.Quantity, &entities.PriceHistoryEntry{
+				SellerID:    transfer.DestinationSellerID,
+				Source:      entities.PriceSourceTransfer,
+				ReferenceID: transfer.ID,
+				ChangedBy:   operationalId,
+			})
+			if err != nil {
+				return nil, err
+			}
+			item.DestinationInventoryProductID = product.InventoryProductID
+
+			err = insertInventoryLedgerEntry(sc, r.db, &entities.InventoryLedgerEntry{
+				InventoryProductID: product.InventoryProductID,
+				InventoryID:        inventory.InventoryID,
+				StoreID:            transfer.DestinationStoreID,
+				SellerID:           transfer.DestinationSellerID,
+				MetadataProductID:  item.MetadataProductID,
+				Type:               entities.LedgerTypeTransferIn,
+				QuantityChange:     item.Quantity,
+				QuantityAfter:      product.ProductQuantity,
+				ReferenceID:        transfer.ID,
+				CreatedBy:          operationalId,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		now := time.Now()
+		transfer.Status = entities.StockTransferReceived
+		transfer.ReceivedBy = operationalId
+		transfer.ReceivedAt = &now
+		result, err := r.db.Collection("stock_transfers").UpdateOne(sc,
+			bson.M{"_id": objectId, "status": entities.StockTransferDispatched},
+			bson.M{"$set": bson.M{"status": transfer.Status, "items": transfer.Items, "received_by": operationalId, "received_at": now}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, fmt.Errorf("transfer is no longer in transit")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (r *StockTransferRepositoryMongoDB) CancelStockTransfer(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, error) {
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var transfer *entities.StockTransfer
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var objectId primitive.ObjectID
+		var err error
+		transfer, objectId, err = r.loadDispatchedTransfer(sc, transferId, operationalId)
+		if err != nil {
+			return nil, err
+		}
+
+		// Dispatched stock goes back to the batches it was taken from
+		for _, item := range transfer.Items {
+			product, err := adjustInventoryProductQuantity(sc, r.db, item.SourceInventoryProductID, item.Quantity)
+			if err != nil {
+				return nil, err
+			}
+			err = insertInventoryLedgerEntry(sc, r.db, &entities.InventoryLedgerEntry{
+				InventoryProductID: item.SourceInventoryProductID,
+				InventoryID:        product.InventoryID,
+				StoreID:            transfer.SourceStoreID,
+				SellerID:           transfer.SourceSellerID,
+				MetadataProductID:  item.MetadataProductID,
+				Type:               entities.LedgerTypeTransferReturn,
+				QuantityChange:     item.Quantity,
+				QuantityAfter:      product.ProductQuantity,
+				ReferenceID:        transfer.ID,
+				CreatedBy:          operationalId,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		now := time.Now()
+		transfer.Status = entities.StockTransferCancelled
+		transfer.CancelledBy = operationalId
+		transfer.CancelledAt = &now
+		result, err := r.db.Collection("stock_transfers").UpdateOne(sc,
+			bson.M{"_id": objectId, "status": entities.StockTransferDispatched},
+			bson.M{"$set": bson.M{"status": transfer.Status, "cancelled_by": operationalId, "cancelled_at": now}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, fmt.Errorf("transfer is no longer in transit")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// GetStockTransferById returns a transfer, limited to the warehouse of the operations user when operationalId is set
+func (r *StockTransferRepositoryMongoDB) GetStockTransferById(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, error) {
+	objectId, err := primitive.ObjectIDFromHex(transferId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer id")
+	}
+	var transfer entities.StockTransfer
+	if err := r.db.Collection("stock_transfers").FindOne(ctx, bson.M{"_id": objectId}).Decode(&transfer); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("transfer not found")
+		}
+		return nil, err
+	}
+	if operationalId != "" {
+		if err := checkWarehouseOperator(ctx, r.db, transfer.WarehouseID, operationalId); err != nil {
+			return nil, err
+		}
+	}
+	return &transfer, nil
+}
+
+func (r *StockTransferRepositoryMongoDB) GetStockTransfers(ctx context.Context, request *entities.GetStockTransfersRequest) ([]*entities.StockTransfer, int64, error) {
+	collection := r.db.Collection("stock_transfers")
+
+	filter := bson.M{}
+	if request.OperationalID != "" {
+		var warehouses []*entities.Warehouse
+		cursor, err := r.db.Collection("warehouses").Find(ctx, bson.M{"warehouse_operational_guy_id": request.OperationalID})
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := cursor.All(ctx, &warehouses); err != nil {
+			return nil, 0, err
+		}
+		warehouseIds := bson.A{}
+		for _, warehouse := range warehouses {
+			warehouseIds = append(warehouseIds, warehouse.ID)
+		}
+		filter["warehouse_id"] = bson.M{"$in": warehouseIds}
+	}
+	var conditions bson.A
+	if request.SellerID != "" {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"source_seller_id": request.SellerID},
+			bson.M{"destination_seller_id": request.SellerID},
+		}})
+	}
+	if request.StoreID != "" {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"source_store_id": request.StoreID},
+			bson.M{"destination_store_id": request.StoreID},
+		}})
+	}
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+	if request.Status != "" {
+		filter["status"] = request.Status
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"dispatched_at": -1}).SetSkip(request.Offset * request.Limit).SetLimit(request.Limit)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	transfers := []*entities.StockTransfer{}
+	if err := cursor.All(ctx, &transfers); err != nil {
+		return nil, 0, err
+	}
+	return transfers, total, nil
+}
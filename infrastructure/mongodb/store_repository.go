@@ -26,7 +26,7 @@ func (r *StoreRepositoryMongoDB) GetAllStores(ctx context.Context, request entit
 	collection := r.db.Collection("stores")
 
 	// Build filter
-	filter := bson.M{"warehouse_id": request.WarehouseID}
+	filter := withoutArchived(bson.M{"warehouse_id": request.WarehouseID})
 	if request.Search != "" {
 		filter["store_name"] = bson.M{"$regex": request.Search, "$options": "i"}
 	}
@@ -59,6 +59,9 @@ func (r *StoreRepositoryMongoDB) GetAllStores(ctx context.Context, request entit
 	if err := cursor.All(ctx, &stores); err != nil {
 		return nil, err
 	}
+	if err := withStoreValueRackCounts(ctx, r.db, stores); err != nil {
+		return nil, err
+	}
 
 	// Calculate pagination info
 	hasNext := request.Offset+limit < total
@@ -78,7 +81,7 @@ func (r *StoreRepositoryMongoDB) GetAllStoresForCustomer(ctx context.Context, wa
 	collection := r.db.Collection("stores")
 
 	// Build filter
-	filter := bson.M{"warehouse_id": warehouseId}
+	filter := withoutArchived(bson.M{"warehouse_id": warehouseId})
 	if search != "" {
 		filter["store_name"] = bson.M{"$regex": search, "$options": "i"}
 	}
@@ -93,6 +96,9 @@ func (r *StoreRepositoryMongoDB) GetAllStoresForCustomer(ctx context.Context, wa
 	if err := cursor.All(ctx, &stores); err != nil {
 		return nil, err
 	}
+	if err := withRackCounts(ctx, r.db, stores); err != nil {
+		return nil, err
+	}
 	if len(stores) != 0 {
 		now := time.Now()
 		allStoreOption := &entities.Store{
@@ -126,6 +132,9 @@ func (r *StoreRepositoryMongoDB) GetStoreById(ctx context.Context, storeId strin
 		}
 		return nil, err
 	}
+	if err := withRackCounts(ctx, r.db, []*entities.Store{&store}); err != nil {
+		return nil, err
+	}
 
 	return &store, nil
 }
@@ -135,15 +144,13 @@ func (r *StoreRepositoryMongoDB) CreateStore(ctx context.Context, request *entit
 
 	now := time.Now()
 	store := &entities.Store{
-		StoreName:     request.StoreName,
-		StoreAddress:  request.StoreAddress,
-		StoreContact:  request.StoreContact,
-		WarehouseID:   request.WarehouseID,
-		SellerID:      request.SellerID,
-		NumberOfRacks: request.NumberOfRacks,
-		OccupiedRacks: 0,
-		CreatedAt:     now,
-		UpdatedAt:     now,
+		StoreName:    request.StoreName,
+		StoreAddress: request.StoreAddress,
+		StoreContact: request.StoreContact,
+		WarehouseID:  request.WarehouseID,
+		SellerID:     request.SellerID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 
 	response, err := collection.InsertOne(ctx, store)
@@ -178,12 +185,10 @@ func (r *StoreRepositoryMongoDB) UpdateStore(ctx context.Context, storeId string
 	filter := bson.M{"store_id": storeId}
 	update := bson.M{
 		"$set": bson.M{
-			"store_name":      store.StoreName,
-			"store_address":   store.StoreAddress,
-			"store_contact":   store.StoreContact,
-			"number_of_racks": store.NumberOfRacks,
-			"occupied_racks":  store.OccupiedRacks,
-			"updated_at":      store.UpdatedAt,
+			"store_name":    store.StoreName,
+			"store_address": store.StoreAddress,
+			"store_contact": store.StoreContact,
+			"updated_at":    store.UpdatedAt,
 		},
 	}
 
@@ -199,25 +204,14 @@ func (r *StoreRepositoryMongoDB) UpdateStore(ctx context.Context, storeId string
 	return nil
 }
 
-func (r *StoreRepositoryMongoDB) DeleteStore(ctx context.Context, storeId string) error {
-	collection := r.db.Collection("stores")
-	filter := bson.M{"store_id": storeId}
-
-	result, err := collection.DeleteOne(ctx, filter)
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
-
-	return nil
+// DeleteStore archives a store, along with its empty listings on cascade
+func (r *StoreRepositoryMongoDB) DeleteStore(ctx context.Context, storeId string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
+	return archiveRecord(ctx, r.db, entities.ArchiveKindStore, storeId, request)
 }
 
 func (r *StoreRepositoryMongoDB) GetStoreBySellerId(ctx context.Context, sellerId string) (*entities.Store, error) {
 	collection := r.db.Collection("stores")
-	filter := bson.M{"seller_id": sellerId}
+	filter := withoutArchived(bson.M{"seller_id": sellerId})
 
 	var store entities.Store
 	err := collection.FindOne(ctx, filter).Decode(&store)
@@ -227,13 +221,16 @@ func (r *StoreRepositoryMongoDB) GetStoreBySellerId(ctx context.Context, sellerI
 		}
 		return nil, err
 	}
+	if err := withRackCounts(ctx, r.db, []*entities.Store{&store}); err != nil {
+		return nil, err
+	}
 
 	return &store, nil
 }
 
 func (r *StoreRepositoryMongoDB) GetStoresByWarehouseId(ctx context.Context, warehouseId string) ([]entities.Store, error) {
 	collection := r.db.Collection("stores")
-	filter := bson.M{"warehouse_id": warehouseId}
+	filter := withoutArchived(bson.M{"warehouse_id": warehouseId})
 
 	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
@@ -245,29 +242,18 @@ func (r *StoreRepositoryMongoDB) GetStoresByWarehouseId(ctx context.Context, war
 	if err := cursor.All(ctx, &stores); err != nil {
 		return nil, err
 	}
+	if err := withStoreValueRackCounts(ctx, r.db, stores); err != nil {
+		return nil, err
+	}
 
 	return stores, nil
 }
 
-func (r *StoreRepositoryMongoDB) UpdateStoreRacks(ctx context.Context, storeId string, occupiedRacks int) error {
-	collection := r.db.Collection("stores")
-
-	filter := bson.M{"store_id": storeId}
-	update := bson.M{
-		"$set": bson.M{
-			"occupied_racks": occupiedRacks,
-			"updated_at":     time.Now(),
-		},
+// withStoreValueRackCounts fills in the rack counts of a slice of stores held by value
+func withStoreValueRackCounts(ctx context.Context, db *mongo.Database, stores []entities.Store) error {
+	pointers := make([]*entities.Store, 0, len(stores))
+	for i := range stores {
+		pointers = append(pointers, &stores[i])
 	}
-
-	result, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
-	}
-
-	if result.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
-
-	return nil
+	return withRackCounts(ctx, db, pointers)
 }
@@ -0,0 +1,152 @@
+package mongodb
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type VariantRepositoryMongoDB struct {
+	db *mongo.Database
+}
+
+func NewVariantRepositoryMongoDB(db *mongo.Database) repositories.VariantRepository {
+	return &VariantRepositoryMongoDB{db: db}
+}
+
+func (r *VariantRepositoryMongoDB) CreateVariantGroup(ctx context.Context, group *entities.VariantGroup) (*entities.VariantGroup, error) {
+	result, err := r.db.Collection("variant_groups").InsertOne(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	insertedId, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("error in getting inserted variant group id")
+	}
+	group.ID = insertedId.Hex()
+	return group, nil
+}
+
+func (r *VariantRepositoryMongoDB) UpdateVariantGroup(ctx context.Context, request *entities.UpdateVariantGroupRequest) (*entities.VariantGroup, error) {
+	objectId, err := primitive.ObjectIDFromHex(request.VariantGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variant group id")
+	}
+	var group entities.VariantGroup
+	err = r.db.Collection("variant_groups").FindOneAndUpdate(ctx,
+		bson.M{"_id": objectId},
+		bson.M{"$set": bson.M{"name": request.Name, "axes": request.Axes, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&group)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("variant group not found")
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *VariantRepositoryMongoDB) DeleteVariantGroup(ctx context.Context, groupId string) error {
+	objectId, err := primitive.ObjectIDFromHex(groupId)
+	if err != nil {
+		return fmt.Errorf("invalid variant group id")
+	}
+	result, err := r.db.Collection("variant_groups").DeleteOne(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("variant group not found")
+	}
+	return nil
+}
+
+func (r *VariantRepositoryMongoDB) GetVariantGroups(ctx context.Context, subcategoryId string) ([]*entities.VariantGroup, error) {
+	filter := bson.M{}
+	if subcategoryId != "" {
+		filter["subcategory_id"] = subcategoryId
+	}
+	cursor, err := r.db.Collection("variant_groups").Find(ctx, filter, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	groups := []*entities.VariantGroup{}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *VariantRepositoryMongoDB) GetVariantGroupById(ctx context.Context, groupId string) (*entities.VariantGroupDetail, error) {
+	objectId, err := primitive.ObjectIDFromHex(groupId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variant group id")
+	}
+	var detail entities.VariantGroupDetail
+	if err := r.db.Collection("variant_groups").FindOne(ctx, bson.M{"_id": objectId}).Decode(&detail.VariantGroup); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("variant group not found")
+		}
+		return nil, err
+	}
+
+	cursor, err := r.db.Collection("metadata").Find(ctx, withoutArchived(bson.M{"variant_group_id": groupId}), options.Find().SetSort(bson.M{"metadata_mrp": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	detail.Variants = []*entities.Metadata{}
+	if err := cursor.All(ctx, &detail.Variants); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+func (r *VariantRepositoryMongoDB) GetMetadataById(ctx context.Context, metadataId string) (*entities.Metadata, error) {
+	objectId, err := primitive.ObjectIDFromHex(metadataId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata id")
+	}
+	var metadata entities.Metadata
+	if err := r.db.Collection("metadata").FindOne(ctx, bson.M{"_id": objectId}).Decode(&metadata); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("metadata not found")
+		}
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// SetMetadataVariant puts a metadata product in a variant group, or takes it out when groupId is empty
+func (r *VariantRepositoryMongoDB) SetMetadataVariant(ctx context.Context, metadataId, groupId string, attributes map[string]string) error {
+	objectId, err := primitive.ObjectIDFromHex(metadataId)
+	if err != nil {
+		return fmt.Errorf("invalid metadata id")
+	}
+	update := bson.M{
+		"$unset": bson.M{"variant_group_id": "", "variant_attributes": ""},
+		"$set":   bson.M{"metadata_updated_at": time.Now()},
+	}
+	if groupId != "" {
+		update = bson.M{"$set": bson.M{"variant_group_id": groupId, "variant_attributes": attributes, "metadata_updated_at": time.Now()}}
+	}
+	result, err := r.db.Collection("metadata").UpdateOne(ctx, bson.M{"_id": objectId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("metadata not found")
+	}
+	return nil
+}
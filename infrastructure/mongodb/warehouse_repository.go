@@ -24,7 +24,7 @@ func NewWarehouseRepositoryMongoDB(db *mongo.Database) repositories.WarehouseRep
 func (r *WarehouseRepositoryMongoDB) GetAllWarehouses(ctx context.Context) (*entities.MessageResponse, error) {
 	collection := r.db.Collection("warehouses")
 
-	cursor, err := collection.Find(ctx, bson.M{})
+	cursor, err := collection.Find(ctx, withoutArchived(bson.M{}))
 	if err != nil {
 		return &entities.MessageResponse{
 			Success: false,
@@ -153,15 +153,7 @@ func (r *WarehouseRepositoryMongoDB) UpdateWarehouse(ctx context.Context, id str
 	}, nil
 }
 
-func (r *WarehouseRepositoryMongoDB) DeleteWarehouse(ctx context.Context, id string) error {
-	collection := r.db.Collection("warehouses")
-
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	filter := bson.M{"_id": objectID}
-	_, err = collection.DeleteOne(ctx, filter)
-	return err
+// DeleteWarehouse archives a warehouse, along with its stores on cascade
+func (r *WarehouseRepositoryMongoDB) DeleteWarehouse(ctx context.Context, id string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
+	return archiveRecord(ctx, r.db, entities.ArchiveKindWarehouse, id, request)
 }
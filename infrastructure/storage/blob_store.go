@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"espazeBackend/domain/repositories"
+	"log"
+	"os"
+)
+
+// NewBlobStoreFromEnv picks the blob store from MEDIA_STORE. "s3" uses S3_ENDPOINT, S3_BUCKET, S3_REGION,
+// S3_ACCESS_KEY and S3_SECRET_KEY; anything else stores files under MEDIA_DIR, ./uploads by default.
+func NewBlobStoreFromEnv() repositories.BlobStore {
+	if os.Getenv("MEDIA_STORE") == "s3" {
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		if os.Getenv("S3_ENDPOINT") == "" || os.Getenv("S3_BUCKET") == "" {
+			log.Fatal("❌ S3_ENDPOINT and S3_BUCKET must be set when MEDIA_STORE is s3")
+		}
+		return NewS3BlobStore(os.Getenv("S3_ENDPOINT"), os.Getenv("S3_BUCKET"), region, os.Getenv("S3_ACCESS_KEY"), os.Getenv("S3_SECRET_KEY"))
+	}
+	dir := os.Getenv("MEDIA_DIR")
+	if dir == "" {
+		dir = "uploads"
+	}
+	return NewLocalBlobStore(dir)
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	db "espazeBackend/config"
 	routes "espazeBackend/routes"
@@ -56,5 +62,23 @@ func main() {
 	// Setup routes
 	routes.SetupRoutes(router)
 
-	router.Run(":" + port)
+	// ⏰ Background jobs stop with the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go routes.RunInventorySnapshots(ctx)
+	go routes.RunMetadataImportRecovery(ctx)
+
+	server := &http.Server{Addr: ":" + port, Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("❌ Server error: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("❌ Server shutdown error: %v", err)
+	}
 }
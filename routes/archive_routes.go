@@ -0,0 +1,25 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupArchiveRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var archiveRepo repositories.ArchiveRepository = mongodb.NewArchiveRepositoryMongoDB(database)
+
+	var archiveUseCase *usecase.ArchiveUseCase = usecase.NewArchiveUseCase(archiveRepo)
+
+	var archiveHandler *handlers.ArchiveHandler = handlers.NewArchiveHandler(archiveUseCase)
+
+	router.GET("/getDependencies/:kind/:id", archiveHandler.GetDependencies)
+	router.GET("/getArchived/:kind", archiveHandler.GetArchivedRecords)
+	router.POST("/restore/:kind/:id", archiveHandler.RestoreRecord)
+}
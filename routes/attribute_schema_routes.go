@@ -0,0 +1,25 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAttributeSchemaRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var attributeSchemaRepo repositories.AttributeSchemaRepository = mongodb.NewAttributeSchemaRepositoryMongoDB(database)
+
+	var attributeSchemaUseCase *usecase.AttributeSchemaUseCase = usecase.NewAttributeSchemaUseCase(attributeSchemaRepo)
+
+	var attributeSchemaHandler *handlers.AttributeSchemaHandler = handlers.NewAttributeSchemaHandler(attributeSchemaUseCase)
+
+	router.PUT("/saveSchema/:subcategoryId", attributeSchemaHandler.SaveAttributeSchema)
+	router.GET("/getSchema/:subcategoryId", attributeSchemaHandler.GetAttributeSchema)
+	router.DELETE("/deleteSchema/:subcategoryId", attributeSchemaHandler.DeleteAttributeSchema)
+}
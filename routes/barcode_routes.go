@@ -0,0 +1,26 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBarcodeRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var barcodeRepo repositories.BarcodeRepository = mongodb.NewBarcodeRepositoryMongoDB(database)
+
+	var barcodeUseCase *usecase.BarcodeUseCase = usecase.NewBarcodeUseCase(barcodeRepo)
+
+	var barcodeHandler *handlers.BarcodeHandler = handlers.NewBarcodeHandler(barcodeUseCase)
+
+	router.POST("/addBarcodes", barcodeHandler.AddBarcodes)
+	router.DELETE("/removeBarcode/:code", barcodeHandler.RemoveBarcode)
+	router.GET("/getBarcodes/:metadataId", barcodeHandler.GetBarcodesForMetadata)
+	router.GET("/lookup/:code", barcodeHandler.LookupBarcode)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCatalogProposalRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var catalogProposalRepo repositories.CatalogProposalRepository = mongodb.NewCatalogProposalRepositoryMongoDB(database)
+
+	var catalogProposalUseCase *usecase.CatalogProposalUseCase = usecase.NewCatalogProposalUseCase(catalogProposalRepo)
+
+	var catalogProposalHandler *handlers.CatalogProposalHandler = handlers.NewCatalogProposalHandler(catalogProposalUseCase)
+
+	router.POST("/submitProposal", catalogProposalHandler.SubmitProposal)
+	router.GET("/getMyProposals", catalogProposalHandler.GetMyProposals)
+	router.GET("/getProposalById/:id", catalogProposalHandler.GetProposalById)
+	router.DELETE("/withdrawProposal/:id", catalogProposalHandler.WithdrawProposal)
+	router.GET("/getProposalQueue", catalogProposalHandler.GetProposalQueue)
+	router.PUT("/reviewProposal/:id", catalogProposalHandler.ReviewProposal)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCycleCountRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var cycleCountRepo repositories.CycleCountRepository = mongodb.NewCycleCountRepositoryMongoDB(database)
+
+	var cycleCountUseCase *usecase.CycleCountUseCase = usecase.NewCycleCountUseCase(cycleCountRepo)
+
+	var cycleCountHandler *handlers.CycleCountHandler = handlers.NewCycleCountHandler(cycleCountUseCase)
+
+	router.POST("/createSession", cycleCountHandler.CreateCycleCountSession)
+	router.GET("/getSessions", cycleCountHandler.GetCycleCountSessions)
+	router.GET("/getSession/:id", cycleCountHandler.GetCycleCountSession)
+	router.POST("/submitCounts/:id", cycleCountHandler.SubmitCycleCounts)
+	router.PUT("/submitForReview/:id", cycleCountHandler.SubmitCycleCountForReview)
+	router.PUT("/reopenSession/:id", cycleCountHandler.ReopenCycleCountSession)
+	router.PUT("/cancelSession/:id", cycleCountHandler.CancelCycleCountSession)
+	router.POST("/approveSession/:id", cycleCountHandler.ApproveCycleCountSession)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupHSNRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var hsnRepo repositories.HSNRepository = mongodb.NewHSNRepositoryMongoDB(database)
+
+	var hsnUseCase *usecase.HSNUseCase = usecase.NewHSNUseCase(hsnRepo)
+
+	var hsnHandler *handlers.HSNHandler = handlers.NewHSNHandler(hsnUseCase)
+
+	router.GET("/getHSNCodes", hsnHandler.GetHSNCodes)
+	router.GET("/getHSNCode/:code", hsnHandler.GetHSNCode)
+	router.PUT("/saveHSNCode", hsnHandler.SaveHSNCode)
+	router.POST("/importHSNCodes", hsnHandler.ImportHSNCodes)
+	router.DELETE("/deleteHSNCode/:code", hsnHandler.DeleteHSNCode)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupInboundShipmentRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var inboundShipmentRepo repositories.InboundShipmentRepository = mongodb.NewInboundShipmentRepositoryMongoDB(database)
+
+	var inboundShipmentUseCase *usecase.InboundShipmentUseCase = usecase.NewInboundShipmentUseCase(inboundShipmentRepo)
+
+	var inboundShipmentHandler *handlers.InboundShipmentHandler = handlers.NewInboundShipmentHandler(inboundShipmentUseCase)
+
+	router.POST("/createShipment", inboundShipmentHandler.CreateInboundShipment)
+	router.PUT("/receiveShipment/:id", inboundShipmentHandler.ReceiveInboundShipment)
+	router.PUT("/cancelShipment/:id", inboundShipmentHandler.CancelInboundShipment)
+	router.GET("/getShipments", inboundShipmentHandler.GetInboundShipments)
+	router.GET("/getShipmentById/:id", inboundShipmentHandler.GetInboundShipmentById)
+	router.GET("/downloadGRN/:id", inboundShipmentHandler.DownloadGoodsReceivedNote)
+}
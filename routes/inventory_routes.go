@@ -23,10 +23,14 @@ func SetupInventoryRoutes(router *gin.RouterGroup) {
 	router.GET("/getAllInventory", inventoryHandler.GetAllInventory)
 	router.POST("/addInventory", inventoryHandler.AddInventory)
 	router.PUT("/updateInventory", inventoryHandler.UpdateInventory)
+	router.PUT("/bulkUpdateInventory", inventoryHandler.BulkUpdateInventory)
 	router.DELETE("/deleteInventory", inventoryHandler.DeleteInventory)
 	router.GET("/getInventoryById", inventoryHandler.GetInventoryById)
 	router.POST("/addInventoryByExcel", inventoryHandler.AddInventoryByExcel)
+	router.GET("/exportInventory", inventoryHandler.ExportInventory)
 	router.GET("/getAllInventoryRequests", inventoryHandler.GetAllInventoryRequests)
-	router.GET("/acceptProduct", inventoryHandler.AcceptVisibility)
+	router.POST("/reviewProduct", inventoryHandler.ReviewInventoryProduct)
+	router.GET("/getReviewHistory", inventoryHandler.GetInventoryReviewHistory)
+	router.GET("/getInventoryLedger", inventoryHandler.GetInventoryLedger)
 
 }
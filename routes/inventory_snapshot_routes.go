@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"context"
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupInventorySnapshotRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var inventorySnapshotRepo repositories.InventorySnapshotRepository = mongodb.NewInventorySnapshotRepositoryMongoDB(database)
+
+	var inventorySnapshotUseCase *usecase.InventorySnapshotUseCase = usecase.NewInventorySnapshotUseCase(inventorySnapshotRepo)
+
+	var inventorySnapshotHandler *handlers.InventorySnapshotHandler = handlers.NewInventorySnapshotHandler(inventorySnapshotUseCase)
+
+	router.POST("/takeSnapshot", inventorySnapshotHandler.TakeInventorySnapshot)
+	router.GET("/getSnapshots", inventorySnapshotHandler.GetInventorySnapshots)
+	router.GET("/getValuation", inventorySnapshotHandler.GetInventoryValuation)
+	router.GET("/exportValuation", inventorySnapshotHandler.ExportInventoryValuation)
+}
+
+// RunInventorySnapshots snapshots the closing stock every night for the valuation reports, until ctx is cancelled
+func RunInventorySnapshots(ctx context.Context) {
+	var inventorySnapshotRepo repositories.InventorySnapshotRepository = mongodb.NewInventorySnapshotRepositoryMongoDB(db.GetDatabase())
+	usecase.NewInventorySnapshotUseCase(inventorySnapshotRepo).RunDailySnapshots(ctx)
+}
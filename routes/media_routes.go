@@ -0,0 +1,33 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/infrastructure/storage"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMediaRoutes registers image management on router and image serving on files, which needs no
+// authentication so images can be linked directly
+func SetupMediaRoutes(router *gin.RouterGroup, files *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var mediaRepo repositories.MediaRepository = mongodb.NewMediaRepositoryMongoDB(database)
+
+	var blobStore repositories.BlobStore = storage.NewBlobStoreFromEnv()
+
+	var mediaUseCase *usecase.MediaUseCase = usecase.NewMediaUseCase(mediaRepo, blobStore)
+
+	var mediaHandler *handlers.MediaHandler = handlers.NewMediaHandler(mediaUseCase)
+
+	router.POST("/upload", mediaHandler.UploadMedia)
+	router.GET("/getGallery", mediaHandler.GetGallery)
+	router.PUT("/setPrimary/:id", mediaHandler.SetPrimaryMedia)
+	router.DELETE("/deleteImage/:id", mediaHandler.DeleteMedia)
+
+	files.GET("/:id", mediaHandler.GetMediaFile)
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"context"
 	db "espazeBackend/config"
 	"espazeBackend/domain/repositories"
 	"espazeBackend/handlers"
@@ -20,6 +21,18 @@ func SetupMetadataRoutes(router *gin.RouterGroup) {
 
 	var metadataHandler *handlers.MetadataHandler = handlers.NewMetadataHandler(metadataUseCase)
 
+	var metadataImportRepo repositories.MetadataImportRepository = mongodb.NewMetadataImportRepositoryMongoDB(database)
+
+	var metadataImportUseCase *usecase.MetadataImportUseCase = usecase.NewMetadataImportUseCase(metadataImportRepo, metadataRepo)
+
+	var metadataImportHandler *handlers.MetadataImportHandler = handlers.NewMetadataImportHandler(metadataImportUseCase)
+
+	var metadataMergeRepo repositories.MetadataMergeRepository = mongodb.NewMetadataMergeRepositoryMongoDB(database)
+
+	var metadataMergeUseCase *usecase.MetadataMergeUseCase = usecase.NewMetadataMergeUseCase(metadataMergeRepo)
+
+	var metadataMergeHandler *handlers.MetadataMergeHandler = handlers.NewMetadataMergeHandler(metadataMergeUseCase)
+
 	router.GET("/getMetadata", metadataHandler.GetMetadata)
 	router.GET("/getMetadata/:id", metadataHandler.GetMetadataByID)
 
@@ -35,4 +48,26 @@ func SetupMetadataRoutes(router *gin.RouterGroup) {
 
 	router.GET("/getMetadataForSubcategories", metadataHandler.GetMetadataForSubcategories)
 
+	router.POST("/importMetadata", metadataImportHandler.ImportMetadata)
+	router.GET("/getImportJobs", metadataImportHandler.GetImportJobs)
+	router.GET("/getImportJob/:id", metadataImportHandler.GetImportJob)
+	router.GET("/downloadImportErrors/:id", metadataImportHandler.ExportImportErrors)
+
+	router.GET("/getDuplicates", metadataMergeHandler.GetDuplicates)
+	router.POST("/dismissDuplicate", metadataMergeHandler.DismissDuplicate)
+	router.POST("/mergeMetadata", metadataMergeHandler.MergeMetadata)
+	router.GET("/getMerges", metadataMergeHandler.GetMerges)
+
+	router.GET("/getRevisions/:id", metadataHandler.GetMetadataRevisions)
+	router.GET("/getRevision/:id/:revision", metadataHandler.GetMetadataRevision)
+	router.POST("/rollbackMetadata/:id", metadataHandler.RollbackMetadata)
+
+}
+
+// RunMetadataImportRecovery fails the import jobs a previous server left running, until ctx is cancelled
+func RunMetadataImportRecovery(ctx context.Context) {
+	database := db.GetDatabase()
+	var metadataImportRepo repositories.MetadataImportRepository = mongodb.NewMetadataImportRepositoryMongoDB(database)
+	var metadataRepo repositories.MetadataRepository = mongodb.NewMetadataRepositoryMongoDB(database)
+	usecase.NewMetadataImportUseCase(metadataImportRepo, metadataRepo).FailStaleImports(ctx)
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNotificationRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var notificationRepo repositories.NotificationRepository = mongodb.NewNotificationRepositoryMongoDB(database)
+
+	var notificationUseCase *usecase.NotificationUseCase = usecase.NewNotificationUseCase(notificationRepo)
+
+	var notificationHandler *handlers.NotificationHandler = handlers.NewNotificationHandler(notificationUseCase)
+
+	router.GET("/getNotifications", notificationHandler.GetNotifications)
+	router.PUT("/markNotificationRead/:id", notificationHandler.MarkNotificationRead)
+	router.PUT("/markAllNotificationsRead", notificationHandler.MarkAllNotificationsRead)
+}
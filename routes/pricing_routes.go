@@ -0,0 +1,31 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPricingRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var pricingRepo repositories.PricingRepository = mongodb.NewPricingRepositoryMongoDB(database)
+
+	var pricingUseCase *usecase.PricingUseCase = usecase.NewPricingUseCase(pricingRepo)
+
+	var pricingHandler *handlers.PricingHandler = handlers.NewPricingHandler(pricingUseCase)
+
+	router.GET("/getGuardrails", pricingHandler.GetPricingGuardrails)
+	router.PUT("/upsertGuardrail", pricingHandler.UpsertPricingGuardrail)
+	router.DELETE("/deleteGuardrail", pricingHandler.DeletePricingGuardrail)
+	router.GET("/getPriceHistory", pricingHandler.GetPriceHistory)
+	router.GET("/getPriceChangeRequests", pricingHandler.GetPriceChangeRequests)
+	router.PUT("/reviewPriceChangeRequest/:id", pricingHandler.ReviewPriceChangeRequest)
+	router.POST("/createScheduledPrice", pricingHandler.CreateScheduledPrice)
+	router.GET("/getScheduledPrices", pricingHandler.GetScheduledPrices)
+	router.PUT("/cancelScheduledPrice/:id", pricingHandler.CancelScheduledPrice)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupProductReviewRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var productReviewRepo repositories.ProductReviewRepository = mongodb.NewProductReviewRepositoryMongoDB(database)
+
+	var productReviewUseCase *usecase.ProductReviewUseCase = usecase.NewProductReviewUseCase(productReviewRepo)
+
+	var productReviewHandler *handlers.ProductReviewHandler = handlers.NewProductReviewHandler(productReviewUseCase)
+
+	router.POST("/submitReview", productReviewHandler.SubmitReview)
+	router.GET("/getReviews/:metadataId", productReviewHandler.GetProductReviews)
+	router.GET("/getMyReviews", productReviewHandler.GetMyReviews)
+	router.DELETE("/deleteReview/:id", productReviewHandler.DeleteReview)
+	router.GET("/getModerationQueue", productReviewHandler.GetModerationQueue)
+	router.PUT("/moderateReview/:id", productReviewHandler.ModerateReview)
+}
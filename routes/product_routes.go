@@ -29,6 +29,8 @@ func SetupProductRoutes(router *gin.RouterGroup) {
 	router.GET("/getProductsForSpecificStore", productHandler.GetProductsForSpecificStore)
 	router.GET("/getProductsForAllStores", productHandler.GetProductsForAllStores)
 	router.GET("/getAllProductsForSubcategory", productHandler.GetAllProductsForSubcategory)
+	router.GET("/searchProducts", productHandler.SearchProducts)
+	router.GET("/autocomplete", productHandler.Autocomplete)
 	router.GET("/getBasicDetailsForProduct", productHandler.GetBasicDetailsForProduct)
 	router.GET("/getProductComparisonByStores", productHandler.GetProductComparisonByStore)
 }
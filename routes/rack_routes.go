@@ -0,0 +1,29 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRackRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var rackRepo repositories.RackRepository = mongodb.NewRackRepositoryMongoDB(database)
+
+	var rackUseCase *usecase.RackUseCase = usecase.NewRackUseCase(rackRepo)
+
+	var rackHandler *handlers.RackHandler = handlers.NewRackHandler(rackUseCase)
+
+	router.POST("/createRack", rackHandler.CreateRack)
+	router.PUT("/updateRack/:id", rackHandler.UpdateRack)
+	router.DELETE("/deleteRack/:id", rackHandler.DeleteRack)
+	router.GET("/getRacks", rackHandler.GetRacks)
+	router.GET("/getRackById/:id", rackHandler.GetRackById)
+	router.PUT("/assignProduct", rackHandler.AssignRackBin)
+	router.GET("/findProduct", rackHandler.FindProductLocations)
+}
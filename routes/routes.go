@@ -72,5 +72,80 @@ func SetupRoutes(router *gin.Engine) {
 		{
 			SetupOnboardingRoutes(onboarding)
 		}
+
+		notification := protected.Group("/notification")
+		{
+			SetupNotificationRoutes(notification)
+		}
+
+		transfer := protected.Group("/transfer")
+		{
+			SetupStockTransferRoutes(transfer)
+		}
+
+		cycleCount := protected.Group("/cycleCount")
+		{
+			SetupCycleCountRoutes(cycleCount)
+		}
+
+		pricing := protected.Group("/pricing")
+		{
+			SetupPricingRoutes(pricing)
+		}
+
+		valuation := protected.Group("/valuation")
+		{
+			SetupInventorySnapshotRoutes(valuation)
+		}
+
+		inbound := protected.Group("/inbound")
+		{
+			SetupInboundShipmentRoutes(inbound)
+		}
+
+		rack := protected.Group("/rack")
+		{
+			SetupRackRoutes(rack)
+		}
+
+		variant := protected.Group("/variant")
+		{
+			SetupVariantRoutes(variant)
+		}
+
+		media := protected.Group("/media")
+		{
+			SetupMediaRoutes(media, router.Group("/media/file"))
+		}
+
+		attributes := protected.Group("/attributes")
+		{
+			SetupAttributeSchemaRoutes(attributes)
+		}
+
+		review := protected.Group("/review")
+		{
+			SetupProductReviewRoutes(review)
+		}
+
+		proposal := protected.Group("/proposal")
+		{
+			SetupCatalogProposalRoutes(proposal)
+		}
+
+		hsn := protected.Group("/hsn")
+		{
+			SetupHSNRoutes(hsn)
+		}
+
+		barcode := protected.Group("/barcode")
+		{
+			SetupBarcodeRoutes(barcode)
+		}
+
+		archive := protected.Group("/archive")
+		{
+			SetupArchiveRoutes(archive)
+		}
 	}
 }
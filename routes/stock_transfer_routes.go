@@ -0,0 +1,27 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupStockTransferRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var stockTransferRepo repositories.StockTransferRepository = mongodb.NewStockTransferRepositoryMongoDB(database)
+
+	var stockTransferUseCase *usecase.StockTransferUseCase = usecase.NewStockTransferUseCase(stockTransferRepo)
+
+	var stockTransferHandler *handlers.StockTransferHandler = handlers.NewStockTransferHandler(stockTransferUseCase)
+
+	router.POST("/createTransfer", stockTransferHandler.CreateStockTransfer)
+	router.PUT("/receiveTransfer/:id", stockTransferHandler.ReceiveStockTransfer)
+	router.PUT("/cancelTransfer/:id", stockTransferHandler.CancelStockTransfer)
+	router.GET("/getTransfers", stockTransferHandler.GetStockTransfers)
+	router.GET("/getTransferById/:id", stockTransferHandler.GetStockTransferById)
+}
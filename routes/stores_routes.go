@@ -26,5 +26,4 @@ func SetupStoreRoutes(router *gin.RouterGroup) {
 
 	// Additional store routes
 	router.GET("/seller/:seller_id", storeHandler.GetStoreBySellerId) // GET /stores/seller/:seller_id
-	router.PATCH("/:id/racks", storeHandler.UpdateStoreRacks)         // PATCH /stores/:id/racks
 }
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	db "espazeBackend/config"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/handlers"
+	"espazeBackend/infrastructure/mongodb"
+	"espazeBackend/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupVariantRoutes(router *gin.RouterGroup) {
+	database := db.GetDatabase()
+
+	var variantRepo repositories.VariantRepository = mongodb.NewVariantRepositoryMongoDB(database)
+
+	var variantUseCase *usecase.VariantUseCase = usecase.NewVariantUseCase(variantRepo)
+
+	var variantHandler *handlers.VariantHandler = handlers.NewVariantHandler(variantUseCase)
+
+	router.POST("/createVariantGroup", variantHandler.CreateVariantGroup)
+	router.PUT("/updateVariantGroup/:id", variantHandler.UpdateVariantGroup)
+	router.DELETE("/deleteVariantGroup/:id", variantHandler.DeleteVariantGroup)
+	router.GET("/getVariantGroups", variantHandler.GetVariantGroups)
+	router.GET("/getVariantGroupById/:id", variantHandler.GetVariantGroupById)
+	router.PUT("/addVariant", variantHandler.AddVariant)
+	router.PUT("/removeVariant/:metadataId", variantHandler.RemoveVariant)
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+)
+
+type ArchiveUseCase struct {
+	archiveRepo repositories.ArchiveRepository
+}
+
+func NewArchiveUseCase(archiveRepo repositories.ArchiveRepository) *ArchiveUseCase {
+	return &ArchiveUseCase{
+		archiveRepo: archiveRepo,
+	}
+}
+
+// GetDependencies reports what references a record without archiving it, as a dry run of its delete
+func (u *ArchiveUseCase) GetDependencies(ctx context.Context, kind, id string, cascade bool) (*entities.DependencyReport, error) {
+	return u.archiveRepo.GetDependencies(ctx, kind, id, cascade)
+}
+
+func (u *ArchiveUseCase) GetArchivedRecords(ctx context.Context, kind string, limit, offset int64) (*entities.PaginatedArchivedRecords, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	records, total, err := u.archiveRepo.GetArchivedRecords(ctx, kind, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PaginatedArchivedRecords{
+		Records:    records,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+func (u *ArchiveUseCase) RestoreRecord(ctx context.Context, kind, id string) (*entities.RestoreReport, error) {
+	return u.archiveRepo.RestoreRecord(ctx, kind, id)
+}
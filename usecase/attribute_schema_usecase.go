@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type AttributeSchemaUseCase struct {
+	attributeSchemaRepo repositories.AttributeSchemaRepository
+}
+
+func NewAttributeSchemaUseCase(attributeSchemaRepo repositories.AttributeSchemaRepository) *AttributeSchemaUseCase {
+	return &AttributeSchemaUseCase{
+		attributeSchemaRepo: attributeSchemaRepo,
+	}
+}
+
+func (u *AttributeSchemaUseCase) SaveAttributeSchema(ctx context.Context, request *entities.SaveAttributeSchemaRequest) (*entities.AttributeSchema, error) {
+	if request.SubcategoryID == "" {
+		return nil, errors.New("subcategory_id is required")
+	}
+	exists, err := u.attributeSchemaRepo.SubcategoryExists(ctx, request.SubcategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("subcategory not found")
+	}
+
+	seen := make(map[string]bool)
+	for _, attribute := range request.Attributes {
+		if attribute == nil {
+			return nil, errors.New("attribute cannot be empty")
+		}
+		attribute.Key = strings.ToLower(strings.TrimSpace(attribute.Key))
+		if !attributeKeyPattern.MatchString(attribute.Key) {
+			return nil, fmt.Errorf("invalid attribute key %q, use lowercase letters, digits and underscores", attribute.Key)
+		}
+		if seen[attribute.Key] {
+			return nil, fmt.Errorf("attribute %s is defined more than once", attribute.Key)
+		}
+		seen[attribute.Key] = true
+
+		attribute.Label = strings.TrimSpace(attribute.Label)
+		if attribute.Label == "" {
+			attribute.Label = attribute.Key
+		}
+		attribute.Unit = strings.TrimSpace(attribute.Unit)
+
+		switch attribute.Type {
+		case entities.AttributeTypeEnum:
+			options := make([]string, 0, len(attribute.Options))
+			seenOptions := make(map[string]bool)
+			for _, option := range attribute.Options {
+				option = strings.TrimSpace(option)
+				if option == "" || seenOptions[strings.ToLower(option)] {
+					continue
+				}
+				seenOptions[strings.ToLower(option)] = true
+				options = append(options, option)
+			}
+			if len(options) == 0 {
+				return nil, fmt.Errorf("enum attribute %s needs at least one option", attribute.Key)
+			}
+			attribute.Options = options
+			attribute.Min, attribute.Max = nil, nil
+		case entities.AttributeTypeNumber:
+			if attribute.Min != nil && attribute.Max != nil && *attribute.Min > *attribute.Max {
+				return nil, fmt.Errorf("min of attribute %s cannot be greater than max", attribute.Key)
+			}
+			attribute.Options = nil
+		case entities.AttributeTypeText, entities.AttributeTypeBoolean:
+			attribute.Options = nil
+			attribute.Min, attribute.Max = nil, nil
+		default:
+			return nil, fmt.Errorf("invalid type %q for attribute %s", attribute.Type, attribute.Key)
+		}
+	}
+	if request.Attributes == nil {
+		request.Attributes = []*entities.AttributeDefinition{}
+	}
+
+	return u.attributeSchemaRepo.SaveAttributeSchema(ctx, &entities.AttributeSchema{
+		SubcategoryID: request.SubcategoryID,
+		Attributes:    request.Attributes,
+		UpdatedBy:     request.OperationalID,
+	})
+}
+
+func (u *AttributeSchemaUseCase) GetAttributeSchema(ctx context.Context, subcategoryId string) (*entities.AttributeSchema, error) {
+	if subcategoryId == "" {
+		return nil, errors.New("subcategory_id is required")
+	}
+	return u.attributeSchemaRepo.GetAttributeSchema(ctx, subcategoryId)
+}
+
+func (u *AttributeSchemaUseCase) DeleteAttributeSchema(ctx context.Context, subcategoryId string) error {
+	if subcategoryId == "" {
+		return errors.New("subcategory_id is required")
+	}
+	return u.attributeSchemaRepo.DeleteAttributeSchema(ctx, subcategoryId)
+}
+
+// matchEnumOption returns the option of an enum attribute equal to value ignoring case
+func matchEnumOption(attribute *entities.AttributeDefinition, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, option := range attribute.Options {
+		if strings.EqualFold(option, value) {
+			return option, true
+		}
+	}
+	return "", false
+}
+
+// validateMetadataAttributes checks the attributes of a metadata against the schema of its subcategory and
+// returns them normalized, so numbers are stored as numbers and enums with the casing of their option
+func validateMetadataAttributes(schema *entities.AttributeSchema, attributes map[string]interface{}) (map[string]interface{}, error) {
+	definitions := make(map[string]*entities.AttributeDefinition, len(schema.Attributes))
+	for _, attribute := range schema.Attributes {
+		definitions[attribute.Key] = attribute
+	}
+
+	normalized := make(map[string]interface{}, len(attributes))
+	for key, value := range attributes {
+		attribute, ok := definitions[key]
+		if !ok {
+			return nil, fmt.Errorf("attribute %s is not defined for this subcategory", key)
+		}
+		if value == nil {
+			continue
+		}
+
+		switch attribute.Type {
+		case entities.AttributeTypeNumber:
+			number, ok := value.(float64)
+			if !ok {
+				return nil, fmt.Errorf("attribute %s must be a number", key)
+			}
+			if attribute.Min != nil && number < *attribute.Min {
+				return nil, fmt.Errorf("attribute %s cannot be less than %v", key, *attribute.Min)
+			}
+			if attribute.Max != nil && number > *attribute.Max {
+				return nil, fmt.Errorf("attribute %s cannot be greater than %v", key, *attribute.Max)
+			}
+			normalized[key] = number
+		case entities.AttributeTypeBoolean:
+			flag, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("attribute %s must be true or false", key)
+			}
+			normalized[key] = flag
+		case entities.AttributeTypeEnum:
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("attribute %s must be one of %s", key, strings.Join(attribute.Options, ", "))
+			}
+			option, ok := matchEnumOption(attribute, text)
+			if !ok {
+				return nil, fmt.Errorf("attribute %s must be one of %s", key, strings.Join(attribute.Options, ", "))
+			}
+			normalized[key] = option
+		default:
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("attribute %s must be text", key)
+			}
+			if text = strings.TrimSpace(text); text != "" {
+				normalized[key] = text
+			}
+		}
+	}
+
+	for _, attribute := range schema.Attributes {
+		if _, ok := normalized[attribute.Key]; attribute.Required && !ok {
+			return nil, fmt.Errorf("attribute %s is required", attribute.Key)
+		}
+	}
+	return normalized, nil
+}
+
+// parseAttributeFilters turns the attribute query of a listing, a comma separated list of values per key and
+// optional bounds for numbers, into filters typed by the schema of the subcategory
+func parseAttributeFilters(schema *entities.AttributeSchema, values map[string][]string, min, max map[string]string) ([]*entities.AttributeFilter, error) {
+	definitions := make(map[string]*entities.AttributeDefinition, len(schema.Attributes))
+	for _, attribute := range schema.Attributes {
+		definitions[attribute.Key] = attribute
+	}
+
+	filters := make(map[string]*entities.AttributeFilter)
+	filterFor := func(key string) (*entities.AttributeFilter, *entities.AttributeDefinition, error) {
+		attribute, ok := definitions[key]
+		if !ok {
+			return nil, nil, fmt.Errorf("attribute %s is not defined for this subcategory", key)
+		}
+		if filters[key] == nil {
+			filters[key] = &entities.AttributeFilter{Key: key}
+		}
+		return filters[key], attribute, nil
+	}
+
+	for key, raw := range values {
+		filter, attribute, err := filterFor(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range raw {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			switch attribute.Type {
+			case entities.AttributeTypeNumber:
+				number, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return nil, fmt.Errorf("attribute %s must be a number", key)
+				}
+				filter.Values = append(filter.Values, number)
+			case entities.AttributeTypeBoolean:
+				flag, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("attribute %s must be true or false", key)
+				}
+				filter.Values = append(filter.Values, flag)
+			case entities.AttributeTypeEnum:
+				option, ok := matchEnumOption(attribute, value)
+				if !ok {
+					return nil, fmt.Errorf("attribute %s must be one of %s", key, strings.Join(attribute.Options, ", "))
+				}
+				filter.Values = append(filter.Values, option)
+			default:
+				filter.Values = append(filter.Values, value)
+			}
+		}
+	}
+
+	bound := func(bounds map[string]string, isMin bool) error {
+		for key, value := range bounds {
+			filter, attribute, err := filterFor(key)
+			if err != nil {
+				return err
+			}
+			if attribute.Type != entities.AttributeTypeNumber {
+				return fmt.Errorf("attribute %s is not a number and cannot be filtered by range", key)
+			}
+			number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return fmt.Errorf("range of attribute %s must be a number", key)
+			}
+			if isMin {
+				filter.Min = &number
+			} else {
+				filter.Max = &number
+			}
+		}
+		return nil
+	}
+	if err := bound(min, true); err != nil {
+		return nil, err
+	}
+	if err := bound(max, false); err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.AttributeFilter, 0, len(filters))
+	for _, filter := range filters {
+		if len(filter.Values) > 0 || filter.Min != nil || filter.Max != nil {
+			result = append(result, filter)
+		}
+	}
+	return result, nil
+}
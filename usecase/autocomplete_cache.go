@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"espazeBackend/domain/entities"
+	"sync"
+	"time"
+)
+
+const (
+	autocompleteCacheTTL  = 2 * time.Minute
+	autocompleteCacheSize = 1000
+)
+
+type autocompleteCacheEntry struct {
+	response  *entities.AutocompleteResponse
+	hits      int
+	expiresAt time.Time
+}
+
+// autocompleteCache keeps the suggestions of recent queries. When full it drops expired entries first and then
+// the least requested ones, so popular queries stay cached.
+type autocompleteCache struct {
+	mu      sync.Mutex
+	entries map[string]*autocompleteCacheEntry
+}
+
+func newAutocompleteCache() *autocompleteCache {
+	return &autocompleteCache{entries: make(map[string]*autocompleteCacheEntry)}
+}
+
+func (c *autocompleteCache) get(key string) (*entities.AutocompleteResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	entry.hits++
+	return entry.response, true
+}
+
+func (c *autocompleteCache) put(key string, response *entities.AutocompleteResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	hits := 0
+	if existing, ok := c.entries[key]; ok {
+		hits = existing.hits
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= autocompleteCacheSize {
+		c.evict(now)
+	}
+	c.entries[key] = &autocompleteCacheEntry{response: response, hits: hits + 1, expiresAt: now.Add(autocompleteCacheTTL)}
+}
+
+func (c *autocompleteCache) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < autocompleteCacheSize {
+		return
+	}
+	leastKey, leastHits := "", -1
+	for key, entry := range c.entries {
+		if leastHits < 0 || entry.hits < leastHits {
+			leastKey, leastHits = key, entry.hits
+		}
+	}
+	delete(c.entries, leastKey)
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const maxBarcodesPerRequest = 20
+
+var barcodeFormats = map[int]string{
+	8:  entities.BarcodeEAN8,
+	12: entities.BarcodeUPCA,
+	13: entities.BarcodeEAN13,
+	14: entities.BarcodeGTIN14,
+}
+
+type BarcodeUseCase struct {
+	barcodeRepo repositories.BarcodeRepository
+}
+
+func NewBarcodeUseCase(barcodeRepo repositories.BarcodeRepository) *BarcodeUseCase {
+	return &BarcodeUseCase{
+		barcodeRepo: barcodeRepo,
+	}
+}
+
+// parseBarcode checks an EAN-8, UPC-A, EAN-13 or GTIN-14 code and its check digit. It returns the code without
+// separators, its format and the GTIN-14 it is stored under.
+func parseBarcode(code string) (string, string, string, error) {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code))
+	format, ok := barcodeFormats[len(code)]
+	if !ok {
+		return "", "", "", fmt.Errorf("barcode %s must have 8, 12, 13 or 14 digits", code)
+	}
+	for _, digit := range code {
+		if digit < '0' || digit > '9' {
+			return "", "", "", fmt.Errorf("barcode %s must only contain digits", code)
+		}
+	}
+
+	// GS1 check digit: weights 3 and 1 alternate leftwards from the digit before the check digit
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	if checkDigit := (10 - sum%10) % 10; checkDigit != int(code[len(code)-1]-'0') {
+		return "", "", "", fmt.Errorf("barcode %s has an invalid check digit", code)
+	}
+	return code, format, strings.Repeat("0", 14-len(code)) + code, nil
+}
+
+func (u *BarcodeUseCase) AddBarcodes(ctx context.Context, request *entities.AddBarcodesRequest) ([]*entities.Barcode, error) {
+	if len(request.Codes) == 0 {
+		return nil, errors.New("at least one barcode is required")
+	}
+	if len(request.Codes) > maxBarcodesPerRequest {
+		return nil, errors.New("at most 20 barcodes can be added at once")
+	}
+
+	now := time.Now()
+	barcodes := make([]*entities.Barcode, 0, len(request.Codes))
+	seen := make(map[string]bool, len(request.Codes))
+	for _, code := range request.Codes {
+		code, format, gtin, err := parseBarcode(code)
+		if err != nil {
+			return nil, err
+		}
+		if seen[gtin] {
+			continue
+		}
+		seen[gtin] = true
+		barcodes = append(barcodes, &entities.Barcode{
+			GTIN:              gtin,
+			Code:              code,
+			Format:            format,
+			MetadataProductID: request.MetadataProductID,
+			AddedBy:           request.AddedBy,
+			CreatedAt:         now,
+		})
+	}
+
+	if err := u.barcodeRepo.AddBarcodes(ctx, barcodes); err != nil {
+		return nil, err
+	}
+	return u.barcodeRepo.GetBarcodesForMetadata(ctx, request.MetadataProductID)
+}
+
+func (u *BarcodeUseCase) RemoveBarcode(ctx context.Context, code string) error {
+	_, _, gtin, err := parseBarcode(code)
+	if err != nil {
+		return err
+	}
+	return u.barcodeRepo.RemoveBarcode(ctx, gtin)
+}
+
+func (u *BarcodeUseCase) GetBarcodesForMetadata(ctx context.Context, metadataId string) ([]*entities.Barcode, error) {
+	if metadataId == "" {
+		return nil, errors.New("metadata id is required")
+	}
+	return u.barcodeRepo.GetBarcodesForMetadata(ctx, metadataId)
+}
+
+// LookupBarcode resolves a scanned code. sellerId is set for sellers, whose own listing of the product is
+// returned with it.
+func (u *BarcodeUseCase) LookupBarcode(ctx context.Context, code, sellerId string) (*entities.BarcodeLookupResponse, error) {
+	_, _, gtin, err := parseBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+	return u.barcodeRepo.LookupBarcode(ctx, gtin, sellerId)
+}
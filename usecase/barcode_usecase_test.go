@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"espazeBackend/domain/entities"
+	"testing"
+)
+
+func TestParseBarcode(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   string
+		want   string
+		format string
+		gtin   string
+		ok     bool
+	}{
+		{name: "ean-8", code: "96385074", want: "96385074", format: entities.BarcodeEAN8, gtin: "00000096385074", ok: true},
+		{name: "upc-a", code: "036000291452", want: "036000291452", format: entities.BarcodeUPCA, gtin: "00036000291452", ok: true},
+		{name: "ean-13", code: "4006381333931", want: "4006381333931", format: entities.BarcodeEAN13, gtin: "04006381333931", ok: true},
+		{name: "gtin-14", code: "10012345678902", want: "10012345678902", format: entities.BarcodeGTIN14, gtin: "10012345678902", ok: true},
+		{name: "separators", code: " 4006-381 333931 ", want: "4006381333931", format: entities.BarcodeEAN13, gtin: "04006381333931", ok: true},
+		{name: "upc-a wrong check digit", code: "036000291453"},
+		{name: "ean-13 wrong check digit", code: "4006381333932"},
+		{name: "ean-8 wrong check digit", code: "96385075"},
+		{name: "upc-a read as ean-13 keeps its gtin", code: "0036000291452", want: "0036000291452", format: entities.BarcodeEAN13, gtin: "00036000291452", ok: true},
+		{name: "letters", code: "40063813339A1"},
+		{name: "unsupported length", code: "1234567890"},
+		{name: "empty", code: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, format, gtin, err := parseBarcode(tt.code)
+			if !tt.ok {
+				if err == nil {
+					t.Fatalf("parseBarcode(%q) accepted an invalid barcode", tt.code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBarcode(%q) failed: %v", tt.code, err)
+			}
+			if code != tt.want || format != tt.format || gtin != tt.gtin {
+				t.Errorf("parseBarcode(%q) = %q, %q, %q, want %q, %q, %q", tt.code, code, format, gtin, tt.want, tt.format, tt.gtin)
+			}
+		})
+	}
+}
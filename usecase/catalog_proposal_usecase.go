@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"strings"
+)
+
+const maxProposalImages = 10
+
+type CatalogProposalUseCase struct {
+	catalogProposalRepo repositories.CatalogProposalRepository
+}
+
+func NewCatalogProposalUseCase(catalogProposalRepo repositories.CatalogProposalRepository) *CatalogProposalUseCase {
+	return &CatalogProposalUseCase{
+		catalogProposalRepo: catalogProposalRepo,
+	}
+}
+
+func (u *CatalogProposalUseCase) SubmitProposal(ctx context.Context, request *entities.SubmitCatalogProposalRequest) (*entities.CatalogProposal, error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	request.HsnCode = strings.TrimSpace(request.HsnCode)
+	if err := validateHSNCode(request.HsnCode); err != nil {
+		return nil, err
+	}
+	if _, err := u.catalogProposalRepo.GetHSNCode(ctx, request.HsnCode); err != nil {
+		return nil, err
+	}
+	if request.MRP <= 0 {
+		return nil, errors.New("mrp must be greater than zero")
+	}
+	if len(request.Images) > maxProposalImages {
+		return nil, errors.New("a proposal can have at most 10 images")
+	}
+	images := make([]string, 0, len(request.Images))
+	for _, image := range request.Images {
+		if image = strings.TrimSpace(image); image != "" {
+			images = append(images, image)
+		}
+	}
+
+	schema, err := u.catalogProposalRepo.GetAttributeSchema(ctx, request.SubcategoryID)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := validateMetadataAttributes(schema, request.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.catalogProposalRepo.SubmitProposal(ctx, &entities.CatalogProposal{
+		SellerID:      request.SellerID,
+		Name:          request.Name,
+		HsnCode:       request.HsnCode,
+		Description:   strings.TrimSpace(request.Description),
+		MRP:           request.MRP,
+		CategoryID:    request.CategoryID,
+		SubcategoryID: request.SubcategoryID,
+		Images:        images,
+		Attributes:    attributes,
+	})
+}
+
+func (u *CatalogProposalUseCase) GetProposalById(ctx context.Context, proposalId, sellerId string) (*entities.CatalogProposal, error) {
+	if proposalId == "" {
+		return nil, errors.New("proposal id is required")
+	}
+	return u.catalogProposalRepo.GetProposalById(ctx, proposalId, sellerId)
+}
+
+func (u *CatalogProposalUseCase) GetSellerProposals(ctx context.Context, sellerId string) ([]*entities.CatalogProposal, error) {
+	return u.catalogProposalRepo.GetSellerProposals(ctx, sellerId)
+}
+
+func (u *CatalogProposalUseCase) GetProposalQueue(ctx context.Context, status string, limit, offset int64) (*entities.PaginatedCatalogProposals, error) {
+	if status == "" {
+		status = entities.CatalogProposalPending
+	}
+	switch status {
+	case entities.CatalogProposalPending, entities.CatalogProposalApproved, entities.CatalogProposalMerged, entities.CatalogProposalRejected:
+	default:
+		return nil, errors.New("invalid status")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	proposals, total, err := u.catalogProposalRepo.GetProposalQueue(ctx, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PaginatedCatalogProposals{
+		Proposals:  proposals,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+func (u *CatalogProposalUseCase) WithdrawProposal(ctx context.Context, proposalId, sellerId string) error {
+	if proposalId == "" {
+		return errors.New("proposal id is required")
+	}
+	return u.catalogProposalRepo.WithdrawProposal(ctx, proposalId, sellerId)
+}
+
+func (u *CatalogProposalUseCase) ReviewProposal(ctx context.Context, review *entities.ReviewCatalogProposalRequest) (*entities.CatalogProposal, error) {
+	if review.ProposalID == "" {
+		return nil, errors.New("proposal id is required")
+	}
+	review.Note = strings.TrimSpace(review.Note)
+	switch review.Decision {
+	case entities.CatalogProposalApprove:
+		// the schema may have changed since the seller submitted
+		proposal, err := u.catalogProposalRepo.GetProposalById(ctx, review.ProposalID, "")
+		if err != nil {
+			return nil, err
+		}
+		schema, err := u.catalogProposalRepo.GetAttributeSchema(ctx, proposal.SubcategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := validateMetadataAttributes(schema, proposal.Attributes); err != nil {
+			return nil, err
+		}
+	case entities.CatalogProposalMerge:
+		if review.MetadataProductID == "" {
+			return nil, errors.New("metadata_product_id is required to merge a proposal")
+		}
+	case entities.CatalogProposalReject:
+		if review.Note == "" {
+			return nil, errors.New("a note is required to reject a proposal")
+		}
+	default:
+		return nil, errors.New("decision must be approve, merge or reject")
+	}
+	return u.catalogProposalRepo.ReviewProposal(ctx, review)
+}
@@ -134,8 +134,8 @@ func (u *CategorySubcategoryUseCase) UpdateSubcategory(ctx context.Context, subc
 // 	return u.categorySubcategoryRepo.GetCategoryById(ctx, categoryID)
 // }
 
-func (u *CategorySubcategoryUseCase) DeleteCategory(ctx context.Context, categoryID string) (*entities.MessageResponse, error) {
-	return u.categorySubcategoryRepo.DeleteCategory(ctx, categoryID)
+func (u *CategorySubcategoryUseCase) DeleteCategory(ctx context.Context, categoryID string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
+	return u.categorySubcategoryRepo.DeleteCategory(ctx, categoryID, request)
 }
 
 func (u *CategorySubcategoryUseCase) DeleteSubcategory(ctx context.Context, subcategoryID string) (*entities.MessageResponse, error) {
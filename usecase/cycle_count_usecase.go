@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type CycleCountUseCase struct {
+	cycleCountRepo repositories.CycleCountRepository
+}
+
+func NewCycleCountUseCase(cycleCountRepo repositories.CycleCountRepository) *CycleCountUseCase {
+	return &CycleCountUseCase{
+		cycleCountRepo: cycleCountRepo,
+	}
+}
+
+func (u *CycleCountUseCase) CreateCycleCountSession(ctx context.Context, request *entities.CreateCycleCountSessionRequest) (*entities.CycleCountSession, error) {
+	if request.StoreID == "" {
+		return nil, errors.New("store_id is required")
+	}
+	request.Rack = strings.TrimSpace(request.Rack)
+	return u.cycleCountRepo.CreateCycleCountSession(ctx, request)
+}
+
+// GetCycleCountSession returns a session with its variance summary. Completed sessions keep the summary taken at approval.
+func (u *CycleCountUseCase) GetCycleCountSession(ctx context.Context, sessionId, operationalId string) (*entities.CycleCountSession, error) {
+	if sessionId == "" {
+		return nil, errors.New("session id is required")
+	}
+	session, err := u.cycleCountRepo.GetCycleCountSessionById(ctx, sessionId, operationalId)
+	if err != nil {
+		return nil, err
+	}
+	if session.Summary == nil {
+		session.Summary = summarizeCycleCount(session.Lines)
+	}
+	return session, nil
+}
+
+func (u *CycleCountUseCase) GetCycleCountSessions(ctx context.Context, request *entities.GetCycleCountSessionsRequest) (*entities.PaginatedCycleCountSessionResponse, error) {
+	if request.Limit <= 0 {
+		request.Limit = 10
+	}
+	if request.Offset < 0 {
+		request.Offset = 0
+	}
+	sessions, total, err := u.cycleCountRepo.GetCycleCountSessions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	var totalPages int64 = (total + request.Limit - 1) / request.Limit
+
+	return &entities.PaginatedCycleCountSessionResponse{
+		Sessions:   sessions,
+		Total:      total,
+		TotalPages: totalPages,
+		Limit:      request.Limit,
+		Offset:     request.Offset,
+	}, nil
+}
+
+func (u *CycleCountUseCase) SubmitCycleCounts(ctx context.Context, sessionId, operationalId string, request *entities.SubmitCycleCountsRequest) (*entities.CycleCountSession, error) {
+	if sessionId == "" {
+		return nil, errors.New("session id is required")
+	}
+	if len(request.Counts) == 0 {
+		return nil, errors.New("at least one count is required")
+	}
+	now := time.Now()
+	for _, count := range request.Counts {
+		if count.InventoryProductID == "" {
+			return nil, errors.New("inventory_product_id is required for every count")
+		}
+		if count.CountedQuantity < 0 {
+			return nil, fmt.Errorf("counted quantity for inventory product %s cannot be negative", count.InventoryProductID)
+		}
+		if count.CountedAt.IsZero() {
+			count.CountedAt = now
+		}
+	}
+	session, err := u.cycleCountRepo.SubmitCycleCounts(ctx, sessionId, operationalId, request.Counts)
+	if err != nil {
+		return nil, err
+	}
+	session.Summary = summarizeCycleCount(session.Lines)
+	return session, nil
+}
+
+// SubmitCycleCountForReview freezes the counts so the variance can be reviewed
+func (u *CycleCountUseCase) SubmitCycleCountForReview(ctx context.Context, sessionId, operationalId string) (*entities.CycleCountSession, error) {
+	session, err := u.GetCycleCountSession(ctx, sessionId, operationalId)
+	if err != nil {
+		return nil, err
+	}
+	if session.Summary.CountedLines == 0 {
+		return nil, errors.New("no counts have been submitted for this session")
+	}
+	return u.cycleCountRepo.UpdateCycleCountStatus(ctx, sessionId, operationalId, []string{entities.CycleCountOpen}, entities.CycleCountInReview)
+}
+
+// ReopenCycleCountSession sends a session in review back for recounting
+func (u *CycleCountUseCase) ReopenCycleCountSession(ctx context.Context, sessionId, operationalId string) (*entities.CycleCountSession, error) {
+	if sessionId == "" {
+		return nil, errors.New("session id is required")
+	}
+	return u.cycleCountRepo.UpdateCycleCountStatus(ctx, sessionId, operationalId, []string{entities.CycleCountInReview}, entities.CycleCountOpen)
+}
+
+func (u *CycleCountUseCase) CancelCycleCountSession(ctx context.Context, sessionId, operationalId string) (*entities.CycleCountSession, error) {
+	if sessionId == "" {
+		return nil, errors.New("session id is required")
+	}
+	return u.cycleCountRepo.UpdateCycleCountStatus(ctx, sessionId, operationalId, []string{entities.CycleCountOpen, entities.CycleCountInReview}, entities.CycleCountCancelled)
+}
+
+// ApproveCycleCountSession posts the approved variances to inventory and completes the session.
+// Lines that are not approved keep their variance in the report without changing stock.
+func (u *CycleCountUseCase) ApproveCycleCountSession(ctx context.Context, sessionId, operationalId string, request *entities.ApproveCycleCountRequest) (*entities.CycleCountSession, error) {
+	if sessionId == "" {
+		return nil, errors.New("session id is required")
+	}
+	session, err := u.cycleCountRepo.GetCycleCountSessionById(ctx, sessionId, operationalId)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != entities.CycleCountInReview {
+		return nil, fmt.Errorf("count session is %s, only sessions in review can be approved", session.Status)
+	}
+
+	lines := make(map[string]*entities.CycleCountLine)
+	for _, line := range session.Lines {
+		lines[line.InventoryProductID] = line
+	}
+	for _, decision := range request.Lines {
+		line, ok := lines[decision.InventoryProductID]
+		if !ok {
+			return nil, fmt.Errorf("inventory product %s is not part of this session", decision.InventoryProductID)
+		}
+		if line.CountedQuantity == nil {
+			return nil, fmt.Errorf("inventory product %s has not been counted", decision.InventoryProductID)
+		}
+		reason := strings.TrimSpace(decision.Reason)
+		if decision.Approve && line.Variance != 0 && reason == "" {
+			return nil, fmt.Errorf("a reason is required to adjust inventory product %s", decision.InventoryProductID)
+		}
+		line.Approved = decision.Approve
+		line.Reason = reason
+	}
+
+	session.Summary = summarizeCycleCount(session.Lines)
+	return u.cycleCountRepo.ApproveCycleCountSession(ctx, session, operationalId)
+}
+
+func summarizeCycleCount(lines []*entities.CycleCountLine) *entities.CycleCountSummary {
+	summary := &entities.CycleCountSummary{TotalLines: len(lines)}
+	for _, line := range lines {
+		if line.CountedQuantity == nil {
+			continue
+		}
+		summary.CountedLines++
+		if line.Variance == 0 {
+			continue
+		}
+		summary.VarianceLines++
+		if line.Approved {
+			summary.AdjustedLines++
+		}
+		if line.Variance < 0 {
+			summary.ShortageUnits -= line.Variance
+		} else {
+			summary.ExcessUnits += line.Variance
+		}
+		summary.NetVarianceUnits += line.Variance
+		summary.NetVarianceValue += float64(line.Variance) * line.ProductPrice
+	}
+	summary.NetVarianceValue = math.Round(summary.NetVarianceValue*100) / 100
+	return summary
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/utils"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HSNUseCase struct {
+	hsnRepo repositories.HSNRepository
+}
+
+func NewHSNUseCase(hsnRepo repositories.HSNRepository) *HSNUseCase {
+	return &HSNUseCase{
+		hsnRepo: hsnRepo,
+	}
+}
+
+// normalizeHSNCode strips the dots and spaces HSN codes are often written with. Spreadsheets store codes as
+// numbers, which drops the leading zero of chapters 01 to 09, so it is put back.
+func normalizeHSNCode(code string) string {
+	code = strings.NewReplacer(".", "", " ", "").Replace(strings.TrimSpace(code))
+	if len(code)%2 == 1 {
+		code = "0" + code
+	}
+	return code
+}
+
+// prepareHSNCode validates a save request and fills in its defaults
+func prepareHSNCode(request *entities.SaveHSNCodeRequest) error {
+	request.Code = normalizeHSNCode(request.Code)
+	if err := validateHSNCode(request.Code); err != nil {
+		return err
+	}
+	request.Description = strings.TrimSpace(request.Description)
+	if request.GSTRate == nil {
+		return errors.New("gst rate is required")
+	}
+	if *request.GSTRate < 0 || *request.GSTRate > 100 {
+		return errors.New("gst rate must be between 0 and 100")
+	}
+	if request.EffectiveFrom == nil {
+		today := time.Now().Truncate(24 * time.Hour)
+		request.EffectiveFrom = &today
+	}
+	return nil
+}
+
+func (u *HSNUseCase) SaveHSNCode(ctx context.Context, request *entities.SaveHSNCodeRequest) (*entities.HSNCode, error) {
+	if err := prepareHSNCode(request); err != nil {
+		return nil, err
+	}
+	saved, err := u.hsnRepo.SaveHSNCodes(ctx, []*entities.SaveHSNCodeRequest{request})
+	if err != nil {
+		return nil, err
+	}
+	return saved[0], nil
+}
+
+// ImportHSNCodes loads an .xlsx or .csv sheet of HSN codes and rates into the master. Valid rows are saved even
+// when others fail, and the failures are reported by row.
+func (u *HSNUseCase) ImportHSNCodes(ctx context.Context, operationalId, fileName string, file io.Reader) (*entities.HSNImportResult, error) {
+	rows, err := utils.ReadSpreadsheet(fileName, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("sheet has no rows to import")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.ToLower(header)] = i
+	}
+	for _, required := range []string{entities.HSNSheetCode, entities.HSNSheetGSTRate} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("sheet is missing the %s column", required)
+		}
+	}
+	cell := func(row []string, column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(row) {
+			return ""
+		}
+		return row[index]
+	}
+
+	result := &entities.HSNImportResult{Errors: []*entities.HSNImportRowError{}}
+	requests := []*entities.SaveHSNCodeRequest{}
+	for i, row := range rows[1:] {
+		rowNumber := i + 2 // 1-based, after the header row
+		if isBlankRow(row) {
+			continue
+		}
+		result.TotalRows++
+
+		code := cell(row, entities.HSNSheetCode)
+		addError := func(column, message string) {
+			result.Errors = append(result.Errors, &entities.HSNImportRowError{Row: rowNumber, Column: column, HsnCode: code, Message: message})
+		}
+
+		request := &entities.SaveHSNCodeRequest{
+			Code:        code,
+			Description: cell(row, entities.HSNSheetDescription),
+			UpdatedBy:   operationalId,
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSuffix(cell(row, entities.HSNSheetGSTRate), "%"), 64)
+		if err != nil {
+			addError(entities.HSNSheetGSTRate, "gst rate must be a number")
+			continue
+		}
+		request.GSTRate = &rate
+		if value := cell(row, entities.HSNSheetEffectiveFrom); value != "" {
+			effectiveFrom, err := utils.ParseSpreadsheetDate(value)
+			if err != nil {
+				addError(entities.HSNSheetEffectiveFrom, err.Error())
+				continue
+			}
+			request.EffectiveFrom = &effectiveFrom
+		}
+		if err := prepareHSNCode(request); err != nil {
+			addError(entities.HSNSheetCode, err.Error())
+			continue
+		}
+		requests = append(requests, request)
+	}
+
+	if len(requests) > 0 {
+		if _, err := u.hsnRepo.SaveHSNCodes(ctx, requests); err != nil {
+			return nil, err
+		}
+	}
+	result.SavedCount = len(requests)
+	result.FailedCount = len(result.Errors)
+	return result, nil
+}
+
+func (u *HSNUseCase) GetHSNCode(ctx context.Context, code string) (*entities.HSNCode, error) {
+	code = normalizeHSNCode(code)
+	if err := validateHSNCode(code); err != nil {
+		return nil, err
+	}
+	return u.hsnRepo.GetHSNCode(ctx, code)
+}
+
+func (u *HSNUseCase) GetHSNCodes(ctx context.Context, search string, limit, offset int64) (*entities.PaginatedHSNCodes, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	codes, total, err := u.hsnRepo.GetHSNCodes(ctx, strings.TrimSpace(search), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PaginatedHSNCodes{
+		Codes:      codes,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+func (u *HSNUseCase) DeleteHSNCode(ctx context.Context, code string) error {
+	code = normalizeHSNCode(code)
+	if err := validateHSNCode(code); err != nil {
+		return err
+	}
+	return u.hsnRepo.DeleteHSNCode(ctx, code)
+}
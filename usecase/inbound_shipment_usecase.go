@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/utils"
+	"fmt"
+	"strings"
+)
+
+type InboundShipmentUseCase struct {
+	inboundShipmentRepo repositories.InboundShipmentRepository
+}
+
+func NewInboundShipmentUseCase(inboundShipmentRepo repositories.InboundShipmentRepository) *InboundShipmentUseCase {
+	return &InboundShipmentUseCase{
+		inboundShipmentRepo: inboundShipmentRepo,
+	}
+}
+
+// CreateInboundShipment declares a shipment a seller is sending to one of their stores
+func (u *InboundShipmentUseCase) CreateInboundShipment(ctx context.Context, request *entities.CreateInboundShipmentRequest) (*entities.InboundShipment, error) {
+	if request.StoreID == "" {
+		return nil, errors.New("store_id is required")
+	}
+	if len(request.Items) == 0 {
+		return nil, errors.New("at least one item is required")
+	}
+	store, err := u.inboundShipmentRepo.GetStoreById(ctx, request.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	if store.SellerID != request.SellerID {
+		return nil, errors.New("store does not belong to this seller")
+	}
+
+	metadataIds := make([]string, 0, len(request.Items))
+	for _, item := range request.Items {
+		if item.MetadataProductID == "" {
+			return nil, errors.New("metadata_product_id is required for every item")
+		}
+		metadataIds = append(metadataIds, item.MetadataProductID)
+	}
+	metadata, err := u.inboundShipmentRepo.GetMetadataByIds(ctx, metadataIds)
+	if err != nil {
+		return nil, err
+	}
+	metadataById := make(map[string]*entities.Metadata, len(metadata))
+	for _, m := range metadata {
+		metadataById[m.MetadataProductID] = m
+	}
+
+	shipment := &entities.InboundShipment{
+		WarehouseID: store.WarehouseID,
+		StoreID:     store.StoreID,
+		StoreName:   store.StoreName,
+		SellerID:    store.SellerID,
+		Note:        strings.TrimSpace(request.Note),
+	}
+	if request.ExpectedAt != "" {
+		expectedAt, err := utils.ParseSpreadsheetDate(request.ExpectedAt)
+		if err != nil {
+			return nil, fmt.Errorf("expected_at: %w", err)
+		}
+		shipment.ExpectedAt = &expectedAt
+	}
+
+	seen := make(map[string]bool)
+	for i, item := range request.Items {
+		m, ok := metadataById[item.MetadataProductID]
+		if !ok {
+			return nil, fmt.Errorf("line %d: metadata product %s not found", i, item.MetadataProductID)
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("line %d: quantity must be greater than 0", i)
+		}
+		if item.ProductPrice <= 0 || item.ProductPrice > m.MetadataMRP {
+			return nil, fmt.Errorf("line %d: product_price must be greater than 0 and not above the mrp %.2f", i, m.MetadataMRP)
+		}
+		if item.UnitCost < 0 {
+			return nil, fmt.Errorf("line %d: unit_cost cannot be negative", i)
+		}
+		expiryDate, err := utils.ParseSpreadsheetDate(item.ProductExpiryDate)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: product_expiry_date: %w", i, err)
+		}
+		manufacturingDate, err := utils.ParseSpreadsheetDate(item.ProductManufacturingDate)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: product_manufacturing_date: %w", i, err)
+		}
+		if !expiryDate.After(manufacturingDate) {
+			return nil, fmt.Errorf("line %d: product_expiry_date must be after product_manufacturing_date", i)
+		}
+		// Each line posts to one batch, so a batch may only be declared once
+		key := fmt.Sprintf("%s|%.2f|%s", item.MetadataProductID, item.ProductPrice, expiryDate.Format("2006-01-02"))
+		if seen[key] {
+			return nil, fmt.Errorf("line %d: the same product, price and expiry is declared more than once", i)
+		}
+		seen[key] = true
+
+		shipment.Items = append(shipment.Items, &entities.InboundShipmentItem{
+			MetadataProductID:        item.MetadataProductID,
+			MetadataName:             m.MetadataName,
+			DeclaredQuantity:         item.Quantity,
+			ProductPrice:             item.ProductPrice,
+			UnitCost:                 item.UnitCost,
+			ProductExpiryDate:        expiryDate,
+			ProductManufacturingDate: manufacturingDate,
+		})
+	}
+	return u.inboundShipmentRepo.CreateInboundShipment(ctx, shipment)
+}
+
+// ReceiveInboundShipment records what arrived. Every declared unit must be counted as accepted, short or damaged.
+func (u *InboundShipmentUseCase) ReceiveInboundShipment(ctx context.Context, request *entities.ReceiveInboundShipmentRequest) (*entities.InboundShipment, error) {
+	if request.ShipmentID == "" {
+		return nil, errors.New("shipment id is required")
+	}
+	shipment, err := u.inboundShipmentRepo.GetInboundShipmentById(ctx, request.ShipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	for _, counts := range request.Items {
+		if counts.Line < 0 || counts.Line >= len(shipment.Items) {
+			return nil, fmt.Errorf("line %d does not exist on the shipment", counts.Line)
+		}
+		if seen[counts.Line] {
+			return nil, fmt.Errorf("line %d is counted more than once", counts.Line)
+		}
+		seen[counts.Line] = true
+		if counts.AcceptedQuantity < 0 || counts.ShortQuantity < 0 || counts.DamagedQuantity < 0 {
+			return nil, fmt.Errorf("line %d: counts cannot be negative", counts.Line)
+		}
+		declared := shipment.Items[counts.Line].DeclaredQuantity
+		if counts.AcceptedQuantity+counts.ShortQuantity+counts.DamagedQuantity != declared {
+			return nil, fmt.Errorf("line %d: accepted, short and damaged must add up to the declared %d", counts.Line, declared)
+		}
+		if counts.AcceptedQuantity != declared && strings.TrimSpace(counts.Remark) == "" {
+			return nil, fmt.Errorf("line %d: a remark is required when not everything is accepted", counts.Line)
+		}
+	}
+	if len(seen) != len(shipment.Items) {
+		return nil, fmt.Errorf("counts are required for all %d lines of the shipment", len(shipment.Items))
+	}
+	return u.inboundShipmentRepo.ReceiveInboundShipment(ctx, request)
+}
+
+func (u *InboundShipmentUseCase) CancelInboundShipment(ctx context.Context, shipmentId, sellerId string) (*entities.InboundShipment, error) {
+	if shipmentId == "" {
+		return nil, errors.New("shipment id is required")
+	}
+	return u.inboundShipmentRepo.CancelInboundShipment(ctx, shipmentId, sellerId)
+}
+
+// GetInboundShipmentById returns a shipment, limited to its seller when sellerId is set
+func (u *InboundShipmentUseCase) GetInboundShipmentById(ctx context.Context, shipmentId, sellerId string) (*entities.InboundShipment, error) {
+	if shipmentId == "" {
+		return nil, errors.New("shipment id is required")
+	}
+	shipment, err := u.inboundShipmentRepo.GetInboundShipmentById(ctx, shipmentId)
+	if err != nil {
+		return nil, err
+	}
+	if sellerId != "" && shipment.SellerID != sellerId {
+		return nil, errors.New("shipment not found")
+	}
+	return shipment, nil
+}
+
+func (u *InboundShipmentUseCase) GetInboundShipments(ctx context.Context, request *entities.GetInboundShipmentsRequest) (*entities.PaginatedInboundShipmentResponse, error) {
+	if request.Limit <= 0 {
+		request.Limit = 10
+	}
+	if request.Offset < 0 {
+		request.Offset = 0
+	}
+	switch request.Status {
+	case "", entities.InboundShipmentDeclared, entities.InboundShipmentReceived, entities.InboundShipmentCancelled:
+	default:
+		return nil, fmt.Errorf("invalid status %s", request.Status)
+	}
+	shipments, total, err := u.inboundShipmentRepo.GetInboundShipments(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	var totalPages int64 = (total + request.Limit - 1) / request.Limit
+
+	return &entities.PaginatedInboundShipmentResponse{
+		Shipments:  shipments,
+		Total:      total,
+		TotalPages: totalPages,
+		Limit:      request.Limit,
+		Offset:     request.Offset,
+	}, nil
+}
+
+// ExportGoodsReceivedNote renders the GRN of a received shipment as a spreadsheet
+func (u *InboundShipmentUseCase) ExportGoodsReceivedNote(ctx context.Context, shipmentId, sellerId, format string) (*entities.InboundShipment, []byte, error) {
+	if format != "xlsx" && format != "csv" {
+		return nil, nil, errors.New("format must be xlsx or csv")
+	}
+	shipment, err := u.GetInboundShipmentById(ctx, shipmentId, sellerId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if shipment.Status != entities.InboundShipmentReceived {
+		return nil, nil, errors.New("the goods-received note is issued once the shipment is received")
+	}
+
+	rows := [][]interface{}{
+		{"GRN Number", shipment.GRNNumber},
+		{"Store", shipment.StoreName},
+		{"Seller ID", shipment.SellerID},
+		{"Declared At", shipment.DeclaredAt.Format("02-01-2006 15:04")},
+		{"Received At", shipment.ReceivedAt.Format("02-01-2006 15:04")},
+		{"Note", shipment.ReceiveNote},
+		{},
+		{"metadata_product_id", "name", "price", "unit_cost", "expiry_date", "manufacturing_date", "declared", "accepted", "short", "damaged", "remark"},
+	}
+	accepted, short, damaged, declared := 0, 0, 0, 0
+	for _, item := range shipment.Items {
+		rows = append(rows, []interface{}{
+			item.MetadataProductID,
+			item.MetadataName,
+			item.ProductPrice,
+			item.UnitCost,
+			item.ProductExpiryDate.Format(inventorySheetDateLayout),
+			item.ProductManufacturingDate.Format(inventorySheetDateLayout),
+			item.DeclaredQuantity,
+			item.AcceptedQuantity,
+			item.ShortQuantity,
+			item.DamagedQuantity,
+			item.Remark,
+		})
+		declared += item.DeclaredQuantity
+		accepted += item.AcceptedQuantity
+		short += item.ShortQuantity
+		damaged += item.DamagedQuantity
+	}
+	rows = append(rows, []interface{}{"Total", "", "", "", "", "", declared, accepted, short, damaged, ""})
+
+	var buffer bytes.Buffer
+	if err := utils.WriteSpreadsheet(&buffer, format, rows); err != nil {
+		return nil, nil, err
+	}
+	return shipment, buffer.Bytes(), nil
+}
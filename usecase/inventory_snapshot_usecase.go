@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/utils"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// snapshotDateLayout is the format of snapshot dates in requests and storage
+const snapshotDateLayout = "2006-01-02"
+
+// Daily snapshots are taken shortly before midnight so they hold the closing stock of the day
+const (
+	snapshotHour   = 23
+	snapshotMinute = 55
+)
+
+// Days missed while no instance was running are backfilled, up to snapshotBackfillDays back. The lock lease
+// outlasts a run so a slow run is not taken over.
+const (
+	snapshotBackfillDays = 31
+	snapshotLockLease    = 30 * time.Minute
+)
+
+type InventorySnapshotUseCase struct {
+	inventorySnapshotRepo repositories.InventorySnapshotRepository
+}
+
+func NewInventorySnapshotUseCase(inventorySnapshotRepo repositories.InventorySnapshotRepository) *InventorySnapshotUseCase {
+	return &InventorySnapshotUseCase{
+		inventorySnapshotRepo: inventorySnapshotRepo,
+	}
+}
+
+// TakeInventorySnapshot snapshots the current stock under today's date, replacing an earlier snapshot of today
+func (u *InventorySnapshotUseCase) TakeInventorySnapshot(ctx context.Context) (*entities.InventorySnapshotSummary, error) {
+	now := time.Now()
+	return u.inventorySnapshotRepo.CreateInventorySnapshot(ctx, now.Format(snapshotDateLayout), now)
+}
+
+// RunDailySnapshots takes the closing snapshot every day until ctx is cancelled. It catches up on start, so
+// days missed while no instance was running are backfilled. Instances share a lock, one of them takes the snapshots.
+func (u *InventorySnapshotUseCase) RunDailySnapshots(ctx context.Context) {
+	holder := primitive.NewObjectID().Hex()
+	for {
+		u.takeDueSnapshots(ctx, holder)
+
+		now := time.Now()
+		next := snapshotTime(now)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// takeDueSnapshots takes the closing snapshot of every day since the latest one, when holder gets the lock
+func (u *InventorySnapshotUseCase) takeDueSnapshots(ctx context.Context, holder string) {
+	locked, err := u.inventorySnapshotRepo.AcquireSnapshotLock(ctx, holder, snapshotLockLease)
+	if err != nil {
+		log.Printf("inventory snapshot lock failed: %v", err)
+		return
+	}
+	if !locked {
+		return
+	}
+	defer func() {
+		if err := u.inventorySnapshotRepo.ReleaseSnapshotLock(context.Background(), holder); err != nil {
+			log.Printf("inventory snapshot lock release failed: %v", err)
+		}
+	}()
+
+	// The latest closed day is today once its snapshot time has passed, yesterday before
+	now := time.Now()
+	last := snapshotTime(now)
+	if last.After(now) {
+		last = last.AddDate(0, 0, -1)
+	}
+	first := last.AddDate(0, 0, -snapshotBackfillDays)
+	snapshots, err := u.inventorySnapshotRepo.GetInventorySnapshots(ctx)
+	if err != nil {
+		log.Printf("inventory snapshot failed: %v", err)
+		return
+	}
+	if len(snapshots) == 0 {
+		first = last
+	} else if latest, err := time.ParseInLocation(snapshotDateLayout, snapshots[0].SnapshotDate, now.Location()); err == nil && snapshotTime(latest).After(first) {
+		first = snapshotTime(latest).AddDate(0, 0, 1)
+	}
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if ctx.Err() != nil {
+			return
+		}
+		summary, err := u.inventorySnapshotRepo.CreateInventorySnapshot(ctx, day.Format(snapshotDateLayout), day)
+		if err != nil {
+			log.Printf("inventory snapshot %s failed: %v", day.Format(snapshotDateLayout), err)
+			return
+		}
+		log.Printf("inventory snapshot %s taken for %d products", summary.SnapshotDate, summary.Products)
+	}
+}
+
+// snapshotTime is the time the closing snapshot of the day of t is taken at
+func snapshotTime(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), snapshotHour, snapshotMinute, 0, 0, t.Location())
+}
+
+func (u *InventorySnapshotUseCase) GetInventorySnapshots(ctx context.Context) ([]*entities.InventorySnapshotSummary, error) {
+	return u.inventorySnapshotRepo.GetInventorySnapshots(ctx)
+}
+
+// GetInventoryValuation values the snapshot of request.Date by store, seller, category or warehouse. With a
+// CompareDate every group also carries its value on that date and the change between the two.
+func (u *InventorySnapshotUseCase) GetInventoryValuation(ctx context.Context, request *entities.InventoryValuationRequest) (*entities.InventoryValuationReport, error) {
+	if request.GroupBy == "" {
+		request.GroupBy = entities.ValuationGroupWarehouse
+	}
+	switch request.GroupBy {
+	case entities.ValuationGroupStore, entities.ValuationGroupSeller, entities.ValuationGroupCategory, entities.ValuationGroupWarehouse:
+	default:
+		return nil, fmt.Errorf("group_by must be one of %s, %s, %s or %s", entities.ValuationGroupStore, entities.ValuationGroupSeller,
+			entities.ValuationGroupCategory, entities.ValuationGroupWarehouse)
+	}
+
+	if request.Date == "" {
+		snapshots, err := u.inventorySnapshotRepo.GetInventorySnapshots(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(snapshots) == 0 {
+			return nil, errors.New("no inventory snapshot has been taken yet")
+		}
+		request.Date = snapshots[0].SnapshotDate
+	}
+	dates := []string{request.Date}
+	if request.CompareDate != "" {
+		if request.CompareDate == request.Date {
+			return nil, errors.New("compare_date must differ from date")
+		}
+		dates = append(dates, request.CompareDate)
+	}
+	for _, date := range dates {
+		if _, err := time.Parse(snapshotDateLayout, date); err != nil {
+			return nil, fmt.Errorf("invalid date %s, expected YYYY-MM-DD", date)
+		}
+		exists, err := u.inventorySnapshotRepo.HasInventorySnapshot(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("no inventory snapshot for %s", date)
+		}
+	}
+
+	current, err := u.inventorySnapshotRepo.GetInventoryValuation(ctx, request, request.Date)
+	if err != nil {
+		return nil, err
+	}
+	report := &entities.InventoryValuationReport{
+		Date:    request.Date,
+		GroupBy: request.GroupBy,
+		Rows:    []*entities.InventoryValuationRow{},
+		Totals:  &entities.InventoryValuationRow{GroupID: "total", GroupName: "Total", Current: &entities.InventoryValuationGroup{}},
+	}
+	rowsById := make(map[string]*entities.InventoryValuationRow)
+	for _, group := range current {
+		row := &entities.InventoryValuationRow{GroupID: group.GroupID, GroupName: group.GroupName, Current: group}
+		rowsById[group.GroupID] = row
+		report.Rows = append(report.Rows, row)
+		addValuationGroup(report.Totals.Current, group)
+	}
+
+	if request.CompareDate != "" {
+		compare, err := u.inventorySnapshotRepo.GetInventoryValuation(ctx, request, request.CompareDate)
+		if err != nil {
+			return nil, err
+		}
+		report.CompareDate = request.CompareDate
+		report.Totals.Compare = &entities.InventoryValuationGroup{}
+		// Groups that only held stock on the compare date show up with nothing on the report date
+		for _, group := range compare {
+			row, ok := rowsById[group.GroupID]
+			if !ok {
+				row = &entities.InventoryValuationRow{
+					GroupID:   group.GroupID,
+					GroupName: group.GroupName,
+					Current:   &entities.InventoryValuationGroup{GroupID: group.GroupID, GroupName: group.GroupName},
+				}
+				rowsById[group.GroupID] = row
+				report.Rows = append(report.Rows, row)
+			}
+			row.Compare = group
+			addValuationGroup(report.Totals.Compare, group)
+		}
+		for _, row := range append(report.Rows, report.Totals) {
+			if row.Compare == nil {
+				row.Compare = &entities.InventoryValuationGroup{GroupID: row.GroupID, GroupName: row.GroupName}
+			}
+			row.QuantityChange = row.Current.Quantity - row.Compare.Quantity
+			row.CostValueChange = math.Round((row.Current.CostValue-row.Compare.CostValue)*100) / 100
+			row.PriceValueChange = math.Round((row.Current.PriceValue-row.Compare.PriceValue)*100) / 100
+			row.MRPValueChange = math.Round((row.Current.MRPValue-row.Compare.MRPValue)*100) / 100
+		}
+	}
+	return report, nil
+}
+
+// ExportInventoryValuation renders the valuation report as a spreadsheet, one row per group and a total row
+func (u *InventorySnapshotUseCase) ExportInventoryValuation(ctx context.Context, request *entities.InventoryValuationRequest, format string) ([]byte, error) {
+	if format != "xlsx" && format != "csv" {
+		return nil, errors.New("format must be xlsx or csv")
+	}
+	report, err := u.GetInventoryValuation(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	header := []interface{}{request.GroupBy + "_id", request.GroupBy + "_name", "products", "quantity", "value_at_cost", "value_at_price", "value_at_mrp"}
+	if report.CompareDate != "" {
+		header = append(header,
+			"compare_quantity", "compare_value_at_cost", "compare_value_at_price", "compare_value_at_mrp",
+			"quantity_change", "value_at_cost_change", "value_at_price_change", "value_at_mrp_change",
+		)
+	}
+	rows := [][]interface{}{
+		{"snapshot_date", report.Date, "compare_date", report.CompareDate},
+		header,
+	}
+	for _, row := range append(report.Rows, report.Totals) {
+		record := []interface{}{row.GroupID, row.GroupName, row.Current.Products, row.Current.Quantity, row.Current.CostValue, row.Current.PriceValue, row.Current.MRPValue}
+		if report.CompareDate != "" {
+			record = append(record,
+				row.Compare.Quantity, row.Compare.CostValue, row.Compare.PriceValue, row.Compare.MRPValue,
+				row.QuantityChange, row.CostValueChange, row.PriceValueChange, row.MRPValueChange,
+			)
+		}
+		rows = append(rows, record)
+	}
+
+	var buffer bytes.Buffer
+	if err := utils.WriteSpreadsheet(&buffer, format, rows); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func addValuationGroup(total, group *entities.InventoryValuationGroup) {
+	total.Products += group.Products
+	total.Quantity += group.Quantity
+	total.CostValue = math.Round((total.CostValue+group.CostValue)*100) / 100
+	total.PriceValue = math.Round((total.PriceValue+group.PriceValue)*100) / 100
+	total.MRPValue = math.Round((total.MRPValue+group.MRPValue)*100) / 100
+}
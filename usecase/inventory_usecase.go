@@ -1,9 +1,21 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"espazeBackend/domain/entities"
 	"espazeBackend/domain/repositories"
+	"espazeBackend/utils"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type InventoryUseCaseInterface struct {
@@ -14,14 +26,17 @@ func NewInventoryUseCase(inventoryRepo repositories.InventoryRepository) *Invent
 	return &InventoryUseCaseInterface{inventoryRepo: inventoryRepo}
 }
 
-func (u *InventoryUseCaseInterface) GetAllInventory(ctx context.Context, seller_id string, offset, limit int64, search, sort string) (*entities.PaginatedInventoryResponse, error) {
+func (u *InventoryUseCaseInterface) GetAllInventory(ctx context.Context, seller_id string, offset, limit int64, search, sort string, filter *entities.InventoryListFilter) (*entities.PaginatedInventoryResponse, error) {
 	if limit <= 0 {
 		limit = 10
 	}
 	if offset < 0 {
 		offset = 0
 	}
-	inventory, total, err := u.inventoryRepo.GetAllInventory(ctx, seller_id, offset, limit, search, sort)
+	if err := validateInventoryListing(sort, filter); err != nil {
+		return nil, err
+	}
+	inventory, total, facets, err := u.inventoryRepo.GetAllInventory(ctx, seller_id, offset, limit, search, sort, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -33,22 +48,106 @@ func (u *InventoryUseCaseInterface) GetAllInventory(ctx context.Context, seller_
 		Limit:            limit,
 		Offset:           offset,
 		TotalPages:       totalPages,
+		Facets:           facets,
 	}, nil
 }
 
+// ErrInvalidInventoryListing marks listing sort or filter parameters that cannot be applied
+var ErrInvalidInventoryListing = errors.New("invalid inventory listing parameters")
+
+// validateInventoryListing checks the sort fields and that every filter range is in order
+func validateInventoryListing(sort string, filter *entities.InventoryListFilter) error {
+	switch sort {
+	case "", "asc", "desc", "mrp_asc", "mrp_desc":
+	default:
+		for _, field := range strings.Split(sort, ",") {
+			field = strings.TrimPrefix(strings.TrimSpace(field), "-")
+			if !slices.Contains(entities.InventorySortFields, field) {
+				return fmt.Errorf("%w: cannot sort on %q, sortable fields are %s", ErrInvalidInventoryListing, field, strings.Join(entities.InventorySortFields, ", "))
+			}
+		}
+	}
+
+	if filter == nil {
+		return nil
+	}
+	if filter.MinQuantity != nil && filter.MaxQuantity != nil && *filter.MinQuantity > *filter.MaxQuantity {
+		return fmt.Errorf("%w: min_quantity cannot be greater than max_quantity", ErrInvalidInventoryListing)
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return fmt.Errorf("%w: min_price cannot be greater than max_price", ErrInvalidInventoryListing)
+	}
+	if filter.ExpiryFrom != nil && filter.ExpiryTo != nil && filter.ExpiryFrom.After(*filter.ExpiryTo) {
+		return fmt.Errorf("%w: expiry_from cannot be after expiry_to", ErrInvalidInventoryListing)
+	}
+	return nil
+}
+
 func (u *InventoryUseCaseInterface) AddInventory(ctx context.Context, inventoryRequest *entities.AddInventoryRequest) (*entities.MessageResponse, error) {
 	return u.inventoryRepo.CreateInventory(ctx, inventoryRequest)
 
 }
 
+// UpdateInventory updates an inventory product. A price change is checked against the pricing guardrails and,
+// when it breaks one that routes to ops, the rest of the update is applied while the price waits for approval.
 func (u *InventoryUseCaseInterface) UpdateInventory(ctx context.Context, inventoryRequest entities.UpdateInventoryRequest) (*entities.MessageResponse, error) {
-	return u.inventoryRepo.UpdateInventory(ctx, inventoryRequest)
+	pricingContexts, err := u.inventoryRepo.GetPricingContexts(ctx, []string{inventoryRequest.InventoryProductID})
+	if err != nil {
+		return &entities.MessageResponse{Success: false, Message: "Invalid Request", Error: err.Error()}, err
+	}
+	if len(pricingContexts) == 0 || (inventoryRequest.SellerID != "" && pricingContexts[0].SellerID != inventoryRequest.SellerID) {
+		err := errors.New("inventory product not found")
+		return &entities.MessageResponse{Success: false, Message: "Invalid Request", Error: err.Error()}, err
+	}
+	pricingContext := pricingContexts[0]
+	if inventoryRequest.ProductPrice == pricingContext.CurrentPrice {
+		return u.inventoryRepo.UpdateInventory(ctx, inventoryRequest, nil)
+	}
 
+	violations, err := checkPriceGuardrails(pricingContext, inventoryRequest.ProductPrice)
+	if err != nil {
+		return &entities.MessageResponse{Success: false, Message: "Price Not Allowed", Error: err.Error()}, err
+	}
+	if len(violations) == 0 {
+		return u.inventoryRepo.UpdateInventory(ctx, inventoryRequest, nil)
+	}
+	if pricingContext.Guardrail.ViolationAction != entities.PriceViolationReview {
+		err := fmt.Errorf("%w: %s", ErrPriceGuardrail, strings.Join(violations, "; "))
+		return &entities.MessageResponse{Success: false, Message: "Price Not Allowed", Error: err.Error()}, err
+	}
+
+	priceChange := newPriceChangeRequest(pricingContext, inventoryRequest.ProductPrice, violations, inventoryRequest.SellerID)
+	inventoryRequest.ProductPrice = pricingContext.CurrentPrice
+	response, err := u.inventoryRepo.UpdateInventory(ctx, inventoryRequest, []*entities.PriceChangeRequest{priceChange})
+	if err != nil || !response.Success {
+		return response, err
+	}
+	response.Message = "Product Updated Successfully, price change sent for approval"
+	return response, nil
 }
 
-func (u *InventoryUseCaseInterface) AcceptVisibility(ctx context.Context, productId string) (*entities.MessageResponse, error) {
-	return u.inventoryRepo.AcceptVisibility(ctx, productId)
+// ReviewInventoryProduct records an approve, reject or request-changes decision on an inventory product
+func (u *InventoryUseCaseInterface) ReviewInventoryProduct(ctx context.Context, review *entities.InventoryReviewRequest) (*entities.MessageResponse, error) {
+	if review.InventoryProductID == "" {
+		return nil, errors.New("inventory_product_id is required")
+	}
+	switch review.Decision {
+	case entities.ReviewStatusApproved, entities.ReviewStatusRejected, entities.ReviewStatusChangesRequested:
+	default:
+		return nil, fmt.Errorf("decision must be one of %s, %s or %s", entities.ReviewStatusApproved, entities.ReviewStatusRejected, entities.ReviewStatusChangesRequested)
+	}
+	review.Reason = strings.TrimSpace(review.Reason)
+	if review.Reason == "" {
+		return nil, errors.New("reason is required")
+	}
+	return u.inventoryRepo.ReviewInventoryProduct(ctx, review)
+}
 
+func (u *InventoryUseCaseInterface) GetInventoryReviewHistory(ctx context.Context, inventoryProductId, sellerId string) ([]*entities.InventoryReviewDecision, error) {
+	if inventoryProductId == "" {
+		return nil, errors.New("inventory_product_id is required")
+	}
+	return u.inventoryRepo.GetInventoryReviewHistory(ctx, inventoryProductId, sellerId)
 }
 
 func (u *InventoryUseCaseInterface) DeleteInventory(ctx context.Context, inventoryRequest entities.DeleteInventoryRequest) error {
@@ -65,18 +164,131 @@ func (u *InventoryUseCaseInterface) GetInventoryById(ctx context.Context, invent
 }
 
 func (u *InventoryUseCaseInterface) AddInventoryByExcel(ctx context.Context, inventoryRequest *entities.AddInventoryByExcelRequest) (*entities.MessageResponse, error) {
-	return u.inventoryRepo.AddInventoryByExcel(ctx, inventoryRequest)
+	priceErrors, priceChanges, err := u.checkSheetPrices(ctx, inventoryRequest.SellerID, inventoryRequest.MetadataProducts)
+	if err != nil {
+		return &entities.MessageResponse{Success: false, Message: "Invalid Request", Error: err.Error()}, err
+	}
+	for i := range inventoryRequest.MetadataProducts {
+		if message, ok := priceErrors[i]; ok {
+			err := fmt.Errorf("product %d: %s", i, message)
+			return &entities.MessageResponse{Success: false, Message: "Price Not Allowed", Error: err.Error()}, err
+		}
+	}
+	response, err := u.inventoryRepo.AddInventoryByExcel(ctx, inventoryRequest, priceChanges)
+	if err != nil {
+		return response, err
+	}
+	if len(priceChanges) > 0 {
+		response.Message = fmt.Sprintf("%s, %d price changes sent for approval", response.Message, len(priceChanges))
+	}
+	return response, nil
+}
+
+// checkSheetPrices checks sheet prices against the pricing guardrails. A listing update breaking a guardrail that
+// routes to ops keeps the current price and its change is returned to be queued once the sheet is applied; any other
+// violation is returned as the error of the product at that index. New batches have no price to change from, so only
+// the floor applies to them, and they have no listing to queue a change on, so breaking it is an error.
+func (u *InventoryUseCaseInterface) checkSheetPrices(ctx context.Context, sellerId string, products []entities.AddInventoryByExcelProduct) (map[int]string, []*entities.PriceChangeRequest, error) {
+	var listingIds, metadataIds []string
+	for _, product := range products {
+		if product.InventoryProductID != "" {
+			listingIds = append(listingIds, product.InventoryProductID)
+		} else {
+			metadataIds = append(metadataIds, product.ProductMetadataId)
+		}
+	}
+	listingContexts := make(map[string]*entities.PricingContext)
+	if len(listingIds) > 0 {
+		contexts, err := u.inventoryRepo.GetPricingContexts(ctx, listingIds)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pricingContext := range contexts {
+			listingContexts[pricingContext.InventoryProductID] = pricingContext
+		}
+	}
+	metadataContexts := make(map[string]*entities.PricingContext)
+	if len(metadataIds) > 0 {
+		contexts, err := u.inventoryRepo.GetMetadataPricingContexts(ctx, metadataIds)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pricingContext := range contexts {
+			metadataContexts[pricingContext.MetadataProductID] = pricingContext
+		}
+	}
+
+	priceErrors := make(map[int]string)
+	var priceChanges []*entities.PriceChangeRequest
+	for i := range products {
+		product := &products[i]
+		pricingContext := metadataContexts[product.ProductMetadataId]
+		if product.InventoryProductID != "" {
+			pricingContext = listingContexts[product.InventoryProductID]
+			if pricingContext != nil && pricingContext.SellerID != sellerId {
+				pricingContext = nil
+			}
+		}
+		if pricingContext == nil {
+			priceErrors[i] = "product not found in your inventory"
+			continue
+		}
+		if product.InventoryProductID != "" && product.ProductPrice == pricingContext.CurrentPrice {
+			continue
+		}
+
+		violations, err := checkPriceGuardrails(pricingContext, product.ProductPrice)
+		if err != nil {
+			priceErrors[i] = err.Error()
+			continue
+		}
+		if len(violations) == 0 {
+			continue
+		}
+		if product.InventoryProductID == "" || pricingContext.Guardrail.ViolationAction != entities.PriceViolationReview {
+			priceErrors[i] = fmt.Errorf("%w: %s", ErrPriceGuardrail, strings.Join(violations, "; ")).Error()
+			continue
+		}
+		priceChanges = append(priceChanges, newPriceChangeRequest(pricingContext, product.ProductPrice, violations, sellerId))
+		product.ProductPrice = pricingContext.CurrentPrice
+	}
+	return priceErrors, priceChanges, nil
 
 }
 
-func (u *InventoryUseCaseInterface) GetAllInventoryRequests(ctx context.Context, operational_id string, offset, limit int64, search string) (*entities.PaginatedInventoryRequestResponse, error) {
+func (u *InventoryUseCaseInterface) GetInventoryLedger(ctx context.Context, inventoryProductId, sellerId string, offset, limit int64) (*entities.PaginatedInventoryLedgerResponse, error) {
+	if inventoryProductId == "" {
+		return nil, errors.New("inventory_product_id is required")
+	}
 	if limit <= 0 {
 		limit = 10
 	}
 	if offset < 0 {
 		offset = 0
 	}
-	inventory, total, err := u.inventoryRepo.GetAllInventoryRequests(ctx, operational_id, offset, limit, search)
+	entries, total, err := u.inventoryRepo.GetInventoryLedger(ctx, inventoryProductId, sellerId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	var totalPages int64 = (total + limit - 1) / limit
+
+	return &entities.PaginatedInventoryLedgerResponse{
+		Entries:    entries,
+		Total:      total,
+		TotalPages: totalPages,
+		Limit:      limit,
+		Offset:     offset,
+	}, nil
+}
+
+func (u *InventoryUseCaseInterface) GetAllInventoryRequests(ctx context.Context, operational_id string, offset, limit int64, search, status string) (*entities.PaginatedInventoryRequestResponse, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	inventory, total, err := u.inventoryRepo.GetAllInventoryRequests(ctx, operational_id, offset, limit, search, status)
 	if err != nil {
 		return nil, err
 	}
@@ -90,3 +302,487 @@ func (u *InventoryUseCaseInterface) GetAllInventoryRequests(ctx context.Context,
 		Offset:           offset,
 	}, nil
 }
+
+const maxInventorySheetRows = 5000
+
+// UploadInventorySheet parses an .xlsx or .csv inventory sheet, validates every row and,
+// unless dryRun is set or a row is invalid, adds the rows to the seller's inventory.
+// Rows with an inventory product id set that listing's quantity, price and dates instead.
+func (u *InventoryUseCaseInterface) UploadInventorySheet(ctx context.Context, sellerID, fileName string, file io.Reader, dryRun bool) (*entities.InventorySheetUploadResponse, error) {
+	rows, err := utils.ReadSpreadsheet(fileName, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("sheet has no data rows")
+	}
+	if len(rows)-1 > maxInventorySheetRows {
+		return nil, fmt.Errorf("sheet has more than %d rows", maxInventorySheetRows)
+	}
+
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.ToLower(header)] = i
+	}
+	_, hasId := columns[entities.InventorySheetMetadataID]
+	_, hasHsn := columns[entities.InventorySheetHSNCode]
+	if !hasId && !hasHsn {
+		return nil, fmt.Errorf("sheet must have a %s or %s column", entities.InventorySheetMetadataID, entities.InventorySheetHSNCode)
+	}
+	for _, required := range []string{entities.InventorySheetQuantity, entities.InventorySheetPrice, entities.InventorySheetExpiryDate, entities.InventorySheetManufacturingDate} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("sheet is missing the %s column", required)
+		}
+	}
+	cell := func(row []string, column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(row) {
+			return ""
+		}
+		return row[index]
+	}
+
+	// Rows exported with their inventory product id update that listing instead of adding stock
+	var listingIds []string
+	for _, row := range rows[1:] {
+		if id := cell(row, entities.InventorySheetInventoryProductID); id != "" {
+			listingIds = append(listingIds, id)
+		}
+	}
+	listings := make(map[string]*entities.BulkInventoryTarget)
+	if len(listingIds) > 0 {
+		targets, err := u.inventoryRepo.GetBulkInventoryTargets(ctx, sellerID, listingIds, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range targets {
+			listings[target.InventoryProductID] = target
+		}
+	}
+
+	var metadataIds, hsnCodes []string
+	for _, row := range rows[1:] {
+		if id := cell(row, entities.InventorySheetMetadataID); id != "" {
+			metadataIds = append(metadataIds, id)
+		} else if listing := listings[cell(row, entities.InventorySheetInventoryProductID)]; listing != nil {
+			metadataIds = append(metadataIds, listing.MetadataProductID)
+		} else if hsn := cell(row, entities.InventorySheetHSNCode); hsn != "" {
+			hsnCodes = append(hsnCodes, hsn)
+		}
+	}
+	metadata, err := u.inventoryRepo.GetMetadataForSheet(ctx, metadataIds, hsnCodes)
+	if err != nil {
+		return nil, err
+	}
+	metadataById := make(map[string]*entities.Metadata)
+	// an HSN code is a tariff heading many products can share, so it only identifies a product it alone holds
+	metadataByHsn := make(map[string][]*entities.Metadata)
+	for _, m := range metadata {
+		metadataById[m.MetadataProductID] = m
+		metadataByHsn[m.MetadataHSNCode] = append(metadataByHsn[m.MetadataHSNCode], m)
+	}
+
+	response := &entities.InventorySheetUploadResponse{DryRun: dryRun}
+	inventoryRequest := &entities.AddInventoryByExcelRequest{SellerID: sellerID}
+	listingRows := make(map[string]int)
+	for i, row := range rows[1:] {
+		rowNumber := i + 2 // 1-based, after the header row
+		if isBlankRow(row) {
+			continue
+		}
+		response.TotalRows++
+
+		var rowErrors []*entities.InventorySheetRowError
+		addError := func(column, message string) {
+			rowErrors = append(rowErrors, &entities.InventorySheetRowError{Row: rowNumber, Column: column, Message: message})
+		}
+
+		var listing *entities.BulkInventoryTarget
+		listingId := cell(row, entities.InventorySheetInventoryProductID)
+		if listingId != "" {
+			listing = listings[listingId]
+			if listing == nil {
+				addError(entities.InventorySheetInventoryProductID, "inventory product not found in your inventory")
+			} else if firstRow, ok := listingRows[listingId]; ok {
+				addError(entities.InventorySheetInventoryProductID, fmt.Sprintf("inventory product is already updated by row %d", firstRow))
+			} else {
+				listingRows[listingId] = rowNumber
+			}
+		}
+
+		var m *entities.Metadata
+		id := cell(row, entities.InventorySheetMetadataID)
+		if id == "" && listing != nil {
+			id = listing.MetadataProductID
+		}
+		hsn := cell(row, entities.InventorySheetHSNCode)
+		switch {
+		case id != "":
+			m = metadataById[id]
+			if m == nil {
+				addError(entities.InventorySheetMetadataID, "metadata not found")
+			} else if hsn != "" && hsn != m.MetadataHSNCode {
+				addError(entities.InventorySheetHSNCode, "hsn code does not match the metadata")
+			}
+		case hsn != "":
+			switch matches := metadataByHsn[hsn]; len(matches) {
+			case 0:
+				addError(entities.InventorySheetHSNCode, "no metadata found for hsn code")
+			case 1:
+				m = matches[0]
+			default:
+				addError(entities.InventorySheetMetadataID, fmt.Sprintf("hsn code is shared by %d products, metadata id is required", len(matches)))
+			}
+		default:
+			addError(entities.InventorySheetMetadataID, "metadata id or hsn code is required")
+		}
+		if m != nil && listing != nil && m.MetadataProductID != listing.MetadataProductID {
+			addError(entities.InventorySheetMetadataID, "metadata id does not match the inventory product")
+		}
+
+		quantity, err := strconv.ParseFloat(cell(row, entities.InventorySheetQuantity), 64)
+		if err != nil || quantity != math.Trunc(quantity) {
+			addError(entities.InventorySheetQuantity, "quantity must be a whole number")
+		} else if quantity < 0 {
+			addError(entities.InventorySheetQuantity, "quantity cannot be negative")
+		}
+
+		price, err := strconv.ParseFloat(cell(row, entities.InventorySheetPrice), 64)
+		if err != nil {
+			addError(entities.InventorySheetPrice, "price must be a number")
+		} else if price <= 0 {
+			addError(entities.InventorySheetPrice, "price must be greater than zero")
+		} else if m != nil && price > m.MetadataMRP {
+			addError(entities.InventorySheetPrice, fmt.Sprintf("price %.2f exceeds mrp %.2f", price, m.MetadataMRP))
+		}
+
+		expiryDate, expiryErr := utils.ParseSpreadsheetDate(cell(row, entities.InventorySheetExpiryDate))
+		if expiryErr != nil {
+			addError(entities.InventorySheetExpiryDate, expiryErr.Error())
+		}
+		manufacturingDate, manufacturingErr := utils.ParseSpreadsheetDate(cell(row, entities.InventorySheetManufacturingDate))
+		if manufacturingErr != nil {
+			addError(entities.InventorySheetManufacturingDate, manufacturingErr.Error())
+		}
+		if expiryErr == nil && manufacturingErr == nil && manufacturingDate.After(expiryDate) {
+			addError(entities.InventorySheetManufacturingDate, "manufacturing date is after expiry date")
+		}
+
+		if len(rowErrors) > 0 {
+			response.InvalidRows++
+			response.Errors = append(response.Errors, rowErrors...)
+			continue
+		}
+
+		response.ValidRows++
+		parsed := &entities.InventorySheetRow{
+			Row:                      rowNumber,
+			InventoryProductID:       listingId,
+			MetadataProductID:        m.MetadataProductID,
+			MetadataName:             m.MetadataName,
+			MetadataHSNCode:          m.MetadataHSNCode,
+			MetadataMRP:              m.MetadataMRP,
+			ProductQuantity:          int(quantity),
+			ProductPrice:             price,
+			ProductExpiryDate:        expiryDate.Format(inventorySheetDateLayout),
+			ProductManufacturingDate: manufacturingDate.Format(inventorySheetDateLayout),
+		}
+		response.Rows = append(response.Rows, parsed)
+		inventoryRequest.MetadataProducts = append(inventoryRequest.MetadataProducts, entities.AddInventoryByExcelProduct{
+			InventoryProductID:       parsed.InventoryProductID,
+			ProductMetadataId:        parsed.MetadataProductID,
+			ProductQuantity:          parsed.ProductQuantity,
+			ProductPrice:             parsed.ProductPrice,
+			ProductExpiryDate:        parsed.ProductExpiryDate,
+			ProductManufacturingDate: parsed.ProductManufacturingDate,
+		})
+	}
+
+	if response.TotalRows == 0 {
+		return nil, errors.New("sheet has no data rows")
+	}
+
+	// Rows priced outside the guardrails are invalid, or wait for approval at their current price
+	priceErrors, priceChanges, err := u.checkSheetPrices(ctx, sellerID, inventoryRequest.MetadataProducts)
+	if err != nil {
+		return nil, err
+	}
+	for i, product := range inventoryRequest.MetadataProducts {
+		if _, ok := priceErrors[i]; !ok && product.ProductPrice != response.Rows[i].ProductPrice {
+			response.Rows[i].PendingApproval = true
+		}
+	}
+	if len(priceErrors) > 0 {
+		validRows := response.Rows[:0]
+		for i, row := range response.Rows {
+			message, ok := priceErrors[i]
+			if !ok {
+				validRows = append(validRows, row)
+				continue
+			}
+			response.ValidRows--
+			response.InvalidRows++
+			response.Errors = append(response.Errors, &entities.InventorySheetRowError{Row: row.Row, Column: entities.InventorySheetPrice, Message: message})
+		}
+		response.Rows = validRows
+		slices.SortStableFunc(response.Errors, func(a, b *entities.InventorySheetRowError) int { return a.Row - b.Row })
+	}
+	if dryRun || response.InvalidRows > 0 {
+		return response, nil
+	}
+
+	if _, err := u.inventoryRepo.AddInventoryByExcel(ctx, inventoryRequest, priceChanges); err != nil {
+		return nil, err
+	}
+	response.Committed = true
+	return response, nil
+}
+
+// inventorySheetDateLayout is the date layout AddInventoryByExcel stores rows with
+const inventorySheetDateLayout = "02-01-2006"
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ExportInventorySheet renders the seller's inventory in the same column layout UploadInventorySheet accepts
+func (u *InventoryUseCaseInterface) ExportInventorySheet(ctx context.Context, sellerID, format, search, sort string, filter *entities.InventoryListFilter) ([]byte, error) {
+	if format != "xlsx" && format != "csv" {
+		return nil, fmt.Errorf("%w: format must be xlsx or csv", ErrInvalidInventoryListing)
+	}
+	if err := validateInventoryListing(sort, filter); err != nil {
+		return nil, err
+	}
+	inventory, err := u.inventoryRepo.GetAllInventoryForExport(ctx, sellerID, search, sort, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(entities.InventorySheetColumns))
+	for i, column := range entities.InventorySheetColumns {
+		header[i] = column
+	}
+	rows := [][]interface{}{header}
+	for _, item := range inventory {
+		rows = append(rows, []interface{}{
+			item.InventoryProductId,
+			item.MetadataProductId,
+			item.MetadataName,
+			item.MetadataHSNCode,
+			item.MetadataMrp,
+			item.ProductQuantity,
+			item.ProductPrice,
+			formatSheetDate(item.ProductExpiryDate),
+			formatSheetDate(item.ProductManufacturingDate),
+		})
+	}
+
+	var buffer bytes.Buffer
+	if err := utils.WriteSpreadsheet(&buffer, format, rows); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// formatSheetDate converts the listing's "2006-01-02 15:04:05" dates to the sheet's DD-MM-YYYY layout
+func formatSheetDate(value string) string {
+	t, err := time.Parse("2006-01-02 15:04:05", value)
+	if err != nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(inventorySheetDateLayout)
+}
+
+// BulkUpdateInventory applies explicit per-product changes, or one operation to every product matching a filter.
+// Invalid items are reported per item; with AllOrNothing a single invalid item cancels the whole batch.
+func (u *InventoryUseCaseInterface) BulkUpdateInventory(ctx context.Context, request *entities.BulkInventoryRequest) (*entities.BulkInventoryResponse, error) {
+	hasChanges := len(request.Changes) > 0
+	hasOperation := request.Operation != nil
+	if hasChanges == hasOperation {
+		return nil, errors.New("provide either a list of changes or a filter with an operation")
+	}
+	if hasOperation && request.Filter == nil {
+		return nil, errors.New("filter is required with an operation")
+	}
+
+	var ids []string
+	if hasChanges {
+		// a product listed twice would have its second change computed from values the first one replaced
+		ids = make([]string, 0, len(request.Changes))
+		seen := make(map[string]bool, len(request.Changes))
+		for _, change := range request.Changes {
+			if seen[change.InventoryProductID] {
+				return nil, fmt.Errorf("inventory product %s is listed more than once", change.InventoryProductID)
+			}
+			seen[change.InventoryProductID] = true
+			ids = append(ids, change.InventoryProductID)
+		}
+	}
+	targets, err := u.inventoryRepo.GetBulkInventoryTargets(ctx, request.SellerID, ids, request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	targetsById := make(map[string]*entities.BulkInventoryTarget, len(targets))
+	for _, target := range targets {
+		targetsById[target.InventoryProductID] = target
+	}
+
+	response := &entities.BulkInventoryResponse{}
+	var valid []*entities.BulkInventoryItemResult
+	apply := func(target *entities.BulkInventoryTarget, result *entities.BulkInventoryItemResult) {
+		result.MetadataName = target.MetadataName
+		// Only changed values are validated, so restocking an unpriced product still works
+		priceChanged := result.ProductPrice != target.ProductPrice
+		switch {
+		case priceChanged && result.ProductPrice <= 0:
+			result.Error = "price must be greater than zero"
+		case priceChanged && result.ProductPrice > target.MetadataMRP:
+			result.Error = fmt.Sprintf("price %.2f exceeds mrp %.2f", result.ProductPrice, target.MetadataMRP)
+		case result.ProductQuantity != target.ProductQuantity && result.ProductQuantity < 0:
+			result.Error = "quantity cannot be negative"
+		case result.ProductVisibility && !target.ProductVisibility:
+			result.Error = "products go live through the inventory review, bulk updates can only hide them"
+		default:
+			result.Success = true
+			valid = append(valid, result)
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if hasChanges {
+		for _, change := range request.Changes {
+			target, ok := targetsById[change.InventoryProductID]
+			if !ok {
+				message := "product not found in your inventory"
+				if !primitive.IsValidObjectID(change.InventoryProductID) {
+					message = "invalid inventory product id"
+				}
+				response.Results = append(response.Results, &entities.BulkInventoryItemResult{
+					InventoryProductID: change.InventoryProductID,
+					Error:              message,
+				})
+				continue
+			}
+			result := newBulkInventoryItemResult(target)
+			if change.ProductPrice != nil {
+				result.ProductPrice = *change.ProductPrice
+			}
+			if change.ProductQuantity != nil {
+				result.ProductQuantity = *change.ProductQuantity
+			}
+			if change.ProductVisibility != nil {
+				result.ProductVisibility = *change.ProductVisibility
+			}
+			apply(target, result)
+		}
+	} else {
+		for _, target := range targets {
+			result := newBulkInventoryItemResult(target)
+			if err := applyBulkOperation(request.Operation, target, result); err != nil {
+				return nil, err
+			}
+			apply(target, result)
+		}
+	}
+
+	// Price changes that pass the hard limits are checked against the configurable guardrails
+	var priceChangedIds []string
+	for _, result := range valid {
+		if result.ProductPrice != result.PreviousPrice {
+			priceChangedIds = append(priceChangedIds, result.InventoryProductID)
+		}
+	}
+	pricingContexts := make(map[string]*entities.PricingContext)
+	if len(priceChangedIds) > 0 {
+		contexts, err := u.inventoryRepo.GetPricingContexts(ctx, priceChangedIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, pricingContext := range contexts {
+			pricingContexts[pricingContext.InventoryProductID] = pricingContext
+		}
+	}
+	var updates []*entities.BulkInventoryItemResult
+	var priceChanges []*entities.PriceChangeRequest
+	for _, result := range valid {
+		pricingContext, ok := pricingContexts[result.InventoryProductID]
+		if ok {
+			violations, err := checkPriceGuardrails(pricingContext, result.ProductPrice)
+			if err == nil && len(violations) > 0 {
+				if pricingContext.Guardrail.ViolationAction != entities.PriceViolationReview {
+					result.Success = false
+					result.Error = strings.Join(violations, "; ")
+					continue
+				}
+				priceChanges = append(priceChanges, newPriceChangeRequest(pricingContext, result.ProductPrice, violations, request.SellerID))
+				result.ProductPrice = result.PreviousPrice
+				result.PendingApproval = true
+			}
+		}
+		updates = append(updates, result)
+	}
+
+	response.Matched = len(response.Results)
+	response.Failed = response.Matched - len(updates)
+	if request.AllOrNothing && response.Failed > 0 {
+		for _, result := range updates {
+			result.Success = false
+			result.Error = "not applied, another item in the batch failed"
+		}
+		response.Failed = response.Matched
+		return response, nil
+	}
+
+	// Items the repository could not write are marked failed, their price changes are not queued either
+	if err := u.inventoryRepo.BulkUpdateInventory(ctx, request.SellerID, updates, priceChanges, request.AllOrNothing); err != nil {
+		return nil, err
+	}
+	for _, result := range updates {
+		if result.Success {
+			response.Updated++
+		}
+	}
+	response.Failed = response.Matched - response.Updated
+	response.Committed = response.Updated > 0
+	return response, nil
+}
+
+// applyBulkOperation sets the values the operation gives the target on its result. Adjusted prices are rounded
+// to the paisa.
+func applyBulkOperation(operation *entities.BulkInventoryOperation, target *entities.BulkInventoryTarget, result *entities.BulkInventoryItemResult) error {
+	switch operation.Type {
+	case entities.BulkOperationSetPrice:
+		result.ProductPrice = operation.Value
+	case entities.BulkOperationAdjustPricePercent:
+		result.ProductPrice = math.Round(target.ProductPrice*(100+operation.Value)) / 100
+	case entities.BulkOperationAdjustPriceAmount:
+		result.ProductPrice = math.Round((target.ProductPrice+operation.Value)*100) / 100
+	case entities.BulkOperationSetQuantity:
+		result.ProductQuantity = int(operation.Value)
+	case entities.BulkOperationAdjustQuantity:
+		result.ProductQuantity = target.ProductQuantity + int(operation.Value)
+	case entities.BulkOperationSetVisibility:
+		result.ProductVisibility = operation.Visibility
+	default:
+		return fmt.Errorf("unknown operation type %q", operation.Type)
+	}
+	return nil
+}
+
+func newBulkInventoryItemResult(target *entities.BulkInventoryTarget) *entities.BulkInventoryItemResult {
+	return &entities.BulkInventoryItemResult{
+		InventoryProductID: target.InventoryProductID,
+		ProductPrice:       target.ProductPrice,
+		ProductQuantity:    target.ProductQuantity,
+		PreviousPrice:      target.ProductPrice,
+		ProductVisibility:  target.ProductVisibility,
+		PreviousQuantity:   target.ProductQuantity,
+		PreviousVisibility: target.ProductVisibility,
+		MetadataProductID:  target.MetadataProductID,
+	}
+}
@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeInventoryRepository serves pricing contexts and bulk targets from memory and counts the bulk updates written,
+// the methods a test does not use are left unimplemented
+type fakeInventoryRepository struct {
+	repositories.InventoryRepository
+	pricingContexts []*entities.PricingContext
+	bulkTargets     []*entities.BulkInventoryTarget
+	bulkWritten     int
+}
+
+func (r *fakeInventoryRepository) GetBulkInventoryTargets(ctx context.Context, sellerId string, inventoryProductIds []string, filter *entities.BulkInventoryFilter) ([]*entities.BulkInventoryTarget, error) {
+	if inventoryProductIds == nil {
+		return r.bulkTargets, nil
+	}
+	var targets []*entities.BulkInventoryTarget
+	for _, target := range r.bulkTargets {
+		if slices.Contains(inventoryProductIds, target.InventoryProductID) {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+func (r *fakeInventoryRepository) BulkUpdateInventory(ctx context.Context, sellerId string, updates []*entities.BulkInventoryItemResult, priceChanges []*entities.PriceChangeRequest, atomic bool) error {
+	r.bulkWritten += len(updates)
+	return nil
+}
+
+func (r *fakeInventoryRepository) GetPricingContexts(ctx context.Context, inventoryProductIds []string) ([]*entities.PricingContext, error) {
+	var contexts []*entities.PricingContext
+	for _, pricingContext := range r.pricingContexts {
+		if pricingContext.InventoryProductID != "" && slices.Contains(inventoryProductIds, pricingContext.InventoryProductID) {
+			contexts = append(contexts, pricingContext)
+		}
+	}
+	return contexts, nil
+}
+
+func (r *fakeInventoryRepository) GetMetadataPricingContexts(ctx context.Context, metadataIds []string) ([]*entities.PricingContext, error) {
+	var contexts []*entities.PricingContext
+	for _, pricingContext := range r.pricingContexts {
+		if pricingContext.InventoryProductID == "" && slices.Contains(metadataIds, pricingContext.MetadataProductID) {
+			contexts = append(contexts, pricingContext)
+		}
+	}
+	return contexts, nil
+}
+
+func TestCheckSheetPrices(t *testing.T) {
+	review := &entities.PricingGuardrail{MaxDailyChangePercent: 10, MinPricePercentOfMRP: 50, ViolationAction: entities.PriceViolationReview}
+	reject := &entities.PricingGuardrail{MaxDailyChangePercent: 10, MinPricePercentOfMRP: 50, ViolationAction: entities.PriceViolationReject}
+	repo := &fakeInventoryRepository{pricingContexts: []*entities.PricingContext{
+		{InventoryProductID: "listing-review", SellerID: "seller", MetadataProductID: "metadata", MetadataMRP: 100, CurrentPrice: 80, DayStartPrice: 80, Guardrail: review},
+		{InventoryProductID: "listing-reject", SellerID: "seller", MetadataProductID: "metadata", MetadataMRP: 100, CurrentPrice: 80, DayStartPrice: 80, Guardrail: reject},
+		{InventoryProductID: "listing-other", SellerID: "other", MetadataProductID: "metadata", MetadataMRP: 100, CurrentPrice: 80, DayStartPrice: 80, Guardrail: review},
+		{MetadataProductID: "metadata", MetadataMRP: 100, Guardrail: review},
+	}}
+	u := NewInventoryUseCase(repo)
+
+	tests := []struct {
+		name      string
+		product   entities.AddInventoryByExcelProduct
+		wantError string
+		queued    bool
+		wantPrice float64
+	}{
+		{name: "listing keeps its price", product: entities.AddInventoryByExcelProduct{InventoryProductID: "listing-review", ProductPrice: 80}, wantPrice: 80},
+		{name: "listing within the guardrails", product: entities.AddInventoryByExcelProduct{InventoryProductID: "listing-review", ProductPrice: 85}, wantPrice: 85},
+		{name: "listing above mrp", product: entities.AddInventoryByExcelProduct{InventoryProductID: "listing-review", ProductPrice: 120}, wantError: "exceeds mrp"},
+		{name: "listing breaking a reviewed guardrail waits at its price", product: entities.AddInventoryByExcelProduct{InventoryProductID: "listing-review", ProductPrice: 60}, queued: true, wantPrice: 80},
+		{name: "listing breaking a rejecting guardrail", product: entities.AddInventoryByExcelProduct{InventoryProductID: "listing-reject", ProductPrice: 60}, wantError: "price changes by"},
+		{name: "listing of another seller", product: entities.AddInventoryByExcelProduct{InventoryProductID: "listing-other", ProductPrice: 85}, wantError: "not found"},
+		{name: "unknown listing", product: entities.AddInventoryByExcelProduct{InventoryProductID: "missing", ProductPrice: 85}, wantError: "not found"},
+		{name: "new batch above the floor", product: entities.AddInventoryByExcelProduct{ProductMetadataId: "metadata", ProductPrice: 55}, wantPrice: 55},
+		{name: "new batch below the floor has nothing to queue on", product: entities.AddInventoryByExcelProduct{ProductMetadataId: "metadata", ProductPrice: 40}, wantError: "below the floor"},
+		{name: "unknown metadata", product: entities.AddInventoryByExcelProduct{ProductMetadataId: "missing", ProductPrice: 55}, wantError: "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := []entities.AddInventoryByExcelProduct{tt.product}
+			priceErrors, priceChanges, err := u.checkSheetPrices(context.Background(), "seller", products)
+			if err != nil {
+				t.Fatalf("checkSheetPrices failed: %v", err)
+			}
+			if tt.wantError != "" {
+				if !strings.Contains(priceErrors[0], tt.wantError) {
+					t.Errorf("checkSheetPrices error = %q, want it to mention %q", priceErrors[0], tt.wantError)
+				}
+				return
+			}
+			if message, ok := priceErrors[0]; ok {
+				t.Fatalf("checkSheetPrices rejected the price: %s", message)
+			}
+			if queued := len(priceChanges) == 1; queued != tt.queued {
+				t.Errorf("checkSheetPrices queued %d price changes, want queued %v", len(priceChanges), tt.queued)
+			}
+			if tt.queued && priceChanges[0].RequestedPrice != tt.product.ProductPrice {
+				t.Errorf("queued price = %.2f, want %.2f", priceChanges[0].RequestedPrice, tt.product.ProductPrice)
+			}
+			if products[0].ProductPrice != tt.wantPrice {
+				t.Errorf("sheet price = %.2f, want %.2f", products[0].ProductPrice, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestValidateInventoryListing(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	day := func(d int) *time.Time {
+		date := time.Date(2026, time.January, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	tests := []struct {
+		name   string
+		sort   string
+		filter *entities.InventoryListFilter
+		ok     bool
+	}{
+		{name: "default sort", ok: true},
+		{name: "legacy price sort", sort: "desc", ok: true},
+		{name: "legacy mrp sort", sort: "mrp_asc", ok: true},
+		{name: "field sort", sort: "name", ok: true},
+		{name: "multi-field sort with descending fields", sort: "-price, quantity,-expiry_date", ok: true},
+		{name: "unknown sort field", sort: "name,colour"},
+		{name: "empty field in a sort list", sort: "name,"},
+		{name: "quantity range in order", filter: &entities.InventoryListFilter{MinQuantity: intPtr(1), MaxQuantity: intPtr(1)}, ok: true},
+		{name: "quantity range reversed", filter: &entities.InventoryListFilter{MinQuantity: intPtr(5), MaxQuantity: intPtr(1)}},
+		{name: "open price range", filter: &entities.InventoryListFilter{MinPrice: floatPtr(10)}, ok: true},
+		{name: "price range reversed", filter: &entities.InventoryListFilter{MinPrice: floatPtr(10), MaxPrice: floatPtr(9.99)}},
+		{name: "expiry range in order", filter: &entities.InventoryListFilter{ExpiryFrom: day(1), ExpiryTo: day(31)}, ok: true},
+		{name: "expiry range reversed", filter: &entities.InventoryListFilter{ExpiryFrom: day(2), ExpiryTo: day(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateInventoryListing(tt.sort, tt.filter)
+			if tt.ok && err != nil {
+				t.Fatalf("validateInventoryListing(%q) failed: %v", tt.sort, err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidInventoryListing) {
+				t.Fatalf("validateInventoryListing(%q) error = %v, want an invalid listing error", tt.sort, err)
+			}
+		})
+	}
+}
+
+func TestApplyBulkOperation(t *testing.T) {
+	target := &entities.BulkInventoryTarget{ProductPrice: 19.99, ProductQuantity: 10, ProductVisibility: true}
+	tests := []struct {
+		name       string
+		operation  entities.BulkInventoryOperation
+		price      float64
+		quantity   int
+		visibility bool
+		err        bool
+	}{
+		{name: "set price", operation: entities.BulkInventoryOperation{Type: entities.BulkOperationSetPrice, Value: 25}, price: 25, quantity: 10, visibility: true},
+		{name: "raise by percent rounds to the paisa", operation: entities.BulkInventoryOperation{Type: entities.BulkOperationAdjustPricePercent, Value: 10}, price: 21.99, quantity: 10, visibility: true},
+		{name: "cut by percent rounds to the paisa", operation: entities.BulkInventoryOperation{Type: entities.BulkOperationAdjustPricePercent, Value: -15}, price: 16.99, quantity: 10, visibility: true},
+		{name: "cut by the whole price", operation: entities.BulkInventoryOperation{Type: entities.BulkOperationAdjustPricePercent, Value: -100}, price: 0, quantity: 10, visibility: true},
+		{name: "adjust by amount rounds to the paisa", operation: entities.BulkInventoryOperation{Type: entities.BulkOperationAdjustPriceAmount, Value: 0.02}, price: 20.01, quantity: 10, visibility: true},
+		{name: "set quantity", operation: entities.BulkInventoryOperation{Type: entities.BulkOperationSetQuantity, Value: 3}, price: 19.99, quantity: 3, visibility: true},
+		{name: "adjust quantity", operation: entities.BulkInventoryOperation{Type: entities.BulkOperationAdjustQuantity, Value: -4}, price: 19.99, quantity: 6, visibility: true},
+		{name: "hide", operation: entities.BulkInventoryOperation{Type: entities.BulkOperationSetVisibility}, price: 19.99, quantity: 10},
+		{name: "unknown operation", operation: entities.BulkInventoryOperation{Type: "double_price"}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newBulkInventoryItemResult(target)
+			err := applyBulkOperation(&tt.operation, target, result)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("applyBulkOperation(%q) accepted an unknown operation", tt.operation.Type)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyBulkOperation(%q) failed: %v", tt.operation.Type, err)
+			}
+			if result.ProductPrice != tt.price || result.ProductQuantity != tt.quantity || result.ProductVisibility != tt.visibility {
+				t.Errorf("applyBulkOperation(%q) = %.2f, %d, %v, want %.2f, %d, %v", tt.operation.Type,
+					result.ProductPrice, result.ProductQuantity, result.ProductVisibility, tt.price, tt.quantity, tt.visibility)
+			}
+		})
+	}
+}
+
+func TestBulkUpdateInventory(t *testing.T) {
+	floatPtr := func(v float64) *float64 { return &v }
+	intPtr := func(v int) *int { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+	targets := []*entities.BulkInventoryTarget{
+		{InventoryProductID: "000000000000000000000001", MetadataMRP: 100, ProductPrice: 80, ProductQuantity: 5, ProductVisibility: true},
+		{InventoryProductID: "000000000000000000000002", MetadataMRP: 50, ProductPrice: 40, ProductQuantity: 2},
+	}
+	tests := []struct {
+		name         string
+		changes      []*entities.BulkInventoryChange
+		allOrNothing bool
+		err          bool
+		updated      int
+		failed       int
+	}{
+		{
+			name: "valid changes apply",
+			changes: []*entities.BulkInventoryChange{
+				{InventoryProductID: "000000000000000000000001", ProductQuantity: intPtr(7), ProductVisibility: boolPtr(false)},
+				{InventoryProductID: "000000000000000000000002", ProductPrice: floatPtr(45)},
+			},
+			updated: 2,
+		},
+		{
+			name: "invalid items fail on their own",
+			changes: []*entities.BulkInventoryChange{
+				{InventoryProductID: "000000000000000000000001", ProductQuantity: intPtr(7)},
+				{InventoryProductID: "000000000000000000000002", ProductPrice: floatPtr(60)},
+				{InventoryProductID: "000000000000000000000003", ProductQuantity: intPtr(1)},
+				{InventoryProductID: "not-an-id", ProductQuantity: intPtr(1)},
+			},
+			updated: 1, failed: 3,
+		},
+		{
+			name: "one invalid item cancels an all-or-nothing batch",
+			changes: []*entities.BulkInventoryChange{
+				{InventoryProductID: "000000000000000000000001", ProductQuantity: intPtr(7)},
+				{InventoryProductID: "000000000000000000000002", ProductQuantity: intPtr(-1)},
+			},
+			allOrNothing: true, failed: 2,
+		},
+		{
+			name: "publishing is left to the review",
+			changes: []*entities.BulkInventoryChange{
+				{InventoryProductID: "000000000000000000000002", ProductVisibility: boolPtr(true)},
+			},
+			failed: 1,
+		},
+		{
+			name: "a product listed twice",
+			changes: []*entities.BulkInventoryChange{
+				{InventoryProductID: "000000000000000000000001", ProductQuantity: intPtr(7)},
+				{InventoryProductID: "000000000000000000000001", ProductQuantity: intPtr(8)},
+			},
+			err: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInventoryRepository{bulkTargets: targets}
+			u := NewInventoryUseCase(repo)
+			response, err := u.BulkUpdateInventory(context.Background(), &entities.BulkInventoryRequest{
+				SellerID:     "seller",
+				Changes:      tt.changes,
+				AllOrNothing: tt.allOrNothing,
+			})
+			if tt.err {
+				if err == nil {
+					t.Fatal("BulkUpdateInventory accepted the request")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BulkUpdateInventory failed: %v", err)
+			}
+			if response.Updated != tt.updated || response.Failed != tt.failed {
+				t.Errorf("BulkUpdateInventory updated %d and failed %d, want %d and %d", response.Updated, response.Failed, tt.updated, tt.failed)
+			}
+			if repo.bulkWritten != tt.updated {
+				t.Errorf("BulkUpdateInventory wrote %d updates, want %d", repo.bulkWritten, tt.updated)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/utils"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MediaUseCase struct {
+	mediaRepo repositories.MediaRepository
+	blobStore repositories.BlobStore
+}
+
+func NewMediaUseCase(mediaRepo repositories.MediaRepository, blobStore repositories.BlobStore) *MediaUseCase {
+	return &MediaUseCase{
+		mediaRepo: mediaRepo,
+		blobStore: blobStore,
+	}
+}
+
+// checkMediaAccess lets operations users manage every image and sellers manage the images of their own stores
+func (u *MediaUseCase) checkMediaAccess(ctx context.Context, ownerType, ownerId, userId, role string) error {
+	sellerId, err := u.mediaRepo.GetMediaOwnerSeller(ctx, ownerType, ownerId)
+	if err != nil {
+		return err
+	}
+	switch role {
+	case "operations":
+		return nil
+	case "seller":
+		if ownerType == entities.MediaOwnerStore && sellerId == userId {
+			return nil
+		}
+		return errors.New("sellers can only manage images of their own stores")
+	default:
+		return errors.New("user role is not allowed to manage images")
+	}
+}
+
+// renderImage produces the stored sizes of an uploaded image
+func renderImage(data []byte, img image.Image, contentType string) (map[string]*utils.ImageRendition, error) {
+	web, err := utils.EncodeWebImage(utils.ResizeImage(img, entities.MediaWebMaxSide))
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := utils.EncodeWebImage(utils.ResizeImage(img, entities.MediaThumbnailMaxSide))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]*utils.ImageRendition{
+		entities.MediaSizeOriginal:  utils.OriginalRendition(data, img, contentType),
+		entities.MediaSizeWeb:       web,
+		entities.MediaSizeThumbnail: thumbnail,
+	}, nil
+}
+
+// deleteBlobs removes stored renditions, logging failures since the image record is already gone or never existed
+func (u *MediaUseCase) deleteBlobs(ctx context.Context, renditions map[string]*entities.MediaRendition) {
+	for _, rendition := range renditions {
+		if err := u.blobStore.Delete(ctx, rendition.Key); err != nil {
+			log.Printf("failed to delete media blob %s: %v", rendition.Key, err)
+		}
+	}
+}
+
+// UploadMedia validates an image, stores it in every size and attaches it to its owner
+func (u *MediaUseCase) UploadMedia(ctx context.Context, request *entities.UploadMediaRequest) (*entities.Media, error) {
+	if err := u.checkMediaAccess(ctx, request.OwnerType, request.OwnerID, request.UploadedBy, request.UploaderRole); err != nil {
+		return nil, err
+	}
+	img, contentType, err := utils.DecodeUploadedImage(request.Data)
+	if err != nil {
+		return nil, err
+	}
+	images, err := renderImage(request.Data, img, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process image: %w", err)
+	}
+
+	prefix := "media/" + primitive.NewObjectID().Hex()
+	renditions := make(map[string]*entities.MediaRendition, len(images))
+	for size, rendition := range images {
+		key := fmt.Sprintf("%s/%s.%s", prefix, size, rendition.Extension)
+		if err := u.blobStore.Put(ctx, key, rendition.Data, rendition.ContentType); err != nil {
+			u.deleteBlobs(ctx, renditions)
+			return nil, fmt.Errorf("failed to store image: %w", err)
+		}
+		renditions[size] = &entities.MediaRendition{
+			Key:         key,
+			ContentType: rendition.ContentType,
+			Width:       rendition.Width,
+			Height:      rendition.Height,
+			Bytes:       len(rendition.Data),
+		}
+	}
+
+	media, replaced, err := u.mediaRepo.CreateMedia(ctx, &entities.Media{
+		OwnerType:  request.OwnerType,
+		OwnerID:    request.OwnerID,
+		FileName:   request.FileName,
+		Renditions: renditions,
+		UploadedBy: request.UploadedBy,
+		CreatedAt:  time.Now(),
+	}, request.Primary)
+	if err != nil {
+		u.deleteBlobs(ctx, renditions)
+		return nil, err
+	}
+	for _, previous := range replaced {
+		u.deleteBlobs(ctx, previous.Renditions)
+	}
+	return media, nil
+}
+
+// GetMediaFile opens one size of an image, the web size by default
+func (u *MediaUseCase) GetMediaFile(ctx context.Context, mediaId, size string) (io.ReadCloser, *entities.MediaRendition, error) {
+	if size == "" {
+		size = entities.MediaSizeWeb
+	}
+	media, err := u.mediaRepo.GetMediaById(ctx, mediaId)
+	if err != nil {
+		return nil, nil, err
+	}
+	rendition, ok := media.Renditions[size]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid size %s, use original, web or thumbnail", size)
+	}
+	file, err := u.blobStore.Get(ctx, rendition.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, rendition, nil
+}
+
+func (u *MediaUseCase) GetGallery(ctx context.Context, ownerType, ownerId string) ([]*entities.Media, error) {
+	if ownerType == "" || ownerId == "" {
+		return nil, errors.New("owner_type and owner_id are required")
+	}
+	return u.mediaRepo.GetMediaForOwner(ctx, ownerType, ownerId)
+}
+
+func (u *MediaUseCase) SetPrimaryMedia(ctx context.Context, mediaId, userId, role string) (*entities.Media, error) {
+	media, err := u.mediaRepo.GetMediaById(ctx, mediaId)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkMediaAccess(ctx, media.OwnerType, media.OwnerID, userId, role); err != nil {
+		return nil, err
+	}
+	if err := u.mediaRepo.SetPrimaryMedia(ctx, media, userId); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+func (u *MediaUseCase) DeleteMedia(ctx context.Context, mediaId, userId, role string) error {
+	media, err := u.mediaRepo.GetMediaById(ctx, mediaId)
+	if err != nil {
+		return err
+	}
+	if err := u.checkMediaAccess(ctx, media.OwnerType, media.OwnerID, userId, role); err != nil {
+		return err
+	}
+	if err := u.mediaRepo.DeleteMedia(ctx, media, userId); err != nil {
+		return err
+	}
+	u.deleteBlobs(ctx, media.Renditions)
+	return nil
+}
@@ -0,0 +1,458 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"espazeBackend/utils"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxMetadataImportRows = 5000
+	// the job saves its progress every this many rows
+	metadataImportBatch = 100
+	// a queued or running job not saved for this long was lost with the server running it
+	metadataImportStaleAfter = 10 * time.Minute
+)
+
+var hsnCodePattern = regexp.MustCompile(`^\d{4}(\d{2}){0,2}$`)
+
+type MetadataImportUseCase struct {
+	metadataImportRepo repositories.MetadataImportRepository
+	metadataRepo       repositories.MetadataRepository
+}
+
+func NewMetadataImportUseCase(metadataImportRepo repositories.MetadataImportRepository, metadataRepo repositories.MetadataRepository) *MetadataImportUseCase {
+	return &MetadataImportUseCase{
+		metadataImportRepo: metadataImportRepo,
+		metadataRepo:       metadataRepo,
+	}
+}
+
+// validateHSNCode accepts the 4, 6 and 8 digit HSN codes
+func validateHSNCode(hsnCode string) error {
+	if !hsnCodePattern.MatchString(hsnCode) {
+		return errors.New("hsn code must be 4, 6 or 8 digits")
+	}
+	return nil
+}
+
+// StartImport reads an .xlsx or .csv metadata sheet and imports it in the background. The returned job is queued,
+// its progress and row errors are read back with GetImportJob.
+func (u *MetadataImportUseCase) StartImport(ctx context.Context, operationalId, fileName string, file io.Reader, mode string) (*entities.MetadataImportJob, error) {
+	if mode == "" {
+		mode = entities.MetadataImportCreate
+	}
+	if mode != entities.MetadataImportCreate && mode != entities.MetadataImportUpsert {
+		return nil, errors.New("mode must be create or upsert")
+	}
+
+	rows, err := utils.ReadSpreadsheet(fileName, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("sheet has no data rows")
+	}
+	if len(rows)-1 > maxMetadataImportRows {
+		return nil, fmt.Errorf("sheet has more than %d rows", maxMetadataImportRows)
+	}
+
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.ToLower(header)] = i
+	}
+	for _, required := range []string{entities.MetadataSheetName, entities.MetadataSheetHSNCode, entities.MetadataSheetDescription, entities.MetadataSheetCategory, entities.MetadataSheetSubcategory, entities.MetadataSheetMRP} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("sheet is missing the %s column", required)
+		}
+	}
+
+	totalRows := 0
+	for _, row := range rows[1:] {
+		if !isBlankRow(row) {
+			totalRows++
+		}
+	}
+
+	job, err := u.metadataImportRepo.CreateImportJob(ctx, &entities.MetadataImportJob{
+		FileName:  fileName,
+		Mode:      mode,
+		Status:    entities.MetadataImportQueued,
+		TotalRows: totalRows,
+		Errors:    []*entities.MetadataImportRowError{},
+		CreatedBy: operationalId,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the request context ends with the response, the job outlives it
+	go u.runImport(context.Background(), job, columns, rows[1:])
+	return job, nil
+}
+
+// runImport validates and imports every row, saving the job as it goes. A row that fails is reported and skipped,
+// the rest of the sheet is still imported.
+func (u *MetadataImportUseCase) runImport(ctx context.Context, job *entities.MetadataImportJob, columns map[string]int, rows [][]string) {
+	// nothing else would recover a panic in the job, which would take the server down and leave the job running
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("metadata import job %s panicked: %v\n%s", job.ID, r, debug.Stack())
+			job.Status = entities.MetadataImportFailed
+			job.Failure = fmt.Sprintf("import stopped unexpectedly: %v", r)
+			finishedAt := time.Now()
+			job.FinishedAt = &finishedAt
+			u.saveImportJob(ctx, job)
+		}
+	}()
+
+	startedAt := time.Now()
+	job.Status = entities.MetadataImportRunning
+	job.StartedAt = &startedAt
+	u.saveImportJob(ctx, job)
+
+	if err := u.importRows(ctx, job, columns, rows); err != nil {
+		job.Status = entities.MetadataImportFailed
+		job.Failure = err.Error()
+	} else {
+		job.Status = entities.MetadataImportCompleted
+	}
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	u.saveImportJob(ctx, job)
+}
+
+// FailStaleImports marks failed the jobs lost with a previous server, on start and then periodically until ctx is
+// cancelled
+func (u *MetadataImportUseCase) FailStaleImports(ctx context.Context) {
+	ticker := time.NewTicker(metadataImportStaleAfter)
+	defer ticker.Stop()
+	for {
+		failed, err := u.metadataImportRepo.FailStaleImportJobs(ctx, time.Now().Add(-metadataImportStaleAfter), "import was interrupted by a server restart")
+		if err != nil {
+			log.Printf("failed to fail stale metadata import jobs: %v", err)
+		} else if failed > 0 {
+			log.Printf("marked %d interrupted metadata import jobs failed", failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *MetadataImportUseCase) saveImportJob(ctx context.Context, job *entities.MetadataImportJob) {
+	job.UpdatedAt = time.Now()
+	if err := u.metadataImportRepo.UpdateImportJob(ctx, job); err != nil {
+		log.Printf("failed to save metadata import job %s: %v", job.ID, err)
+	}
+}
+
+func (u *MetadataImportUseCase) importRows(ctx context.Context, job *entities.MetadataImportJob, columns map[string]int, rows [][]string) error {
+	cell := func(row []string, column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(row) {
+			return ""
+		}
+		return row[index]
+	}
+
+	categories, subcategories, err := u.metadataImportRepo.GetCategoriesWithSubcategories(ctx)
+	if err != nil {
+		return err
+	}
+	categoryByName := make(map[string]*entities.Category)
+	for _, category := range categories {
+		categoryByName[strings.ToLower(category.CategoryName)] = category
+	}
+	// subcategory names only have to be unique within their category
+	subcategoryByName := make(map[string]*entities.Subcategory)
+	for _, subcategory := range subcategories {
+		subcategoryByName[subcategory.CategoryID+"|"+strings.ToLower(subcategory.SubcategoryName)] = subcategory
+	}
+
+	var hsnCodes []string
+	subcategoryIds := make(map[string]bool)
+	for _, row := range rows {
+		if hsn := cell(row, entities.MetadataSheetHSNCode); hsn != "" {
+			hsnCodes = append(hsnCodes, hsn)
+		}
+		if category := categoryByName[strings.ToLower(cell(row, entities.MetadataSheetCategory))]; category != nil {
+			if subcategory := subcategoryByName[category.CategoryID+"|"+strings.ToLower(cell(row, entities.MetadataSheetSubcategory))]; subcategory != nil {
+				subcategoryIds[subcategory.SubcategoryID] = true
+			}
+		}
+	}
+
+	existing, err := u.metadataImportRepo.GetMetadataByHSNCodes(ctx, hsnCodes)
+	if err != nil {
+		return err
+	}
+	masterHsn, err := u.metadataImportRepo.ResolveHSNCodes(ctx, hsnCodes)
+	if err != nil {
+		return err
+	}
+	existingByHsn := make(map[string]*entities.Metadata)
+	for _, metadata := range existing {
+		existingByHsn[metadata.MetadataHSNCode] = metadata
+	}
+
+	ids := make([]string, 0, len(subcategoryIds))
+	for id := range subcategoryIds {
+		ids = append(ids, id)
+	}
+	siblings, err := u.metadataImportRepo.GetMetadataInSubcategories(ctx, ids)
+	if err != nil {
+		return err
+	}
+	// names already taken in a subcategory, to catch the same product listed again under another HSN code
+	hsnByName := make(map[string]string)
+	for _, metadata := range siblings {
+		hsnByName[metadata.MetadataSubcategoryID+"|"+strings.ToLower(metadata.MetadataName)] = metadata.MetadataHSNCode
+	}
+
+	schemas := make(map[string]*entities.AttributeSchema)
+	seenHsn := make(map[string]int)
+
+	for i, row := range rows {
+		rowNumber := i + 2 // 1-based, after the header row
+		if isBlankRow(row) {
+			continue
+		}
+
+		name := cell(row, entities.MetadataSheetName)
+		hsn := cell(row, entities.MetadataSheetHSNCode)
+		var rowErrors []*entities.MetadataImportRowError
+		addError := func(column, message string) {
+			rowErrors = append(rowErrors, &entities.MetadataImportRowError{Row: rowNumber, Column: column, HsnCode: hsn, Name: name, Message: message})
+		}
+
+		current := existingByHsn[hsn]
+		if err := validateHSNCode(hsn); err != nil {
+			addError(entities.MetadataSheetHSNCode, err.Error())
+		} else if _, ok := masterHsn[hsn]; !ok {
+			addError(entities.MetadataSheetHSNCode, "hsn code is not in the HSN master")
+		} else if firstRow, ok := seenHsn[hsn]; ok {
+			addError(entities.MetadataSheetHSNCode, fmt.Sprintf("hsn code is repeated from row %d", firstRow))
+		} else if current != nil && job.Mode == entities.MetadataImportCreate {
+			addError(entities.MetadataSheetHSNCode, "metadata for this hsn code already exists")
+		}
+		if hsn != "" {
+			if _, ok := seenHsn[hsn]; !ok {
+				seenHsn[hsn] = rowNumber
+			}
+		}
+
+		if name == "" {
+			addError(entities.MetadataSheetName, "name is required")
+		}
+		description := cell(row, entities.MetadataSheetDescription)
+		if description == "" && current == nil {
+			addError(entities.MetadataSheetDescription, "description is required")
+		}
+
+		mrp, err := strconv.ParseFloat(cell(row, entities.MetadataSheetMRP), 64)
+		if err != nil {
+			addError(entities.MetadataSheetMRP, "mrp must be a number")
+		} else if mrp <= 0 {
+			addError(entities.MetadataSheetMRP, "mrp must be greater than zero")
+		}
+
+		var subcategory *entities.Subcategory
+		category := categoryByName[strings.ToLower(cell(row, entities.MetadataSheetCategory))]
+		if category == nil {
+			addError(entities.MetadataSheetCategory, "category not found")
+		} else {
+			subcategory = subcategoryByName[category.CategoryID+"|"+strings.ToLower(cell(row, entities.MetadataSheetSubcategory))]
+			if subcategory == nil {
+				addError(entities.MetadataSheetSubcategory, "subcategory not found in the category")
+			}
+		}
+
+		var attributes map[string]interface{}
+		var nameKey string
+		if subcategory != nil {
+			nameKey = subcategory.SubcategoryID + "|" + strings.ToLower(name)
+			if takenBy, ok := hsnByName[nameKey]; ok && name != "" && takenBy != hsn {
+				addError(entities.MetadataSheetName, fmt.Sprintf("a product with this name already exists in the subcategory under hsn code %s", takenBy))
+			}
+
+			schema, ok := schemas[subcategory.SubcategoryID]
+			if !ok {
+				schema, err = u.metadataRepo.GetAttributeSchema(ctx, subcategory.SubcategoryID)
+				if err != nil {
+					return err
+				}
+				schemas[subcategory.SubcategoryID] = schema
+			}
+			attributes, err = sheetAttributes(schema, columns, row, current)
+			if err == nil {
+				attributes, err = validateMetadataAttributes(schema, attributes)
+			}
+			if err != nil {
+				addError(entities.MetadataSheetAttributePref+"*", err.Error())
+			}
+		}
+
+		if len(rowErrors) == 0 {
+			metadata := &entities.Metadata{
+				MetadataName:          name,
+				MetadataHSNCode:       hsn,
+				MetadataDescription:   description,
+				MetadataImage:         cell(row, entities.MetadataSheetImage),
+				MetadataCategoryID:    category.CategoryID,
+				MetadataSubcategoryID: subcategory.SubcategoryID,
+				MetadataMRP:           mrp,
+				MetadataAttributes:    attributes,
+				MetadataUpdatedBy:     job.CreatedBy,
+			}
+			if err := u.saveImportedMetadata(ctx, metadata, current); err != nil {
+				addError(entities.MetadataSheetHSNCode, err.Error())
+			} else {
+				hsnByName[nameKey] = hsn
+				if current != nil {
+					job.UpdatedCount++
+				} else {
+					job.CreatedCount++
+				}
+			}
+		}
+
+		if len(rowErrors) > 0 {
+			job.FailedCount++
+			job.Errors = append(job.Errors, rowErrors...)
+		}
+		job.ProcessedRows++
+		if job.ProcessedRows%metadataImportBatch == 0 {
+			u.saveImportJob(ctx, job)
+		}
+	}
+	return nil
+}
+
+// sheetAttributes reads the attr.<key> cells of a row typed by the schema. An updated product keeps the attributes
+// the sheet leaves blank.
+func sheetAttributes(schema *entities.AttributeSchema, columns map[string]int, row []string, current *entities.Metadata) (map[string]interface{}, error) {
+	attributes := make(map[string]interface{})
+	if current != nil {
+		for key, value := range current.MetadataAttributes {
+			attributes[key] = value
+		}
+	}
+	definitions := make(map[string]*entities.AttributeDefinition, len(schema.Attributes))
+	for _, attribute := range schema.Attributes {
+		definitions[attribute.Key] = attribute
+	}
+
+	for column, index := range columns {
+		key, ok := strings.CutPrefix(column, entities.MetadataSheetAttributePref)
+		if !ok || index >= len(row) || row[index] == "" {
+			continue
+		}
+		value := row[index]
+		attribute, ok := definitions[key]
+		if !ok {
+			return nil, fmt.Errorf("attribute %s is not defined for this subcategory", key)
+		}
+		switch attribute.Type {
+		case entities.AttributeTypeNumber:
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %s must be a number", key)
+			}
+			attributes[key] = number
+		case entities.AttributeTypeBoolean:
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %s must be true or false", key)
+			}
+			attributes[key] = flag
+		default:
+			attributes[key] = value
+		}
+	}
+	return attributes, nil
+}
+
+func (u *MetadataImportUseCase) saveImportedMetadata(ctx context.Context, metadata *entities.Metadata, current *entities.Metadata) error {
+	now := time.Now()
+	metadata.MetadataUpdatedAt = now
+	if current != nil {
+		response, err := u.metadataRepo.UpdateMetadata(ctx, current.MetadataProductID, metadata)
+		if err != nil {
+			return err
+		}
+		if !response.Success {
+			return errors.New(response.Message)
+		}
+		return nil
+	}
+
+	metadata.MetadataCreatedAt = now
+	response, err := u.metadataRepo.CreateMetadata(ctx, metadata)
+	if err != nil {
+		return err
+	}
+	if !response.Success {
+		return errors.New(response.Message)
+	}
+	metadata.MetadataProductID = response.Id
+	if _, err := u.metadataRepo.CreateReview(ctx, response.Id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *MetadataImportUseCase) GetImportJob(ctx context.Context, jobId string) (*entities.MetadataImportJob, error) {
+	if jobId == "" {
+		return nil, errors.New("import job id is required")
+	}
+	return u.metadataImportRepo.GetImportJob(ctx, jobId)
+}
+
+func (u *MetadataImportUseCase) GetImportJobs(ctx context.Context, limit, offset int64) ([]*entities.MetadataImportJob, int64, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return u.metadataImportRepo.GetImportJobs(ctx, limit, offset)
+}
+
+// ExportImportErrors renders the rows a job could not import as an .xlsx or .csv report
+func (u *MetadataImportUseCase) ExportImportErrors(ctx context.Context, jobId, format string) ([]byte, error) {
+	if format != "xlsx" && format != "csv" {
+		return nil, errors.New("format must be xlsx or csv")
+	}
+	job, err := u.GetImportJob(ctx, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]interface{}{{"row", "column", entities.MetadataSheetHSNCode, entities.MetadataSheetName, "error"}}
+	for _, rowError := range job.Errors {
+		rows = append(rows, []interface{}{rowError.Row, rowError.Column, rowError.HsnCode, rowError.Name, rowError.Message})
+	}
+
+	var buffer bytes.Buffer
+	if err := utils.WriteSpreadsheet(&buffer, format, rows); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultDuplicateMinScore = 0.7
+	defaultDuplicateLimit    = 50
+	maxDuplicateLimit        = 500
+	// maxDuplicateBlockSize skips blocks so common they would only yield noise, e.g. every name starting "fresh"
+	maxDuplicateBlockSize = 200
+
+	duplicateNameWeight = 0.6
+	duplicateHSNWeight  = 0.25
+	duplicateMRPWeight  = 0.15
+	// duplicateMRPTolerance is the relative MRP difference beyond which prices stop counting towards a match
+	duplicateMRPTolerance = 0.05
+)
+
+var (
+	nameSeparatorPattern = regexp.MustCompile(`[^a-z0-9]+`)
+	nameUnitPattern      = regexp.MustCompile(`(\d)([a-z])`)
+	nameUnits            = map[string]string{
+		"kgs": "kg", "kilo": "kg", "kilos": "kg", "kilogram": "kg", "kilograms": "kg",
+		"gm": "g", "gms": "g", "gram": "g", "grams": "g", "gr": "g",
+		"ltr": "l", "ltrs": "l", "litre": "l", "litres": "l", "liter": "l", "liters": "l",
+		"mls": "ml", "millilitre": "ml", "milliliter": "ml",
+		"pc": "pcs", "piece": "pcs", "pieces": "pcs",
+		"&": "and",
+	}
+)
+
+type MetadataMergeUseCase struct {
+	metadataMergeRepo repositories.MetadataMergeRepository
+}
+
+func NewMetadataMergeUseCase(metadataMergeRepo repositories.MetadataMergeRepository) *MetadataMergeUseCase {
+	return &MetadataMergeUseCase{
+		metadataMergeRepo: metadataMergeRepo,
+	}
+}
+
+// FindDuplicates scores metadata pairs by normalised name, HSN code and MRP and returns the pairs scoring at least
+// MinScore, best first. Pairs are only compared when they share the first name token or the HSN code.
+func (u *MetadataMergeUseCase) FindDuplicates(ctx context.Context, request *entities.FindDuplicatesRequest) ([]*entities.DuplicateCandidate, error) {
+	if request.MinScore <= 0 {
+		request.MinScore = defaultDuplicateMinScore
+	}
+	if request.MinScore > 1 {
+		return nil, errors.New("min_score must be between 0 and 1")
+	}
+	if request.Limit <= 0 {
+		request.Limit = defaultDuplicateLimit
+	}
+	if request.Limit > maxDuplicateLimit {
+		request.Limit = maxDuplicateLimit
+	}
+
+	metadata, err := u.metadataMergeRepo.GetMetadataForDuplicateScan(ctx, request.SubcategoryID)
+	if err != nil {
+		return nil, err
+	}
+	dismissals, err := u.metadataMergeRepo.GetDismissedDuplicates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dismissed := make(map[string]bool, len(dismissals))
+	for _, dismissal := range dismissals {
+		dismissed[duplicatePairKey(dismissal.LeftID, dismissal.RightID)] = true
+	}
+
+	tokens := make([][]string, len(metadata))
+	blocks := make(map[string][]int)
+	for i, m := range metadata {
+		tokens[i] = normalizeProductName(m.MetadataName)
+		if len(tokens[i]) > 0 {
+			blocks["name:"+tokens[i][0]] = append(blocks["name:"+tokens[i][0]], i)
+		}
+		if m.MetadataHSNCode != "" {
+			blocks["hsn:"+m.MetadataHSNCode] = append(blocks["hsn:"+m.MetadataHSNCode], i)
+		}
+	}
+
+	seen := make(map[string]bool)
+	candidates := []*entities.DuplicateCandidate{}
+	for _, block := range blocks {
+		if len(block) < 2 || len(block) > maxDuplicateBlockSize {
+			continue
+		}
+		for a := 0; a < len(block); a++ {
+			for b := a + 1; b < len(block); b++ {
+				left, right := metadata[block[a]], metadata[block[b]]
+				key := duplicatePairKey(left.MetadataProductID, right.MetadataProductID)
+				if seen[key] || dismissed[key] {
+					continue
+				}
+				seen[key] = true
+
+				score, reasons := scoreDuplicate(left, right, tokens[block[a]], tokens[block[b]])
+				if score < request.MinScore {
+					continue
+				}
+				if left.MetadataProductID > right.MetadataProductID {
+					left, right = right, left
+				}
+				candidates = append(candidates, &entities.DuplicateCandidate{
+					Left:    left,
+					Right:   right,
+					Score:   math.Round(score*1000) / 1000,
+					Reasons: reasons,
+				})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return duplicatePairKey(candidates[i].Left.MetadataProductID, candidates[i].Right.MetadataProductID) <
+			duplicatePairKey(candidates[j].Left.MetadataProductID, candidates[j].Right.MetadataProductID)
+	})
+	if len(candidates) > request.Limit {
+		candidates = candidates[:request.Limit]
+	}
+	return candidates, nil
+}
+
+func (u *MetadataMergeUseCase) DismissDuplicate(ctx context.Context, request *entities.DismissDuplicateRequest) error {
+	if request.LeftID == request.RightID {
+		return errors.New("a metadata cannot be a duplicate of itself")
+	}
+	left, right := request.LeftID, request.RightID
+	if left > right {
+		left, right = right, left
+	}
+	return u.metadataMergeRepo.DismissDuplicate(ctx, &entities.DismissedDuplicate{
+		LeftID:      left,
+		RightID:     right,
+		DismissedBy: request.DismissedBy,
+	})
+}
+
+func (u *MetadataMergeUseCase) MergeMetadata(ctx context.Context, request *entities.MergeMetadataRequest) (*entities.MetadataMerge, error) {
+	if request.SurvivorID == request.MergedID {
+		return nil, errors.New("cannot merge a metadata into itself")
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+	return u.metadataMergeRepo.MergeMetadata(ctx, request)
+}
+
+func (u *MetadataMergeUseCase) GetMetadataMerges(ctx context.Context, metadataId string, limit, offset int64) ([]*entities.MetadataMerge, int64, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return u.metadataMergeRepo.GetMetadataMerges(ctx, metadataId, limit, offset)
+}
+
+func duplicatePairKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + ":" + b
+}
+
+// scoreDuplicate weighs name similarity, HSN code and MRP into a score between 0 and 1
+func scoreDuplicate(left, right *entities.Metadata, leftTokens, rightTokens []string) (float64, []string) {
+	reasons := []string{}
+
+	nameScore := nameSimilarity(leftTokens, rightTokens)
+	if nameScore == 1 {
+		reasons = append(reasons, "same name")
+	} else if nameScore >= 0.5 {
+		reasons = append(reasons, "similar name")
+	}
+
+	var hsnScore float64
+	switch {
+	case left.MetadataHSNCode == "" || right.MetadataHSNCode == "":
+	case left.MetadataHSNCode == right.MetadataHSNCode:
+		hsnScore = 1
+		reasons = append(reasons, "same hsn code")
+	case len(left.MetadataHSNCode) >= 4 && len(right.MetadataHSNCode) >= 4 && left.MetadataHSNCode[:4] == right.MetadataHSNCode[:4]:
+		hsnScore = 0.5
+		reasons = append(reasons, "same hsn heading")
+	}
+
+	var mrpScore float64
+	if left.MetadataMRP > 0 && right.MetadataMRP > 0 {
+		difference := math.Abs(left.MetadataMRP-right.MetadataMRP) / math.Max(left.MetadataMRP, right.MetadataMRP)
+		if difference == 0 {
+			mrpScore = 1
+			reasons = append(reasons, "same mrp")
+		} else if difference <= duplicateMRPTolerance {
+			mrpScore = 1 - difference/duplicateMRPTolerance
+			reasons = append(reasons, "similar mrp")
+		}
+	}
+
+	return duplicateNameWeight*nameScore + duplicateHSNWeight*hsnScore + duplicateMRPWeight*mrpScore, reasons
+}
+
+// normalizeProductName lowercases a name, splits quantities from their units and spells units one way, so
+// "Basmati Rice 1KG" and "basmati rice - 1 kilogram" give the same tokens
+func normalizeProductName(name string) []string {
+	name = nameUnitPattern.ReplaceAllString(strings.ToLower(name), "$1 $2")
+	name = strings.ReplaceAll(name, "&", " & ")
+	fields := strings.Fields(name)
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if unit, ok := nameUnits[field]; ok {
+			tokens = append(tokens, unit)
+			continue
+		}
+		for _, part := range nameSeparatorPattern.Split(field, -1) {
+			if part == "" {
+				continue
+			}
+			if unit, ok := nameUnits[part]; ok {
+				part = unit
+			}
+			tokens = append(tokens, part)
+		}
+	}
+	return tokens
+}
+
+// nameSimilarity averages the token Jaccard index, which ignores word order, with the character bigram Dice
+// coefficient, which tolerates typos
+func nameSimilarity(left, right []string) float64 {
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+	leftSet, rightSet := make(map[string]bool), make(map[string]bool)
+	for _, token := range left {
+		leftSet[token] = true
+	}
+	for _, token := range right {
+		rightSet[token] = true
+	}
+	shared := 0
+	for token := range leftSet {
+		if rightSet[token] {
+			shared++
+		}
+	}
+	jaccard := float64(shared) / float64(len(leftSet)+len(rightSet)-shared)
+
+	return (jaccard + bigramDice(strings.Join(left, " "), strings.Join(right, " "))) / 2
+}
+
+func bigramDice(left, right string) float64 {
+	if left == right {
+		return 1
+	}
+	if len(left) < 2 || len(right) < 2 {
+		return 0
+	}
+	bigrams := make(map[string]int)
+	for i := 0; i < len(left)-1; i++ {
+		bigrams[left[i:i+2]]++
+	}
+	shared := 0
+	for i := 0; i < len(right)-1; i++ {
+		if bigrams[right[i:i+2]] > 0 {
+			bigrams[right[i:i+2]]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(left)-1+len(right)-1)
+}
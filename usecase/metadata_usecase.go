@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"espazeBackend/domain/entities"
@@ -80,6 +82,15 @@ func (uc *MetadataUseCase) GetMetadataByID(ctx context.Context, id string) (*ent
 func (uc *MetadataUseCase) CreateMetadata(ctx context.Context, req *entities.CreateMetadataRequest) (*entities.MetadataApiResponse, error) {
 	// Generate a new product ID automatically (like UUID)
 
+	req.HsnCode = strings.TrimSpace(req.HsnCode)
+	if err := uc.checkHSNCode(ctx, req.HsnCode); err != nil {
+		return nil, err
+	}
+	attributes, err := uc.checkAttributes(ctx, req.SubcategoryID, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	metadata := &entities.Metadata{
 		MetadataName:          req.Name,
@@ -89,8 +100,10 @@ func (uc *MetadataUseCase) CreateMetadata(ctx context.Context, req *entities.Cre
 		MetadataCategoryID:    req.CategoryID,
 		MetadataSubcategoryID: req.SubcategoryID,
 		MetadataMRP:           req.MRP,
+		MetadataAttributes:    attributes,
 		MetadataCreatedAt:     now,
 		MetadataUpdatedAt:     now,
+		MetadataUpdatedBy:     req.UpdatedBy,
 	}
 
 	response, err := uc.metadataRepo.CreateMetadata(ctx, metadata)
@@ -109,6 +122,27 @@ func (uc *MetadataUseCase) CreateMetadata(ctx context.Context, req *entities.Cre
 // UpdateMetadata updates an existing metadata
 func (uc *MetadataUseCase) UpdateMetadata(ctx context.Context, id string, req *entities.UpdateMetadataRequest) (*entities.MetadataApiResponse, error) {
 
+	// an empty hsn code keeps the current one
+	req.HsnCode = strings.TrimSpace(req.HsnCode)
+	if req.HsnCode != "" {
+		if err := uc.checkHSNCode(ctx, req.HsnCode); err != nil {
+			return nil, err
+		}
+	}
+
+	// attributes left out of the request are kept, but still have to fit the schema of the new subcategory
+	if req.Attributes == nil {
+		existing, err := uc.metadataRepo.GetMetadataByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		req.Attributes = existing.Attributes
+	}
+	attributes, err := uc.checkAttributes(ctx, req.SubcategoryID, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	metadata := &entities.Metadata{
 		MetadataName:          req.Name,
@@ -118,7 +152,9 @@ func (uc *MetadataUseCase) UpdateMetadata(ctx context.Context, id string, req *e
 		MetadataSubcategoryID: req.SubcategoryID,
 		MetadataMRP:           req.MRP,
 		MetadataHSNCode:       req.HsnCode,
+		MetadataAttributes:    attributes,
 		MetadataUpdatedAt:     now,
+		MetadataUpdatedBy:     req.UpdatedBy,
 	}
 
 	response, err := uc.metadataRepo.UpdateMetadata(ctx, id, metadata)
@@ -129,9 +165,9 @@ func (uc *MetadataUseCase) UpdateMetadata(ctx context.Context, id string, req *e
 	return response, nil
 }
 
-// DeleteMetadata deletes a metadata by ID
-func (uc *MetadataUseCase) DeleteMetadata(ctx context.Context, id string) (*entities.MetadataApiResponse, error) {
-	return uc.metadataRepo.DeleteMetadata(ctx, id)
+// DeleteMetadata archives a metadata by ID
+func (uc *MetadataUseCase) DeleteMetadata(ctx context.Context, id string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
+	return uc.metadataRepo.DeleteMetadata(ctx, id, request)
 }
 
 func (uc *MetadataUseCase) AddReview(ctx context.Context, req *entities.AddReviewRequest) error {
@@ -142,6 +178,66 @@ func (uc *MetadataUseCase) AddReview(ctx context.Context, req *entities.AddRevie
 	return nil
 }
 
+func (uc *MetadataUseCase) GetMetadataRevisions(ctx context.Context, id string, limit, offset int64) (*entities.PaginatedMetadataRevisions, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	revisions, total, err := uc.metadataRepo.GetMetadataRevisions(ctx, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PaginatedMetadataRevisions{
+		Revisions:  revisions,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+func (uc *MetadataUseCase) GetMetadataRevision(ctx context.Context, id string, revision int) (*entities.MetadataRevision, error) {
+	return uc.metadataRepo.GetMetadataRevision(ctx, id, revision)
+}
+
+// RollbackMetadata restores a metadata to an earlier revision. The HSN master, attribute schemas and catalog may
+// have changed since, so the old state is validated again like an update before it is restored.
+func (uc *MetadataUseCase) RollbackMetadata(ctx context.Context, id string, req *entities.RollbackMetadataRequest) (*entities.Metadata, error) {
+	target, err := uc.metadataRepo.GetMetadataRevision(ctx, id, *req.Revision)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := target.Snapshot
+	if err := uc.checkHSNCode(ctx, snapshot.MetadataHSNCode); err != nil {
+		return nil, fmt.Errorf("cannot roll back to revision %d: %v", *req.Revision, err)
+	}
+	if _, err := uc.checkAttributes(ctx, snapshot.MetadataSubcategoryID, snapshot.MetadataAttributes); err != nil {
+		return nil, fmt.Errorf("cannot roll back to revision %d: %v", *req.Revision, err)
+	}
+	return uc.metadataRepo.RollbackMetadata(ctx, id, *req.Revision, req.EditedBy)
+}
+
+// checkHSNCode checks the format of an HSN code and that the HSN master can rate it
+func (uc *MetadataUseCase) checkHSNCode(ctx context.Context, hsnCode string) error {
+	if err := validateHSNCode(hsnCode); err != nil {
+		return err
+	}
+	_, err := uc.metadataRepo.GetHSNCode(ctx, hsnCode)
+	return err
+}
+
+// checkAttributes validates attributes against the schema of a subcategory
+func (uc *MetadataUseCase) checkAttributes(ctx context.Context, subcategoryId string, attributes map[string]interface{}) (map[string]interface{}, error) {
+	schema, err := uc.metadataRepo.GetAttributeSchema(ctx, subcategoryId)
+	if err != nil {
+		return nil, err
+	}
+	return validateMetadataAttributes(schema, attributes)
+}
+
 func (uc *MetadataUseCase) GetMetadataForSubcategories(ctx context.Context, subCategoryIds []string) ([]*entities.GetMetadataForSubcategoryResponse, error) {
 	return uc.metadataRepo.GetMetadataForSubcategories(ctx, subCategoryIds)
 }
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+)
+
+type NotificationUseCase struct {
+	notificationRepo repositories.NotificationRepository
+}
+
+func NewNotificationUseCase(notificationRepo repositories.NotificationRepository) *NotificationUseCase {
+	return &NotificationUseCase{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (u *NotificationUseCase) GetNotifications(ctx context.Context, userId string, offset, limit int64, unreadOnly bool) (*entities.PaginatedNotificationResponse, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	notifications, total, unread, err := u.notificationRepo.GetNotifications(ctx, userId, offset, limit, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	var totalPages int64 = (total + limit - 1) / limit
+
+	return &entities.PaginatedNotificationResponse{
+		Notifications: notifications,
+		Total:         total,
+		Unread:        unread,
+		TotalPages:    totalPages,
+		Limit:         limit,
+		Offset:        offset,
+	}, nil
+}
+
+func (u *NotificationUseCase) MarkNotificationRead(ctx context.Context, userId, notificationId string) error {
+	if notificationId == "" {
+		return errors.New("notification id is required")
+	}
+	return u.notificationRepo.MarkNotificationRead(ctx, userId, notificationId)
+}
+
+func (u *NotificationUseCase) MarkAllNotificationsRead(ctx context.Context, userId string) (int64, error) {
+	return u.notificationRepo.MarkAllNotificationsRead(ctx, userId)
+}
@@ -5,6 +5,10 @@ import (
 	"errors"
 	"espazeBackend/domain/entities"
 	"espazeBackend/domain/repositories"
+	"fmt"
+	"log"
+	"math"
+	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -48,10 +52,15 @@ func (u *OrderUsecase) GetAllOrders(ctx context.Context, requestData *entities.G
 }
 
 func (u *OrderUsecase) CreateNewOrder(ctx context.Context, requestOrder *entities.CreateOrderRequest) error {
+	err := u.resolveOrderPrices(ctx, requestOrder)
+	if err != nil {
+		return err
+	}
+
 	OrderId := primitive.NewObjectID().Hex()
 	OrderedAt := time.Now()
 
-	err := u.OrderRepository.CreateNewOrder(ctx, requestOrder, OrderId, OrderedAt)
+	err = u.OrderRepository.CreateNewOrder(ctx, requestOrder, OrderId, OrderedAt)
 	if err != nil {
 		return err
 	}
@@ -64,6 +73,72 @@ func (u *OrderUsecase) CreateNewOrder(ctx context.Context, requestOrder *entitie
 	return nil
 }
 
+// resolveOrderPrices prices every line at the effective price of the product when the order is placed, so a
+// cart loaded during a sale that has since ended cannot keep the sale price, and recomputes the order total.
+// Prices include GST, so each line carries the GST share of its total at the rate of its HSN code. Until the
+// HSN master holds a rate for every code, a line without one is sold untaxed and marked tax pending; setting
+// GST_RATE_REQUIRED=true refuses such lines instead.
+func (u *OrderUsecase) resolveOrderPrices(ctx context.Context, requestOrder *entities.CreateOrderRequest) error {
+	if len(requestOrder.Products) == 0 {
+		return errors.New("order has no products")
+	}
+	productIds := make([]string, 0, len(requestOrder.Products))
+	for _, product := range requestOrder.Products {
+		productIds = append(productIds, product.ProductID)
+	}
+	prices, err := u.OrderRepository.GetEffectivePrices(ctx, productIds)
+	if err != nil {
+		return err
+	}
+	hsnCodes := make([]string, 0, len(prices))
+	for _, price := range prices {
+		hsnCodes = append(hsnCodes, price.HsnCode)
+	}
+	gstRates, err := u.OrderRepository.GetGSTRates(ctx, hsnCodes)
+	if err != nil {
+		return err
+	}
+
+	orderTotal := 0.0
+	taxTotal := 0.0
+	for _, product := range requestOrder.Products {
+		if product.Quantity <= 0 {
+			return fmt.Errorf("quantity of product %s must be greater than zero", product.ProductID)
+		}
+		price, ok := prices[product.ProductID]
+		if !ok {
+			return fmt.Errorf("product %s is not available", product.ProductID)
+		}
+		product.Price = price.Price
+		product.MRP = price.MRP
+		product.SellerID = price.SellerID
+		product.HsnCode = price.HsnCode
+		product.MetadataProductID = price.MetadataProductID
+		product.MetadataRevision = price.MetadataRevision
+		gstRate, ok := gstRates[price.HsnCode]
+		if !ok {
+			if os.Getenv("GST_RATE_REQUIRED") == "true" {
+				return fmt.Errorf("product %s cannot be sold, its HSN code %s has no GST rate in force", product.ProductID, price.HsnCode)
+			}
+			log.Printf("no GST rate in force for HSN code %q of product %s, selling it with tax pending", price.HsnCode, product.ProductID)
+			product.TaxPending = true
+		}
+		product.GSTRate = gstRate
+		lineTotal := product.Price * float64(product.Quantity)
+		product.TaxAmount = includedGST(lineTotal, product.GSTRate)
+		orderTotal += lineTotal
+		taxTotal += product.TaxAmount
+	}
+	requestOrder.OrderTotal = math.Round(orderTotal*100) / 100
+	requestOrder.TaxTotal = math.Round(taxTotal*100) / 100
+	return nil
+}
+
+// includedGST is the GST share, to the paisa, of an amount that already includes GST at rate percent.
+func includedGST(amount, rate float64) float64 {
+	return math.Round(amount*rate/(100+rate)*100) / 100
+}
+
 func (u *OrderUsecase) GetOrderByOrderID(ctx context.Context, orderId *string) (*entities.GetAllOrdersReturn, error) {
 	order, err := u.OrderRepository.GetOrderByOrderID(ctx, orderId)
 	if err == mongo.ErrNoDocuments {
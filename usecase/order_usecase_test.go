@@ -0,0 +1,26 @@
+package usecase
+
+import "testing"
+
+func TestIncludedGST(t *testing.T) {
+	tests := []struct {
+		amount float64
+		rate   float64
+		want   float64
+	}{
+		{amount: 118, rate: 18, want: 18},
+		{amount: 105, rate: 5, want: 5},
+		{amount: 112, rate: 12, want: 12},
+		{amount: 128, rate: 28, want: 28},
+		{amount: 100, rate: 0, want: 0},
+		{amount: 0, rate: 18, want: 0},
+		{amount: 99.99, rate: 18, want: 15.25},
+		{amount: 10, rate: 5, want: 0.48},
+		{amount: 3 * 33.33, rate: 12, want: 10.71},
+	}
+	for _, tt := range tests {
+		if got := includedGST(tt.amount, tt.rate); got != tt.want {
+			t.Errorf("includedGST(%.2f, %.2f) = %.4f, want %.2f", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrPriceGuardrail marks a price change refused by the pricing rules
+var ErrPriceGuardrail = errors.New("price change not allowed")
+
+// checkPriceGuardrails validates a new price. The returned error is a violation no approval can override,
+// the returned violations break configurable guardrails and may be routed to ops.
+func checkPriceGuardrails(pricingContext *entities.PricingContext, newPrice float64) ([]string, error) {
+	if newPrice <= 0 {
+		return nil, fmt.Errorf("%w: price must be greater than zero", ErrPriceGuardrail)
+	}
+	if newPrice > pricingContext.MetadataMRP {
+		return nil, fmt.Errorf("%w: price %.2f exceeds mrp %.2f", ErrPriceGuardrail, newPrice, pricingContext.MetadataMRP)
+	}
+
+	var violations []string
+	guardrail := pricingContext.Guardrail
+	if guardrail == nil {
+		return violations, nil
+	}
+	if guardrail.MaxDailyChangePercent > 0 && pricingContext.DayStartPrice > 0 {
+		change := math.Abs(newPrice-pricingContext.DayStartPrice) / pricingContext.DayStartPrice * 100
+		if change > guardrail.MaxDailyChangePercent {
+			violations = append(violations, fmt.Sprintf("price changes by %.1f%% today, the limit is %.1f%%", change, guardrail.MaxDailyChangePercent))
+		}
+	}
+	if guardrail.MinPricePercentOfMRP > 0 {
+		floor := math.Round(pricingContext.MetadataMRP*guardrail.MinPricePercentOfMRP) / 100
+		if newPrice < floor {
+			violations = append(violations, fmt.Sprintf("price %.2f is below the floor %.2f for this subcategory", newPrice, floor))
+		}
+	}
+	return violations, nil
+}
+
+func newPriceChangeRequest(pricingContext *entities.PricingContext, requestedPrice float64, violations []string, requestedBy string) *entities.PriceChangeRequest {
+	return &entities.PriceChangeRequest{
+		InventoryProductID: pricingContext.InventoryProductID,
+		SellerID:           pricingContext.SellerID,
+		WarehouseID:        pricingContext.WarehouseID,
+		MetadataName:       pricingContext.MetadataName,
+		MetadataMRP:        pricingContext.MetadataMRP,
+		CurrentPrice:       pricingContext.CurrentPrice,
+		RequestedPrice:     requestedPrice,
+		Violations:         violations,
+		RequestedBy:        requestedBy,
+	}
+}
+
+type PricingUseCase struct {
+	pricingRepo repositories.PricingRepository
+}
+
+func NewPricingUseCase(pricingRepo repositories.PricingRepository) *PricingUseCase {
+	return &PricingUseCase{
+		pricingRepo: pricingRepo,
+	}
+}
+
+func (u *PricingUseCase) GetPricingGuardrails(ctx context.Context) ([]*entities.PricingGuardrail, error) {
+	return u.pricingRepo.GetPricingGuardrails(ctx)
+}
+
+func (u *PricingUseCase) UpsertPricingGuardrail(ctx context.Context, guardrail *entities.PricingGuardrail) (*entities.PricingGuardrail, error) {
+	if guardrail.MaxDailyChangePercent < 0 {
+		return nil, errors.New("max_daily_change_percent cannot be negative")
+	}
+	if guardrail.MinPricePercentOfMRP < 0 || guardrail.MinPricePercentOfMRP > 100 {
+		return nil, errors.New("min_price_percent_of_mrp must be between 0 and 100")
+	}
+	switch guardrail.ViolationAction {
+	case "":
+		guardrail.ViolationAction = entities.PriceViolationReject
+	case entities.PriceViolationReject, entities.PriceViolationReview:
+	default:
+		return nil, fmt.Errorf("violation_action must be %s or %s", entities.PriceViolationReject, entities.PriceViolationReview)
+	}
+	return u.pricingRepo.UpsertPricingGuardrail(ctx, guardrail)
+}
+
+func (u *PricingUseCase) DeletePricingGuardrail(ctx context.Context, subcategoryId string) error {
+	return u.pricingRepo.DeletePricingGuardrail(ctx, subcategoryId)
+}
+
+func (u *PricingUseCase) GetPriceHistory(ctx context.Context, inventoryProductId, sellerId string, offset, limit int64) (*entities.PaginatedPriceHistoryResponse, error) {
+	if inventoryProductId == "" {
+		return nil, errors.New("inventory_product_id is required")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	history, total, err := u.pricingRepo.GetPriceHistory(ctx, inventoryProductId, sellerId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	var totalPages int64 = (total + limit - 1) / limit
+
+	return &entities.PaginatedPriceHistoryResponse{
+		History:    history,
+		Total:      total,
+		TotalPages: totalPages,
+		Limit:      limit,
+		Offset:     offset,
+	}, nil
+}
+
+func (u *PricingUseCase) GetPriceChangeRequests(ctx context.Context, request *entities.GetPriceChangeRequestsRequest) (*entities.PaginatedPriceChangeRequestResponse, error) {
+	if request.Limit <= 0 {
+		request.Limit = 10
+	}
+	if request.Offset < 0 {
+		request.Offset = 0
+	}
+	requests, total, err := u.pricingRepo.GetPriceChangeRequests(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	var totalPages int64 = (total + request.Limit - 1) / request.Limit
+
+	return &entities.PaginatedPriceChangeRequestResponse{
+		Requests:   requests,
+		Total:      total,
+		TotalPages: totalPages,
+		Limit:      request.Limit,
+		Offset:     request.Offset,
+	}, nil
+}
+
+// ReviewPriceChangeRequest approves or rejects a queued price change. Approval is refused if the MRP
+// has since dropped below the requested price.
+func (u *PricingUseCase) ReviewPriceChangeRequest(ctx context.Context, review *entities.ReviewPriceChangeRequest) (*entities.PriceChangeRequest, error) {
+	review.Reason = strings.TrimSpace(review.Reason)
+	if review.Reason == "" {
+		return nil, errors.New("reason is required")
+	}
+	if review.Approve {
+		request, err := u.pricingRepo.GetPriceChangeRequestById(ctx, review.RequestID)
+		if err != nil {
+			return nil, err
+		}
+		pricingContexts, err := u.pricingRepo.GetPricingContexts(ctx, []string{request.InventoryProductID})
+		if err != nil {
+			return nil, err
+		}
+		if len(pricingContexts) == 0 {
+			return nil, errors.New("inventory product not found")
+		}
+		if _, err := checkPriceGuardrails(pricingContexts[0], request.RequestedPrice); err != nil {
+			return nil, err
+		}
+	}
+	return u.pricingRepo.ReviewPriceChangeRequest(ctx, review)
+}
+
+// CreateScheduledPrice schedules a sale price for a seller's product. The MRP and the price floor of the
+// subcategory apply, the daily change limit does not since the change is announced in advance.
+func (u *PricingUseCase) CreateScheduledPrice(ctx context.Context, request *entities.CreateScheduledPriceRequest) (*entities.ScheduledPrice, error) {
+	if !request.StartsAt.Before(request.EndsAt) {
+		return nil, errors.New("starts_at must be before ends_at")
+	}
+	if !request.EndsAt.After(time.Now()) {
+		return nil, errors.New("ends_at must be in the future")
+	}
+
+	pricingContexts, err := u.pricingRepo.GetPricingContexts(ctx, []string{request.InventoryProductID})
+	if err != nil {
+		return nil, err
+	}
+	if len(pricingContexts) == 0 || pricingContexts[0].SellerID != request.SellerID {
+		return nil, errors.New("inventory product not found")
+	}
+	saleContext := *pricingContexts[0]
+	saleContext.DayStartPrice = 0
+	violations, err := checkPriceGuardrails(&saleContext, request.Price)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPriceGuardrail, strings.Join(violations, "; "))
+	}
+
+	scheduledPrice, err := u.pricingRepo.CreateScheduledPrice(ctx, &entities.ScheduledPrice{
+		InventoryProductID: request.InventoryProductID,
+		SellerID:           request.SellerID,
+		Price:              request.Price,
+		Label:              strings.TrimSpace(request.Label),
+		StartsAt:           request.StartsAt,
+		EndsAt:             request.EndsAt,
+		CreatedBy:          request.SellerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	scheduledPrice.Status = scheduledPriceStatus(scheduledPrice, time.Now())
+	return scheduledPrice, nil
+}
+
+func (u *PricingUseCase) GetScheduledPrices(ctx context.Context, inventoryProductId, sellerId string, includeEnded bool) ([]*entities.ScheduledPrice, error) {
+	if inventoryProductId == "" {
+		return nil, errors.New("inventory_product_id is required")
+	}
+	scheduledPrices, err := u.pricingRepo.GetScheduledPrices(ctx, inventoryProductId, sellerId, includeEnded)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, scheduledPrice := range scheduledPrices {
+		scheduledPrice.Status = scheduledPriceStatus(scheduledPrice, now)
+	}
+	return scheduledPrices, nil
+}
+
+func (u *PricingUseCase) CancelScheduledPrice(ctx context.Context, scheduledPriceId, sellerId string) (*entities.ScheduledPrice, error) {
+	scheduledPrice, err := u.pricingRepo.CancelScheduledPrice(ctx, scheduledPriceId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+	scheduledPrice.Status = entities.ScheduledPriceCancelled
+	return scheduledPrice, nil
+}
+
+func scheduledPriceStatus(scheduledPrice *entities.ScheduledPrice, now time.Time) string {
+	switch {
+	case scheduledPrice.CancelledAt != nil:
+		return entities.ScheduledPriceCancelled
+	case now.Before(scheduledPrice.StartsAt):
+		return entities.ScheduledPriceUpcoming
+	case now.Before(scheduledPrice.EndsAt):
+		return entities.ScheduledPriceActive
+	default:
+		return entities.ScheduledPriceEnded
+	}
+}
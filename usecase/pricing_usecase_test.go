@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"errors"
+	"espazeBackend/domain/entities"
+	"testing"
+)
+
+func TestCheckPriceGuardrails(t *testing.T) {
+	guardrail := &entities.PricingGuardrail{MaxDailyChangePercent: 10, MinPricePercentOfMRP: 50, ViolationAction: entities.PriceViolationReview}
+	tests := []struct {
+		name          string
+		guardrail     *entities.PricingGuardrail
+		dayStartPrice float64
+		price         float64
+		violations    int
+		err           bool
+	}{
+		{name: "zero price", guardrail: guardrail, dayStartPrice: 80, price: 0, err: true},
+		{name: "negative price", guardrail: guardrail, dayStartPrice: 80, price: -5, err: true},
+		{name: "above mrp", guardrail: guardrail, dayStartPrice: 80, price: 100.01, err: true},
+		{name: "at mrp without a guardrail", dayStartPrice: 80, price: 100},
+		{name: "within the limits", guardrail: guardrail, dayStartPrice: 80, price: 85},
+		{name: "daily change at the limit", guardrail: guardrail, dayStartPrice: 80, price: 88},
+		{name: "daily change above the limit", guardrail: guardrail, dayStartPrice: 80, price: 90, violations: 1},
+		{name: "daily drop above the limit", guardrail: guardrail, dayStartPrice: 80, price: 70, violations: 1},
+		{name: "no price to change from today", guardrail: guardrail, price: 60},
+		{name: "at the floor", guardrail: guardrail, dayStartPrice: 52, price: 50},
+		{name: "below the floor", guardrail: guardrail, dayStartPrice: 48, price: 49, violations: 1},
+		{name: "below the floor and above the daily change", guardrail: guardrail, dayStartPrice: 80, price: 40, violations: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricingContext := &entities.PricingContext{MetadataMRP: 100, CurrentPrice: 80, DayStartPrice: tt.dayStartPrice, Guardrail: tt.guardrail}
+			violations, err := checkPriceGuardrails(pricingContext, tt.price)
+			if tt.err {
+				if !errors.Is(err, ErrPriceGuardrail) {
+					t.Fatalf("checkPriceGuardrails(%.2f) error = %v, want a guardrail error", tt.price, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkPriceGuardrails(%.2f) failed: %v", tt.price, err)
+			}
+			if len(violations) != tt.violations {
+				t.Errorf("checkPriceGuardrails(%.2f) = %q, want %d violations", tt.price, violations, tt.violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxReviewTitleLength = 120
+	maxReviewTextLength  = 2000
+	maxReviewPhotos      = 5
+)
+
+type ProductReviewUseCase struct {
+	productReviewRepo repositories.ProductReviewRepository
+}
+
+func NewProductReviewUseCase(productReviewRepo repositories.ProductReviewRepository) *ProductReviewUseCase {
+	return &ProductReviewUseCase{
+		productReviewRepo: productReviewRepo,
+	}
+}
+
+// reviewPage clamps a page request to 20 reviews by default and 100 at most
+func reviewPage(limit, offset int64) (int64, int64) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func (u *ProductReviewUseCase) SubmitReview(ctx context.Context, request *entities.SubmitProductReviewRequest) (*entities.ProductReview, error) {
+	if request.Rating < 1 || request.Rating > 5 {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+	request.Title = strings.TrimSpace(request.Title)
+	if utf8.RuneCountInString(request.Title) > maxReviewTitleLength {
+		return nil, errors.New("title cannot be longer than 120 characters")
+	}
+	request.Text = strings.TrimSpace(request.Text)
+	if utf8.RuneCountInString(request.Text) > maxReviewTextLength {
+		return nil, errors.New("text cannot be longer than 2000 characters")
+	}
+	if len(request.Photos) > maxReviewPhotos {
+		return nil, errors.New("a review can have at most 5 photos")
+	}
+	photos := make([]string, 0, len(request.Photos))
+	for _, photo := range request.Photos {
+		if photo = strings.TrimSpace(photo); photo != "" {
+			photos = append(photos, photo)
+		}
+	}
+
+	return u.productReviewRepo.SubmitReview(ctx, &entities.ProductReview{
+		MetadataProductID: request.MetadataProductID,
+		UserID:            request.UserID,
+		Rating:            request.Rating,
+		Title:             request.Title,
+		Text:              request.Text,
+		Photos:            photos,
+	})
+}
+
+func (u *ProductReviewUseCase) GetProductReviews(ctx context.Context, metadataId string, limit, offset int64) (*entities.PaginatedProductReviews, error) {
+	if metadataId == "" {
+		return nil, errors.New("metadata product id is required")
+	}
+	limit, offset = reviewPage(limit, offset)
+	return u.productReviewRepo.GetProductReviews(ctx, metadataId, limit, offset)
+}
+
+func (u *ProductReviewUseCase) GetUserReviews(ctx context.Context, userId string) ([]*entities.ProductReview, error) {
+	return u.productReviewRepo.GetUserReviews(ctx, userId)
+}
+
+func (u *ProductReviewUseCase) GetModerationQueue(ctx context.Context, status string, limit, offset int64) ([]*entities.ProductReview, int64, error) {
+	if status == "" {
+		status = entities.ProductReviewPending
+	}
+	switch status {
+	case entities.ProductReviewPending, entities.ProductReviewApproved, entities.ProductReviewRejected:
+	default:
+		return nil, 0, errors.New("invalid status")
+	}
+	limit, offset = reviewPage(limit, offset)
+	return u.productReviewRepo.GetModerationQueue(ctx, status, limit, offset)
+}
+
+func (u *ProductReviewUseCase) ModerateReview(ctx context.Context, request *entities.ModerateProductReviewRequest) (*entities.ProductReview, error) {
+	if request.ReviewID == "" {
+		return nil, errors.New("review id is required")
+	}
+	if request.Decision != entities.ProductReviewApproved && request.Decision != entities.ProductReviewRejected {
+		return nil, errors.New("decision must be approved or rejected")
+	}
+	request.Note = strings.TrimSpace(request.Note)
+	if request.Decision == entities.ProductReviewRejected && request.Note == "" {
+		return nil, errors.New("a note is required to reject a review")
+	}
+	return u.productReviewRepo.ModerateReview(ctx, request)
+}
+
+func (u *ProductReviewUseCase) DeleteReview(ctx context.Context, reviewId, userId string) error {
+	if reviewId == "" {
+		return errors.New("review id is required")
+	}
+	return u.productReviewRepo.DeleteReview(ctx, reviewId, userId)
+}
@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"espazeBackend/domain/entities"
 	"espazeBackend/domain/repositories"
@@ -11,13 +14,15 @@ import (
 
 // ProductUseCase handles business logic for product operations
 type ProductUseCase struct {
-	productRepo repositories.ProductRepository
+	productRepo       repositories.ProductRepository
+	autocompleteCache *autocompleteCache
 }
 
 // NewProductUseCase creates a new product use case
 func NewProductUseCase(productRepo repositories.ProductRepository) *ProductUseCase {
 	return &ProductUseCase{
-		productRepo: productRepo,
+		productRepo:       productRepo,
+		autocompleteCache: newAutocompleteCache(),
 	}
 }
 
@@ -43,12 +48,85 @@ func (u *ProductUseCase) GetProductsForAllStores(ctx context.Context, getProduct
 	return products, nil
 }
 
-func (u *ProductUseCase) GetAllProductsForSubcategory(ctx context.Context, storeId, warehouseId, subcategoryId string) ([]*entities.GetProductsForStoreSubcategory, error) {
+// GetAllProductsForSubcategory lists the products of a subcategory, narrowed by the attribute filters of the query.
+// attr.<key>=a,b keeps products whose attribute is any of the values and attr.<key>.min / .max bound a number.
+func (u *ProductUseCase) GetAllProductsForSubcategory(ctx context.Context, storeId, warehouseId, subcategoryId string, query map[string][]string) ([]*entities.GetProductsForStoreSubcategory, error) {
+	values := make(map[string][]string)
+	min := make(map[string]string)
+	max := make(map[string]string)
+	for param, raw := range query {
+		key, ok := strings.CutPrefix(param, "attr.")
+		if !ok || len(raw) == 0 {
+			continue
+		}
+		if attribute, ok := strings.CutSuffix(key, ".min"); ok {
+			min[attribute] = raw[0]
+		} else if attribute, ok := strings.CutSuffix(key, ".max"); ok {
+			max[attribute] = raw[0]
+		} else {
+			for _, value := range raw {
+				values[key] = append(values[key], strings.Split(value, ",")...)
+			}
+		}
+	}
+
+	var filters []*entities.AttributeFilter
+	if len(values) > 0 || len(min) > 0 || len(max) > 0 {
+		schema, err := u.productRepo.GetAttributeSchema(ctx, subcategoryId)
+		if err != nil {
+			return nil, err
+		}
+		filters, err = parseAttributeFilters(schema, values, min, max)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if storeId == "0" {
-		return u.productRepo.GetProductsForAllStoresSubcategory(ctx, warehouseId, subcategoryId)
+		return u.productRepo.GetProductsForAllStoresSubcategory(ctx, warehouseId, subcategoryId, filters)
 	}
-	return u.productRepo.GetProductsForStoreSubcategory(ctx, storeId, subcategoryId)
+	return u.productRepo.GetProductsForStoreSubcategory(ctx, storeId, subcategoryId, filters)
+
+}
 
+// SearchProducts returns up to limit products ranked by relevance to the search, defaulting to 20
+func (u *ProductUseCase) SearchProducts(ctx context.Context, storeId, warehouseId, search string, limit int) ([]*entities.GetProductsForStoreSubcategory, error) {
+	if strings.TrimSpace(search) == "" {
+		return nil, errors.New("search is required")
+	}
+	if storeId == "" {
+		storeId = "0"
+	}
+	if storeId == "0" && warehouseId == "" {
+		return nil, errors.New("warehouseId is required when searching all stores")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return u.productRepo.SearchProducts(ctx, storeId, warehouseId, search, limit)
+}
+
+// Autocomplete suggests products, categories and subcategories of a warehouse for a partial query, answering
+// repeated queries from the cache
+func (u *ProductUseCase) Autocomplete(ctx context.Context, warehouseId, query string, limit int) (*entities.AutocompleteResponse, error) {
+	if warehouseId == "" {
+		return nil, errors.New("warehouseId is required")
+	}
+	if limit <= 0 || limit > 20 {
+		limit = 5
+	}
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	key := fmt.Sprintf("%s|%d|%s", warehouseId, limit, query)
+	if response, ok := u.autocompleteCache.get(key); ok {
+		return response, nil
+	}
+
+	response, err := u.productRepo.GetAutocompleteSuggestions(ctx, warehouseId, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	u.autocompleteCache.put(key, response)
+	return response, nil
 }
 
 func (u *ProductUseCase) GetBasicDetailsForProduct(ctx context.Context, inventoryProductID string) (*entities.GetBasicDetailsForProductResponse, error) {
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"strings"
+)
+
+type RackUseCase struct {
+	rackRepo repositories.RackRepository
+}
+
+func NewRackUseCase(rackRepo repositories.RackRepository) *RackUseCase {
+	return &RackUseCase{
+		rackRepo: rackRepo,
+	}
+}
+
+func validateRackSize(binCount, capacity int) error {
+	if binCount <= 0 {
+		return errors.New("bin_count must be greater than 0")
+	}
+	if capacity < 0 {
+		return errors.New("capacity cannot be negative")
+	}
+	return nil
+}
+
+func (u *RackUseCase) CreateRack(ctx context.Context, request *entities.CreateRackRequest) (*entities.Rack, error) {
+	if request.StoreID == "" {
+		return nil, errors.New("store_id is required")
+	}
+	request.Code = strings.ToUpper(strings.TrimSpace(request.Code))
+	if request.Code == "" {
+		return nil, errors.New("code is required")
+	}
+	if err := validateRackSize(request.BinCount, request.Capacity); err != nil {
+		return nil, err
+	}
+	request.Description = strings.TrimSpace(request.Description)
+	return u.rackRepo.CreateRack(ctx, request)
+}
+
+func (u *RackUseCase) UpdateRack(ctx context.Context, request *entities.UpdateRackRequest) (*entities.Rack, error) {
+	if request.RackID == "" {
+		return nil, errors.New("rack id is required")
+	}
+	if err := validateRackSize(request.BinCount, request.Capacity); err != nil {
+		return nil, err
+	}
+	request.Description = strings.TrimSpace(request.Description)
+	return u.rackRepo.UpdateRack(ctx, request)
+}
+
+func (u *RackUseCase) DeleteRack(ctx context.Context, rackId, operationalId string) error {
+	if rackId == "" {
+		return errors.New("rack id is required")
+	}
+	return u.rackRepo.DeleteRack(ctx, rackId, operationalId)
+}
+
+func (u *RackUseCase) GetRacks(ctx context.Context, storeId, operationalId string) ([]*entities.Rack, error) {
+	if storeId == "" {
+		return nil, errors.New("store_id is required")
+	}
+	return u.rackRepo.GetRacks(ctx, storeId, operationalId)
+}
+
+func (u *RackUseCase) GetRackById(ctx context.Context, rackId, operationalId string) (*entities.Rack, error) {
+	if rackId == "" {
+		return nil, errors.New("rack id is required")
+	}
+	return u.rackRepo.GetRackById(ctx, rackId, operationalId)
+}
+
+func (u *RackUseCase) AssignRackBin(ctx context.Context, request *entities.AssignRackBinRequest) (*entities.InventoryProduct, error) {
+	if request.InventoryProductID == "" {
+		return nil, errors.New("inventory_product_id is required")
+	}
+	return u.rackRepo.AssignRackBin(ctx, request)
+}
+
+func (u *RackUseCase) FindProductLocations(ctx context.Context, request *entities.FindProductLocationRequest) ([]*entities.ProductLocation, error) {
+	if request.WarehouseID == "" {
+		return nil, errors.New("warehouse_id is required")
+	}
+	request.Search = strings.TrimSpace(request.Search)
+	if request.Search == "" {
+		return nil, errors.New("search is required")
+	}
+	return u.rackRepo.FindProductLocations(ctx, request)
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+)
+
+type StockTransferUseCase struct {
+	stockTransferRepo repositories.StockTransferRepository
+}
+
+func NewStockTransferUseCase(stockTransferRepo repositories.StockTransferRepository) *StockTransferUseCase {
+	return &StockTransferUseCase{
+		stockTransferRepo: stockTransferRepo,
+	}
+}
+
+// CreateStockTransfer dispatches stock from one store to another store of the same warehouse
+func (u *StockTransferUseCase) CreateStockTransfer(ctx context.Context, request *entities.CreateStockTransferRequest) (*entities.StockTransfer, error) {
+	if request.SourceStoreID == "" || request.DestinationStoreID == "" {
+		return nil, errors.New("source_store_id and destination_store_id are required")
+	}
+	if request.SourceStoreID == request.DestinationStoreID {
+		return nil, errors.New("source and destination store must be different")
+	}
+	if len(request.Items) == 0 {
+		return nil, errors.New("at least one item is required")
+	}
+	seen := make(map[string]bool)
+	for _, item := range request.Items {
+		if item.InventoryProductID == "" {
+			return nil, errors.New("inventory_product_id is required for every item")
+		}
+		if seen[item.InventoryProductID] {
+			return nil, fmt.Errorf("inventory product %s is listed more than once", item.InventoryProductID)
+		}
+		seen[item.InventoryProductID] = true
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity for inventory product %s must be greater than 0", item.InventoryProductID)
+		}
+	}
+	return u.stockTransferRepo.CreateStockTransfer(ctx, request)
+}
+
+func (u *StockTransferUseCase) ReceiveStockTransfer(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, error) {
+	if transferId == "" {
+		return nil, errors.New("transfer id is required")
+	}
+	return u.stockTransferRepo.ReceiveStockTransfer(ctx, transferId, operationalId)
+}
+
+func (u *StockTransferUseCase) CancelStockTransfer(ctx context.Context, transferId, operationalId string) (*entities.StockTransfer, error) {
+	if transferId == "" {
+		return nil, errors.New("transfer id is required")
+	}
+	return u.stockTransferRepo.CancelStockTransfer(ctx, transferId, operationalId)
+}
+
+// GetStockTransferById returns a transfer, limited to the two sellers involved when sellerId is set
+// and to the warehouse of the operations user when operationalId is set
+func (u *StockTransferUseCase) GetStockTransferById(ctx context.Context, transferId, sellerId, operationalId string) (*entities.StockTransfer, error) {
+	if transferId == "" {
+		return nil, errors.New("transfer id is required")
+	}
+	transfer, err := u.stockTransferRepo.GetStockTransferById(ctx, transferId, operationalId)
+	if err != nil {
+		return nil, err
+	}
+	if sellerId != "" && transfer.SourceSellerID != sellerId && transfer.DestinationSellerID != sellerId {
+		return nil, errors.New("transfer not found")
+	}
+	return transfer, nil
+}
+
+func (u *StockTransferUseCase) GetStockTransfers(ctx context.Context, request *entities.GetStockTransfersRequest) (*entities.PaginatedStockTransferResponse, error) {
+	if request.Limit <= 0 {
+		request.Limit = 10
+	}
+	if request.Offset < 0 {
+		request.Offset = 0
+	}
+	switch request.Status {
+	case "", entities.StockTransferDispatched, entities.StockTransferReceived, entities.StockTransferCancelled:
+	default:
+		return nil, fmt.Errorf("invalid status %s", request.Status)
+	}
+	transfers, total, err := u.stockTransferRepo.GetStockTransfers(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	var totalPages int64 = (total + request.Limit - 1) / request.Limit
+
+	return &entities.PaginatedStockTransferResponse{
+		Transfers:  transfers,
+		Total:      total,
+		TotalPages: totalPages,
+		Limit:      request.Limit,
+		Offset:     request.Offset,
+	}, nil
+}
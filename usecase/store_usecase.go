@@ -96,16 +96,6 @@ func (u *StoreUseCase) UpdateStore(ctx context.Context, storeId string, request
 	if request.StoreContact != "" {
 		existingStore.StoreContact = request.StoreContact
 	}
-	if request.NumberOfRacks > 0 {
-		existingStore.NumberOfRacks = request.NumberOfRacks
-	}
-	if request.OccupiedRacks >= 0 {
-		// Validate that occupied racks don't exceed total racks
-		if request.OccupiedRacks > existingStore.NumberOfRacks {
-			return nil, errors.New("occupied_racks cannot exceed number_of_racks")
-		}
-		existingStore.OccupiedRacks = request.OccupiedRacks
-	}
 
 	// Update timestamp
 	existingStore.UpdatedAt = time.Now()
@@ -123,28 +113,14 @@ func (u *StoreUseCase) UpdateStore(ctx context.Context, storeId string, request
 	}, nil
 }
 
-func (u *StoreUseCase) DeleteStore(ctx context.Context, storeId string) (*entities.DeleteStoreResponse, error) {
+func (u *StoreUseCase) DeleteStore(ctx context.Context, storeId string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
 	// Validate store ID
 	if storeId == "" {
 		return nil, errors.New("store_id is required")
 	}
 
-	// Check if store exists
-	_, err := u.storeRepository.GetStoreById(ctx, storeId)
-	if err != nil {
-		return nil, err
-	}
-
-	// Delete from repository
-	err = u.storeRepository.DeleteStore(ctx, storeId)
-	if err != nil {
-		return nil, err
-	}
-
-	return &entities.DeleteStoreResponse{
-		Success: true,
-		Message: "Store deleted successfully",
-	}, nil
+	// Archive in repository, which also checks the store exists
+	return u.storeRepository.DeleteStore(ctx, storeId, request)
 }
 
 func (u *StoreUseCase) GetStoreBySellerId(ctx context.Context, sellerId string) (*entities.GetStoreBySellerIdResponse, error) {
@@ -165,29 +141,3 @@ func (u *StoreUseCase) GetStoreBySellerId(ctx context.Context, sellerId string)
 		Store:   *store,
 	}, nil
 }
-
-func (u *StoreUseCase) UpdateStoreRacks(ctx context.Context, storeId string, occupiedRacks int) error {
-	// Validate store ID
-	if storeId == "" {
-		return errors.New("store_id is required")
-	}
-
-	// Validate occupied racks
-	if occupiedRacks < 0 {
-		return errors.New("occupied_racks cannot be negative")
-	}
-
-	// Get existing store to validate against total racks
-	existingStore, err := u.storeRepository.GetStoreById(ctx, storeId)
-	if err != nil {
-		return err
-	}
-
-	// Validate that occupied racks don't exceed total racks
-	if occupiedRacks > existingStore.NumberOfRacks {
-		return errors.New("occupied_racks cannot exceed number_of_racks")
-	}
-
-	// Update racks in repository
-	return u.storeRepository.UpdateStoreRacks(ctx, storeId, occupiedRacks)
-}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"espazeBackend/domain/entities"
+	"espazeBackend/domain/repositories"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const maxVariantAxes = 3
+
+type VariantUseCase struct {
+	variantRepo repositories.VariantRepository
+}
+
+func NewVariantUseCase(variantRepo repositories.VariantRepository) *VariantUseCase {
+	return &VariantUseCase{
+		variantRepo: variantRepo,
+	}
+}
+
+// normalizeVariantAxes lowercases axis names and checks there are one to three distinct ones
+func normalizeVariantAxes(axes []string) ([]string, error) {
+	normalized := make([]string, 0, len(axes))
+	for _, axis := range axes {
+		axis = strings.ToLower(strings.TrimSpace(axis))
+		if axis == "" {
+			return nil, errors.New("axis names cannot be empty")
+		}
+		if slices.Contains(normalized, axis) {
+			return nil, fmt.Errorf("axis %s is repeated", axis)
+		}
+		normalized = append(normalized, axis)
+	}
+	if len(normalized) == 0 || len(normalized) > maxVariantAxes {
+		return nil, fmt.Errorf("a variant group needs between 1 and %d axes", maxVariantAxes)
+	}
+	return normalized, nil
+}
+
+func (u *VariantUseCase) CreateVariantGroup(ctx context.Context, request *entities.CreateVariantGroupRequest) (*entities.VariantGroup, error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if request.SubcategoryID == "" {
+		return nil, errors.New("subcategory_id is required")
+	}
+	axes, err := normalizeVariantAxes(request.Axes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return u.variantRepo.CreateVariantGroup(ctx, &entities.VariantGroup{
+		Name:          request.Name,
+		Axes:          axes,
+		SubcategoryID: request.SubcategoryID,
+		CreatedBy:     request.OperationalID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+}
+
+// UpdateVariantGroup renames a group. Its axes can only change while it has no variants, since every variant
+// holds a value per axis.
+func (u *VariantUseCase) UpdateVariantGroup(ctx context.Context, request *entities.UpdateVariantGroupRequest) (*entities.VariantGroup, error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	existing, err := u.variantRepo.GetVariantGroupById(ctx, request.VariantGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.Axes) == 0 {
+		request.Axes = existing.Axes
+	}
+	axes, err := normalizeVariantAxes(request.Axes)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing.Variants) > 0 && !slices.Equal(axes, existing.Axes) {
+		return nil, errors.New("axes cannot change while the group has variants")
+	}
+	request.Axes = axes
+	return u.variantRepo.UpdateVariantGroup(ctx, request)
+}
+
+func (u *VariantUseCase) DeleteVariantGroup(ctx context.Context, groupId string) error {
+	existing, err := u.variantRepo.GetVariantGroupById(ctx, groupId)
+	if err != nil {
+		return err
+	}
+	if len(existing.Variants) > 0 {
+		return fmt.Errorf("variant group still has %d variants", len(existing.Variants))
+	}
+	return u.variantRepo.DeleteVariantGroup(ctx, groupId)
+}
+
+func (u *VariantUseCase) GetVariantGroups(ctx context.Context, subcategoryId string) ([]*entities.VariantGroup, error) {
+	return u.variantRepo.GetVariantGroups(ctx, subcategoryId)
+}
+
+func (u *VariantUseCase) GetVariantGroupById(ctx context.Context, groupId string) (*entities.VariantGroupDetail, error) {
+	if groupId == "" {
+		return nil, errors.New("variant group id is required")
+	}
+	return u.variantRepo.GetVariantGroupById(ctx, groupId)
+}
+
+// AddVariant puts a metadata product in a group, or changes its attributes if it is already there. It must be in
+// the group's subcategory, give a value for each axis and differ from the other variants in at least one of them.
+func (u *VariantUseCase) AddVariant(ctx context.Context, request *entities.AddVariantRequest) (*entities.VariantGroupDetail, error) {
+	if request.VariantGroupID == "" || request.MetadataProductID == "" {
+		return nil, errors.New("variant_group_id and metadata_product_id are required")
+	}
+	group, err := u.variantRepo.GetVariantGroupById(ctx, request.VariantGroupID)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := u.variantRepo.GetMetadataById(ctx, request.MetadataProductID)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.VariantGroupID != "" && metadata.VariantGroupID != group.ID {
+		return nil, errors.New("metadata already belongs to another variant group")
+	}
+	if metadata.MetadataSubcategoryID != group.SubcategoryID {
+		return nil, errors.New("metadata is not in the subcategory of the variant group")
+	}
+
+	attributes := make(map[string]string, len(request.Attributes))
+	for axis, value := range request.Attributes {
+		axis = strings.ToLower(strings.TrimSpace(axis))
+		if !slices.Contains(group.Axes, axis) {
+			return nil, fmt.Errorf("%s is not an axis of this variant group", axis)
+		}
+		attributes[axis] = strings.TrimSpace(value)
+	}
+	for _, axis := range group.Axes {
+		if attributes[axis] == "" {
+			return nil, fmt.Errorf("a value for %s is required", axis)
+		}
+	}
+	for _, variant := range group.Variants {
+		if variant.MetadataProductID == metadata.MetadataProductID {
+			continue
+		}
+		same := true
+		for _, axis := range group.Axes {
+			if !strings.EqualFold(variant.VariantAttributes[axis], attributes[axis]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return nil, fmt.Errorf("%s already has these attributes", variant.MetadataName)
+		}
+	}
+
+	if err := u.variantRepo.SetMetadataVariant(ctx, metadata.MetadataProductID, group.ID, attributes); err != nil {
+		return nil, err
+	}
+	return u.variantRepo.GetVariantGroupById(ctx, group.ID)
+}
+
+func (u *VariantUseCase) RemoveVariant(ctx context.Context, metadataId string) error {
+	metadata, err := u.variantRepo.GetMetadataById(ctx, metadataId)
+	if err != nil {
+		return err
+	}
+	if metadata.VariantGroupID == "" {
+		return errors.New("metadata is not a variant of any group")
+	}
+	return u.variantRepo.SetMetadataVariant(ctx, metadataId, "", nil)
+}
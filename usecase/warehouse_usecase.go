@@ -42,12 +42,12 @@ func (u *WarehouseUseCase) UpdateWarehouse(ctx context.Context, id string, wareh
 	return u.warehouseRepo.UpdateWarehouse(ctx, id, warehouse)
 }
 
-func (u *WarehouseUseCase) DeleteWarehouse(ctx context.Context, id string) error {
+func (u *WarehouseUseCase) DeleteWarehouse(ctx context.Context, id string, request *entities.ArchiveRequest) (*entities.DependencyReport, error) {
 	// Validate ObjectID format
 	_, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return u.warehouseRepo.DeleteWarehouse(ctx, id)
+	return u.warehouseRepo.DeleteWarehouse(ctx, id, request)
 }
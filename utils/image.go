@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	MaxImageUploadBytes = 10 << 20
+	maxImageSide        = 8000
+	jpegQuality         = 82
+)
+
+// allowedImageTypes maps the sniffed content type of an upload to the format name the image package reports
+var allowedImageTypes = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// ImageRendition is an encoded copy of an uploaded image
+type ImageRendition struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// DecodeUploadedImage checks an upload is a JPEG, PNG, GIF or WebP image within the size limits and decodes it.
+// The dimensions are read from the header first so oversized images are refused before being decoded.
+func DecodeUploadedImage(data []byte) (image.Image, string, error) {
+	if len(data) == 0 {
+		return nil, "", errors.New("file is empty")
+	}
+	if len(data) > MaxImageUploadBytes {
+		return nil, "", fmt.Errorf("image is larger than %d MB", MaxImageUploadBytes>>20)
+	}
+	contentType := http.DetectContentType(data)
+	format, ok := allowedImageTypes[contentType]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported image type %s, use jpeg, png, gif or webp", contentType)
+	}
+	config, decodedFormat, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || decodedFormat != format {
+		return nil, "", errors.New("file is not a valid image")
+	}
+	if config.Width > maxImageSide || config.Height > maxImageSide {
+		return nil, "", fmt.Errorf("image dimensions cannot exceed %dx%d", maxImageSide, maxImageSide)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", errors.New("file is not a valid image")
+	}
+	return img, contentType, nil
+}
+
+// hasTransparency reports whether any pixel of the image is not fully opaque
+func hasTransparency(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return !opaque.Opaque()
+	}
+	return true
+}
+
+// ResizeImage scales an image to fit within maxSide by maxSide, never enlarging it
+func ResizeImage(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Src, nil)
+	return resized
+}
+
+// EncodeWebImage encodes an image for serving, as PNG when it has transparency and as JPEG otherwise
+func EncodeWebImage(img image.Image) (*ImageRendition, error) {
+	var buffer bytes.Buffer
+	rendition := &ImageRendition{Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	if hasTransparency(img) {
+		if err := png.Encode(&buffer, img); err != nil {
+			return nil, err
+		}
+		rendition.ContentType, rendition.Extension = "image/png", "png"
+	} else {
+		if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		rendition.ContentType, rendition.Extension = "image/jpeg", "jpg"
+	}
+	rendition.Data = buffer.Bytes()
+	return rendition, nil
+}
+
+// imageExtensions is the file extension kept for an original upload of each content type
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// OriginalRendition wraps the uploaded bytes, kept as they are
+func OriginalRendition(data []byte, img image.Image, contentType string) *ImageRendition {
+	return &ImageRendition{
+		Data:        data,
+		ContentType: contentType,
+		Extension:   imageExtensions[contentType],
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ReadSpreadsheet reads the first sheet of an .xlsx or .csv upload into rows of trimmed cells
+func ReadSpreadsheet(fileName string, r io.Reader) ([][]string, error) {
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open xlsx file: %w", err)
+		}
+		defer f.Close()
+		sheet := f.GetSheetName(0)
+		if sheet == "" {
+			return nil, errors.New("xlsx file has no sheets")
+		}
+		// Raw values keep dates as Excel serial numbers instead of locale formatted strings
+		rows, err = f.GetRows(sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read xlsx rows: %w", err)
+		}
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		var err error
+		rows, err = reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv rows: %w", err)
+		}
+	default:
+		return nil, errors.New("unsupported file type, only .xlsx and .csv are accepted")
+	}
+
+	for i, row := range rows {
+		for j, cell := range row {
+			rows[i][j] = strings.TrimSpace(cell)
+		}
+	}
+	return rows, nil
+}
+
+// ParseSpreadsheetDate parses the date formats accepted in spreadsheet uploads, including Excel serial dates
+func ParseSpreadsheetDate(value string) (time.Time, error) {
+	layouts := []string{"02-01-2006", "2006-01-02", "2006-01-02 15:04:05", "02/01/2006"}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil {
+		return excelize.ExcelDateToTime(serial, false)
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected DD-MM-YYYY", value)
+}
+
+// WriteSpreadsheet writes rows as a single sheet in the given format ("xlsx" or "csv")
+func WriteSpreadsheet(w io.Writer, format string, rows [][]interface{}) error {
+	switch format {
+	case "xlsx":
+		f := excelize.NewFile()
+		defer f.Close()
+		sheet := f.GetSheetName(0)
+		for i, row := range rows {
+			cell, err := excelize.CoordinatesToCellName(1, i+1)
+			if err != nil {
+				return err
+			}
+			if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+				return fmt.Errorf("failed to write xlsx row: %w", err)
+			}
+		}
+		return f.Write(w)
+	case "csv":
+		writer := csv.NewWriter(w)
+		for _, row := range rows {
+			record := make([]string, len(row))
+			for i, value := range row {
+				record[i] = fmt.Sprint(value)
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write csv row: %w", err)
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return errors.New("unsupported format, only xlsx and csv are supported")
+	}
+}